@@ -127,6 +127,38 @@ load _helpers
   [ "${actual}" != null ]
 }
 
+@test "connectInject/ClusterRole: does not set access to endpointslices by default" {
+  cd `chart_dir`
+  local actual=$(helm template \
+      -s templates/connect-inject-clusterrole.yaml  \
+      --set 'connectInject.enabled=true' \
+      . | tee /dev/stderr |
+      yq '[.rules[] | select(.apiGroups[0] == "discovery.k8s.io")] | length' | tee /dev/stderr)
+  [ "${actual}" = "0" ]
+}
+
+@test "connectInject/ClusterRole: sets get, list and watch access to endpointslices when connectInject.enableEndpointSlices is true" {
+  cd `chart_dir`
+  local object=$(helm template \
+      -s templates/connect-inject-clusterrole.yaml  \
+      --set 'connectInject.enabled=true' \
+      --set 'connectInject.enableEndpointSlices=true' \
+      . | tee /dev/stderr |
+      yq -r '.rules[] | select(.apiGroups[0] == "discovery.k8s.io")' | tee /dev/stderr)
+
+  local actual=$(echo $object | yq -r '.resources | index("endpointslices")' | tee /dev/stderr)
+  [ "${actual}" != null ]
+
+  local actual=$(echo $object | yq -r '.verbs | index("get")' | tee /dev/stderr)
+  [ "${actual}" != null ]
+
+  local actual=$(echo $object | yq -r '.verbs | index("list")' | tee /dev/stderr)
+  [ "${actual}" != null ]
+
+  local actual=$(echo $object | yq -r '.verbs | index("watch")' | tee /dev/stderr)
+  [ "${actual}" != null ]
+}
+
 @test "connectInject/ClusterRole: sets get access to serviceaccounts and secrets when manageSystemACLSis true" {
   cd `chart_dir`
   local object=$(helm template \
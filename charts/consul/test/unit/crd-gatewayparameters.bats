@@ -0,0 +1,21 @@
+#!/usr/bin/env bats
+
+load _helpers
+
+@test "gatewayparameters/CustomResourceDefinition: enabled by default" {
+    cd `chart_dir`
+    local actual=$(helm template \
+        -s templates/crd-gatewayparameters.yaml \
+        . | tee /dev/stderr |
+        yq 'length > 0' | tee /dev/stderr)
+    [ "$actual" = "true" ]
+}
+
+@test "gatewayparameters/CustomResourceDefinition: disabled with connectInject.enabled=false" {
+    cd `chart_dir`
+    assert_empty helm template \
+        -s templates/crd-gatewayparameters.yaml \
+        --set 'connectInject.enabled=false' \
+        . 
+}
+
@@ -84,3 +84,51 @@ load _helpers
       yq -c '.rules[0].verbs' | tee /dev/stderr)
   [ "${actual}" = '["get","list","watch","update","patch","delete","create"]' ]
 }
+
+#--------------------------------------------------------------------
+# endpointslices
+
+@test "syncCatalog/ClusterRole: allows endpointslices access" {
+  cd `chart_dir`
+  local actual=$(helm template \
+      -s templates/sync-catalog-clusterrole.yaml  \
+      --set 'syncCatalog.enabled=true' \
+      . | tee /dev/stderr |
+      yq -r '.rules[] | select(.apiGroups[0] == "discovery.k8s.io") | .resources[0]' | tee /dev/stderr)
+  [ "${actual}" = "endpointslices" ]
+}
+
+@test "syncCatalog/ClusterRole: has endpointslices write permissions if toK8s=true and k8sSyncEndpoints=true" {
+  cd `chart_dir`
+  local actual=$(helm template \
+      -s templates/sync-catalog-clusterrole.yaml  \
+      --set 'syncCatalog.enabled=true' \
+      --set 'syncCatalog.toK8S=true' \
+      --set 'syncCatalog.k8sSyncEndpoints=true' \
+      . | tee /dev/stderr |
+      yq -c '.rules[] | select(.apiGroups[0] == "discovery.k8s.io") | .verbs' | tee /dev/stderr)
+  [ "${actual}" = '["get","list","watch","update","delete","create"]' ]
+}
+
+@test "syncCatalog/ClusterRole: has read-only endpointslices permissions if toK8s=true and k8sSyncEndpoints=false" {
+  cd `chart_dir`
+  local actual=$(helm template \
+      -s templates/sync-catalog-clusterrole.yaml  \
+      --set 'syncCatalog.enabled=true' \
+      --set 'syncCatalog.toK8S=true' \
+      . | tee /dev/stderr |
+      yq -c '.rules[] | select(.apiGroups[0] == "discovery.k8s.io") | .verbs' | tee /dev/stderr)
+  [ "${actual}" = '["get","list","watch"]' ]
+}
+
+@test "syncCatalog/ClusterRole: has read-only endpointslices permissions if toK8s=false" {
+  cd `chart_dir`
+  local actual=$(helm template \
+      -s templates/sync-catalog-clusterrole.yaml  \
+      --set 'syncCatalog.enabled=true' \
+      --set 'syncCatalog.toK8S=false' \
+      --set 'syncCatalog.k8sSyncEndpoints=true' \
+      . | tee /dev/stderr |
+      yq -c '.rules[] | select(.apiGroups[0] == "discovery.k8s.io") | .verbs' | tee /dev/stderr)
+  [ "${actual}" = '["get","list","watch"]' ]
+}
@@ -0,0 +1,304 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package delete
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/posener/complete"
+	helmCLI "helm.sh/helm/v3/pkg/cli"
+	"k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	"github.com/hashicorp/consul-k8s/cli/common/flag"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+)
+
+const (
+	flagNameFile        = "file"
+	flagNameNamespace   = "namespace"
+	flagNameDryRun      = "dry-run"
+	flagNameKubeConfig  = "kubeconfig"
+	flagNameKubeContext = "context"
+)
+
+// DeleteCommand is the command struct for the config delete command.
+type DeleteCommand struct {
+	*common.BaseCommand
+
+	dynamic dynamic.Interface
+
+	set *flag.Sets
+
+	flagKind      string
+	flagName      string
+	flagFile      string
+	flagNamespace string
+	flagDryRun    bool
+
+	flagKubeConfig  string
+	flagKubeContext string
+
+	// deleteConfigEntry deletes a config entry, or only validates the deletion
+	// with dryRun.
+	deleteConfigEntry func(ctx context.Context, client dynamic.Interface, kind, namespace, name string, dryRun bool) error
+
+	once sync.Once
+	help string
+}
+
+// configEntryRef identifies a config entry custom resource.
+type configEntryRef struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func (r configEntryRef) String() string {
+	return fmt.Sprintf("%s %s/%s", r.kind, r.namespace, r.name)
+}
+
+// init sets up flags and help text for the command.
+func (c *DeleteCommand) init() {
+	if c.deleteConfigEntry == nil {
+		c.deleteConfigEntry = common.DeleteConfigEntry
+	}
+
+	c.set = flag.NewSets()
+
+	f := c.set.NewSet("Command Options")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameFile,
+		Target:  &c.flagFile,
+		Usage:   "Delete the config entries in the given YAML or JSON file instead of the one named by the arguments.",
+		Aliases: []string{"f"},
+	})
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameNamespace,
+		Target:  &c.flagNamespace,
+		Usage:   "The namespace of the config entry. Defaults to the namespace of the current Kubernetes context.",
+		Aliases: []string{"n"},
+	})
+	f.BoolVar(&flag.BoolVar{
+		Name:    flagNameDryRun,
+		Target:  &c.flagDryRun,
+		Default: false,
+		Usage:   "Check that the config entries can be deleted without deleting them.",
+	})
+
+	f = c.set.NewSet("Global Options")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameKubeConfig,
+		Aliases: []string{"c"},
+		Target:  &c.flagKubeConfig,
+		Usage:   "Set the path to kubeconfig file.",
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameKubeContext,
+		Target: &c.flagKubeContext,
+		Usage:  "Set the Kubernetes context to use.",
+	})
+
+	c.help = c.set.Help()
+}
+
+// Run deletes the config entries if all of them can be deleted.
+func (c *DeleteCommand) Run(args []string) int {
+	c.once.Do(c.init)
+	c.Log.ResetNamed("delete")
+	defer common.CloseWithError(c.BaseCommand)
+
+	if err := c.parseFlags(args); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		c.UI.Output("\n" + c.Help())
+		return 1
+	}
+
+	if err := c.validateFlags(); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		c.UI.Output("\n" + c.Help())
+		return 1
+	}
+
+	settings := helmCLI.New()
+	if c.flagKubeConfig != "" {
+		settings.KubeConfig = c.flagKubeConfig
+	}
+	if c.flagKubeContext != "" {
+		settings.KubeContext = c.flagKubeContext
+	}
+
+	namespace := c.flagNamespace
+	if namespace == "" {
+		namespace = settings.Namespace()
+	}
+
+	refs, err := c.configEntryRefs(namespace)
+	if err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.dynamic == nil {
+		if err := c.initKubernetes(settings); err != nil {
+			c.UI.Output(err.Error(), terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	// Check that every config entry can be deleted before deleting any of them.
+	valid := true
+	for _, ref := range refs {
+		if err := c.deleteConfigEntry(c.Ctx, c.dynamic, ref.kind, ref.namespace, ref.name, true); err != nil {
+			c.UI.Output(fmt.Sprintf("%s cannot be deleted: %v", ref, err), terminal.WithErrorStyle())
+			valid = false
+		}
+	}
+	if !valid {
+		c.UI.Output("No config entries were deleted.", terminal.WithErrorStyle())
+		return 1
+	}
+	if c.flagDryRun {
+		c.UI.Output(fmt.Sprintf("%d config entries can be deleted.", len(refs)), terminal.WithSuccessStyle())
+		return 0
+	}
+
+	for _, ref := range refs {
+		if err := c.deleteConfigEntry(c.Ctx, c.dynamic, ref.kind, ref.namespace, ref.name, false); err != nil {
+			c.UI.Output(fmt.Sprintf("Error deleting %s: %v", ref, err), terminal.WithErrorStyle())
+			return 1
+		}
+		c.UI.Output(fmt.Sprintf("%s deleted", ref), terminal.WithSuccessStyle())
+	}
+
+	return 0
+}
+
+// Help returns a description of the command and how it is used.
+func (c *DeleteCommand) Help() string {
+	c.once.Do(c.init)
+	return fmt.Sprintf("%s\n\nUsage: consul-k8s config delete <kind> <name> [flags]\n"+
+		"       consul-k8s config delete -f <file> [flags]\n\n%s", c.Synopsis(), c.help)
+}
+
+// Synopsis returns a one-line command summary.
+func (c *DeleteCommand) Synopsis() string {
+	return "Delete Consul config entry custom resources."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *DeleteCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		fmt.Sprintf("-%s", flagNameFile):        complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", flagNameNamespace):   complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameDryRun):      complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameKubeConfig):  complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", flagNameKubeContext): complete.PredictNothing,
+	}
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// Since argument completion is not supported, this will return
+// complete.PredictNothing.
+func (c *DeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *DeleteCommand) parseFlags(args []string) error {
+	// Separate positional arguments from keyed arguments.
+	positional := []string{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		positional = append(positional, arg)
+	}
+	keyed := args[len(positional):]
+
+	switch len(positional) {
+	case 0:
+	case 2:
+		c.flagKind, c.flagName = positional[0], positional[1]
+	default:
+		return errors.New("exactly two positional arguments must be given: <kind> <name>")
+	}
+
+	return c.set.Parse(keyed)
+}
+
+// validateFlags ensures that the flags passed in by the user can be used.
+func (c *DeleteCommand) validateFlags() error {
+	if len(c.set.Args()) > 0 {
+		return errors.New("positional arguments must be given before flags")
+	}
+	if c.flagFile == "" && c.flagName == "" {
+		return errors.New("either <kind> <name> or -file must be given")
+	}
+	if c.flagFile != "" && c.flagName != "" {
+		return errors.New("<kind> <name> and -file cannot both be given")
+	}
+	if c.flagKind != "" {
+		kind, ok := common.ConfigEntryKind(c.flagKind)
+		if !ok {
+			return fmt.Errorf("%q is not a Consul config entry kind, must be one of %s", c.flagKind, strings.Join(common.ConfigEntryKinds(), ", "))
+		}
+		c.flagKind = kind
+	}
+	if errs := validation.ValidateNamespaceName(c.flagNamespace, false); c.flagNamespace != "" && len(errs) > 0 {
+		return fmt.Errorf("invalid namespace name passed for -namespace/-n: %v", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// initKubernetes initializes the Kubernetes client.
+func (c *DeleteCommand) initKubernetes(settings *helmCLI.EnvSettings) error {
+	restConfig, err := settings.RESTClientGetter().ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("error retrieving Kubernetes authentication %v", err)
+	}
+	if c.dynamic, err = dynamic.NewForConfig(restConfig); err != nil {
+		return fmt.Errorf("error creating Kubernetes client %v", err)
+	}
+
+	return nil
+}
+
+// configEntryRefs returns the config entries to delete, either the one named
+// by the arguments or those in the file. Config entries in the file which do
+// not set a namespace are in the given namespace.
+func (c *DeleteCommand) configEntryRefs(namespace string) ([]configEntryRef, error) {
+	if c.flagFile == "" {
+		return []configEntryRef{{kind: c.flagKind, namespace: namespace, name: c.flagName}}, nil
+	}
+
+	raw, err := os.ReadFile(c.flagFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config entries: %v", err)
+	}
+	entries, err := common.ParseConfigEntries(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config entries: %v", err)
+	}
+
+	refs := make([]configEntryRef, 0, len(entries))
+	for _, entry := range entries {
+		ref := configEntryRef{kind: entry.GetKind(), namespace: entry.GetNamespace(), name: entry.GetName()}
+		if ref.namespace == "" {
+			ref.namespace = namespace
+		}
+		if c.flagNamespace != "" && ref.namespace != c.flagNamespace {
+			return nil, fmt.Errorf("the namespace of %s %q does not match -namespace %q", ref.kind, ref.name, c.flagNamespace)
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
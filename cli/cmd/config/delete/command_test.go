@@ -0,0 +1,205 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package delete
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	cmnFlag "github.com/hashicorp/consul-k8s/cli/common/flag"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+)
+
+func TestFlagParsing(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config-entries.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceDefaults
+metadata:
+  name: web
+  namespace: apps
+`), 0600))
+
+	cases := map[string]struct {
+		args []string
+		out  int
+	}{
+		"No args": {
+			args: []string{},
+			out:  1,
+		},
+		"One positional argument": {
+			args: []string{"servicedefaults"},
+			out:  1,
+		},
+		"Invalid kind": {
+			args: []string{"deployment", "web", "-namespace", "apps"},
+			out:  1,
+		},
+		"Positional arguments after flags": {
+			args: []string{"-namespace", "apps", "servicedefaults", "web"},
+			out:  1,
+		},
+		"Positional arguments and file": {
+			args: []string{"servicedefaults", "web", "-f", file},
+			out:  1,
+		},
+		"Invalid argument passed, -namespace YOLO": {
+			args: []string{"servicedefaults", "web", "-namespace", "YOLO"},
+			out:  1,
+		},
+		"Namespace conflicts with the file": {
+			args: []string{"-f", file, "-namespace", "default"},
+			out:  1,
+		},
+		"Kind and name": {
+			args: []string{"servicedefaults", "web", "-namespace", "apps"},
+			out:  0,
+		},
+		"File": {
+			args: []string{"-f", file},
+			out:  0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := setupCommand(new(bytes.Buffer))
+			c.dynamic = newDynamicClient(t, newServiceDefaults("apps", "web"))
+			skipDryRun(c)
+			out := c.Run(tc.args)
+			require.Equal(t, tc.out, out)
+		})
+	}
+}
+
+func TestDeleteCommand(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	client := newDynamicClient(t, newServiceDefaults("apps", "web"))
+	c.dynamic = client
+	skipDryRun(c)
+
+	out := c.Run([]string{"ServiceDefaults", "web", "-namespace", "apps"})
+	require.Equal(t, 0, out)
+	require.Contains(t, buf.String(), "ServiceDefaults apps/web deleted")
+
+	_, err := client.Resource(common.ConfigEntryResource("ServiceDefaults")).Namespace("apps").Get(context.Background(), "web", metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err))
+}
+
+func TestDeleteCommand_NotFound(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	c.dynamic = newDynamicClient(t)
+
+	out := c.Run([]string{"serviceresolvers", "web", "-namespace", "apps"})
+	require.Equal(t, 1, out)
+	require.Contains(t, buf.String(), "ServiceResolver apps/web cannot be deleted")
+	require.Contains(t, buf.String(), "No config entries were deleted.")
+}
+
+// skipDryRun only checks that the config entry exists on a dry run, since the
+// fake client ignores the dry run option and would delete it.
+func skipDryRun(c *DeleteCommand) {
+	c.deleteConfigEntry = func(ctx context.Context, client dynamic.Interface, kind, namespace, name string, dryRun bool) error {
+		if dryRun {
+			_, err := client.Resource(common.ConfigEntryResource(kind)).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+			return err
+		}
+		return common.DeleteConfigEntry(ctx, client, kind, namespace, name, false)
+	}
+}
+
+// newDynamicClient returns a fake dynamic client holding the config entries.
+// They are created with the resources of their kinds, since the fake client
+// otherwise guesses their plural names, e.g. servicedefaultses.
+func newDynamicClient(t *testing.T, entries ...*unstructured.Unstructured) *dynamicFake.FakeDynamicClient {
+	client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+	for _, entry := range entries {
+		require.NoError(t, client.Tracker().Create(common.ConfigEntryResource(entry.GetKind()), entry, entry.GetNamespace()))
+	}
+	return client
+}
+
+func newServiceDefaults(namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": common.ConfigEntryGroupVersion.String(),
+		"kind":       "ServiceDefaults",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+	}}
+}
+
+func setupCommand(buf io.Writer) *DeleteCommand {
+	// Log at a test level to standard out.
+	log := hclog.New(&hclog.LoggerOptions{
+		Name:   "test",
+		Level:  hclog.Debug,
+		Output: os.Stdout,
+	})
+
+	// Setup and initialize the command struct
+	command := &DeleteCommand{
+		BaseCommand: &common.BaseCommand{
+			Log: log,
+			UI:  terminal.NewUI(context.Background(), buf),
+		},
+	}
+	command.init()
+
+	return command
+}
+
+func TestTaskCreateCommand_AutocompleteFlags(t *testing.T) {
+	t.Parallel()
+	buf := new(bytes.Buffer)
+	cmd := setupCommand(buf)
+
+	predictor := cmd.AutocompleteFlags()
+
+	// Test that we get the expected number of predictions
+	args := complete.Args{Last: "-"}
+	res := predictor.Predict(args)
+
+	// Grab the list of flags from the Flag object
+	flags := make([]string, 0)
+	cmd.set.VisitSets(func(name string, set *cmnFlag.Set) {
+		set.VisitAll(func(flag *flag.Flag) {
+			flags = append(flags, fmt.Sprintf("-%s", flag.Name))
+		})
+	})
+
+	// Verify that there is a prediction for each flag associated with the command
+	assert.Equal(t, len(flags), len(res))
+	assert.ElementsMatch(t, flags, res, "flags and predictions didn't match, make sure to add "+
+		"new flags to the command AutoCompleteFlags function")
+}
+
+func TestTaskCreateCommand_AutocompleteArgs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := setupCommand(buf)
+	c := cmd.AutocompleteArgs()
+	assert.Equal(t, complete.PredictNothing, c)
+}
@@ -0,0 +1,348 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package list
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/posener/complete"
+	helmCLI "helm.sh/helm/v3/pkg/cli"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	"github.com/hashicorp/consul-k8s/cli/common/flag"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+)
+
+const (
+	Table = "table"
+	JSON  = "json"
+
+	flagNameNamespace   = "namespace"
+	flagNameKind        = "kind"
+	flagNameOutput      = "output"
+	flagNameKubeConfig  = "kubeconfig"
+	flagNameKubeContext = "context"
+
+	// conditionSynced is the condition set by the controllers once a config
+	// entry has been written to Consul.
+	conditionSynced = "Synced"
+)
+
+// ListCommand is the command struct for the config list command.
+type ListCommand struct {
+	*common.BaseCommand
+
+	dynamic dynamic.Interface
+
+	set *flag.Sets
+
+	flagNamespace string
+	flagKind      string
+	flagOutput    string
+
+	flagKubeConfig  string
+	flagKubeContext string
+
+	once sync.Once
+	help string
+}
+
+// configEntry is a config entry custom resource and whether it has been synced
+// to Consul.
+type configEntry struct {
+	Kind           string `json:"kind"`
+	Namespace      string `json:"namespace"`
+	Name           string `json:"name"`
+	Synced         string `json:"synced"`
+	LastSyncedTime string `json:"lastSyncedTime,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+	Message        string `json:"message,omitempty"`
+}
+
+// init sets up flags and help text for the command.
+func (c *ListCommand) init() {
+	c.set = flag.NewSets()
+
+	f := c.set.NewSet("Command Options")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameNamespace,
+		Target:  &c.flagNamespace,
+		Usage:   "The namespace to list config entries in. Defaults to all namespaces.",
+		Aliases: []string{"n"},
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameKind,
+		Target: &c.flagKind,
+		Usage:  fmt.Sprintf("Only list config entries of the given kind, one of %s.", strings.Join(common.ConfigEntryKinds(), ", ")),
+	})
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameOutput,
+		Target:  &c.flagOutput,
+		Default: Table,
+		Usage:   "Output the config entries as 'table' or 'json'.",
+		Aliases: []string{"o"},
+	})
+
+	f = c.set.NewSet("Global Options")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameKubeConfig,
+		Aliases: []string{"c"},
+		Target:  &c.flagKubeConfig,
+		Usage:   "Set the path to kubeconfig file.",
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameKubeContext,
+		Target: &c.flagKubeContext,
+		Usage:  "Set the Kubernetes context to use.",
+	})
+
+	c.help = c.set.Help()
+}
+
+// Run executes the list command.
+func (c *ListCommand) Run(args []string) int {
+	c.once.Do(c.init)
+	c.Log.ResetNamed("list")
+	defer common.CloseWithError(c.BaseCommand)
+
+	if err := c.set.Parse(args); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		c.UI.Output("\n" + c.Help())
+		return 1
+	}
+
+	if err := c.validateFlags(); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		c.UI.Output("\n" + c.Help())
+		return 1
+	}
+
+	if c.dynamic == nil {
+		if err := c.initKubernetes(); err != nil {
+			c.UI.Output(err.Error(), terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	entries, err := c.fetchConfigEntries()
+	if err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	switch c.flagOutput {
+	case Table:
+		c.outputTable(entries)
+	case JSON:
+		if err := c.outputJSON(entries); err != nil {
+			c.UI.Output(err.Error(), terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// Help returns a description of the command and how it is used.
+func (c *ListCommand) Help() string {
+	c.once.Do(c.init)
+	return fmt.Sprintf("%s\n\nUsage: consul-k8s config list [flags]\n\n%s", c.Synopsis(), c.help)
+}
+
+// Synopsis returns a one-line command summary.
+func (c *ListCommand) Synopsis() string {
+	return "List Consul config entry custom resources and whether they are synced to Consul."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *ListCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		fmt.Sprintf("-%s", flagNameNamespace):   complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameKind):        complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameOutput):      complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameKubeConfig):  complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", flagNameKubeContext): complete.PredictNothing,
+	}
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// Since argument completion is not supported, this will return
+// complete.PredictNothing.
+func (c *ListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+// validateFlags ensures that the flags passed in by the user can be used.
+func (c *ListCommand) validateFlags() error {
+	if len(c.set.Args()) > 0 {
+		return errors.New("should have no non-flag arguments")
+	}
+	if errs := validation.ValidateNamespaceName(c.flagNamespace, false); c.flagNamespace != "" && len(errs) > 0 {
+		return fmt.Errorf("invalid namespace name passed for -namespace/-n: %v", strings.Join(errs, "; "))
+	}
+	if c.flagKind != "" {
+		kind, ok := common.ConfigEntryKind(c.flagKind)
+		if !ok {
+			return fmt.Errorf("-kind must be one of %s", strings.Join(common.ConfigEntryKinds(), ", "))
+		}
+		c.flagKind = kind
+	}
+	if c.flagOutput != Table && c.flagOutput != JSON {
+		return fmt.Errorf("-output must be one of %s, %s", Table, JSON)
+	}
+	return nil
+}
+
+// initKubernetes initializes the Kubernetes client.
+func (c *ListCommand) initKubernetes() error {
+	settings := helmCLI.New()
+
+	if c.flagKubeConfig != "" {
+		settings.KubeConfig = c.flagKubeConfig
+	}
+
+	if c.flagKubeContext != "" {
+		settings.KubeContext = c.flagKubeContext
+	}
+
+	restConfig, err := settings.RESTClientGetter().ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("error retrieving Kubernetes authentication %v", err)
+	}
+	if c.dynamic, err = dynamic.NewForConfig(restConfig); err != nil {
+		return fmt.Errorf("error creating Kubernetes client %v", err)
+	}
+
+	return nil
+}
+
+// fetchConfigEntries lists the config entries of each kind, sorted by kind,
+// namespace and name. Kinds which cannot be listed, e.g. because their custom
+// resource definition is not installed, are reported and skipped.
+func (c *ListCommand) fetchConfigEntries() ([]configEntry, error) {
+	kinds := common.ConfigEntryKinds()
+	if c.flagKind != "" {
+		kinds = []string{c.flagKind}
+	}
+
+	var entries []configEntry
+	var failed int
+	for _, kind := range kinds {
+		list, err := c.dynamic.Resource(common.ConfigEntryResource(kind)).Namespace(c.flagNamespace).List(c.Ctx, metav1.ListOptions{})
+		if err != nil {
+			c.UI.Output(fmt.Sprintf("Unable to list %s: %v", kind, err), terminal.WithWarningStyle())
+			failed++
+			continue
+		}
+
+		for _, item := range list.Items {
+			entries = append(entries, newConfigEntry(item))
+		}
+	}
+	if failed == len(kinds) {
+		return nil, errors.New("unable to list any config entries, are the Consul custom resource definitions installed?")
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		if entries[i].Namespace != entries[j].Namespace {
+			return entries[i].Namespace < entries[j].Namespace
+		}
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries, nil
+}
+
+// newConfigEntry reads the Synced condition and the last synced time from the
+// status of a config entry custom resource. Config entries which have not
+// been reconciled yet have no Synced condition and are reported as Unknown.
+func newConfigEntry(item unstructured.Unstructured) configEntry {
+	entry := configEntry{
+		Kind:      item.GetKind(),
+		Namespace: item.GetNamespace(),
+		Name:      item.GetName(),
+		Synced:    string(corev1.ConditionUnknown),
+	}
+
+	entry.LastSyncedTime, _, _ = unstructured.NestedString(item.Object, "status", "lastSyncedTime")
+
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != conditionSynced {
+			continue
+		}
+		if status, ok := condition["status"].(string); ok {
+			entry.Synced = status
+		}
+		entry.Reason, _ = condition["reason"].(string)
+		entry.Message, _ = condition["message"].(string)
+	}
+
+	return entry
+}
+
+// outputTable prints a table of the config entries to the terminal. Config
+// entries which failed to sync are highlighted.
+func (c *ListCommand) outputTable(entries []configEntry) {
+	if len(entries) == 0 {
+		if c.flagNamespace == "" {
+			c.UI.Output("No config entries found across all namespaces.")
+		} else {
+			c.UI.Output(fmt.Sprintf("No config entries found in %s namespace.", c.flagNamespace))
+		}
+		return
+	}
+
+	tbl := terminal.NewTable("Kind", "Namespace", "Name", "Synced", "Last Synced", "Message")
+	for _, entry := range entries {
+		var color string
+		switch entry.Synced {
+		case string(corev1.ConditionFalse):
+			color = terminal.Red
+		case string(corev1.ConditionUnknown):
+			color = terminal.Yellow
+		}
+
+		message := entry.Message
+		if entry.Reason != "" && message != "" {
+			message = fmt.Sprintf("%s: %s", entry.Reason, message)
+		}
+
+		tbl.AddRow([]string{entry.Kind, entry.Namespace, entry.Name, entry.Synced, entry.LastSyncedTime, message},
+			[]string{color, color, color, color, color, color})
+	}
+
+	c.UI.Table(tbl)
+}
+
+// outputJSON prints the config entries as JSON to the terminal.
+func (c *ListCommand) outputJSON(entries []configEntry) error {
+	if entries == nil {
+		entries = []configEntry{}
+	}
+
+	out, err := json.MarshalIndent(entries, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	c.UI.Output(string(out))
+	return nil
+}
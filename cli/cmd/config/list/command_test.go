@@ -0,0 +1,267 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package list
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	cmnFlag "github.com/hashicorp/consul-k8s/cli/common/flag"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+)
+
+func TestFlagParsing(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+		out  int
+	}{
+		"No args": {
+			args: []string{},
+			out:  0,
+		},
+		"Nonexistent flag passed, -foo bar": {
+			args: []string{"-foo", "bar"},
+			out:  1,
+		},
+		"Invalid argument passed, -namespace YOLO": {
+			args: []string{"-namespace", "YOLO"},
+			out:  1,
+		},
+		"Kind by resource name, -kind serviceintentions": {
+			args: []string{"-kind", "serviceintentions"},
+			out:  0,
+		},
+		"Invalid kind, -kind Deployment": {
+			args: []string{"-kind", "Deployment"},
+			out:  1,
+		},
+		"Invalid output, -output yaml": {
+			args: []string{"-output", "yaml"},
+			out:  1,
+		},
+		"Non-flag argument": {
+			args: []string{"web"},
+			out:  1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := setupCommand(new(bytes.Buffer))
+			c.dynamic = newDynamicClient(t)
+			out := c.Run(tc.args)
+			require.Equal(t, tc.out, out)
+		})
+	}
+}
+
+func TestListCommandOutput(t *testing.T) {
+	objects := []*unstructured.Unstructured{
+		newConfigEntry("ServiceDefaults", "default", "web", map[string]interface{}{
+			"lastSyncedTime": "2023-06-01T10:00:00Z",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Synced", "status": "True"},
+			},
+		}),
+		newConfigEntry("ServiceIntentions", "apps", "api", map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"type":    "Synced",
+					"status":  "False",
+					"reason":  "ConsulAgentError",
+					"message": "config entry managed in different datacenter",
+				},
+			},
+		}),
+		newConfigEntry("ServiceRouter", "apps", "api", nil),
+	}
+
+	cases := map[string]struct {
+		args        []string
+		expected    []string
+		notExpected []string
+	}{
+		"all namespaces": {
+			args: []string{},
+			expected: []string{
+				"Kind", "Namespace", "Name", "Synced", "Last Synced", "Message",
+				"ServiceDefaults", "2023-06-01T10:00:00Z",
+				"ServiceIntentions", "ConsulAgentError: config entry managed in different datacenter",
+				"ServiceRouter", "Unknown",
+			},
+		},
+		"namespace": {
+			args:        []string{"-namespace", "apps"},
+			expected:    []string{"ServiceIntentions", "ServiceRouter"},
+			notExpected: []string{"ServiceDefaults"},
+		},
+		"kind": {
+			args:        []string{"-kind", "ServiceDefaults"},
+			expected:    []string{"ServiceDefaults", "web"},
+			notExpected: []string{"ServiceIntentions", "ServiceRouter"},
+		},
+		"none found": {
+			args:     []string{"-namespace", "other"},
+			expected: []string{"No config entries found in other namespace."},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			c := setupCommand(buf)
+			c.dynamic = newDynamicClient(t, objects...)
+
+			out := c.Run(tc.args)
+			require.Equal(t, 0, out)
+
+			actual := buf.String()
+			for _, expected := range tc.expected {
+				require.Contains(t, actual, expected)
+			}
+			for _, notExpected := range tc.notExpected {
+				require.NotContains(t, actual, notExpected)
+			}
+		})
+	}
+}
+
+func TestListCommandOutput_JSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	c.dynamic = newDynamicClient(t,
+		newConfigEntry("ServiceDefaults", "default", "web", map[string]interface{}{
+			"lastSyncedTime": "2023-06-01T10:00:00Z",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Synced", "status": "True"},
+			},
+		}),
+		newConfigEntry("Mesh", "consul", "mesh", nil),
+	)
+
+	out := c.Run([]string{"-output", "json"})
+	require.Equal(t, 0, out)
+
+	var actual []configEntry
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &actual))
+	require.Equal(t, []configEntry{
+		{Kind: "Mesh", Namespace: "consul", Name: "mesh", Synced: "Unknown"},
+		{Kind: "ServiceDefaults", Namespace: "default", Name: "web", Synced: "True", LastSyncedTime: "2023-06-01T10:00:00Z"},
+	}, actual)
+}
+
+func TestListCommand_ListError(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	client := newDynamicClient(t)
+	client.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("the server could not find the requested resource")
+	})
+	c.dynamic = client
+
+	out := c.Run([]string{})
+	require.Equal(t, 1, out)
+	require.Contains(t, buf.String(), "Unable to list ServiceDefaults: the server could not find the requested resource")
+	require.Contains(t, buf.String(), "are the Consul custom resource definitions installed?")
+}
+
+// newDynamicClient returns a fake dynamic client holding the config entries.
+// They are created with the resources of their kinds, since the fake client
+// otherwise guesses their plural names, e.g. servicedefaultses.
+func newDynamicClient(t *testing.T, entries ...*unstructured.Unstructured) *dynamicFake.FakeDynamicClient {
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for _, kind := range common.ConfigEntryKinds() {
+		listKinds[common.ConfigEntryResource(kind)] = kind + "List"
+	}
+	client := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	for _, entry := range entries {
+		require.NoError(t, client.Tracker().Create(common.ConfigEntryResource(entry.GetKind()), entry, entry.GetNamespace()))
+	}
+	return client
+}
+
+func newConfigEntry(kind, namespace, name string, status map[string]interface{}) *unstructured.Unstructured {
+	entry := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": common.ConfigEntryGroupVersion.String(),
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+	}}
+	if status != nil {
+		entry.Object["status"] = status
+	}
+	return entry
+}
+
+func setupCommand(buf io.Writer) *ListCommand {
+	// Log at a test level to standard out.
+	log := hclog.New(&hclog.LoggerOptions{
+		Name:   "test",
+		Level:  hclog.Debug,
+		Output: os.Stdout,
+	})
+
+	// Setup and initialize the command struct
+	command := &ListCommand{
+		BaseCommand: &common.BaseCommand{
+			Log: log,
+			UI:  terminal.NewUI(context.Background(), buf),
+		},
+	}
+	command.init()
+
+	return command
+}
+
+func TestTaskCreateCommand_AutocompleteFlags(t *testing.T) {
+	t.Parallel()
+	buf := new(bytes.Buffer)
+	cmd := setupCommand(buf)
+
+	predictor := cmd.AutocompleteFlags()
+
+	// Test that we get the expected number of predictions
+	args := complete.Args{Last: "-"}
+	res := predictor.Predict(args)
+
+	// Grab the list of flags from the Flag object
+	flags := make([]string, 0)
+	cmd.set.VisitSets(func(name string, set *cmnFlag.Set) {
+		set.VisitAll(func(flag *flag.Flag) {
+			flags = append(flags, fmt.Sprintf("-%s", flag.Name))
+		})
+	})
+
+	// Verify that there is a prediction for each flag associated with the command
+	assert.Equal(t, len(flags), len(res))
+	assert.ElementsMatch(t, flags, res, "flags and predictions didn't match, make sure to add "+
+		"new flags to the command AutoCompleteFlags function")
+}
+
+func TestTaskCreateCommand_AutocompleteArgs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := setupCommand(buf)
+	c := cmd.AutocompleteArgs()
+	assert.Equal(t, complete.PredictNothing, c)
+}
@@ -0,0 +1,381 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package write
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/posener/complete"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	helmCLI "helm.sh/helm/v3/pkg/cli"
+	"k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	"github.com/hashicorp/consul-k8s/cli/common/flag"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+	"github.com/hashicorp/consul-k8s/cli/helm"
+	apicommon "github.com/hashicorp/consul-k8s/control-plane/api/common"
+)
+
+const (
+	flagNameFile        = "file"
+	flagNameNamespace   = "namespace"
+	flagNameDryRun      = "dry-run"
+	flagNameKubeConfig  = "kubeconfig"
+	flagNameKubeContext = "context"
+)
+
+// WriteCommand is the command struct for the config write command.
+type WriteCommand struct {
+	*common.BaseCommand
+
+	helmActionsRunner helm.HelmActionsRunner
+
+	dynamic dynamic.Interface
+
+	set *flag.Sets
+
+	flagFile      string
+	flagNamespace string
+	flagDryRun    bool
+
+	flagKubeConfig  string
+	flagKubeContext string
+
+	// applyConfigEntry applies a config entry, or only validates it with dryRun.
+	applyConfigEntry func(context.Context, dynamic.Interface, *unstructured.Unstructured, bool) (string, error)
+
+	// stdin is read when the file is "-".
+	stdin io.Reader
+
+	once sync.Once
+	help string
+}
+
+// init sets up flags and help text for the command.
+func (c *WriteCommand) init() {
+	if c.applyConfigEntry == nil {
+		c.applyConfigEntry = common.ApplyConfigEntry
+	}
+	if c.stdin == nil {
+		c.stdin = os.Stdin
+	}
+
+	c.set = flag.NewSets()
+
+	f := c.set.NewSet("Command Options")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameFile,
+		Target:  &c.flagFile,
+		Usage:   "The YAML or JSON file holding the config entries to write, or '-' to read them from standard input. Multiple config entries can be separated by '---'.",
+		Aliases: []string{"f"},
+	})
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameNamespace,
+		Target:  &c.flagNamespace,
+		Usage:   "The namespace to write config entries to which do not set one. Defaults to the namespace of the current Kubernetes context.",
+		Aliases: []string{"n"},
+	})
+	f.BoolVar(&flag.BoolVar{
+		Name:    flagNameDryRun,
+		Target:  &c.flagDryRun,
+		Default: false,
+		Usage:   "Validate the config entries without writing them.",
+	})
+
+	f = c.set.NewSet("Global Options")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameKubeConfig,
+		Aliases: []string{"c"},
+		Target:  &c.flagKubeConfig,
+		Usage:   "Set the path to kubeconfig file.",
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameKubeContext,
+		Target: &c.flagKubeContext,
+		Usage:  "Set the Kubernetes context to use.",
+	})
+
+	c.help = c.set.Help()
+}
+
+// Run validates the config entries and writes them if they are all valid.
+func (c *WriteCommand) Run(args []string) int {
+	c.once.Do(c.init)
+	if c.helmActionsRunner == nil {
+		c.helmActionsRunner = &helm.ActionRunner{}
+	}
+	c.Log.ResetNamed("write")
+	defer common.CloseWithError(c.BaseCommand)
+
+	if err := c.set.Parse(args); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		c.UI.Output("\n" + c.Help())
+		return 1
+	}
+
+	if err := c.validateFlags(); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		c.UI.Output("\n" + c.Help())
+		return 1
+	}
+
+	entries, err := c.readConfigEntries()
+	if err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	settings := helmCLI.New()
+	if c.flagKubeConfig != "" {
+		settings.KubeConfig = c.flagKubeConfig
+	}
+	if c.flagKubeContext != "" {
+		settings.KubeContext = c.flagKubeContext
+	}
+
+	namespace := c.flagNamespace
+	if namespace == "" {
+		namespace = settings.Namespace()
+	}
+	if err := setNamespaces(entries, namespace, c.flagNamespace != ""); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.dynamic == nil {
+		if err := c.initKubernetes(settings); err != nil {
+			c.UI.Output(err.Error(), terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	consulMeta, err := c.consulMeta(settings)
+	if err != nil {
+		c.UI.Output(fmt.Sprintf("Error reading the Consul installation: %v", err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Validate every config entry before writing any of them so that a file is
+	// either written completely or not at all.
+	if !c.validateConfigEntries(entries, consulMeta) {
+		return 1
+	}
+	if c.flagDryRun {
+		c.UI.Output(fmt.Sprintf("%d config entries are valid.", len(entries)), terminal.WithSuccessStyle())
+		return 0
+	}
+
+	for _, entry := range entries {
+		result, err := c.applyConfigEntry(c.Ctx, c.dynamic, entry, false)
+		if err != nil {
+			c.UI.Output(fmt.Sprintf("Error writing %s: %v", describe(entry), err), terminal.WithErrorStyle())
+			return 1
+		}
+		c.UI.Output(fmt.Sprintf("%s %s", describe(entry), result), terminal.WithSuccessStyle())
+	}
+
+	return 0
+}
+
+// Help returns a description of the command and how it is used.
+func (c *WriteCommand) Help() string {
+	c.once.Do(c.init)
+	return fmt.Sprintf("%s\n\nUsage: consul-k8s config write -f <file> [flags]\n\n"+
+		"  Config entries are validated before any of them are written. They are\n"+
+		"  checked with the same validation as the admission webhooks of Consul,\n"+
+		"  using the namespace and partition settings of the Consul installation,\n"+
+		"  and then by the Kubernetes API server with a dry run.\n\n%s", c.Synopsis(), c.help)
+}
+
+// Synopsis returns a one-line command summary.
+func (c *WriteCommand) Synopsis() string {
+	return "Validate and write Consul config entry custom resources."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *WriteCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		fmt.Sprintf("-%s", flagNameFile):        complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", flagNameNamespace):   complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameDryRun):      complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameKubeConfig):  complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", flagNameKubeContext): complete.PredictNothing,
+	}
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// Since argument completion is not supported, this will return
+// complete.PredictNothing.
+func (c *WriteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+// validateFlags ensures that the flags passed in by the user can be used.
+func (c *WriteCommand) validateFlags() error {
+	if len(c.set.Args()) > 0 {
+		return errors.New("should have no non-flag arguments")
+	}
+	if c.flagFile == "" {
+		return errors.New("-file must be set")
+	}
+	if errs := validation.ValidateNamespaceName(c.flagNamespace, false); c.flagNamespace != "" && len(errs) > 0 {
+		return fmt.Errorf("invalid namespace name passed for -namespace/-n: %v", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// initKubernetes initializes the Kubernetes client.
+func (c *WriteCommand) initKubernetes(settings *helmCLI.EnvSettings) error {
+	restConfig, err := settings.RESTClientGetter().ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("error retrieving Kubernetes authentication %v", err)
+	}
+	if c.dynamic, err = dynamic.NewForConfig(restConfig); err != nil {
+		return fmt.Errorf("error creating Kubernetes client %v", err)
+	}
+
+	return nil
+}
+
+// readConfigEntries reads the config entries from the file or standard input.
+func (c *WriteCommand) readConfigEntries() ([]*unstructured.Unstructured, error) {
+	var (
+		raw []byte
+		err error
+	)
+	if c.flagFile == "-" {
+		raw, err = io.ReadAll(c.stdin)
+	} else {
+		raw, err = os.ReadFile(c.flagFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading config entries: %v", err)
+	}
+
+	entries, err := common.ParseConfigEntries(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config entries: %v", err)
+	}
+	return entries, nil
+}
+
+// consulMeta returns the settings of the Consul installation that config
+// entries are validated with. They are read from the values of the Consul Helm
+// release, or are those of Consul without namespaces and partitions if there is
+// no release.
+func (c *WriteCommand) consulMeta(settings *helmCLI.EnvSettings) (apicommon.ConsulMeta, error) {
+	uiLogger := func(s string, args ...interface{}) {
+		c.Log.Debug(fmt.Sprintf(s, args...))
+	}
+
+	found, releaseName, namespace, err := c.helmActionsRunner.CheckForInstallations(&helm.CheckForInstallationsOptions{
+		Settings:              settings,
+		ReleaseName:           common.DefaultReleaseName,
+		DebugLog:              uiLogger,
+		SkipErrorWhenNotFound: true,
+	})
+	if err != nil || !found {
+		return apicommon.ConsulMeta{}, err
+	}
+
+	cfg, err := helm.InitActionConfig(new(action.Configuration), namespace, settings, uiLogger)
+	if err != nil {
+		return apicommon.ConsulMeta{}, err
+	}
+	release, err := c.helmActionsRunner.GetStatus(action.NewStatus(cfg), releaseName)
+	if err != nil {
+		return apicommon.ConsulMeta{}, err
+	}
+	values, err := chartutil.CoalesceValues(release.Chart, release.Config)
+	if err != nil {
+		return apicommon.ConsulMeta{}, err
+	}
+
+	return consulMetaFromValues(values), nil
+}
+
+// consulMetaFromValues returns the settings that the Helm chart passes to the
+// admission webhooks of Consul which validate config entries.
+func consulMetaFromValues(values chartutil.Values) apicommon.ConsulMeta {
+	boolValue := func(path string) bool {
+		value, _ := values.PathValue(path)
+		b, _ := value.(bool)
+		return b
+	}
+	stringValue := func(path string) string {
+		value, _ := values.PathValue(path)
+		s, _ := value.(string)
+		return s
+	}
+
+	var consulMeta apicommon.ConsulMeta
+	if boolValue("global.adminPartitions.enabled") {
+		consulMeta.PartitionsEnabled = true
+		consulMeta.Partition = stringValue("global.adminPartitions.name")
+	}
+	if boolValue("global.enableConsulNamespaces") {
+		consulMeta.NamespacesEnabled = true
+		consulMeta.DestinationNamespace = stringValue("connectInject.consulNamespaces.consulDestinationNamespace")
+		if boolValue("connectInject.consulNamespaces.mirroringK8S") {
+			consulMeta.Mirroring = true
+			consulMeta.Prefix = stringValue("connectInject.consulNamespaces.mirroringK8SPrefix")
+		}
+	}
+	return consulMeta
+}
+
+// validateConfigEntries validates each config entry, first with the validation
+// of the admission webhooks of Consul and then with a dry run, and reports all
+// invalid config entries. It returns whether all of them are valid.
+func (c *WriteCommand) validateConfigEntries(entries []*unstructured.Unstructured, consulMeta apicommon.ConsulMeta) bool {
+	valid := true
+	for _, entry := range entries {
+		if err := common.ValidateConfigEntry(entry, consulMeta); err != nil {
+			c.UI.Output(fmt.Sprintf("%s is invalid: %v", describe(entry), err), terminal.WithErrorStyle())
+			valid = false
+			continue
+		}
+		if _, err := c.applyConfigEntry(c.Ctx, c.dynamic, entry, true); err != nil {
+			c.UI.Output(fmt.Sprintf("%s is invalid: %v", describe(entry), err), terminal.WithErrorStyle())
+			valid = false
+		}
+	}
+
+	if !valid {
+		c.UI.Output("No config entries were written.", terminal.WithErrorStyle())
+	}
+	return valid
+}
+
+// setNamespaces sets the namespace of config entries which do not set one. If
+// the namespace was passed explicitly, config entries must not set a different
+// one.
+func setNamespaces(entries []*unstructured.Unstructured, namespace string, explicit bool) error {
+	for _, entry := range entries {
+		if entry.GetNamespace() == "" {
+			entry.SetNamespace(namespace)
+			continue
+		}
+		if explicit && entry.GetNamespace() != namespace {
+			return fmt.Errorf("the namespace of %s %q does not match -namespace %q", entry.GetKind(), entry.GetName(), namespace)
+		}
+	}
+	return nil
+}
+
+func describe(entry *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s %s/%s", entry.GetKind(), entry.GetNamespace(), entry.GetName())
+}
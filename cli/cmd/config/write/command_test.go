@@ -0,0 +1,398 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package write
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	helmRelease "helm.sh/helm/v3/pkg/release"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	cmnFlag "github.com/hashicorp/consul-k8s/cli/common/flag"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+	"github.com/hashicorp/consul-k8s/cli/helm"
+	apicommon "github.com/hashicorp/consul-k8s/control-plane/api/common"
+)
+
+const configEntries = `
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceDefaults
+metadata:
+  name: web
+spec:
+  protocol: http
+---
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceIntentions
+metadata:
+  name: web
+  namespace: apps
+spec:
+  destination:
+    name: web
+  sources:
+  - name: api
+    action: allow
+`
+
+func TestFlagParsing(t *testing.T) {
+	file := writeFile(t, configEntries)
+
+	cases := map[string]struct {
+		args []string
+		out  int
+	}{
+		"No args": {
+			args: []string{},
+			out:  1,
+		},
+		"Nonexistent flag passed, -foo bar": {
+			args: []string{"-f", file, "-foo", "bar"},
+			out:  1,
+		},
+		"Invalid argument passed, -namespace YOLO": {
+			args: []string{"-f", file, "-namespace", "YOLO"},
+			out:  1,
+		},
+		"Non-flag argument": {
+			args: []string{"-f", file, "web"},
+			out:  1,
+		},
+		"Missing file": {
+			args: []string{"-f", filepath.Join(t.TempDir(), "missing.yaml")},
+			out:  1,
+		},
+		"Namespace conflicts with the file": {
+			args: []string{"-f", file, "-namespace", "default"},
+			out:  1,
+		},
+		"File": {
+			args: []string{"-f", file, "-namespace", "apps"},
+			out:  0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := setupCommand(new(bytes.Buffer))
+			c.dynamic = dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+			skipDryRun(c)
+			out := c.Run(tc.args)
+			require.Equal(t, tc.out, out)
+		})
+	}
+}
+
+func TestWriteCommand(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+	c.dynamic = client
+	skipDryRun(c)
+
+	out := c.Run([]string{"-f", writeFile(t, configEntries), "-namespace", "apps"})
+	require.Equal(t, 0, out)
+	require.Contains(t, buf.String(), "ServiceDefaults apps/web created")
+	require.Contains(t, buf.String(), "ServiceIntentions apps/web created")
+
+	_, err := client.Resource(common.ConfigEntryResource("ServiceDefaults")).Namespace("apps").Get(context.Background(), "web", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	buf.Reset()
+	out = c.Run([]string{"-f", writeFile(t, configEntries), "-namespace", "apps"})
+	require.Equal(t, 0, out)
+	require.Contains(t, buf.String(), "ServiceDefaults apps/web configured")
+}
+
+func TestWriteCommand_Stdin(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	c.dynamic = dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+	c.stdin = strings.NewReader(configEntries)
+	skipDryRun(c)
+
+	out := c.Run([]string{"-f", "-"})
+	require.Equal(t, 0, out)
+	// The ServiceDefaults are written to the namespace of the current context.
+	require.Regexp(t, "ServiceDefaults [a-z0-9-]+/web created", buf.String())
+	require.Contains(t, buf.String(), "ServiceIntentions apps/web created")
+}
+
+func TestWriteCommand_Invalid(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+	c.dynamic = client
+	skipDryRun(c)
+
+	out := c.Run([]string{"-f", writeFile(t, `
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceDefaults
+metadata:
+  name: web
+  namespace: apps
+spec:
+  protocol: http
+---
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceIntentions
+metadata:
+  name: web
+  namespace: apps
+spec:
+  destination:
+    name: web
+---
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceResolver
+metadata:
+  name: web
+  namespace: apps
+spec:
+  connectTimeout: 5
+`)})
+	require.Equal(t, 1, out)
+	require.Contains(t, buf.String(), `ServiceIntentions apps/web is invalid: serviceintentions.consul.hashicorp.com "web" is invalid: spec.sources: Required value: at least one source must be specified`)
+	require.Contains(t, buf.String(), "ServiceResolver apps/web is invalid:")
+	require.NotContains(t, buf.String(), "ServiceDefaults apps/web is invalid")
+	require.Contains(t, buf.String(), "No config entries were written.")
+
+	_, err := client.Resource(common.ConfigEntryResource("ServiceDefaults")).Namespace("apps").Get(context.Background(), "web", metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err))
+}
+
+func TestWriteCommand_ConsulNamespaces(t *testing.T) {
+	entry := `
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceIntentions
+metadata:
+  name: web
+  namespace: apps
+spec:
+  destination:
+    name: web
+    namespace: apps
+  sources:
+  - name: api
+    action: allow
+`
+
+	// Without an installation that enables namespaces, Consul namespaces cannot be set.
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	c.dynamic = dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+	skipDryRun(c)
+
+	out := c.Run([]string{"-f", writeFile(t, entry)})
+	require.Equal(t, 1, out)
+	require.Contains(t, buf.String(), "Consul Enterprise namespaces must be enabled to set destination.namespace")
+
+	buf = new(bytes.Buffer)
+	c = setupCommand(buf)
+	c.dynamic = dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+	c.helmActionsRunner = &helm.MockActionRunner{
+		CheckForInstallationsFunc: func(options *helm.CheckForInstallationsOptions) (bool, string, string, error) {
+			return true, "consul", "consul", nil
+		},
+		GetStatusFunc: func(status *action.Status, name string) (*helmRelease.Release, error) {
+			return &helmRelease.Release{
+				Name:      "consul",
+				Namespace: "consul",
+				Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "consul"}},
+				Config: map[string]interface{}{
+					"global": map[string]interface{}{"enableConsulNamespaces": true},
+				},
+			}, nil
+		},
+	}
+	skipDryRun(c)
+
+	out = c.Run([]string{"-f", writeFile(t, entry)})
+	require.Equal(t, 0, out, buf.String())
+	require.Contains(t, buf.String(), "ServiceIntentions apps/web created")
+}
+
+func TestConsulMetaFromValues(t *testing.T) {
+	cases := map[string]struct {
+		values   map[string]interface{}
+		expected apicommon.ConsulMeta
+	}{
+		"defaults": {
+			values: map[string]interface{}{
+				"global": map[string]interface{}{
+					"adminPartitions": map[string]interface{}{"enabled": false, "name": "default"},
+				},
+				"connectInject": map[string]interface{}{
+					"consulNamespaces": map[string]interface{}{"consulDestinationNamespace": "default"},
+				},
+			},
+			expected: apicommon.ConsulMeta{},
+		},
+		"partitions": {
+			values: map[string]interface{}{
+				"global": map[string]interface{}{
+					"adminPartitions": map[string]interface{}{"enabled": true, "name": "team"},
+				},
+			},
+			expected: apicommon.ConsulMeta{PartitionsEnabled: true, Partition: "team"},
+		},
+		"namespaces": {
+			values: map[string]interface{}{
+				"global": map[string]interface{}{"enableConsulNamespaces": true},
+				"connectInject": map[string]interface{}{
+					"consulNamespaces": map[string]interface{}{"consulDestinationNamespace": "apps", "mirroringK8SPrefix": "k8s-"},
+				},
+			},
+			expected: apicommon.ConsulMeta{NamespacesEnabled: true, DestinationNamespace: "apps"},
+		},
+		"namespace mirroring": {
+			values: map[string]interface{}{
+				"global": map[string]interface{}{"enableConsulNamespaces": true},
+				"connectInject": map[string]interface{}{
+					"consulNamespaces": map[string]interface{}{"consulDestinationNamespace": "default", "mirroringK8S": true, "mirroringK8SPrefix": "k8s-"},
+				},
+			},
+			expected: apicommon.ConsulMeta{NamespacesEnabled: true, DestinationNamespace: "default", Mirroring: true, Prefix: "k8s-"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, consulMetaFromValues(tc.values))
+		})
+	}
+}
+
+func TestWriteCommand_Rejected(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	c.dynamic = dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+
+	var applied []string
+	c.applyConfigEntry = func(_ context.Context, _ dynamic.Interface, entry *unstructured.Unstructured, dryRun bool) (string, error) {
+		if entry.GetKind() == "ServiceIntentions" {
+			return "", errors.New(`admission webhook "mutate-serviceintentions.consul.hashicorp.com" denied the request: serviceintentions resource with name "web" is already defined`)
+		}
+		if !dryRun {
+			applied = append(applied, entry.GetKind())
+		}
+		return "created", nil
+	}
+
+	out := c.Run([]string{"-f", writeFile(t, configEntries)})
+	require.Equal(t, 1, out)
+	require.Contains(t, buf.String(), "ServiceIntentions apps/web is invalid: admission webhook")
+	require.Contains(t, buf.String(), "No config entries were written.")
+	require.Empty(t, applied)
+}
+
+func TestWriteCommand_DryRun(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	c.dynamic = dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+
+	var dryRuns int
+	c.applyConfigEntry = func(_ context.Context, _ dynamic.Interface, _ *unstructured.Unstructured, dryRun bool) (string, error) {
+		require.True(t, dryRun)
+		dryRuns++
+		return "created", nil
+	}
+
+	out := c.Run([]string{"-f", writeFile(t, configEntries), "-dry-run"})
+	require.Equal(t, 0, out)
+	require.Equal(t, 2, dryRuns)
+	require.Contains(t, buf.String(), "2 config entries are valid.")
+}
+
+// skipDryRun makes the dry run always succeed, since the fake client ignores
+// the dry run option and would write the config entries.
+func skipDryRun(c *WriteCommand) {
+	c.applyConfigEntry = func(ctx context.Context, client dynamic.Interface, entry *unstructured.Unstructured, dryRun bool) (string, error) {
+		if dryRun {
+			return "", nil
+		}
+		return common.ApplyConfigEntry(ctx, client, entry, false)
+	}
+}
+
+func writeFile(t *testing.T, contents string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config-entries.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(contents), 0600))
+	return file
+}
+
+func setupCommand(buf io.Writer) *WriteCommand {
+	// Log at a test level to standard out.
+	log := hclog.New(&hclog.LoggerOptions{
+		Name:   "test",
+		Level:  hclog.Debug,
+		Output: os.Stdout,
+	})
+
+	// Setup and initialize the command struct
+	command := &WriteCommand{
+		BaseCommand: &common.BaseCommand{
+			Log: log,
+			UI:  terminal.NewUI(context.Background(), buf),
+		},
+		helmActionsRunner: &helm.MockActionRunner{},
+	}
+	command.init()
+
+	return command
+}
+
+func TestTaskCreateCommand_AutocompleteFlags(t *testing.T) {
+	t.Parallel()
+	buf := new(bytes.Buffer)
+	cmd := setupCommand(buf)
+
+	predictor := cmd.AutocompleteFlags()
+
+	// Test that we get the expected number of predictions
+	args := complete.Args{Last: "-"}
+	res := predictor.Predict(args)
+
+	// Grab the list of flags from the Flag object
+	flags := make([]string, 0)
+	cmd.set.VisitSets(func(name string, set *cmnFlag.Set) {
+		set.VisitAll(func(flag *flag.Flag) {
+			flags = append(flags, fmt.Sprintf("-%s", flag.Name))
+		})
+	})
+
+	// Verify that there is a prediction for each flag associated with the command
+	assert.Equal(t, len(flags), len(res))
+	assert.ElementsMatch(t, flags, res, "flags and predictions didn't match, make sure to add "+
+		"new flags to the command AutoCompleteFlags function")
+}
+
+func TestTaskCreateCommand_AutocompleteArgs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := setupCommand(buf)
+	c := cmd.AutocompleteArgs()
+	assert.Equal(t, complete.PredictNothing, c)
+}
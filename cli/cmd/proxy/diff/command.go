@@ -0,0 +1,452 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/posener/complete"
+	helmCLI "helm.sh/helm/v3/pkg/cli"
+	"k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/strings/slices"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	"github.com/hashicorp/consul-k8s/cli/common/envoy"
+	"github.com/hashicorp/consul-k8s/cli/common/flag"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+)
+
+const (
+	Table = "table"
+	JSON  = "json"
+
+	flagNameNamespace   = "namespace"
+	flagNameSnapshot    = "snapshot"
+	flagNameOutput      = "output"
+	flagNameKubeConfig  = "kubeconfig"
+	flagNameKubeContext = "context"
+)
+
+type DiffCommand struct {
+	*common.BaseCommand
+
+	kubernetes kubernetes.Interface
+
+	set *flag.Sets
+
+	// Command Flags
+	flagNamespace string
+	flagPodNames  []string
+	flagSnapshot  string
+	flagOutput    string
+
+	// Global Flags
+	flagKubeConfig  string
+	flagKubeContext string
+
+	fetchConfig func(context.Context, common.PortForwarder) (*envoy.EnvoyConfig, error)
+
+	restConfig *rest.Config
+
+	once sync.Once
+	help string
+}
+
+func (c *DiffCommand) init() {
+	if c.fetchConfig == nil {
+		c.fetchConfig = envoy.FetchConfig
+	}
+
+	c.set = flag.NewSets()
+	f := c.set.NewSet("Command Options")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameNamespace,
+		Target:  &c.flagNamespace,
+		Usage:   "The namespace where the target Pods can be found.",
+		Aliases: []string{"n"},
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameSnapshot,
+		Target: &c.flagSnapshot,
+		Usage:  "Compare the Pod against a snapshot of its Envoy configuration saved with `consul-k8s proxy read <pod-name> -output json`.",
+	})
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameOutput,
+		Target:  &c.flagOutput,
+		Usage:   "Output the difference as 'table' or 'json'.",
+		Default: Table,
+		Aliases: []string{"o"},
+	})
+
+	f = c.set.NewSet("GlobalOptions")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameKubeConfig,
+		Aliases: []string{"c"},
+		Target:  &c.flagKubeConfig,
+		Usage:   "Set the path to kubeconfig file.",
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameKubeContext,
+		Target: &c.flagKubeContext,
+		Usage:  "Set the Kubernetes context to use.",
+	})
+
+	c.help = c.set.Help()
+}
+
+func (c *DiffCommand) Run(args []string) int {
+	c.once.Do(c.init)
+	c.Log.ResetNamed("diff")
+	defer common.CloseWithError(c.BaseCommand)
+
+	if err := c.parseFlags(args); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		c.UI.Output("\n" + c.Help())
+		return 1
+	}
+
+	if err := c.validateFlags(); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		c.UI.Output("\n" + c.Help())
+		return 1
+	}
+
+	if err := c.initKubernetes(); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	from, err := c.fetchConfigs(c.flagPodNames[0])
+	if err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	var to map[string]proxyConfig
+	if c.flagSnapshot != "" {
+		to, err = readSnapshot(c.flagSnapshot)
+	} else {
+		to, err = c.fetchConfigs(c.flagPodNames[1])
+	}
+	if err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	diffs, err := diffProxies(from, to)
+	if err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if err := c.outputDiffs(diffs); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	return 0
+}
+
+func (c *DiffCommand) Help() string {
+	c.once.Do(c.init)
+	return fmt.Sprintf("%s\n\nUsage: consul-k8s proxy diff <pod-name> <other-pod-name> [flags]\n       consul-k8s proxy diff <pod-name> -snapshot <file> [flags]\n\n%s", c.Synopsis(), c.help)
+}
+
+func (c *DiffCommand) Synopsis() string {
+	return "Compare the Envoy configuration of two Pods."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *DiffCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		fmt.Sprintf("-%s", flagNameNamespace):   complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameSnapshot):    complete.PredictFiles("*.json"),
+		fmt.Sprintf("-%s", flagNameOutput):      complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameKubeConfig):  complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", flagNameKubeContext): complete.PredictNothing,
+	}
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// Since argument completion is not supported, this will return
+// complete.PredictNothing.
+func (c *DiffCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *DiffCommand) parseFlags(args []string) error {
+	// Separate positional arguments from keyed arguments.
+	positional := []string{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		positional = append(positional, arg)
+	}
+	keyed := args[len(positional):]
+
+	if err := c.set.Parse(keyed); err != nil {
+		return err
+	}
+
+	if c.flagSnapshot != "" && len(positional) != 1 {
+		return fmt.Errorf("Exactly one positional argument is required with -snapshot: <pod-name>")
+	}
+	if c.flagSnapshot == "" && len(positional) != 2 {
+		return fmt.Errorf("Exactly two positional arguments are required: <pod-name> <other-pod-name>")
+	}
+	c.flagPodNames = positional
+
+	return nil
+}
+
+func (c *DiffCommand) validateFlags() error {
+	if errs := validation.ValidateNamespaceName(c.flagNamespace, false); c.flagNamespace != "" && len(errs) > 0 {
+		return fmt.Errorf("invalid namespace name passed for -namespace/-n: %v", strings.Join(errs, "; "))
+	}
+	if outputs := []string{Table, JSON}; !slices.Contains(outputs, c.flagOutput) {
+		return fmt.Errorf("-output must be one of %s.", strings.Join(outputs, ", "))
+	}
+	return nil
+}
+
+func (c *DiffCommand) initKubernetes() (err error) {
+	settings := helmCLI.New()
+
+	if c.flagKubeConfig != "" {
+		settings.KubeConfig = c.flagKubeConfig
+	}
+
+	if c.flagKubeContext != "" {
+		settings.KubeContext = c.flagKubeContext
+	}
+
+	if c.restConfig == nil {
+		if c.restConfig, err = settings.RESTClientGetter().ToRESTConfig(); err != nil {
+			return fmt.Errorf("error creating Kubernetes REST config %v", err)
+		}
+	}
+
+	if c.kubernetes == nil {
+		if c.kubernetes, err = kubernetes.NewForConfig(c.restConfig); err != nil {
+			return fmt.Errorf("error creating Kubernetes client %v", err)
+		}
+	}
+
+	if c.flagNamespace == "" {
+		c.flagNamespace = settings.Namespace()
+	}
+
+	return nil
+}
+
+// fetchConfigs fetches the configuration of each Envoy proxy in the Pod.
+func (c *DiffCommand) fetchConfigs(podName string) (map[string]proxyConfig, error) {
+	pod, err := c.kubernetes.CoreV1().Pods(c.flagNamespace).Get(c.Ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make(map[string]proxyConfig)
+	for name, adminPort := range common.EnvoyAdminPorts(*pod) {
+		pf := common.PortForward{
+			Namespace:  c.flagNamespace,
+			PodName:    podName,
+			RemotePort: adminPort,
+			KubeClient: c.kubernetes,
+			RestConfig: c.restConfig,
+		}
+
+		config, err := c.fetchConfig(c.Ctx, &pf)
+		if err != nil {
+			return nil, err
+		}
+
+		configs[name] = newProxyConfig(config)
+	}
+
+	return configs, nil
+}
+
+// readSnapshot reads the Envoy configuration saved with `proxy read -o json`.
+// Proxies whose configuration could not be read are saved as an `error` entry
+// and sections filtered out when saving are missing. Both are reported as errors
+// rather than being compared as empty configuration.
+func readSnapshot(path string) (map[string]proxyConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot: %v", err)
+	}
+
+	proxies := make(map[string]map[string]json.RawMessage)
+	if err := json.Unmarshal(raw, &proxies); err != nil {
+		return nil, fmt.Errorf("error parsing snapshot %s: %v", path, err)
+	}
+	if len(proxies) == 0 {
+		return nil, fmt.Errorf("snapshot %s does not contain any proxies", path)
+	}
+
+	for name, sections := range proxies {
+		if msg, ok := sections["error"]; ok {
+			var reason string
+			if err := json.Unmarshal(msg, &reason); err != nil {
+				reason = string(msg)
+			}
+			return nil, fmt.Errorf("snapshot %s contains an error for proxy %s: %s", path, name, reason)
+		}
+
+		for _, section := range snapshotSections {
+			if _, ok := sections[section]; !ok {
+				return nil, fmt.Errorf("snapshot %s does not contain the %s of proxy %s", path, section, name)
+			}
+		}
+	}
+
+	snapshot := make(map[string]proxyConfig, len(proxies))
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return nil, fmt.Errorf("error parsing snapshot %s: %v", path, err)
+	}
+
+	return snapshot, nil
+}
+
+// diffProxies pairs up the proxies of both sides and compares their configuration.
+// Pods with a single proxy name it after the Pod, so if both sides have a single
+// proxy they are compared regardless of their names. Otherwise, proxies are
+// paired by the name of the service they run for.
+func diffProxies(from, to map[string]proxyConfig) (map[string]configDiff, error) {
+	diffs := make(map[string]configDiff)
+
+	if len(from) == 1 && len(to) == 1 {
+		for fromName, fromConfig := range from {
+			for toName, toConfig := range to {
+				d, err := diffConfigs(fromConfig, toConfig)
+				if err != nil {
+					return nil, err
+				}
+
+				name := fromName
+				if fromName != toName {
+					name = fmt.Sprintf("%s -> %s", fromName, toName)
+				}
+				diffs[name] = d
+			}
+		}
+		return diffs, nil
+	}
+
+	for name, fromConfig := range from {
+		toConfig, ok := to[name]
+		if !ok {
+			return nil, fmt.Errorf("proxy %s was not found in the configuration to compare against", name)
+		}
+
+		d, err := diffConfigs(fromConfig, toConfig)
+		if err != nil {
+			return nil, err
+		}
+		diffs[name] = d
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			return nil, fmt.Errorf("proxy %s was not found in the configuration being compared", name)
+		}
+	}
+
+	return diffs, nil
+}
+
+func (c *DiffCommand) outputDiffs(diffs map[string]configDiff) error {
+	switch c.flagOutput {
+	case Table:
+		return c.outputTables(diffs)
+	case JSON:
+		return c.outputJSON(diffs)
+	}
+
+	return nil
+}
+
+func (c *DiffCommand) outputTables(diffs map[string]configDiff) error {
+	names := make([]string, 0, len(diffs))
+	for name := range diffs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		d := diffs[name]
+		c.UI.Output(fmt.Sprintf("Envoy configuration difference for %s in namespace %s:", name, c.flagNamespace))
+
+		if d.Empty() {
+			c.UI.Output("No differences found.", terminal.WithSuccessStyle())
+			c.UI.Output("")
+			continue
+		}
+
+		for _, section := range []struct {
+			title string
+			diff  sectionDiff
+		}{
+			{"Clusters", d.Clusters},
+			{"Listeners", d.Listeners},
+			{"Routes", d.Routes},
+			{"Secrets", d.Secrets},
+		} {
+			if err := c.outputSection(section.title, section.diff); err != nil {
+				return err
+			}
+		}
+		c.UI.Output("")
+	}
+
+	return nil
+}
+
+func (c *DiffCommand) outputSection(title string, d sectionDiff) error {
+	c.UI.Output(fmt.Sprintf("%s (%d added, %d removed, %d changed)", title, len(d.Added), len(d.Removed), len(d.Changed)),
+		terminal.WithHeaderStyle())
+	if d.Empty() {
+		return nil
+	}
+
+	diff, err := common.Diff(d.From, d.To)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		if strings.HasPrefix(line, "+") {
+			c.UI.Output(line, terminal.WithDiffAddedStyle())
+		} else if strings.HasPrefix(line, "-") {
+			c.UI.Output(line, terminal.WithDiffRemovedStyle())
+		} else {
+			c.UI.Output(line, terminal.WithDiffUnchangedStyle())
+		}
+	}
+
+	return nil
+}
+
+func (c *DiffCommand) outputJSON(diffs map[string]configDiff) error {
+	out, err := json.MarshalIndent(diffs, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	c.UI.Output(string(out))
+
+	return nil
+}
@@ -0,0 +1,290 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package diff
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	"github.com/hashicorp/consul-k8s/cli/common/envoy"
+	cmnFlag "github.com/hashicorp/consul-k8s/cli/common/flag"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestFlagParsing(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+		out  int
+	}{
+		"No args": {
+			args: []string{},
+			out:  1,
+		},
+		"Single podname passed": {
+			args: []string{"podname"},
+			out:  1,
+		},
+		"Two podnames passed with a snapshot": {
+			args: []string{"podname", "podname2", "-snapshot", "snapshot.json"},
+			out:  1,
+		},
+		"Nonexistent flag passed, -foo bar": {
+			args: []string{"podname", "podname2", "-foo", "bar"},
+			out:  1,
+		},
+		"Invalid argument passed, -namespace YOLO": {
+			args: []string{"podname", "podname2", "-namespace", "YOLO"},
+			out:  1,
+		},
+		"User passed incorrect output": {
+			args: []string{"podname", "podname2", "-output", "image"},
+			out:  1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := setupCommand(new(bytes.Buffer))
+			c.kubernetes = fake.NewSimpleClientset()
+
+			out := c.Run(tc.args)
+			require.Equal(t, tc.out, out)
+		})
+	}
+}
+
+func TestDiffCommandOutput(t *testing.T) {
+	changed := *testEnvoyConfig
+	changed.Clusters = append([]envoy.Cluster{
+		{Name: "backend", FullyQualifiedDomainName: "backend.default.dc1.internal.consul", Endpoints: []string{"192.168.63.121:20000"}, Type: "EDS"},
+	}, testEnvoyConfig.Clusters[1:]...)
+
+	cases := map[string]struct {
+		args     []string
+		expected []string
+	}{
+		"table": {
+			args: []string{"web-1", "web-2"},
+			expected: []string{
+				"Envoy configuration difference for web-1 -> web-2 in namespace default:",
+				`Clusters \(1 added, 1 removed, 0 changed\)`,
+				`\+ *backend:`,
+				`- *local_app:`,
+				`Listeners \(0 added, 0 removed, 0 changed\)`,
+			},
+		},
+		"json": {
+			args: []string{"web-1", "web-2", "-output", "json"},
+			expected: []string{
+				`"added": \[\s*"backend"\s*\]`,
+				`"removed": \[\s*"local_app"\s*\]`,
+				`"from": \{\s*"local_app": \{`,
+				`"to": \{\s*"backend": \{`,
+			},
+		},
+		"identical": {
+			args: []string{"web-1", "web-1"},
+			expected: []string{
+				"No differences found.",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			c := setupCommand(buf)
+			c.kubernetes = fake.NewSimpleClientset(&v1.PodList{Items: []v1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "default"}},
+			}})
+			c.fetchConfig = func(_ context.Context, pf common.PortForwarder) (*envoy.EnvoyConfig, error) {
+				if pf.(*common.PortForward).PodName == "web-2" {
+					return &changed, nil
+				}
+				return testEnvoyConfig, nil
+			}
+
+			out := c.Run(tc.args)
+			require.Equal(t, 0, out)
+
+			actual := buf.String()
+			for _, expression := range tc.expected {
+				require.Regexp(t, expression, actual)
+			}
+		})
+	}
+}
+
+func TestDiffCommandOutput_Snapshot(t *testing.T) {
+	snapshot, err := json.Marshal(map[string]interface{}{
+		"web-1": map[string]interface{}{
+			"clusters":  testEnvoyConfig.Clusters,
+			"endpoints": testEnvoyConfig.Endpoints,
+			"listeners": testEnvoyConfig.Listeners,
+			"routes":    testEnvoyConfig.Routes,
+			"secrets":   []envoy.Secret{},
+		},
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, os.WriteFile(path, snapshot, 0600))
+
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	c.kubernetes = fake.NewSimpleClientset(&v1.PodList{Items: []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}},
+	}})
+	c.fetchConfig = func(context.Context, common.PortForwarder) (*envoy.EnvoyConfig, error) {
+		return testEnvoyConfig, nil
+	}
+
+	out := c.Run([]string{"web-1", "-snapshot", path})
+	require.Equal(t, 0, out)
+
+	actual := buf.String()
+	require.Regexp(t, `Clusters \(0 added, 0 removed, 0 changed\)`, actual)
+	require.Regexp(t, `Secrets \(0 added, 1 removed, 0 changed\)`, actual)
+	require.Regexp(t, `- *default:`, actual)
+}
+
+func TestDiffCommandOutput_InvalidSnapshot(t *testing.T) {
+	cases := map[string]struct {
+		snapshot map[string]interface{}
+		expected string
+	}{
+		"error entry": {
+			snapshot: map[string]interface{}{
+				"web-1": map[string]string{"error": "connection refused"},
+			},
+			expected: "contains an error for proxy web-1: connection refused",
+		},
+		"missing section": {
+			snapshot: map[string]interface{}{
+				"web-1": map[string]interface{}{
+					"clusters":  testEnvoyConfig.Clusters,
+					"listeners": testEnvoyConfig.Listeners,
+					"routes":    testEnvoyConfig.Routes,
+				},
+			},
+			expected: "does not contain the secrets of proxy web-1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			snapshot, err := json.Marshal(tc.snapshot)
+			require.NoError(t, err)
+
+			path := filepath.Join(t.TempDir(), "snapshot.json")
+			require.NoError(t, os.WriteFile(path, snapshot, 0600))
+
+			buf := new(bytes.Buffer)
+			c := setupCommand(buf)
+			c.kubernetes = fake.NewSimpleClientset(&v1.PodList{Items: []v1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}},
+			}})
+			c.fetchConfig = func(context.Context, common.PortForwarder) (*envoy.EnvoyConfig, error) {
+				return testEnvoyConfig, nil
+			}
+
+			out := c.Run([]string{"web-1", "-snapshot", path})
+			require.Equal(t, 1, out)
+			require.Contains(t, buf.String(), tc.expected)
+		})
+	}
+}
+
+func setupCommand(buf io.Writer) *DiffCommand {
+	// Log at a test level to standard out.
+	log := hclog.New(&hclog.LoggerOptions{
+		Name:   "test",
+		Level:  hclog.Debug,
+		Output: os.Stdout,
+	})
+
+	// Setup and initialize the command struct
+	command := &DiffCommand{
+		BaseCommand: &common.BaseCommand{
+			Log: log,
+			UI:  terminal.NewUI(context.Background(), buf),
+		},
+	}
+	command.init()
+
+	return command
+}
+
+func TestTaskCreateCommand_AutocompleteFlags(t *testing.T) {
+	t.Parallel()
+	buf := new(bytes.Buffer)
+	cmd := setupCommand(buf)
+
+	predictor := cmd.AutocompleteFlags()
+
+	// Test that we get the expected number of predictions
+	args := complete.Args{Last: "-"}
+	res := predictor.Predict(args)
+
+	// Grab the list of flags from the Flag object
+	flags := make([]string, 0)
+	cmd.set.VisitSets(func(name string, set *cmnFlag.Set) {
+		set.VisitAll(func(flag *flag.Flag) {
+			flags = append(flags, fmt.Sprintf("-%s", flag.Name))
+		})
+	})
+
+	// Verify that there is a prediction for each flag associated with the command
+	assert.Equal(t, len(flags), len(res))
+	assert.ElementsMatch(t, flags, res, "flags and predictions didn't match, make sure to add "+
+		"new flags to the command AutoCompleteFlags function")
+}
+
+func TestTaskCreateCommand_AutocompleteArgs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := setupCommand(buf)
+	c := cmd.AutocompleteArgs()
+	assert.Equal(t, complete.PredictNothing, c)
+}
+
+var testEnvoyConfig = &envoy.EnvoyConfig{
+	Clusters: []envoy.Cluster{
+		{Name: "local_app", FullyQualifiedDomainName: "local_app", Endpoints: []string{"127.0.0.1:8080"}, Type: "STATIC", LastUpdated: "2022-05-13T04:22:39.655Z"},
+
+		{Name: "client", FullyQualifiedDomainName: "client.default.dc1.internal.bc3815c2-1a0f-f3ff-a2e9-20d791f08d00.consul", Endpoints: []string{"192.168.18.110:20000", "192.168.52.101:20000", "192.168.65.131:20000"}, Type: "EDS", LastUpdated: "2022-08-10T12:30:32.326Z"},
+	},
+
+	Endpoints: []envoy.Endpoint{
+		{Address: "127.0.0.1:8080", Cluster: "local_app", Weight: 1, Status: "HEALTHY"},
+	},
+
+	Listeners: []envoy.Listener{
+		{Name: "public_listener", Address: "192.168.69.179:20000", FilterChain: []envoy.FilterChain{{Filters: []string{"HTTP: * -> local_app/"}, FilterChainMatch: "Any"}}, Direction: "INBOUND", LastUpdated: "2022-08-10T12:30:47.142Z"},
+	},
+
+	Routes: []envoy.Route{
+		{Name: "public_listener", DestinationCluster: "local_app/", LastUpdated: "2022-08-10T12:30:47.141Z"},
+	},
+
+	Secrets: []envoy.Secret{
+		{Name: "default", Type: "Dynamic Active", LastUpdated: "2022-05-24T17:41:59.078Z"},
+	},
+}
@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package diff
+
+import (
+	"encoding/json"
+	"net"
+	"sort"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/consul-k8s/cli/common/envoy"
+)
+
+// proxyConfig holds the sections of the Envoy configuration which are compared.
+// Its fields match the output of `consul-k8s proxy read -o json` so that saved
+// snapshots can be read into it.
+type proxyConfig struct {
+	Clusters  []envoy.Cluster  `json:"clusters"`
+	Listeners []envoy.Listener `json:"listeners"`
+	Routes    []envoy.Route    `json:"routes"`
+	Secrets   []envoy.Secret   `json:"secrets"`
+}
+
+// snapshotSections are the keys of the sections of proxyConfig in a snapshot.
+var snapshotSections = []string{"clusters", "listeners", "routes", "secrets"}
+
+func newProxyConfig(config *envoy.EnvoyConfig) proxyConfig {
+	return proxyConfig{
+		Clusters:  config.Clusters,
+		Listeners: config.Listeners,
+		Routes:    config.Routes,
+		Secrets:   config.Secrets,
+	}
+}
+
+// configDiff is the difference between the Envoy configuration of two proxies.
+type configDiff struct {
+	Clusters  sectionDiff `json:"clusters"`
+	Listeners sectionDiff `json:"listeners"`
+	Routes    sectionDiff `json:"routes"`
+	Secrets   sectionDiff `json:"secrets"`
+}
+
+// sectionDiff is the difference between a section of the Envoy configuration
+// of two proxies. Entries are identified by their name. `From` and `To` hold
+// the entries which differ so that the details can be displayed.
+type sectionDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+
+	From map[string]interface{} `json:"from,omitempty"`
+	To   map[string]interface{} `json:"to,omitempty"`
+}
+
+// Empty returns true if there are no differences in the section.
+func (s sectionDiff) Empty() bool {
+	return len(s.Added) == 0 && len(s.Removed) == 0 && len(s.Changed) == 0
+}
+
+// Empty returns true if there are no differences in the configuration.
+func (d configDiff) Empty() bool {
+	return d.Clusters.Empty() && d.Listeners.Empty() && d.Routes.Empty() && d.Secrets.Empty()
+}
+
+// diffConfigs compares the Envoy configuration of two proxies. The time an
+// entry was last updated is ignored as it differs between any two proxies.
+func diffConfigs(from, to proxyConfig) (configDiff, error) {
+	var (
+		d   configDiff
+		err error
+	)
+
+	if d.Clusters, err = diffSection(clusterEntries(from.Clusters), clusterEntries(to.Clusters)); err != nil {
+		return d, err
+	}
+	if d.Listeners, err = diffSection(listenerEntries(from.Listeners), listenerEntries(to.Listeners)); err != nil {
+		return d, err
+	}
+	if d.Routes, err = diffSection(routeEntries(from.Routes), routeEntries(to.Routes)); err != nil {
+		return d, err
+	}
+	if d.Secrets, err = diffSection(secretEntries(from.Secrets), secretEntries(to.Secrets)); err != nil {
+		return d, err
+	}
+
+	return d, nil
+}
+
+func diffSection(from, to map[string]interface{}) (sectionDiff, error) {
+	d := sectionDiff{
+		Added:   []string{},
+		Removed: []string{},
+		Changed: []string{},
+		From:    make(map[string]interface{}),
+		To:      make(map[string]interface{}),
+	}
+
+	for name, entry := range from {
+		other, ok := to[name]
+		if !ok {
+			d.Removed = append(d.Removed, name)
+			d.From[name] = entry
+			continue
+		}
+		if !cmp.Equal(entry, other) {
+			d.Changed = append(d.Changed, name)
+			d.From[name] = entry
+			d.To[name] = other
+		}
+	}
+	for name, entry := range to {
+		if _, ok := from[name]; !ok {
+			d.Added = append(d.Added, name)
+			d.To[name] = entry
+		}
+	}
+
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Changed)
+
+	// Convert the entries to generic maps so they can be diffed field by field.
+	var err error
+	if d.From, err = toMap(d.From); err != nil {
+		return d, err
+	}
+	if d.To, err = toMap(d.To); err != nil {
+		return d, err
+	}
+
+	return d, nil
+}
+
+func clusterEntries(clusters []envoy.Cluster) map[string]interface{} {
+	entries := make(map[string]interface{}, len(clusters))
+	for _, cluster := range clusters {
+		cluster.LastUpdated = ""
+		entries[cluster.Name] = cluster
+	}
+	return entries
+}
+
+// listenerEntries keys the listeners by name. The address of the public listener
+// is the IP of the Pod, so only the port of listener addresses is compared.
+func listenerEntries(listeners []envoy.Listener) map[string]interface{} {
+	entries := make(map[string]interface{}, len(listeners))
+	for _, listener := range listeners {
+		listener.LastUpdated = ""
+		if _, port, err := net.SplitHostPort(listener.Address); err == nil {
+			listener.Address = ":" + port
+		}
+		entries[listener.Name] = listener
+	}
+	return entries
+}
+
+func routeEntries(routes []envoy.Route) map[string]interface{} {
+	entries := make(map[string]interface{}, len(routes))
+	for _, route := range routes {
+		route.LastUpdated = ""
+		entries[route.Name] = route
+	}
+	return entries
+}
+
+func secretEntries(secrets []envoy.Secret) map[string]interface{} {
+	entries := make(map[string]interface{}, len(secrets))
+	for _, secret := range secrets {
+		secret.LastUpdated = ""
+		entries[secret.Name] = secret
+	}
+	return entries
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
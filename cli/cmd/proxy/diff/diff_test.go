@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul-k8s/cli/common/envoy"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	from := proxyConfig{
+		Clusters: []envoy.Cluster{
+			{Name: "local_app", FullyQualifiedDomainName: "local_app", Endpoints: []string{"127.0.0.1:8080"}, Type: "STATIC", LastUpdated: "2022-05-13T04:22:39.655Z"},
+			{Name: "client", FullyQualifiedDomainName: "client.default.dc1.internal.consul", Endpoints: []string{"192.168.18.110:20000"}, Type: "EDS", LastUpdated: "2022-08-10T12:30:32.326Z"},
+			{Name: "frontend", FullyQualifiedDomainName: "frontend.default.dc1.internal.consul", Endpoints: []string{"192.168.63.120:20000"}, Type: "EDS", LastUpdated: "2022-08-10T12:30:32.233Z"},
+		},
+		Listeners: []envoy.Listener{
+			{Name: "public_listener", Address: "192.168.69.179:20000", Direction: "INBOUND", LastUpdated: "2022-08-10T12:30:47.142Z"},
+		},
+		Routes: []envoy.Route{
+			{Name: "public_listener", DestinationCluster: "local_app/", LastUpdated: "2022-08-10T12:30:47.141Z"},
+		},
+		Secrets: []envoy.Secret{
+			{Name: "default", Type: "Dynamic Active", LastUpdated: "2022-05-24T17:41:59.078Z"},
+		},
+	}
+	to := proxyConfig{
+		Clusters: []envoy.Cluster{
+			{Name: "local_app", FullyQualifiedDomainName: "local_app", Endpoints: []string{"127.0.0.1:8080"}, Type: "STATIC", LastUpdated: "2022-05-14T09:00:00.000Z"},
+			{Name: "client", FullyQualifiedDomainName: "client.default.dc1.internal.consul", Endpoints: []string{"192.168.18.111:20000"}, Type: "EDS", LastUpdated: "2022-08-10T12:30:32.326Z"},
+			{Name: "backend", FullyQualifiedDomainName: "backend.default.dc1.internal.consul", Endpoints: []string{"192.168.63.121:20000"}, Type: "EDS", LastUpdated: "2022-08-10T12:30:32.233Z"},
+		},
+		Listeners: []envoy.Listener{
+			{Name: "public_listener", Address: "192.168.69.180:20000", Direction: "INBOUND", LastUpdated: "2022-08-11T12:30:47.142Z"},
+		},
+		Routes: []envoy.Route{
+			{Name: "public_listener", DestinationCluster: "local_app/", LastUpdated: "2022-08-10T12:30:47.141Z"},
+		},
+		Secrets: []envoy.Secret{},
+	}
+
+	d, err := diffConfigs(from, to)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"backend"}, d.Clusters.Added)
+	require.Equal(t, []string{"frontend"}, d.Clusters.Removed)
+	require.Equal(t, []string{"client"}, d.Clusters.Changed)
+	require.Contains(t, d.Clusters.From, "client")
+	require.Contains(t, d.Clusters.From, "frontend")
+	require.NotContains(t, d.Clusters.From, "local_app")
+	require.Contains(t, d.Clusters.To, "backend")
+
+	// Only the Pod IP of the public listener differs.
+	require.True(t, d.Listeners.Empty())
+	require.True(t, d.Routes.Empty())
+
+	require.Equal(t, []string{"default"}, d.Secrets.Removed)
+	require.False(t, d.Empty())
+}
+
+func TestDiffConfigs_Identical(t *testing.T) {
+	config := proxyConfig{
+		Clusters: []envoy.Cluster{
+			{Name: "local_app", FullyQualifiedDomainName: "local_app", Endpoints: []string{"127.0.0.1:8080"}, Type: "STATIC"},
+		},
+	}
+
+	d, err := diffConfigs(config, config)
+	require.NoError(t, err)
+	require.True(t, d.Empty())
+}
+
+func TestDiffProxies(t *testing.T) {
+	config := proxyConfig{
+		Clusters: []envoy.Cluster{{Name: "local_app"}},
+	}
+
+	cases := map[string]struct {
+		from        map[string]proxyConfig
+		to          map[string]proxyConfig
+		expected    []string
+		expectedErr string
+	}{
+		"single proxies with different names": {
+			from:     map[string]proxyConfig{"web-1": config},
+			to:       map[string]proxyConfig{"web-2": config},
+			expected: []string{"web-1 -> web-2"},
+		},
+		"single proxies with the same name": {
+			from:     map[string]proxyConfig{"web": config},
+			to:       map[string]proxyConfig{"web": config},
+			expected: []string{"web"},
+		},
+		"multiple proxies paired by name": {
+			from:     map[string]proxyConfig{"web": config, "web-admin": config},
+			to:       map[string]proxyConfig{"web": config, "web-admin": config},
+			expected: []string{"web", "web-admin"},
+		},
+		"proxy missing from the other side": {
+			from:        map[string]proxyConfig{"web": config, "web-admin": config},
+			to:          map[string]proxyConfig{"web": config},
+			expectedErr: "proxy web-admin was not found in the configuration to compare against",
+		},
+		"proxy missing from the first side": {
+			from:        map[string]proxyConfig{"web": config},
+			to:          map[string]proxyConfig{"web": config, "web-admin": config},
+			expectedErr: "proxy web-admin was not found in the configuration being compared",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			diffs, err := diffProxies(tc.from, tc.to)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(diffs))
+			for name := range diffs {
+				names = append(names, name)
+			}
+			require.ElementsMatch(t, tc.expected, names)
+		})
+	}
+}
@@ -16,7 +16,6 @@ import (
 	helmCLI "helm.sh/helm/v3/pkg/cli"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/validation"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -196,36 +195,7 @@ func (c *ListCommand) namespace() string {
 
 // fetchPods fetches all pods in flagNamespace which run Consul proxies.
 func (c *ListCommand) fetchPods() ([]v1.Pod, error) {
-	var pods []v1.Pod
-
-	// Fetch all pods in the namespace with labels matching the gateway component names.
-	gatewaypods, err := c.kubernetes.CoreV1().Pods(c.namespace()).List(c.Ctx, metav1.ListOptions{
-		LabelSelector: "component in (ingress-gateway, mesh-gateway, terminating-gateway), chart=consul-helm",
-	})
-	if err != nil {
-		return nil, err
-	}
-	pods = append(pods, gatewaypods.Items...)
-
-	// Fetch all pods in the namespace with a label indicating they are an API gateway.
-	apigatewaypods, err := c.kubernetes.CoreV1().Pods(c.namespace()).List(c.Ctx, metav1.ListOptions{
-		LabelSelector: "api-gateway.consul.hashicorp.com/managed=true",
-	})
-	if err != nil {
-		return nil, err
-	}
-	pods = append(pods, apigatewaypods.Items...)
-
-	// Fetch all pods in the namespace with a label indicating they are a service networked by Consul.
-	sidecarpods, err := c.kubernetes.CoreV1().Pods(c.namespace()).List(c.Ctx, metav1.ListOptions{
-		LabelSelector: "consul.hashicorp.com/connect-inject-status=injected",
-	})
-	if err != nil {
-		return nil, err
-	}
-	pods = append(pods, sidecarpods.Items...)
-
-	return pods, nil
+	return common.ListProxyPods(c.Ctx, c.kubernetes, c.namespace())
 }
 
 // output prints a table of pods to the terminal.
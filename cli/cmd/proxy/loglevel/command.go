@@ -12,8 +12,8 @@ import (
 
 	"github.com/posener/complete"
 	helmCLI "helm.sh/helm/v3/pkg/cli"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/validation"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
@@ -24,15 +24,17 @@ import (
 )
 
 const (
-	defaultAdminPort    = 19000
-	flagNameNamespace   = "namespace"
-	flagNameUpdateLevel = "update-level"
-	flagNameReset       = "reset"
-	flagNameKubeConfig  = "kubeconfig"
-	flagNameKubeContext = "context"
+	flagNameNamespace     = "namespace"
+	flagNameSelector      = "selector"
+	flagNameDeployment    = "deployment"
+	flagNameAllNamespaces = "all-namespaces"
+	flagNameUpdateLevel   = "update-level"
+	flagNameReset         = "reset"
+	flagNameKubeConfig    = "kubeconfig"
+	flagNameKubeContext   = "context"
 )
 
-var ErrIncorrectArgFormat = errors.New("Exactly one positional argument is required: <pod-name>")
+var ErrIncorrectArgFormat = errors.New("At most one positional argument may be given: <pod-name>")
 
 type LoggerConfig map[string]string
 
@@ -53,12 +55,15 @@ type LogLevelCommand struct {
 	set        *flag.Sets
 
 	// Command Flags
-	podName     string
-	namespace   string
-	level       string
-	reset       bool
-	kubeConfig  string
-	kubeContext string
+	podName       string
+	namespace     string
+	selector      string
+	deployment    string
+	allNamespaces bool
+	level         string
+	reset         bool
+	kubeConfig    string
+	kubeContext   string
 
 	once               sync.Once
 	help               string
@@ -77,6 +82,26 @@ func (l *LogLevelCommand) init() {
 		Aliases: []string{"n"},
 	})
 
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameSelector,
+		Target:  &l.selector,
+		Usage:   "Inspect or modify the log levels of all Pods matching the given label selector instead of a single Pod.",
+		Aliases: []string{"l"},
+	})
+
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameDeployment,
+		Target: &l.deployment,
+		Usage:  "Inspect or modify the log levels of all Pods of the given Deployment instead of a single Pod.",
+	})
+
+	f.BoolVar(&flag.BoolVar{
+		Name:    flagNameAllNamespaces,
+		Target:  &l.allNamespaces,
+		Usage:   "Find the Pods matching -selector in all namespaces.",
+		Aliases: []string{"A"},
+	})
+
 	f.StringVar(&flag.StringVar{
 		Name:    flagNameUpdateLevel,
 		Target:  &l.level,
@@ -134,24 +159,34 @@ func (l *LogLevelCommand) Run(args []string) int {
 		return l.logOutputAndDie(err)
 	}
 
-	adminPorts, err := l.fetchAdminPorts()
+	params, err := parseParams(l.level)
 	if err != nil {
 		return l.logOutputAndDie(err)
 	}
 
-	err = l.fetchOrSetLogLevels(adminPorts)
+	pods, err := l.targets().ListPods(l.Ctx, l.kubernetes)
 	if err != nil {
 		return l.logOutputAndDie(err)
 	}
 
+	results := common.ForEachPod(l.Ctx, pods, common.DefaultMaxConcurrency, func(ctx context.Context, pod v1.Pod) (map[string]LoggerConfig, error) {
+		return l.fetchOrSetLogLevels(ctx, pod, params)
+	})
+	if !l.targets().Multiple() && results[0].Err != nil {
+		return l.logOutputAndDie(results[0].Err)
+	}
+
+	l.outputLevels(results)
+
+	if failed := failedPods(results); failed > 0 {
+		l.UI.Output(fmt.Sprintf("Failed to inspect or modify the log levels of %d of %d Pods.", failed, len(results)), terminal.WithErrorStyle())
+		return 1
+	}
+
 	return 0
 }
 
 func (l *LogLevelCommand) parseFlags(args []string) error {
-	if len(args) == 0 {
-		return ErrIncorrectArgFormat
-	}
-
 	positional := []string{}
 	// Separate positional args from keyed args
 	for _, arg := range args {
@@ -162,11 +197,12 @@ func (l *LogLevelCommand) parseFlags(args []string) error {
 	}
 	keyed := args[len(positional):]
 
-	if len(positional) != 1 {
+	if len(positional) > 1 {
 		return ErrIncorrectArgFormat
 	}
-
-	l.podName = positional[0]
+	if len(positional) == 1 {
+		l.podName = positional[0]
+	}
 
 	err := l.set.Parse(keyed)
 	if err != nil {
@@ -177,6 +213,9 @@ func (l *LogLevelCommand) parseFlags(args []string) error {
 }
 
 func (l *LogLevelCommand) validateFlags() error {
+	if err := l.targets().Validate(); err != nil {
+		return err
+	}
 	if l.level != "" && l.reset {
 		return fmt.Errorf("cannot set log level to %q and reset to 'info' at the same time", l.level)
 	}
@@ -225,53 +264,37 @@ func (l *LogLevelCommand) initKubernetes() error {
 	return nil
 }
 
-// fetchAdminPorts retrieves all admin ports for Envoy Proxies running in a pod given namespace.
-func (l *LogLevelCommand) fetchAdminPorts() (map[string]int, error) {
-	adminPorts := make(map[string]int, 0)
-	pod, err := l.kubernetes.CoreV1().Pods(l.namespace).Get(l.Ctx, l.podName, metav1.GetOptions{})
-	if err != nil {
-		return adminPorts, err
-	}
-
-	connectService, isMultiport := pod.Annotations["consul.hashicorp.com/connect-service"]
-
-	if !isMultiport {
-		// Return the default port configuration.
-		adminPorts[l.podName] = defaultAdminPort
-		return adminPorts, nil
-	}
-
-	for idx, svc := range strings.Split(connectService, ",") {
-		adminPorts[svc] = defaultAdminPort + idx
+// targets returns the Pods the command inspects or modifies the log levels of.
+func (l *LogLevelCommand) targets() common.PodTargets {
+	return common.PodTargets{
+		Namespace:     l.namespace,
+		PodName:       l.podName,
+		Selector:      l.selector,
+		Deployment:    l.deployment,
+		AllNamespaces: l.allNamespaces,
 	}
-
-	return adminPorts, nil
 }
 
-func (l *LogLevelCommand) fetchOrSetLogLevels(adminPorts map[string]int) error {
+// fetchOrSetLogLevels calls the logging endpoint of each Envoy proxy in the Pod.
+func (l *LogLevelCommand) fetchOrSetLogLevels(ctx context.Context, pod v1.Pod, params *envoy.LoggerParams) (map[string]LoggerConfig, error) {
 	loggers := make(map[string]LoggerConfig, 0)
 
-	for name, port := range adminPorts {
+	for name, port := range common.EnvoyAdminPorts(pod) {
 		pf := common.PortForward{
-			Namespace:  l.namespace,
-			PodName:    l.podName,
+			Namespace:  pod.Namespace,
+			PodName:    pod.Name,
 			RemotePort: port,
 			KubeClient: l.kubernetes,
 			RestConfig: l.restConfig,
 		}
-		params, err := parseParams(l.level)
-		if err != nil {
-			return err
-		}
-		logLevels, err := l.envoyLoggingCaller(l.Ctx, &pf, params)
+		logLevels, err := l.envoyLoggingCaller(ctx, &pf, params)
 		if err != nil {
-			return err
+			return loggers, err
 		}
 		loggers[name] = logLevels
 	}
 
-	l.outputLevels(loggers)
-	return nil
+	return loggers, nil
 }
 
 func parseParams(params string) (*envoy.LoggerParams, error) {
@@ -302,22 +325,53 @@ func parseParams(params string) (*envoy.LoggerParams, error) {
 	return loggerParams, nil
 }
 
-func (l *LogLevelCommand) outputLevels(logLevels map[string]LoggerConfig) {
-	l.UI.Output(fmt.Sprintf("Envoy log configuration for %s in namespace default:", l.podName))
-	for n, levels := range logLevels {
-		l.UI.Output(fmt.Sprintf("Log Levels for %s", n), terminal.WithHeaderStyle())
-		table := terminal.NewTable("Name", "Level")
-		for name, level := range levels {
-			table.AddRow([]string{name, level}, []string{"", levelToColor[level]})
+func (l *LogLevelCommand) outputLevels(results []common.PodResult[map[string]LoggerConfig]) {
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+
+		l.UI.Output(fmt.Sprintf("Envoy log configuration for %s in namespace %s:", result.Pod.Name, result.Pod.Namespace))
+		for n, levels := range result.Value {
+			l.UI.Output(fmt.Sprintf("Log Levels for %s", n), terminal.WithHeaderStyle())
+			table := terminal.NewTable("Name", "Level")
+			for name, level := range levels {
+				table.AddRow([]string{name, level}, []string{"", levelToColor[level]})
+			}
+			l.UI.Table(table)
+			l.UI.Output("")
+		}
+	}
+
+	if failedPods(results) == 0 {
+		return
+	}
+
+	l.UI.Output("Failed Pods", terminal.WithHeaderStyle())
+	table := terminal.NewTable("Namespace", "Pod", "Error")
+	for _, result := range results {
+		if result.Err != nil {
+			table.AddRow([]string{result.Pod.Namespace, result.Pod.Name, result.Err.Error()}, []string{"", "", terminal.Red})
+		}
+	}
+	l.UI.Table(table)
+	l.UI.Output("")
+}
+
+// failedPods returns the number of Pods for which the operation failed.
+func failedPods(results []common.PodResult[map[string]LoggerConfig]) int {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
 		}
-		l.UI.Table(table)
-		l.UI.Output("")
 	}
+	return failed
 }
 
 func (l *LogLevelCommand) Help() string {
 	l.once.Do(l.init)
-	return fmt.Sprintf("%s\n\nUsage: consul-k8s proxy log <pod-name> [flags]\n\n%s", l.Synopsis(), l.help)
+	return fmt.Sprintf("%s\n\nUsage: consul-k8s proxy log <pod-name> [flags]\n       consul-k8s proxy log -selector <selector> | -deployment <name> [flags]\n\n%s", l.Synopsis(), l.help)
 }
 
 func (l *LogLevelCommand) Synopsis() string {
@@ -329,9 +383,12 @@ func (l *LogLevelCommand) Synopsis() string {
 // complete flag such as "-foo" or "--foo".
 func (l *LogLevelCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
-		fmt.Sprintf("-%s", flagNameNamespace):   complete.PredictNothing,
-		fmt.Sprintf("-%s", flagNameKubeConfig):  complete.PredictFiles("*"),
-		fmt.Sprintf("-%s", flagNameKubeContext): complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameNamespace):     complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameSelector):      complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameDeployment):    complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameAllNamespaces): complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameKubeConfig):    complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", flagNameKubeContext):   complete.PredictNothing,
 	}
 }
 
@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -207,6 +208,44 @@ func TestOutputForSettingLogLevels(t *testing.T) {
 	}
 }
 
+func TestOutputForSettingLogLevels_Deployment(t *testing.T) {
+	t.Parallel()
+	labels := map[string]string{"app": "web"}
+	deployment := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+		},
+	}
+	pods := []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", Labels: labels}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "default", Labels: labels}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-3", Namespace: "default", Labels: labels}},
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	c := setupCommand(buf)
+	c.envoyLoggingCaller = func(_ context.Context, pf common.PortForwarder, _ *envoy.LoggerParams) (map[string]string, error) {
+		if pf.(*common.PortForward).PodName == "web-3" {
+			return nil, fmt.Errorf("connection refused")
+		}
+		return testLogConfig, nil
+	}
+	c.kubernetes = fake.NewSimpleClientset(&v1.PodList{Items: pods}, &deployment)
+
+	args := []string{"-deployment", "web", "-u", "warning"}
+	out := c.Run(args)
+	require.Equal(t, 1, out)
+
+	actual := buf.String()
+
+	require.Regexp(t, "Envoy log configuration for web-1 in namespace default:", actual)
+	require.Regexp(t, "Envoy log configuration for web-2 in namespace default:", actual)
+	require.NotRegexp(t, "Envoy log configuration for web-3", actual)
+	require.Regexp(t, "default.*web-3.*connection refused", actual)
+	require.Regexp(t, "Failed to inspect or modify the log levels of 1 of 3 Pods.", actual)
+}
+
 func TestHelp(t *testing.T) {
 	t.Parallel()
 	buf := bytes.NewBuffer([]byte{})
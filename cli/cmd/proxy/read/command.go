@@ -12,8 +12,8 @@ import (
 
 	"github.com/posener/complete"
 	helmCLI "helm.sh/helm/v3/pkg/cli"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/validation"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/utils/strings/slices"
@@ -24,26 +24,28 @@ import (
 	"github.com/hashicorp/consul-k8s/cli/common/terminal"
 )
 
-// defaultAdminPort is the port where the Envoy admin API is exposed.
-const defaultAdminPort int = 19000
-
 const (
 	Table = "table"
 	JSON  = "json"
 	Raw   = "raw"
 
-	flagNameNamespace   = "namespace"
-	flagNameOutput      = "output"
-	flagNameClusters    = "clusters"
-	flagNameListeners   = "listeners"
-	flagNameRoutes      = "routes"
-	flagNameEndpoints   = "endpoints"
-	flagNameSecrets     = "secrets"
-	flagNameFQDN        = "fqdn"
-	flagNameAddress     = "address"
-	flagNamePort        = "port"
-	flagNameKubeConfig  = "kubeconfig"
-	flagNameKubeContext = "context"
+	flagNameNamespace     = "namespace"
+	flagNameSelector      = "selector"
+	flagNameDeployment    = "deployment"
+	flagNameAllNamespaces = "all-namespaces"
+	flagNameOutput        = "output"
+	flagNameConfigDump    = "config-dump-file"
+	flagNameClustersFile  = "clusters-file"
+	flagNameClusters      = "clusters"
+	flagNameListeners     = "listeners"
+	flagNameRoutes        = "routes"
+	flagNameEndpoints     = "endpoints"
+	flagNameSecrets       = "secrets"
+	flagNameFQDN          = "fqdn"
+	flagNameAddress       = "address"
+	flagNamePort          = "port"
+	flagNameKubeConfig    = "kubeconfig"
+	flagNameKubeContext   = "context"
 )
 
 type ReadCommand struct {
@@ -54,9 +56,16 @@ type ReadCommand struct {
 	set *flag.Sets
 
 	// Command Flags
-	flagNamespace string
-	flagPodName   string
-	flagOutput    string
+	flagNamespace     string
+	flagPodName       string
+	flagSelector      string
+	flagDeployment    string
+	flagAllNamespaces bool
+	flagOutput        string
+
+	// Offline Opts
+	flagConfigDumpFile string
+	flagClustersFile   string
 
 	// Output Filtering Opts
 	flagClusters  bool
@@ -93,6 +102,23 @@ func (c *ReadCommand) init() {
 		Usage:   "The namespace where the target Pod can be found.",
 		Aliases: []string{"n"},
 	})
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameSelector,
+		Target:  &c.flagSelector,
+		Usage:   "Read the Envoy configuration of all Pods matching the given label selector instead of a single Pod.",
+		Aliases: []string{"l"},
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameDeployment,
+		Target: &c.flagDeployment,
+		Usage:  "Read the Envoy configuration of all Pods of the given Deployment instead of a single Pod.",
+	})
+	f.BoolVar(&flag.BoolVar{
+		Name:    flagNameAllNamespaces,
+		Target:  &c.flagAllNamespaces,
+		Usage:   "Find the Pods matching -selector in all namespaces.",
+		Aliases: []string{"A"},
+	})
 	f.StringVar(&flag.StringVar{
 		Name:    flagNameOutput,
 		Target:  &c.flagOutput,
@@ -101,6 +127,18 @@ func (c *ReadCommand) init() {
 		Aliases: []string{"o"},
 	})
 
+	f = c.set.NewSet("Offline Options")
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameConfigDump,
+		Target: &c.flagConfigDumpFile,
+		Usage:  "Read the Envoy configuration from a file saved from the `/config_dump` admin endpoint instead of a Pod. No Kubernetes access is needed.",
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameClustersFile,
+		Target: &c.flagClustersFile,
+		Usage:  "A file saved from the `/clusters?format=json` admin endpoint to read cluster endpoints from. Requires -config-dump-file.",
+	})
+
 	f = c.set.NewSet("Output Filtering Options")
 	f.BoolVar(&flag.BoolVar{
 		Name:   flagNameClusters,
@@ -177,35 +215,44 @@ func (c *ReadCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.flagConfigDumpFile != "" {
+		return c.readConfigFiles()
+	}
+
 	if err := c.initKubernetes(); err != nil {
 		c.UI.Output(err.Error(), terminal.WithErrorStyle())
 		return 1
 	}
 
-	adminPorts, err := c.fetchAdminPorts()
+	pods, err := c.targets().ListPods(c.Ctx, c.kubernetes)
 	if err != nil {
 		c.UI.Output(err.Error(), terminal.WithErrorStyle())
 		return 1
 	}
 
-	configs, err := c.fetchConfigs(adminPorts)
-	if err != nil {
-		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+	results := common.ForEachPod(c.Ctx, pods, common.DefaultMaxConcurrency, c.fetchConfigs)
+	if !c.targets().Multiple() && results[0].Err != nil {
+		c.UI.Output(results[0].Err.Error(), terminal.WithErrorStyle())
 		return 1
 	}
 
-	err = c.outputConfigs(configs)
+	err = c.outputConfigs(results)
 	if err != nil {
 		c.UI.Output(err.Error(), terminal.WithErrorStyle())
 		return 1
 	}
 
+	if failed := failedPods(results); failed > 0 {
+		c.UI.Output(fmt.Sprintf("Failed to read the Envoy configuration of %d of %d Pods.", failed, len(results)), terminal.WithErrorStyle())
+		return 1
+	}
+
 	return 0
 }
 
 func (c *ReadCommand) Help() string {
 	c.once.Do(c.init)
-	return fmt.Sprintf("%s\n\nUsage: consul-k8s proxy read <pod-name> [flags]\n\n%s", c.Synopsis(), c.help)
+	return fmt.Sprintf("%s\n\nUsage: consul-k8s proxy read <pod-name> [flags]\n       consul-k8s proxy read -selector <selector> | -deployment <name> [flags]\n       consul-k8s proxy read -config-dump-file <file> [-clusters-file <file>] [flags]\n\n%s", c.Synopsis(), c.help)
 }
 
 func (c *ReadCommand) Synopsis() string {
@@ -217,18 +264,23 @@ func (c *ReadCommand) Synopsis() string {
 // complete flag such as "-foo" or "--foo".
 func (c *ReadCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
-		fmt.Sprintf("-%s", flagNameNamespace):   complete.PredictNothing,
-		fmt.Sprintf("-%s", flagNameOutput):      complete.PredictNothing,
-		fmt.Sprintf("-%s", flagNameClusters):    complete.PredictNothing,
-		fmt.Sprintf("-%s", flagNameListeners):   complete.PredictNothing,
-		fmt.Sprintf("-%s", flagNameRoutes):      complete.PredictNothing,
-		fmt.Sprintf("-%s", flagNameEndpoints):   complete.PredictNothing,
-		fmt.Sprintf("-%s", flagNameSecrets):     complete.PredictNothing,
-		fmt.Sprintf("-%s", flagNameFQDN):        complete.PredictNothing,
-		fmt.Sprintf("-%s", flagNameAddress):     complete.PredictNothing,
-		fmt.Sprintf("-%s", flagNamePort):        complete.PredictNothing,
-		fmt.Sprintf("-%s", flagNameKubeConfig):  complete.PredictFiles("*"),
-		fmt.Sprintf("-%s", flagNameKubeContext): complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameNamespace):     complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameSelector):      complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameDeployment):    complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameAllNamespaces): complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameOutput):        complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameConfigDump):    complete.PredictFiles("*.json"),
+		fmt.Sprintf("-%s", flagNameClustersFile):  complete.PredictFiles("*.json"),
+		fmt.Sprintf("-%s", flagNameClusters):      complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameListeners):     complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameRoutes):        complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameEndpoints):     complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameSecrets):       complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameFQDN):          complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameAddress):       complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNamePort):          complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameKubeConfig):    complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", flagNameKubeContext):   complete.PredictNothing,
 	}
 }
 
@@ -250,10 +302,12 @@ func (c *ReadCommand) parseFlags(args []string) error {
 	}
 	keyed := args[len(positional):]
 
-	if len(positional) != 1 {
-		return fmt.Errorf("Exactly one positional argument is required: <pod-name>")
+	if len(positional) > 1 {
+		return fmt.Errorf("At most one positional argument may be given: <pod-name>")
+	}
+	if len(positional) == 1 {
+		c.flagPodName = positional[0]
 	}
-	c.flagPodName = positional[0]
 
 	if err := c.set.Parse(keyed); err != nil {
 		return err
@@ -263,6 +317,16 @@ func (c *ReadCommand) parseFlags(args []string) error {
 }
 
 func (c *ReadCommand) validateFlags() error {
+	if c.flagClustersFile != "" && c.flagConfigDumpFile == "" {
+		return fmt.Errorf("-clusters-file requires -config-dump-file.")
+	}
+	if c.flagConfigDumpFile != "" {
+		if c.flagPodName != "" || c.flagSelector != "" || c.flagDeployment != "" || c.flagAllNamespaces {
+			return fmt.Errorf("-config-dump-file cannot be combined with <pod-name>, -selector, -deployment or -all-namespaces.")
+		}
+	} else if err := c.targets().Validate(); err != nil {
+		return err
+	}
 	if errs := validation.ValidateNamespaceName(c.flagNamespace, false); c.flagNamespace != "" && len(errs) > 0 {
 		return fmt.Errorf("invalid namespace name passed for -namespace/-n: %v", strings.Join(errs, "; "))
 	}
@@ -302,42 +366,51 @@ func (c *ReadCommand) initKubernetes() (err error) {
 	return nil
 }
 
-func (c *ReadCommand) fetchAdminPorts() (map[string]int, error) {
-	adminPorts := make(map[string]int, 0)
-
-	pod, err := c.kubernetes.CoreV1().Pods(c.flagNamespace).Get(c.Ctx, c.flagPodName, metav1.GetOptions{})
+// readConfigFiles outputs the Envoy configuration read from saved admin
+// endpoint responses rather than from a Pod.
+func (c *ReadCommand) readConfigFiles() int {
+	config, err := envoy.ReadConfigFiles(c.flagConfigDumpFile, c.flagClustersFile)
 	if err != nil {
-		return adminPorts, err
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
 	}
 
-	connectService, isMultiport := pod.Annotations["consul.hashicorp.com/connect-service"]
-
-	if !isMultiport {
-		// Return the default port configuration.
-		adminPorts[c.flagPodName] = defaultAdminPort
-		return adminPorts, nil
+	results := []common.PodResult[map[string]*envoy.EnvoyConfig]{
+		{Value: map[string]*envoy.EnvoyConfig{c.flagConfigDumpFile: config}},
 	}
-
-	for index, service := range strings.Split(connectService, ",") {
-		adminPorts[service] = defaultAdminPort + index
+	if err := c.outputConfigs(results); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
 	}
 
-	return adminPorts, nil
+	return 0
+}
+
+// targets returns the Pods the command reads the Envoy configuration of.
+func (c *ReadCommand) targets() common.PodTargets {
+	return common.PodTargets{
+		Namespace:     c.flagNamespace,
+		PodName:       c.flagPodName,
+		Selector:      c.flagSelector,
+		Deployment:    c.flagDeployment,
+		AllNamespaces: c.flagAllNamespaces,
+	}
 }
 
-func (c *ReadCommand) fetchConfigs(adminPorts map[string]int) (map[string]*envoy.EnvoyConfig, error) {
+// fetchConfigs fetches the configuration of each Envoy proxy in the Pod.
+func (c *ReadCommand) fetchConfigs(ctx context.Context, pod v1.Pod) (map[string]*envoy.EnvoyConfig, error) {
 	configs := make(map[string]*envoy.EnvoyConfig, 0)
 
-	for name, adminPort := range adminPorts {
+	for name, adminPort := range common.EnvoyAdminPorts(pod) {
 		pf := common.PortForward{
-			Namespace:  c.flagNamespace,
-			PodName:    c.flagPodName,
+			Namespace:  pod.Namespace,
+			PodName:    pod.Name,
 			RemotePort: adminPort,
 			KubeClient: c.kubernetes,
 			RestConfig: c.restConfig,
 		}
 
-		config, err := c.fetchConfig(c.Ctx, &pf)
+		config, err := c.fetchConfig(ctx, &pf)
 		if err != nil {
 			return configs, err
 		}
@@ -348,14 +421,27 @@ func (c *ReadCommand) fetchConfigs(adminPorts map[string]int) (map[string]*envoy
 	return configs, nil
 }
 
-func (c *ReadCommand) outputConfigs(configs map[string]*envoy.EnvoyConfig) error {
+// proxyName returns the name a proxy is shown with. When multiple Pods are
+// targeted, the name is qualified by the namespace and Pod the proxy runs in.
+// Pods running multiple services have a proxy per service.
+func (c *ReadCommand) proxyName(pod v1.Pod, name string) string {
+	if !c.targets().Multiple() {
+		return name
+	}
+	if name == pod.Name {
+		return fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, name)
+}
+
+func (c *ReadCommand) outputConfigs(results []common.PodResult[map[string]*envoy.EnvoyConfig]) error {
 	switch c.flagOutput {
 	case Table:
-		return c.outputTables(configs)
+		return c.outputTables(results)
 	case JSON:
-		return c.outputJSON(configs)
+		return c.outputJSON(results)
 	case Raw:
-		return c.outputRaw(configs)
+		return c.outputRaw(results)
 	}
 
 	return nil
@@ -401,7 +487,7 @@ func (c *ReadCommand) filterWarnings() []string {
 	return warnings
 }
 
-func (c *ReadCommand) outputTables(configs map[string]*envoy.EnvoyConfig) error {
+func (c *ReadCommand) outputTables(results []common.PodResult[map[string]*envoy.EnvoyConfig]) error {
 	if c.flagFQDN != "" || c.flagAddress != "" || c.flagPort != -1 {
 		c.UI.Output("Filters applied", terminal.WithHeaderStyle())
 
@@ -422,41 +508,62 @@ func (c *ReadCommand) outputTables(configs map[string]*envoy.EnvoyConfig) error
 		c.UI.Output("")
 	}
 
-	for name, config := range configs {
-		c.UI.Output(fmt.Sprintf("Envoy configuration for %s in namespace %s:", name, c.flagNamespace))
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
 
-		c.outputClustersTable(FilterClusters(config.Clusters, c.flagFQDN, c.flagAddress, c.flagPort))
-		c.outputEndpointsTable(FilterEndpoints(config.Endpoints, c.flagAddress, c.flagPort))
-		c.outputListenersTable(FilterListeners(config.Listeners, c.flagAddress, c.flagPort))
-		c.outputRoutesTable(config.Routes)
-		c.outputSecretsTable(config.Secrets)
-		c.UI.Output("\n")
+		for name, config := range result.Value {
+			if c.flagConfigDumpFile != "" {
+				c.UI.Output(fmt.Sprintf("Envoy configuration from %s:", name))
+			} else if c.targets().Multiple() {
+				c.UI.Output(fmt.Sprintf("Envoy configuration for %s:", c.proxyName(result.Pod, name)))
+			} else {
+				c.UI.Output(fmt.Sprintf("Envoy configuration for %s in namespace %s:", name, result.Pod.Namespace))
+			}
+
+			c.outputClustersTable(FilterClusters(config.Clusters, c.flagFQDN, c.flagAddress, c.flagPort))
+			c.outputEndpointsTable(FilterEndpoints(config.Endpoints, c.flagAddress, c.flagPort))
+			c.outputListenersTable(FilterListeners(config.Listeners, c.flagAddress, c.flagPort))
+			c.outputRoutesTable(config.Routes)
+			c.outputSecretsTable(config.Secrets)
+			c.UI.Output("\n")
+		}
 	}
 
+	c.outputFailuresTable(results)
+
 	return nil
 }
 
-func (c *ReadCommand) outputJSON(configs map[string]*envoy.EnvoyConfig) error {
+func (c *ReadCommand) outputJSON(results []common.PodResult[map[string]*envoy.EnvoyConfig]) error {
 	cfgs := make(map[string]interface{})
-	for name, config := range configs {
-		cfg := make(map[string]interface{})
-		if c.shouldPrintTable(c.flagClusters) {
-			cfg["clusters"] = FilterClusters(config.Clusters, c.flagFQDN, c.flagAddress, c.flagPort)
-		}
-		if c.shouldPrintTable(c.flagEndpoints) {
-			cfg["endpoints"] = FilterEndpoints(config.Endpoints, c.flagAddress, c.flagPort)
-		}
-		if c.shouldPrintTable(c.flagListeners) {
-			cfg["listeners"] = FilterListeners(config.Listeners, c.flagAddress, c.flagPort)
-		}
-		if c.shouldPrintTable(c.flagRoutes) {
-			cfg["routes"] = config.Routes
-		}
-		if c.shouldPrintTable(c.flagSecrets) {
-			cfg["secrets"] = config.Secrets
+	for _, result := range results {
+		if result.Err != nil {
+			cfgs[c.proxyName(result.Pod, result.Pod.Name)] = map[string]string{"error": result.Err.Error()}
+			continue
 		}
 
-		cfgs[name] = cfg
+		for name, config := range result.Value {
+			cfg := make(map[string]interface{})
+			if c.shouldPrintTable(c.flagClusters) {
+				cfg["clusters"] = FilterClusters(config.Clusters, c.flagFQDN, c.flagAddress, c.flagPort)
+			}
+			if c.shouldPrintTable(c.flagEndpoints) {
+				cfg["endpoints"] = FilterEndpoints(config.Endpoints, c.flagAddress, c.flagPort)
+			}
+			if c.shouldPrintTable(c.flagListeners) {
+				cfg["listeners"] = FilterListeners(config.Listeners, c.flagAddress, c.flagPort)
+			}
+			if c.shouldPrintTable(c.flagRoutes) {
+				cfg["routes"] = config.Routes
+			}
+			if c.shouldPrintTable(c.flagSecrets) {
+				cfg["secrets"] = config.Secrets
+			}
+
+			cfgs[c.proxyName(result.Pod, name)] = cfg
+		}
 	}
 
 	escaped, err := json.MarshalIndent(cfgs, "", "\t")
@@ -472,15 +579,22 @@ func (c *ReadCommand) outputJSON(configs map[string]*envoy.EnvoyConfig) error {
 	return nil
 }
 
-func (c *ReadCommand) outputRaw(configs map[string]*envoy.EnvoyConfig) error {
+func (c *ReadCommand) outputRaw(results []common.PodResult[map[string]*envoy.EnvoyConfig]) error {
 	cfgs := make(map[string]interface{}, 0)
-	for name, config := range configs {
-		var cfg interface{}
-		if err := json.Unmarshal(config.RawCfg, &cfg); err != nil {
-			return err
+	for _, result := range results {
+		if result.Err != nil {
+			cfgs[c.proxyName(result.Pod, result.Pod.Name)] = map[string]string{"error": result.Err.Error()}
+			continue
 		}
 
-		cfgs[name] = cfg
+		for name, config := range result.Value {
+			var cfg interface{}
+			if err := json.Unmarshal(config.RawCfg, &cfg); err != nil {
+				return err
+			}
+
+			cfgs[c.proxyName(result.Pod, name)] = cfg
+		}
 	}
 
 	out, err := json.MarshalIndent(cfgs, "", "\t")
@@ -493,6 +607,23 @@ func (c *ReadCommand) outputRaw(configs map[string]*envoy.EnvoyConfig) error {
 	return nil
 }
 
+// outputFailuresTable lists the Pods whose Envoy configuration could not be read.
+func (c *ReadCommand) outputFailuresTable(results []common.PodResult[map[string]*envoy.EnvoyConfig]) {
+	if failedPods(results) == 0 {
+		return
+	}
+
+	c.UI.Output("Failed Pods", terminal.WithHeaderStyle())
+	table := terminal.NewTable("Namespace", "Pod", "Error")
+	for _, result := range results {
+		if result.Err != nil {
+			table.AddRow([]string{result.Pod.Namespace, result.Pod.Name, result.Err.Error()}, []string{"", "", terminal.Red})
+		}
+	}
+	c.UI.Table(table)
+	c.UI.Output("")
+}
+
 func (c *ReadCommand) outputClustersTable(clusters []envoy.Cluster) {
 	if !c.shouldPrintTable(c.flagClusters) {
 		return
@@ -545,3 +676,14 @@ func (c *ReadCommand) outputSecretsTable(secrets []envoy.Secret) {
 	c.UI.Output(fmt.Sprintf("Secrets (%d)", len(secrets)), terminal.WithHeaderStyle())
 	c.UI.Table(formatSecrets(secrets))
 }
+
+// failedPods returns the number of Pods for which the operation failed.
+func failedPods(results []common.PodResult[map[string]*envoy.EnvoyConfig]) int {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
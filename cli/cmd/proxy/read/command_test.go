@@ -51,6 +51,26 @@ func TestFlagParsing(t *testing.T) {
 			args: []string{"podName", "-output", "image"},
 			out:  1,
 		},
+		"Podname and selector passed": {
+			args: []string{"podName", "-selector", "app=web"},
+			out:  1,
+		},
+		"All namespaces without selector": {
+			args: []string{"-deployment", "web", "-all-namespaces"},
+			out:  1,
+		},
+		"Podname and config dump file passed": {
+			args: []string{"podName", "-config-dump-file", "config_dump.json"},
+			out:  1,
+		},
+		"Clusters file without config dump file": {
+			args: []string{"podName", "-clusters-file", "clusters.json"},
+			out:  1,
+		},
+		"Nonexistent config dump file": {
+			args: []string{"-config-dump-file", "nonexistent.json"},
+			out:  1,
+		},
 	}
 
 	for name, tc := range cases {
@@ -159,6 +179,127 @@ func TestReadCommandOutput(t *testing.T) {
 	}
 }
 
+func TestReadCommandOutput_ConfigDumpFile(t *testing.T) {
+	configDump := "../../../common/envoy/testdata/test_config_dump.json"
+	clusters := "../../../common/envoy/testdata/test_clusters.json"
+
+	cases := map[string]struct {
+		args     []string
+		expected []string
+	}{
+		"Config dump and clusters": {
+			args: []string{"-config-dump-file", configDump, "-clusters-file", clusters},
+			expected: []string{
+				fmt.Sprintf("Envoy configuration from %s:", configDump),
+				"==> Clusters \\(5\\)",
+				"local_agent.*192\\.168\\.79\\.187:8502.*STATIC",
+				"==> Listeners \\(2\\)",
+				"==> Secrets \\(2\\)",
+			},
+		},
+		"Config dump only": {
+			args: []string{"-config-dump-file", configDump},
+			expected: []string{
+				"==> Clusters \\(5\\)",
+				"==> Routes \\(1\\)",
+			},
+		},
+		"Config dump as JSON": {
+			args: []string{"-config-dump-file", configDump, "-clusters-file", clusters, "-output", "json", "-routes"},
+			expected: []string{
+				`"routes": \[`,
+				`"DestinationCluster": "local_app/"`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			c := setupCommand(buf)
+			c.fetchConfig = func(context.Context, common.PortForwarder) (*envoy.EnvoyConfig, error) {
+				t.Fatal("the Envoy configuration should not be fetched from a Pod")
+				return nil, nil
+			}
+
+			out := c.Run(tc.args)
+			require.Equal(t, 0, out)
+
+			actual := buf.String()
+			for _, expression := range tc.expected {
+				require.Regexp(t, expression, actual)
+			}
+		})
+	}
+}
+
+func TestReadCommandOutput_MultiplePods(t *testing.T) {
+	labels := map[string]string{"app": "web"}
+	pods := []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", Labels: labels}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "default", Labels: labels}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-3", Namespace: "other", Labels: labels}},
+	}
+
+	cases := map[string]struct {
+		args     []string
+		expected []string
+		failed   bool
+	}{
+		"Selector": {
+			args: []string{"-selector", "app=web"},
+			expected: []string{
+				"Envoy configuration for default/web-1:",
+				"Envoy configuration for default/web-2:",
+			},
+		},
+		"Selector in all namespaces": {
+			args: []string{"-selector", "app=web", "-all-namespaces"},
+			expected: []string{
+				"Envoy configuration for default/web-1:",
+				"Envoy configuration for default/web-2:",
+				"Envoy configuration for other/web-3:",
+			},
+		},
+		"Selector with a failing Pod": {
+			args: []string{"-selector", "app=web", "-A"},
+			expected: []string{
+				"Envoy configuration for default/web-1:",
+				"==> Failed Pods",
+				"other.*web-3.*connection refused",
+				"Failed to read the Envoy configuration of 1 of 3 Pods.",
+			},
+			failed: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			c := setupCommand(buf)
+			c.kubernetes = fake.NewSimpleClientset(&v1.PodList{Items: pods})
+			c.fetchConfig = func(_ context.Context, pf common.PortForwarder) (*envoy.EnvoyConfig, error) {
+				if tc.failed && pf.(*common.PortForward).PodName == "web-3" {
+					return nil, fmt.Errorf("connection refused")
+				}
+				return testEnvoyConfig, nil
+			}
+
+			out := c.Run(append(tc.args, "-clusters"))
+			if tc.failed {
+				require.Equal(t, 1, out)
+			} else {
+				require.Equal(t, 0, out)
+			}
+
+			actual := buf.String()
+			for _, expression := range tc.expected {
+				require.Regexp(t, expression, actual)
+			}
+		})
+	}
+}
+
 // TestFilterWarnings ensures that a warning is printed if the user applies a
 // field filter (e.g. -fqdn default) and a table filter (e.g. -secrets) where
 // the former does not affect the output of the latter.
@@ -0,0 +1,413 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/posener/complete"
+	helmCLI "helm.sh/helm/v3/pkg/cli"
+	"k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/strings/slices"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	"github.com/hashicorp/consul-k8s/cli/common/envoy"
+	"github.com/hashicorp/consul-k8s/cli/common/flag"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+)
+
+const (
+	Table = "table"
+	JSON  = "json"
+
+	flagNameNamespace   = "namespace"
+	flagNameOutput      = "output"
+	flagNameUpstream    = "upstream"
+	flagNameWatch       = "watch"
+	flagNameKubeConfig  = "kubeconfig"
+	flagNameKubeContext = "context"
+)
+
+type StatsCommand struct {
+	*common.BaseCommand
+
+	kubernetes kubernetes.Interface
+
+	set *flag.Sets
+
+	// Command Flags
+	flagNamespace string
+	flagPodName   string
+	flagOutput    string
+	flagUpstream  string
+	flagWatch     time.Duration
+
+	// Global Flags
+	flagKubeConfig  string
+	flagKubeContext string
+
+	fetchStats func(context.Context, common.PortForwarder) (*envoy.ProxyStats, error)
+
+	restConfig *rest.Config
+
+	once sync.Once
+	help string
+}
+
+func (c *StatsCommand) init() {
+	if c.fetchStats == nil {
+		c.fetchStats = envoy.FetchStats
+	}
+
+	c.set = flag.NewSets()
+	f := c.set.NewSet("Command Options")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameNamespace,
+		Target:  &c.flagNamespace,
+		Usage:   "The namespace where the target Pod can be found.",
+		Aliases: []string{"n"},
+	})
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameOutput,
+		Target:  &c.flagOutput,
+		Usage:   "Output the Envoy statistics as 'table' or 'json'.",
+		Default: Table,
+		Aliases: []string{"o"},
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameUpstream,
+		Target: &c.flagUpstream,
+		Usage:  "Filter cluster statistics to clusters with a name which contains the given value.",
+	})
+	f.DurationVar(&flag.DurationVar{
+		Name:   flagNameWatch,
+		Target: &c.flagWatch,
+		Usage:  "Fetch the statistics repeatedly at the given interval and show the rate of requests, 5xx responses, retries and connections per second.",
+	})
+
+	f = c.set.NewSet("GlobalOptions")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameKubeConfig,
+		Aliases: []string{"c"},
+		Target:  &c.flagKubeConfig,
+		Usage:   "Set the path to kubeconfig file.",
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameKubeContext,
+		Target: &c.flagKubeContext,
+		Usage:  "Set the Kubernetes context to use.",
+	})
+
+	c.help = c.set.Help()
+}
+
+func (c *StatsCommand) Run(args []string) int {
+	c.once.Do(c.init)
+	c.Log.ResetNamed("stats")
+	defer common.CloseWithError(c.BaseCommand)
+
+	if err := c.parseFlags(args); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		c.UI.Output("\n" + c.Help())
+		return 1
+	}
+
+	if err := c.validateFlags(); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		c.UI.Output("\n" + c.Help())
+		return 1
+	}
+
+	if err := c.initKubernetes(); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	adminPorts, err := c.fetchAdminPorts()
+	if err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	fetchedAt := time.Now()
+	stats, err := c.fetchAllStats(adminPorts)
+	if err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagWatch == 0 {
+		if err := c.outputStats(stats, nil); err != nil {
+			c.UI.Output(err.Error(), terminal.WithErrorStyle())
+			return 1
+		}
+		return 0
+	}
+
+	if err := c.watch(adminPorts, stats, fetchedAt); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	return 0
+}
+
+func (c *StatsCommand) Help() string {
+	c.once.Do(c.init)
+	return fmt.Sprintf("%s\n\nUsage: consul-k8s proxy stats <pod-name> [flags]\n\n%s", c.Synopsis(), c.help)
+}
+
+func (c *StatsCommand) Synopsis() string {
+	return "Inspect the Envoy traffic statistics for a given Pod."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *StatsCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		fmt.Sprintf("-%s", flagNameNamespace):   complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameOutput):      complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameUpstream):    complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameWatch):       complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameKubeConfig):  complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", flagNameKubeContext): complete.PredictNothing,
+	}
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// Since argument completion is not supported, this will return
+// complete.PredictNothing.
+func (c *StatsCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *StatsCommand) parseFlags(args []string) error {
+	// Separate positional arguments from keyed arguments.
+	positional := []string{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		positional = append(positional, arg)
+	}
+	keyed := args[len(positional):]
+
+	if len(positional) != 1 {
+		return fmt.Errorf("Exactly one positional argument is required: <pod-name>")
+	}
+	c.flagPodName = positional[0]
+
+	if err := c.set.Parse(keyed); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *StatsCommand) validateFlags() error {
+	if errs := validation.ValidateNamespaceName(c.flagNamespace, false); c.flagNamespace != "" && len(errs) > 0 {
+		return fmt.Errorf("invalid namespace name passed for -namespace/-n: %v", strings.Join(errs, "; "))
+	}
+	if outputs := []string{Table, JSON}; !slices.Contains(outputs, c.flagOutput) {
+		return fmt.Errorf("-output must be one of %s.", strings.Join(outputs, ", "))
+	}
+	if c.flagWatch < 0 {
+		return fmt.Errorf("-watch must be a positive duration.")
+	}
+	return nil
+}
+
+func (c *StatsCommand) initKubernetes() (err error) {
+	settings := helmCLI.New()
+
+	if c.flagKubeConfig != "" {
+		settings.KubeConfig = c.flagKubeConfig
+	}
+
+	if c.flagKubeContext != "" {
+		settings.KubeContext = c.flagKubeContext
+	}
+
+	if c.restConfig == nil {
+		if c.restConfig, err = settings.RESTClientGetter().ToRESTConfig(); err != nil {
+			return fmt.Errorf("error creating Kubernetes REST config %v", err)
+		}
+	}
+
+	if c.kubernetes == nil {
+		if c.kubernetes, err = kubernetes.NewForConfig(c.restConfig); err != nil {
+			return fmt.Errorf("error creating Kubernetes client %v", err)
+		}
+	}
+
+	if c.flagNamespace == "" {
+		c.flagNamespace = settings.Namespace()
+	}
+
+	return nil
+}
+
+func (c *StatsCommand) fetchAdminPorts() (map[string]int, error) {
+	pod, err := c.kubernetes.CoreV1().Pods(c.flagNamespace).Get(c.Ctx, c.flagPodName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return common.EnvoyAdminPorts(*pod), nil
+}
+
+func (c *StatsCommand) fetchAllStats(adminPorts map[string]int) (map[string]*envoy.ProxyStats, error) {
+	stats := make(map[string]*envoy.ProxyStats, 0)
+
+	for name, adminPort := range adminPorts {
+		pf := common.PortForward{
+			Namespace:  c.flagNamespace,
+			PodName:    c.flagPodName,
+			RemotePort: adminPort,
+			KubeClient: c.kubernetes,
+			RestConfig: c.restConfig,
+		}
+
+		s, err := c.fetchStats(c.Ctx, &pf)
+		if err != nil {
+			return stats, err
+		}
+
+		stats[name] = filterClusters(s, c.flagUpstream)
+	}
+
+	return stats, nil
+}
+
+// watch fetches the statistics at every tick of the watch interval and outputs
+// them together with the rates since the previous fetch until the command is
+// cancelled. The rates are divided by the time actually elapsed between the
+// fetches, since a fetch through the port forward can take a good part of the
+// interval and ticks are dropped while it is running.
+func (c *StatsCommand) watch(adminPorts map[string]int, previous map[string]*envoy.ProxyStats, previousAt time.Time) error {
+	ticker := time.NewTicker(c.flagWatch)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		fetchedAt := time.Now()
+		current, err := c.fetchAllStats(adminPorts)
+		if err != nil {
+			return err
+		}
+
+		elapsed := fetchedAt.Sub(previousAt)
+		rates := make(map[string]*statsRates, len(current))
+		for name, stats := range current {
+			rates[name] = computeRates(previous[name], stats, elapsed)
+		}
+
+		if err := c.outputStats(current, rates); err != nil {
+			return err
+		}
+		previous, previousAt = current, fetchedAt
+	}
+}
+
+func (c *StatsCommand) outputStats(stats map[string]*envoy.ProxyStats, rates map[string]*statsRates) error {
+	switch c.flagOutput {
+	case Table:
+		c.outputTables(stats, rates)
+	case JSON:
+		return c.outputJSON(stats, rates)
+	}
+
+	return nil
+}
+
+func (c *StatsCommand) outputTables(stats map[string]*envoy.ProxyStats, rates map[string]*statsRates) {
+	if c.flagUpstream != "" {
+		c.UI.Output("Filters applied", terminal.WithHeaderStyle())
+		c.UI.Output(fmt.Sprintf("Cluster names containing: %s", c.flagUpstream), terminal.WithInfoStyle())
+		c.UI.Output("")
+	}
+
+	for _, name := range sortedNames(stats) {
+		s := stats[name]
+		c.UI.Output(fmt.Sprintf("Envoy statistics for %s in namespace %s:", name, c.flagNamespace))
+
+		c.UI.Output(fmt.Sprintf("Clusters (%d)", len(s.Clusters)), terminal.WithHeaderStyle())
+		c.UI.Table(formatClusterStats(s.Clusters, rates[name]))
+		c.UI.Output("")
+
+		c.UI.Output(fmt.Sprintf("Listeners (%d)", len(s.Listeners)), terminal.WithHeaderStyle())
+		c.UI.Table(formatListenerStats(s.Listeners, rates[name]))
+		c.UI.Output("\n")
+	}
+}
+
+func (c *StatsCommand) outputJSON(stats map[string]*envoy.ProxyStats, rates map[string]*statsRates) error {
+	out := make(map[string]interface{})
+	for name, s := range stats {
+		o := map[string]interface{}{
+			"clusters":  s.Clusters,
+			"listeners": s.Listeners,
+		}
+		if r, ok := rates[name]; ok {
+			o["rates"] = r
+		}
+
+		out[name] = o
+	}
+
+	escaped, err := json.MarshalIndent(out, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	c.UI.Output(string(escaped))
+
+	return nil
+}
+
+// sortedNames returns the names of the proxies in stats in a stable order so
+// that the tables of a multiport pod don't move around between refreshes.
+func sortedNames(stats map[string]*envoy.ProxyStats) []string {
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// filterClusters returns the statistics with only the clusters whose name
+// contains the given upstream.
+func filterClusters(stats *envoy.ProxyStats, upstream string) *envoy.ProxyStats {
+	if upstream == "" {
+		return stats
+	}
+
+	filtered := &envoy.ProxyStats{
+		Clusters:  make([]envoy.ClusterStats, 0),
+		Listeners: stats.Listeners,
+	}
+	for _, cluster := range stats.Clusters {
+		if strings.Contains(cluster.Name, upstream) {
+			filtered.Clusters = append(filtered.Clusters, cluster)
+		}
+	}
+
+	return filtered
+}
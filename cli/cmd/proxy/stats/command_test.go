@@ -0,0 +1,283 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package stats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	"github.com/hashicorp/consul-k8s/cli/common/envoy"
+	cmnFlag "github.com/hashicorp/consul-k8s/cli/common/flag"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestFlagParsing(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+		out  int
+	}{
+		"No args": {
+			args: []string{},
+			out:  1,
+		},
+		"Multiple podnames passed": {
+			args: []string{"podname", "podname2"},
+			out:  1,
+		},
+		"Nonexistent flag passed, -foo bar": {
+			args: []string{"podName", "-foo", "bar"},
+			out:  1,
+		},
+		"Invalid argument passed, -namespace YOLO": {
+			args: []string{"podName", "-namespace", "YOLO"},
+			out:  1,
+		},
+		"User passed incorrect output": {
+			args: []string{"podName", "-output", "raw"},
+			out:  1,
+		},
+		"User passed negative watch interval": {
+			args: []string{"podName", "-watch", "-1s"},
+			out:  1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := setupCommand(new(bytes.Buffer))
+			c.kubernetes = fake.NewSimpleClientset()
+
+			out := c.Run(tc.args)
+			require.Equal(t, tc.out, out)
+		})
+	}
+}
+
+func TestStatsCommandOutput(t *testing.T) {
+	podName := "fakePod"
+
+	cases := map[string]struct {
+		args       []string
+		expected   []string
+		unexpected []string
+	}{
+		"No filters": {
+			args: []string{},
+			expected: []string{
+				fmt.Sprintf("Envoy statistics for %s in namespace default:", podName),
+				"==> Clusters \\(2\\)",
+				"Name.*Requests.*5xx.*Active Connections.*Retries.*Circuit Breaker Trips.*Outlier Ejections",
+				"backend\\.default\\.dc1.*120.*3.*4.*5.*3.*2",
+				"local_app.*80.*0.*1.*0.*0.*0",
+				"==> Listeners \\(1\\)",
+				"Name.*Connections.*Active Connections.*Requests.*5xx",
+				"0\\.0\\.0\\.0_20000.*10.*2.*80.*1",
+			},
+		},
+		"Upstream filter": {
+			args: []string{"-upstream", "backend"},
+			expected: []string{
+				"Cluster names containing: backend",
+				"==> Clusters \\(1\\)",
+				"backend\\.default\\.dc1",
+			},
+			unexpected: []string{
+				"local_app",
+			},
+		},
+	}
+
+	fakePod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podName,
+			Namespace: "default",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			c := setupCommand(buf)
+			c.kubernetes = fake.NewSimpleClientset(&v1.PodList{Items: []v1.Pod{fakePod}})
+			c.fetchStats = func(context.Context, common.PortForwarder) (*envoy.ProxyStats, error) {
+				return testProxyStats(), nil
+			}
+
+			out := c.Run(append([]string{podName}, tc.args...))
+			require.Equal(t, 0, out)
+
+			actual := buf.String()
+			for _, expression := range tc.expected {
+				require.Regexp(t, expression, actual)
+			}
+			for _, expression := range tc.unexpected {
+				require.NotRegexp(t, expression, actual)
+			}
+		})
+	}
+}
+
+func TestStatsCommandJSONOutput(t *testing.T) {
+	podName := "fakePod"
+	fakePod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podName,
+			Namespace: "default",
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	c.kubernetes = fake.NewSimpleClientset(&v1.PodList{Items: []v1.Pod{fakePod}})
+	c.fetchStats = func(context.Context, common.PortForwarder) (*envoy.ProxyStats, error) {
+		return testProxyStats(), nil
+	}
+
+	out := c.Run([]string{podName, "-output", "json"})
+	require.Equal(t, 0, out)
+
+	var actual map[string]struct {
+		Clusters  []envoy.ClusterStats
+		Listeners []envoy.ListenerStats
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &actual))
+	require.Equal(t, testProxyStats().Clusters, actual[podName].Clusters)
+	require.Equal(t, testProxyStats().Listeners, actual[podName].Listeners)
+}
+
+func TestComputeRates(t *testing.T) {
+	previous := testProxyStats()
+	current := testProxyStats()
+	current.Clusters[0].RequestsTotal += 50
+	current.Clusters[0].Requests5xx += 10
+	current.Clusters[0].Retries += 5
+	// A counter that was reset has a rate of 0.
+	current.Clusters[1].RequestsTotal = 0
+	current.Listeners[0].ConnectionsTotal += 20
+	current.Listeners[0].RequestsTotal += 40
+	current.Clusters = append(current.Clusters, envoy.ClusterStats{Name: "new", RequestsTotal: 100})
+
+	rates := computeRates(previous, current, 10*time.Second)
+
+	require.Equal(t, &statsRates{
+		Clusters: map[string]clusterRates{
+			"backend.default.dc1.internal.bc3815c2-1a0f-f3ff-a2e9-20d791f08d00.consul": {Requests: 5, Requests5xx: 1, Retries: 0.5},
+			"local_app": {},
+			"new":       {},
+		},
+		Listeners: map[string]listenerRates{
+			"0.0.0.0_20000": {Connections: 2, Requests: 4},
+		},
+	}, rates)
+}
+
+func TestSortedNames(t *testing.T) {
+	stats := map[string]*envoy.ProxyStats{
+		"web-admin": testProxyStats(),
+		"api":       testProxyStats(),
+		"web":       testProxyStats(),
+	}
+
+	for i := 0; i < 10; i++ {
+		require.Equal(t, []string{"api", "web", "web-admin"}, sortedNames(stats))
+	}
+}
+
+func setupCommand(buf io.Writer) *StatsCommand {
+	// Log at a test level to standard out.
+	log := hclog.New(&hclog.LoggerOptions{
+		Name:   "test",
+		Level:  hclog.Debug,
+		Output: os.Stdout,
+	})
+
+	// Setup and initialize the command struct
+	command := &StatsCommand{
+		BaseCommand: &common.BaseCommand{
+			Log: log,
+			UI:  terminal.NewUI(context.Background(), buf),
+		},
+	}
+	command.init()
+
+	return command
+}
+
+func TestStatsCommand_AutocompleteFlags(t *testing.T) {
+	t.Parallel()
+	buf := new(bytes.Buffer)
+	cmd := setupCommand(buf)
+
+	predictor := cmd.AutocompleteFlags()
+
+	// Test that we get the expected number of predictions
+	args := complete.Args{Last: "-"}
+	res := predictor.Predict(args)
+
+	// Grab the list of flags from the Flag object
+	flags := make([]string, 0)
+	cmd.set.VisitSets(func(name string, set *cmnFlag.Set) {
+		set.VisitAll(func(flag *flag.Flag) {
+			flags = append(flags, fmt.Sprintf("-%s", flag.Name))
+		})
+	})
+
+	// Verify that there is a prediction for each flag associated with the command
+	assert.Equal(t, len(flags), len(res))
+	assert.ElementsMatch(t, flags, res, "flags and predictions didn't match, make sure to add "+
+		"new flags to the command AutoCompleteFlags function")
+}
+
+func TestStatsCommand_AutocompleteArgs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := setupCommand(buf)
+	c := cmd.AutocompleteArgs()
+	assert.Equal(t, complete.PredictNothing, c)
+}
+
+func testProxyStats() *envoy.ProxyStats {
+	return &envoy.ProxyStats{
+		Clusters: []envoy.ClusterStats{
+			{
+				Name:                "backend.default.dc1.internal.bc3815c2-1a0f-f3ff-a2e9-20d791f08d00.consul",
+				RequestsTotal:       120,
+				Requests5xx:         3,
+				ConnectionsActive:   4,
+				Retries:             5,
+				CircuitBreakerTrips: 3,
+				OutlierEjections:    2,
+			},
+			{
+				Name:              "local_app",
+				RequestsTotal:     80,
+				ConnectionsActive: 1,
+			},
+		},
+		Listeners: []envoy.ListenerStats{
+			{
+				Name:              "0.0.0.0_20000",
+				ConnectionsTotal:  10,
+				ConnectionsActive: 2,
+				RequestsTotal:     80,
+				Requests5xx:       1,
+			},
+		},
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package stats
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/consul-k8s/cli/common/envoy"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+)
+
+// statsRates holds the per second rates of the counters of each cluster and
+// listener between two fetches of the statistics, keyed by name.
+type statsRates struct {
+	Clusters  map[string]clusterRates
+	Listeners map[string]listenerRates
+}
+
+type clusterRates struct {
+	Requests    float64
+	Requests5xx float64
+	Retries     float64
+}
+
+type listenerRates struct {
+	Connections float64
+	Requests    float64
+}
+
+// computeRates computes the rates of the counters in current since previous,
+// which was fetched the given elapsed time earlier. Counters which are new or
+// were reset since the previous fetch have a rate of 0.
+func computeRates(previous, current *envoy.ProxyStats, elapsed time.Duration) *statsRates {
+	rates := &statsRates{
+		Clusters:  make(map[string]clusterRates),
+		Listeners: make(map[string]listenerRates),
+	}
+
+	previousClusters := make(map[string]envoy.ClusterStats)
+	previousListeners := make(map[string]envoy.ListenerStats)
+	if previous != nil {
+		for _, cluster := range previous.Clusters {
+			previousClusters[cluster.Name] = cluster
+		}
+		for _, listener := range previous.Listeners {
+			previousListeners[listener.Name] = listener
+		}
+	}
+
+	for _, cluster := range current.Clusters {
+		prev, ok := previousClusters[cluster.Name]
+		if !ok {
+			rates.Clusters[cluster.Name] = clusterRates{}
+			continue
+		}
+		rates.Clusters[cluster.Name] = clusterRates{
+			Requests:    rate(prev.RequestsTotal, cluster.RequestsTotal, elapsed),
+			Requests5xx: rate(prev.Requests5xx, cluster.Requests5xx, elapsed),
+			Retries:     rate(prev.Retries, cluster.Retries, elapsed),
+		}
+	}
+
+	for _, listener := range current.Listeners {
+		prev, ok := previousListeners[listener.Name]
+		if !ok {
+			rates.Listeners[listener.Name] = listenerRates{}
+			continue
+		}
+		rates.Listeners[listener.Name] = listenerRates{
+			Connections: rate(prev.ConnectionsTotal, listener.ConnectionsTotal, elapsed),
+			Requests:    rate(prev.RequestsTotal, listener.RequestsTotal, elapsed),
+		}
+	}
+
+	return rates
+}
+
+func rate(previous, current uint64, elapsed time.Duration) float64 {
+	if current < previous || elapsed <= 0 {
+		return 0
+	}
+	return float64(current-previous) / elapsed.Seconds()
+}
+
+func formatClusterStats(clusters []envoy.ClusterStats, rates *statsRates) *terminal.Table {
+	headers := []string{"Name", "Requests", "5xx", "Active Connections", "Retries", "Circuit Breaker Trips", "Outlier Ejections"}
+	if rates != nil {
+		headers = append(headers, "Requests/s", "5xx/s", "Retries/s")
+	}
+
+	table := terminal.NewTable(headers...)
+	for _, cluster := range clusters {
+		row := []string{
+			cluster.Name,
+			fmt.Sprint(cluster.RequestsTotal),
+			fmt.Sprint(cluster.Requests5xx),
+			fmt.Sprint(cluster.ConnectionsActive),
+			fmt.Sprint(cluster.Retries),
+			fmt.Sprint(cluster.CircuitBreakerTrips),
+			fmt.Sprint(cluster.OutlierEjections),
+		}
+		if rates != nil {
+			r := rates.Clusters[cluster.Name]
+			row = append(row, fmt.Sprintf("%.2f", r.Requests), fmt.Sprintf("%.2f", r.Requests5xx), fmt.Sprintf("%.2f", r.Retries))
+		}
+
+		var color string
+		if cluster.Requests5xx > 0 || cluster.CircuitBreakerTrips > 0 || cluster.OutlierEjections > 0 {
+			color = terminal.Yellow
+		}
+		colors := make([]string, len(row))
+		for i := range colors {
+			colors[i] = color
+		}
+
+		table.AddRow(row, colors)
+	}
+
+	return table
+}
+
+func formatListenerStats(listeners []envoy.ListenerStats, rates *statsRates) *terminal.Table {
+	headers := []string{"Name", "Connections", "Active Connections", "Requests", "5xx"}
+	if rates != nil {
+		headers = append(headers, "Connections/s", "Requests/s")
+	}
+
+	table := terminal.NewTable(headers...)
+	for _, listener := range listeners {
+		row := []string{
+			listener.Name,
+			fmt.Sprint(listener.ConnectionsTotal),
+			fmt.Sprint(listener.ConnectionsActive),
+			fmt.Sprint(listener.RequestsTotal),
+			fmt.Sprint(listener.Requests5xx),
+		}
+		if rates != nil {
+			r := rates.Listeners[listener.Name]
+			row = append(row, fmt.Sprintf("%.2f", r.Connections), fmt.Sprintf("%.2f", r.Requests))
+		}
+
+		table.AddRow(row, []string{})
+	}
+
+	return table
+}
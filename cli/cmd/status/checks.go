@@ -0,0 +1,379 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package status
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// consulGroupSuffix is the suffix of the API groups of Consul custom resources.
+	consulGroupSuffix = "consul.hashicorp.com"
+
+	// gatewayControllerName is the controller name of the GatewayClasses
+	// managed by Consul.
+	gatewayControllerName = "consul.hashicorp.com/gateway-controller"
+
+	// dataplaneContainer is the name of the container injected into Pods to
+	// run the Consul dataplane. Pods running multiple services have one
+	// container per service, named after the service.
+	dataplaneContainer = "consul-dataplane"
+
+	conditionSynced     = "Synced"
+	conditionProgrammed = "Programmed"
+)
+
+var (
+	peeringResources = map[string]bool{"peeringacceptors": true, "peeringdialers": true}
+
+	gatewayClassesResource = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "gatewayclasses"}
+	gatewaysResource       = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "gateways"}
+)
+
+// deploymentStatus is the health of a Deployment of the Consul control plane.
+type deploymentStatus struct {
+	Name    string `json:"name"`
+	Desired int    `json:"desired"`
+	Ready   int    `json:"ready"`
+}
+
+func (d deploymentStatus) healthy() bool {
+	return d.Ready >= d.Desired
+}
+
+// webhookStatus is the validity of the CA bundle of a webhook of the Consul
+// control plane.
+type webhookStatus struct {
+	Configuration string     `json:"configuration"`
+	Webhook       string     `json:"webhook"`
+	Expires       *time.Time `json:"expires,omitempty"`
+	Problem       string     `json:"problem,omitempty"`
+}
+
+// resourceStatus is the Synced condition of a Consul custom resource.
+type resourceStatus struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Synced    string `json:"synced"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// gatewayStatus is the Programmed condition of an API gateway.
+type gatewayStatus struct {
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	Programmed string `json:"programmed"`
+	Reason     string `json:"reason,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// dataplaneStatus counts the Pods injected with a Consul dataplane and lists
+// those whose dataplane is not ready.
+type dataplaneStatus struct {
+	Injected int      `json:"injected"`
+	NotReady []string `json:"notReady"`
+}
+
+// checkDeployments returns the health of the Deployments of the Helm release.
+func (c *Command) checkDeployments(releaseName, namespace string) ([]deploymentStatus, error) {
+	deployments, err := c.kubernetes.AppsV1().Deployments(namespace).List(c.Ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=consul,release=%s", releaseName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]deploymentStatus, 0, len(deployments.Items))
+	for _, deployment := range deployments.Items {
+		desired := 1
+		if deployment.Spec.Replicas != nil {
+			desired = int(*deployment.Spec.Replicas)
+		}
+		statuses = append(statuses, deploymentStatus{
+			Name:    deployment.Name,
+			Desired: desired,
+			Ready:   int(deployment.Status.ReadyReplicas),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	return statuses, nil
+}
+
+// checkWebhooks checks that the webhooks of the Helm release have a CA bundle
+// holding certificates which are currently valid. Without it, the Kubernetes
+// API server cannot call the webhooks and rejects Pods and custom resources.
+func (c *Command) checkWebhooks(releaseName string, now time.Time) ([]webhookStatus, error) {
+	selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("app=consul,release=%s", releaseName)}
+
+	var statuses []webhookStatus
+	mutating, err := c.kubernetes.AdmissionregistrationV1().MutatingWebhookConfigurations().List(c.Ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range mutating.Items {
+		for _, webhook := range config.Webhooks {
+			statuses = append(statuses, newWebhookStatus(config.Name, webhook.Name, webhook.ClientConfig.CABundle, now))
+		}
+	}
+
+	validating, err := c.kubernetes.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(c.Ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range validating.Items {
+		for _, webhook := range config.Webhooks {
+			statuses = append(statuses, newWebhookStatus(config.Name, webhook.Name, webhook.ClientConfig.CABundle, now))
+		}
+	}
+
+	return statuses, nil
+}
+
+// newWebhookStatus checks the certificates of a CA bundle. The earliest expiry
+// of the certificates is reported since the bundle is invalid after it.
+func newWebhookStatus(configuration, webhook string, caBundle []byte, now time.Time) webhookStatus {
+	status := webhookStatus{Configuration: configuration, Webhook: webhook}
+	if len(caBundle) == 0 {
+		status.Problem = "CA bundle is empty"
+		return status
+	}
+
+	var certs []*x509.Certificate
+	for rest := caBundle; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			status.Problem = fmt.Sprintf("CA bundle holds an invalid certificate: %v", err)
+			return status
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		status.Problem = "CA bundle holds no certificates"
+		return status
+	}
+
+	for _, cert := range certs {
+		if status.Expires == nil || cert.NotAfter.Before(*status.Expires) {
+			notAfter := cert.NotAfter
+			status.Expires = &notAfter
+		}
+		switch {
+		case now.After(cert.NotAfter):
+			status.Problem = fmt.Sprintf("CA certificate expired at %s", cert.NotAfter.Format(time.RFC3339))
+		case now.Before(cert.NotBefore):
+			status.Problem = fmt.Sprintf("CA certificate is not valid before %s", cert.NotBefore.Format(time.RFC3339))
+		}
+	}
+
+	return status
+}
+
+// checkCustomResources returns the Consul custom resources which failed to sync
+// to Consul and the status of all peerings. A resource failed to sync if its
+// Synced condition is False.
+func (c *Command) checkCustomResources() ([]resourceStatus, []resourceStatus, error) {
+	// Partial results are returned when some API groups cannot be discovered.
+	_, resourceLists, err := c.kubernetes.Discovery().ServerGroupsAndResources()
+	if err != nil && len(resourceLists) == 0 {
+		return nil, nil, err
+	}
+
+	var failures []resourceStatus
+	peerings := []resourceStatus{}
+	seen := make(map[schema.GroupResource]bool)
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil || !strings.HasSuffix(gv.Group, consulGroupSuffix) {
+			continue
+		}
+
+		for _, resource := range resourceList.APIResources {
+			gr := schema.GroupResource{Group: gv.Group, Resource: resource.Name}
+			// Skip subresources such as status and resources served by multiple versions.
+			if strings.Contains(resource.Name, "/") || seen[gr] {
+				continue
+			}
+			seen[gr] = true
+
+			list, err := c.dynamic.Resource(gv.WithResource(resource.Name)).List(c.Ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to list %s: %v", gr, err)
+			}
+
+			for _, item := range list.Items {
+				status := newResourceStatus(item)
+				if peeringResources[resource.Name] {
+					peerings = append(peerings, status)
+				}
+				if status.Synced == string(corev1.ConditionFalse) {
+					failures = append(failures, status)
+				}
+			}
+		}
+	}
+
+	return failures, peerings, nil
+}
+
+// newResourceStatus reads the Synced condition of a Consul custom resource.
+// Resources which have not been reconciled yet have no Synced condition.
+func newResourceStatus(item unstructured.Unstructured) resourceStatus {
+	status := resourceStatus{
+		Kind:      item.GetKind(),
+		Namespace: item.GetNamespace(),
+		Name:      item.GetName(),
+		Synced:    string(corev1.ConditionUnknown),
+	}
+	if condition := findCondition(item, conditionSynced); condition != nil {
+		status.Synced, status.Reason, status.Message = condition.status, condition.reason, condition.message
+	}
+	return status
+}
+
+// checkGateways returns the Programmed condition of the Gateways managed by
+// Consul. No Gateways are returned if the Gateway API is not installed.
+func (c *Command) checkGateways() ([]gatewayStatus, error) {
+	classes, err := c.dynamic.Resource(gatewayClassesResource).List(c.Ctx, metav1.ListOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	consulClasses := make(map[string]bool)
+	for _, class := range classes.Items {
+		controllerName, _, _ := unstructured.NestedString(class.Object, "spec", "controllerName")
+		if controllerName == gatewayControllerName {
+			consulClasses[class.GetName()] = true
+		}
+	}
+	if len(consulClasses) == 0 {
+		return nil, nil
+	}
+
+	gateways, err := c.dynamic.Resource(gatewaysResource).List(c.Ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []gatewayStatus
+	for _, gateway := range gateways.Items {
+		className, _, _ := unstructured.NestedString(gateway.Object, "spec", "gatewayClassName")
+		if !consulClasses[className] {
+			continue
+		}
+
+		status := gatewayStatus{
+			Namespace:  gateway.GetNamespace(),
+			Name:       gateway.GetName(),
+			Programmed: string(corev1.ConditionUnknown),
+		}
+		if condition := findCondition(gateway, conditionProgrammed); condition != nil {
+			status.Programmed, status.Reason, status.Message = condition.status, condition.reason, condition.message
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Namespace != statuses[j].Namespace {
+			return statuses[i].Namespace < statuses[j].Namespace
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses, nil
+}
+
+// checkDataplanes counts the Pods injected with a Consul dataplane across all
+// namespaces and lists those which are running but whose dataplane is not ready.
+func (c *Command) checkDataplanes() (dataplaneStatus, error) {
+	status := dataplaneStatus{NotReady: []string{}}
+
+	pods, err := c.kubernetes.CoreV1().Pods(metav1.NamespaceAll).List(c.Ctx, metav1.ListOptions{
+		LabelSelector: "consul.hashicorp.com/connect-inject-status=injected",
+	})
+	if err != nil {
+		return status, err
+	}
+
+	for _, pod := range pods.Items {
+		// Completed Pods have stopped their dataplane on purpose.
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		status.Injected++
+		if !dataplaneReady(pod) {
+			status.NotReady = append(status.NotReady, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
+	}
+	sort.Strings(status.NotReady)
+
+	return status, nil
+}
+
+// dataplaneReady returns true if the Pod has dataplane containers and all of
+// them are ready. The dataplane can run as a regular or an init container.
+func dataplaneReady(pod corev1.Pod) bool {
+	found := false
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.Name != dataplaneContainer && !strings.HasPrefix(status.Name, dataplaneContainer+"-") {
+			continue
+		}
+		found = true
+		if !status.Ready {
+			return false
+		}
+	}
+	return found
+}
+
+type condition struct {
+	status  string
+	reason  string
+	message string
+}
+
+// findCondition returns the condition of the given type from the status of a
+// custom resource.
+func findCondition(item unstructured.Unstructured, conditionType string) *condition {
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != conditionType {
+			continue
+		}
+		found := &condition{status: string(corev1.ConditionUnknown)}
+		if status, ok := cond["status"].(string); ok {
+			found.status = status
+		}
+		found.reason, _ = cond["reason"].(string)
+		found.message, _ = cond["message"].(string)
+		return found
+	}
+	return nil
+}
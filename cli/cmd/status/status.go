@@ -4,14 +4,17 @@
 package status
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/posener/complete"
 	"helm.sh/helm/v3/pkg/release"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 
 	"github.com/hashicorp/consul-k8s/cli/common"
 	"github.com/hashicorp/consul-k8s/cli/common/flag"
@@ -24,8 +27,16 @@ import (
 )
 
 const (
+	Table = "table"
+	JSON  = "json"
+
+	flagNameOutput      = "output"
 	flagNameKubeConfig  = "kubeconfig"
 	flagNameKubeContext = "context"
+
+	// exitCodeDegraded is returned when the installation was checked and is
+	// not healthy, as opposed to 1 when it could not be checked.
+	exitCodeDegraded = 2
 )
 
 type Command struct {
@@ -34,9 +45,12 @@ type Command struct {
 	helmActionsRunner helm.HelmActionsRunner
 
 	kubernetes kubernetes.Interface
+	dynamic    dynamic.Interface
 
 	set *flag.Sets
 
+	flagOutput string
+
 	flagKubeConfig  string
 	flagKubeContext string
 
@@ -47,7 +61,16 @@ type Command struct {
 func (c *Command) init() {
 	c.set = flag.NewSets()
 
-	f := c.set.NewSet("Global Options")
+	f := c.set.NewSet("Command Options")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameOutput,
+		Target:  &c.flagOutput,
+		Default: Table,
+		Usage:   "Output the status as 'table' or 'json'.",
+		Aliases: []string{"o"},
+	})
+
+	f = c.set.NewSet("Global Options")
 	f.StringVar(&flag.StringVar{
 		Name:    "kubeconfig",
 		Aliases: []string{"c"},
@@ -65,7 +88,53 @@ func (c *Command) init() {
 	c.help = c.set.Help()
 }
 
-// Run checks the status of a Consul installation on Kubernetes.
+// statusReport is the status of a Consul installation on Kubernetes. The
+// installation is degraded if any problems were found.
+type statusReport struct {
+	Release      releaseStatus      `json:"release"`
+	Servers      *serverStatus      `json:"servers,omitempty"`
+	Deployments  []deploymentStatus `json:"deployments"`
+	Webhooks     []webhookStatus    `json:"webhooks"`
+	SyncFailures []resourceStatus   `json:"syncFailures"`
+	Peerings     []resourceStatus   `json:"peerings"`
+	Gateways     []gatewayStatus    `json:"gateways"`
+	Dataplanes   dataplaneStatus    `json:"dataplanes"`
+	Healthy      bool               `json:"healthy"`
+	Problems     []string           `json:"problems"`
+}
+
+// releaseStatus is the status of the Helm release of Consul.
+type releaseStatus struct {
+	Name         string                 `json:"name"`
+	Namespace    string                 `json:"namespace"`
+	Status       string                 `json:"status"`
+	ChartVersion string                 `json:"chartVersion"`
+	AppVersion   string                 `json:"appVersion"`
+	Revision     int                    `json:"revision"`
+	LastDeployed time.Time              `json:"lastDeployed"`
+	Config       map[string]interface{} `json:"config"`
+	Hooks        []hookStatus           `json:"hooks"`
+
+	// hookCount is the number of hooks of the release, including those which
+	// are not reported.
+	hookCount int
+}
+
+// hookStatus is the phase of the last run of a Helm hook.
+type hookStatus struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Phase string `json:"phase"`
+}
+
+// serverStatus is the number of Consul servers which are ready.
+type serverStatus struct {
+	Desired int `json:"desired"`
+	Ready   int `json:"ready"`
+}
+
+// Run checks the status of a Consul installation on Kubernetes. It returns 1 if
+// the installation could not be checked and 2 if it is degraded.
 func (c *Command) Run(args []string) int {
 	c.once.Do(c.init)
 	if c.helmActionsRunner == nil {
@@ -99,13 +168,21 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
-	// Setup logger to stream Helm library logs.
+	// Setup logger to stream Helm library logs. They are only logged at debug
+	// level with JSON output so that the output can be parsed.
 	var uiLogger = func(s string, args ...interface{}) {
 		logMsg := fmt.Sprintf(s, args...)
 		c.UI.Output(logMsg, terminal.WithLibraryStyle())
 	}
+	if c.flagOutput == JSON {
+		uiLogger = func(s string, args ...interface{}) {
+			c.Log.Debug(fmt.Sprintf(s, args...))
+		}
+	}
 
-	c.UI.Output("Consul Status Summary", terminal.WithHeaderStyle())
+	if c.flagOutput == Table {
+		c.UI.Output("Consul Status Summary", terminal.WithHeaderStyle())
+	}
 
 	_, releaseName, namespace, err := c.helmActionsRunner.CheckForInstallations(&helm.CheckForInstallationsOptions{
 		Settings:    settings,
@@ -117,16 +194,25 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
-	if err := c.checkHelmInstallation(settings, uiLogger, releaseName, namespace); err != nil {
+	report, err := c.checkStatus(settings, uiLogger, releaseName, namespace)
+	if err != nil {
 		c.UI.Output(err.Error(), terminal.WithErrorStyle())
 		return 1
 	}
 
-	if err := c.checkConsulServers(namespace); err != nil {
-		c.UI.Output("Unable to check Kubernetes cluster for Consul servers: %v", err)
-		return 1
+	switch c.flagOutput {
+	case Table:
+		c.outputTable(report)
+	case JSON:
+		if err := c.outputJSON(report); err != nil {
+			c.UI.Output(err.Error(), terminal.WithErrorStyle())
+			return 1
+		}
 	}
 
+	if !report.Healthy {
+		return exitCodeDegraded
+	}
 	return 0
 }
 
@@ -135,6 +221,9 @@ func (c *Command) validateFlags() error {
 	if len(c.set.Args()) > 0 {
 		return errors.New("should have no non-flag arguments")
 	}
+	if c.flagOutput != Table && c.flagOutput != JSON {
+		return fmt.Errorf("-output must be one of %s, %s", Table, JSON)
+	}
 	return nil
 }
 
@@ -143,6 +232,7 @@ func (c *Command) validateFlags() error {
 // complete flag such as "-foo" or "--foo".
 func (c *Command) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
+		fmt.Sprintf("-%s", flagNameOutput):      complete.PredictNothing,
 		fmt.Sprintf("-%s", flagNameKubeConfig):  complete.PredictFiles("*"),
 		fmt.Sprintf("-%s", flagNameKubeContext): complete.PredictNothing,
 	}
@@ -155,99 +245,334 @@ func (c *Command) AutocompleteArgs() complete.Predictor {
 	return complete.PredictNothing
 }
 
-// checkHelmInstallation uses the helm Go SDK to depict the status of a named release. This function then prints
-// the version of the release, it's status (unknown, deployed, uninstalled, ...), and the overwritten values.
-func (c *Command) checkHelmInstallation(settings *helmCLI.EnvSettings, uiLogger action.DebugLog, releaseName, namespace string) error {
+// checkStatus checks the Helm release and the Consul servers, which must
+// succeed, and then the rest of the installation. Checks of the rest of the
+// installation which cannot be completed are reported as problems.
+func (c *Command) checkStatus(settings *helmCLI.EnvSettings, uiLogger action.DebugLog, releaseName, namespace string) (*statusReport, error) {
+	report := &statusReport{
+		Deployments:  []deploymentStatus{},
+		Webhooks:     []webhookStatus{},
+		SyncFailures: []resourceStatus{},
+		Peerings:     []resourceStatus{},
+		Gateways:     []gatewayStatus{},
+		Problems:     []string{},
+	}
+	problem := func(format string, args ...interface{}) {
+		report.Problems = append(report.Problems, fmt.Sprintf(format, args...))
+	}
+
+	rel, err := c.checkHelmInstallation(settings, uiLogger, releaseName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	report.Release = *rel
+	if status := release.Status(rel.Status); status == release.StatusFailed || status.IsPending() {
+		problem("Helm release %s is %s", releaseName, status)
+	}
+
+	servers, err := c.checkConsulServers(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to check Kubernetes cluster for Consul servers: %v", err)
+	}
+	report.Servers = servers
+	if servers != nil && servers.Ready < servers.Desired {
+		problem("%d of %d Consul servers are ready", servers.Ready, servers.Desired)
+	}
+
+	if deployments, err := c.checkDeployments(releaseName, namespace); err != nil {
+		problem("Unable to check control plane Deployments: %v", err)
+	} else {
+		report.Deployments = deployments
+		for _, deployment := range deployments {
+			if !deployment.healthy() {
+				problem("Deployment %s has %d of %d replicas ready", deployment.Name, deployment.Ready, deployment.Desired)
+			}
+		}
+	}
+
+	if webhooks, err := c.checkWebhooks(releaseName, time.Now()); err != nil {
+		problem("Unable to check webhook configurations: %v", err)
+	} else {
+		report.Webhooks = webhooks
+		for _, webhook := range webhooks {
+			if webhook.Problem != "" {
+				problem("Webhook %s of %s: %s", webhook.Webhook, webhook.Configuration, webhook.Problem)
+			}
+		}
+	}
+
+	if failures, peerings, err := c.checkCustomResources(); err != nil {
+		problem("Unable to check Consul custom resources: %v", err)
+	} else {
+		report.SyncFailures, report.Peerings = failures, peerings
+		for _, failure := range failures {
+			problem("%s %s/%s failed to sync: %s", failure.Kind, failure.Namespace, failure.Name, failure.Message)
+		}
+	}
+
+	if gateways, err := c.checkGateways(); err != nil {
+		problem("Unable to check API gateways: %v", err)
+	} else if gateways != nil {
+		report.Gateways = gateways
+		for _, gateway := range gateways {
+			if gateway.Programmed != "True" {
+				problem("Gateway %s/%s is not programmed: %s", gateway.Namespace, gateway.Name, gateway.Message)
+			}
+		}
+	}
+
+	if dataplanes, err := c.checkDataplanes(); err != nil {
+		problem("Unable to check injected Pods: %v", err)
+	} else {
+		report.Dataplanes = dataplanes
+		if len(dataplanes.NotReady) > 0 {
+			problem("%d of %d injected Pods do not have a ready dataplane", len(dataplanes.NotReady), dataplanes.Injected)
+		}
+	}
+
+	report.Healthy = len(report.Problems) == 0
+	return report, nil
+}
+
+// checkHelmInstallation uses the helm Go SDK to get the status of a named release: the version of the release,
+// it's status (unknown, deployed, uninstalled, ...), the overwritten values and the status of its hooks.
+func (c *Command) checkHelmInstallation(settings *helmCLI.EnvSettings, uiLogger action.DebugLog, releaseName, namespace string) (*releaseStatus, error) {
 	// Need a specific action config to call helm status, where namespace comes from the previous call to list.
 	statusConfig := new(action.Configuration)
 	statusConfig, err := helm.InitActionConfig(statusConfig, namespace, settings, uiLogger)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	statuser := action.NewStatus(statusConfig)
 	rel, err := c.helmActionsRunner.GetStatus(statuser, releaseName)
 	if err != nil {
-		return fmt.Errorf("couldn't check for installations: %s", err)
+		return nil, fmt.Errorf("couldn't check for installations: %s", err)
 	}
 
-	timezone, _ := rel.Info.LastDeployed.Zone()
+	status := &releaseStatus{
+		Name:         releaseName,
+		Namespace:    namespace,
+		Status:       string(rel.Info.Status),
+		ChartVersion: rel.Chart.Metadata.Version,
+		AppVersion:   rel.Chart.Metadata.AppVersion,
+		Revision:     rel.Version,
+		LastDeployed: rel.Info.LastDeployed.Time,
+		Config:       rel.Config,
+		Hooks:        []hookStatus{},
+		hookCount:    len(rel.Hooks),
+	}
+	for _, hook := range rel.Hooks {
+		// Remember that we only report the status of pre-install or pre-upgrade hooks.
+		if validEvent(hook.Events) {
+			status.Hooks = append(status.Hooks, hookStatus{Name: hook.Name, Kind: hook.Kind, Phase: hook.LastRun.Phase.String()})
+		}
+	}
+
+	return status, nil
+}
+
+// validEvent is a helper function that checks if the given hook's events are pre-install or pre-upgrade.
+// Only pre-install and pre-upgrade hooks are expected to have run when using the status command against
+// a running installation.
+func validEvent(events []release.HookEvent) bool {
+	for _, event := range events {
+		if event.String() == "pre-install" || event.String() == "pre-upgrade" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConsulServers returns the status of Consul servers if they
+// are expected to be found in the Kubernetes cluster. It does not check for
+// server status if they are not running within the Kubernetes cluster.
+func (c *Command) checkConsulServers(namespace string) (*serverStatus, error) {
+	servers, err := c.kubernetes.AppsV1().StatefulSets(namespace).List(c.Ctx, metav1.ListOptions{LabelSelector: "app=consul,chart=consul-helm,component=server"})
+	if err != nil {
+		return nil, err
+	}
+	if len(servers.Items) == 0 {
+		return nil, nil
+	}
+
+	return &serverStatus{
+		Desired: int(*servers.Items[0].Spec.Replicas),
+		Ready:   int(servers.Items[0].Status.ReadyReplicas),
+	}, nil
+}
+
+// outputTable prints the status as tables to the terminal.
+func (c *Command) outputTable(report *statusReport) {
+	c.outputRelease(report.Release)
+	if report.Servers != nil {
+		c.outputServers(*report.Servers)
+	}
+
+	if len(report.Deployments) > 0 {
+		c.UI.Output("Control Plane Deployments:", terminal.WithHeaderStyle())
+		tbl := terminal.NewTable("Name", "Ready")
+		for _, deployment := range report.Deployments {
+			color := ""
+			if !deployment.healthy() {
+				color = terminal.Red
+			}
+			tbl.AddRow([]string{deployment.Name, fmt.Sprintf("%d/%d", deployment.Ready, deployment.Desired)}, []string{color, color})
+		}
+		c.UI.Table(tbl)
+	}
+
+	if len(report.Webhooks) > 0 {
+		c.UI.Output("Webhook Certificates:", terminal.WithHeaderStyle())
+		tbl := terminal.NewTable("Configuration", "Webhook", "Expires", "Problem")
+		for _, webhook := range report.Webhooks {
+			var expires, color string
+			if webhook.Expires != nil {
+				expires = webhook.Expires.Format("2006/01/02 15:04:05 MST")
+			}
+			if webhook.Problem != "" {
+				color = terminal.Red
+			}
+			tbl.AddRow([]string{webhook.Configuration, webhook.Webhook, expires, webhook.Problem}, []string{color, color, color, color})
+		}
+		c.UI.Table(tbl)
+	}
+
+	if len(report.SyncFailures) > 0 {
+		c.UI.Output("Custom Resources Failing To Sync:", terminal.WithHeaderStyle())
+		tbl := terminal.NewTable("Kind", "Namespace", "Name", "Reason", "Message")
+		for _, failure := range report.SyncFailures {
+			tbl.AddRow([]string{failure.Kind, failure.Namespace, failure.Name, failure.Reason, failure.Message}, []string{})
+		}
+		c.UI.Table(tbl)
+	}
+
+	if len(report.Peerings) > 0 {
+		c.UI.Output("Peerings:", terminal.WithHeaderStyle())
+		tbl := terminal.NewTable("Kind", "Namespace", "Name", "Synced", "Message")
+		for _, peering := range report.Peerings {
+			tbl.AddRow([]string{peering.Kind, peering.Namespace, peering.Name, peering.Synced, peering.Message}, syncedColors(peering.Synced, 5))
+		}
+		c.UI.Table(tbl)
+	}
+
+	if len(report.Gateways) > 0 {
+		c.UI.Output("API Gateways:", terminal.WithHeaderStyle())
+		tbl := terminal.NewTable("Namespace", "Name", "Programmed", "Message")
+		for _, gateway := range report.Gateways {
+			tbl.AddRow([]string{gateway.Namespace, gateway.Name, gateway.Programmed, gateway.Message}, syncedColors(gateway.Programmed, 4))
+		}
+		c.UI.Table(tbl)
+	}
+
+	if report.Dataplanes.Injected > 0 {
+		c.UI.Output("Dataplanes:", terminal.WithHeaderStyle())
+		ready := report.Dataplanes.Injected - len(report.Dataplanes.NotReady)
+		c.UI.Output(fmt.Sprintf("Injected Pods with a ready dataplane %d/%d", ready, report.Dataplanes.Injected))
+		for _, pod := range report.Dataplanes.NotReady {
+			c.UI.Output(pod, terminal.WithErrorStyle())
+		}
+	}
+
+	if report.Healthy {
+		c.UI.Output("Consul is healthy", terminal.WithSuccessStyle())
+		return
+	}
+	c.UI.Output("Consul is degraded:", terminal.WithErrorStyle())
+	for _, problem := range report.Problems {
+		c.UI.Output(problem, terminal.WithErrorStyle())
+	}
+}
+
+// outputRelease prints the Helm release, its values and the status of its hooks.
+func (c *Command) outputRelease(rel releaseStatus) {
+	timezone, _ := rel.LastDeployed.Zone()
 
 	tbl := terminal.NewTable("Name", "Namespace", "Status", "Chart Version", "AppVersion", "Revision", "Last Updated")
-	tbl.AddRow([]string{releaseName, namespace, string(rel.Info.Status), rel.Chart.Metadata.Version,
-		rel.Chart.Metadata.AppVersion, strconv.Itoa(rel.Version),
-		rel.Info.LastDeployed.Format("2006/01/02 15:04:05") + " " + timezone}, []string{})
+	tbl.AddRow([]string{rel.Name, rel.Namespace, rel.Status, rel.ChartVersion,
+		rel.AppVersion, strconv.Itoa(rel.Revision),
+		rel.LastDeployed.Format("2006/01/02 15:04:05") + " " + timezone}, []string{})
 	c.UI.Table(tbl)
 
 	valuesYaml, err := yaml.Marshal(rel.Config)
 	c.UI.Output("Config:", terminal.WithHeaderStyle())
 	if err != nil {
 		c.UI.Output("%+v", err, terminal.WithInfoStyle())
-	} else if len(rel.Config) == 0 {
-		c.UI.Output(string(valuesYaml), terminal.WithInfoStyle())
 	} else {
 		c.UI.Output(string(valuesYaml), terminal.WithInfoStyle())
 	}
 
 	// Check the status of the hooks.
-	if len(rel.Hooks) > 1 {
+	if rel.hookCount > 1 {
 		c.UI.Output("Status Of Helm Hooks:", terminal.WithHeaderStyle())
 
 		for _, hook := range rel.Hooks {
-			// Remember that we only report the status of pre-install or pre-upgrade hooks.
-			if validEvent(hook.Events) {
-				c.UI.Output("%s %s: %s", hook.Name, hook.Kind, hook.LastRun.Phase.String())
-			}
+			c.UI.Output("%s %s: %s", hook.Name, hook.Kind, hook.Phase)
 		}
 		fmt.Println("")
 	}
-
-	return nil
 }
 
-// validEvent is a helper function that checks if the given hook's events are pre-install or pre-upgrade.
-// Only pre-install and pre-upgrade hooks are expected to have run when using the status command against
-// a running installation.
-func validEvent(events []release.HookEvent) bool {
-	for _, event := range events {
-		if event.String() == "pre-install" || event.String() == "pre-upgrade" {
-			return true
-		}
+// outputServers prints the number of Consul servers which are ready.
+func (c *Command) outputServers(servers serverStatus) {
+	if servers.Ready < servers.Desired {
+		c.UI.Output("Consul servers healthy %d/%d", servers.Ready, servers.Desired, terminal.WithErrorStyle())
+	} else {
+		c.UI.Output("Consul servers healthy %d/%d", servers.Ready, servers.Desired)
 	}
-	return false
 }
 
-// checkConsulServers prints the status of Consul servers if they
-// are expected to be found in the Kubernetes cluster. It does not check for
-// server status if they are not running within the Kubernetes cluster.
-func (c *Command) checkConsulServers(namespace string) error {
-	servers, err := c.kubernetes.AppsV1().StatefulSets(namespace).List(c.Ctx, metav1.ListOptions{LabelSelector: "app=consul,chart=consul-helm,component=server"})
+// outputJSON prints the status as JSON to the terminal.
+func (c *Command) outputJSON(report *statusReport) error {
+	out, err := json.MarshalIndent(report, "", "\t")
 	if err != nil {
 		return err
 	}
-	if len(servers.Items) != 0 {
-		desiredServers, readyServers := int(*servers.Items[0].Spec.Replicas), int(servers.Items[0].Status.ReadyReplicas)
-		if readyServers < desiredServers {
-			c.UI.Output("Consul servers healthy %d/%d", readyServers, desiredServers, terminal.WithErrorStyle())
-		} else {
-			c.UI.Output("Consul servers healthy %d/%d", readyServers, desiredServers)
-		}
-	}
 
+	c.UI.Output(string(out))
 	return nil
 }
 
+// syncedColors highlights the columns of a row whose condition is not True.
+func syncedColors(status string, columns int) []string {
+	color := ""
+	switch status {
+	case "True":
+	case "False":
+		color = terminal.Red
+	default:
+		color = terminal.Yellow
+	}
+
+	colors := make([]string, columns)
+	for i := range colors {
+		colors[i] = color
+	}
+	return colors
+}
+
 // setupKubeClient to use for non Helm SDK calls to the Kubernetes API The Helm SDK will use
 // settings.RESTClientGetter for its calls as well, so this will use a consistent method to
 // target the right cluster for both Helm SDK and non Helm SDK calls.
 func (c *Command) setupKubeClient(settings *helmCLI.EnvSettings) error {
+	if c.kubernetes != nil && c.dynamic != nil {
+		return nil
+	}
+
+	restConfig, err := settings.RESTClientGetter().ToRESTConfig()
+	if err != nil {
+		c.UI.Output("Error retrieving Kubernetes authentication: %v", err, terminal.WithErrorStyle())
+		return err
+	}
 	if c.kubernetes == nil {
-		restConfig, err := settings.RESTClientGetter().ToRESTConfig()
+		c.kubernetes, err = kubernetes.NewForConfig(restConfig)
 		if err != nil {
-			c.UI.Output("Error retrieving Kubernetes authentication: %v", err, terminal.WithErrorStyle())
+			c.UI.Output("Error initializing Kubernetes client: %v", err, terminal.WithErrorStyle())
 			return err
 		}
-		c.kubernetes, err = kubernetes.NewForConfig(restConfig)
+	}
+	if c.dynamic == nil {
+		c.dynamic, err = dynamic.NewForConfig(restConfig)
 		if err != nil {
 			c.UI.Output("Error initializing Kubernetes client: %v", err, terminal.WithErrorStyle())
 			return err
@@ -260,7 +585,10 @@ func (c *Command) setupKubeClient(settings *helmCLI.EnvSettings) error {
 // Help returns a description of the command and how it is used.
 func (c *Command) Help() string {
 	c.once.Do(c.init)
-	return c.Synopsis() + "\n\nUsage: consul-k8s status [flags]\n\n" + c.help
+	return c.Synopsis() + "\n\nUsage: consul-k8s status [flags]\n\n" +
+		"  The command exits with 1 if the installation could not be checked and\n" +
+		"  with 2 if it is degraded, e.g. because servers or control plane Deployments\n" +
+		"  are not ready or custom resources failed to sync to Consul.\n\n" + c.help
 }
 
 // Synopsis returns a one-line command summary.
@@ -6,12 +6,21 @@ package status
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"math/big"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul-k8s/cli/common"
 	cmnFlag "github.com/hashicorp/consul-k8s/cli/common/flag"
@@ -25,8 +34,14 @@ import (
 	"helm.sh/helm/v3/pkg/chart"
 	helmRelease "helm.sh/helm/v3/pkg/release"
 	helmTime "helm.sh/helm/v3/pkg/time"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 )
@@ -53,8 +68,11 @@ func TestCheckConsulServers(t *testing.T) {
 			require.NoError(t, err)
 
 			// Verify that the correct server statuses are seen.
-			err = c.checkConsulServers(namespace)
+			servers, err := c.checkConsulServers(namespace)
 			require.NoError(t, err)
+			if servers != nil {
+				c.outputServers(*servers)
+			}
 
 			actual := buf.String()
 			if tc.desired != 0 {
@@ -195,6 +213,7 @@ func TestStatus(t *testing.T) {
 			buf := new(bytes.Buffer)
 			c := getInitializedCommand(t, buf)
 			c.kubernetes = fake.NewSimpleClientset()
+			c.dynamic = newDynamicClient()
 			c.helmActionsRunner = tc.helmActionsRunner
 			if tc.preProcessingFunc != nil {
 				err := tc.preProcessingFunc(c.kubernetes)
@@ -210,6 +229,247 @@ func TestStatus(t *testing.T) {
 	}
 }
 
+func TestStatus_Degraded(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := getInitializedCommand(t, buf)
+	c.kubernetes = fake.NewSimpleClientset()
+	c.dynamic = newDynamicClient()
+	c.helmActionsRunner = &helm.MockActionRunner{
+		CheckForInstallationsFunc: func(options *helm.CheckForInstallationsOptions) (bool, string, string, error) {
+			return true, "consul", "consul", nil
+		},
+		GetStatusFunc: func(status *action.Status, name string) (*helmRelease.Release, error) {
+			return &helmRelease.Release{
+				Name: "consul", Namespace: "consul",
+				Info:   &helmRelease.Info{LastDeployed: helmTime.Now(), Status: helmRelease.StatusDeployed},
+				Chart:  &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}},
+				Config: make(map[string]interface{})}, nil
+		},
+	}
+	require.NoError(t, createServers("consul-server", "consul", 3, 2, c.kubernetes))
+	require.NoError(t, createDeployment("consul-connect-injector", "consul", 1, 0, c.kubernetes))
+
+	returnCode := c.Run([]string{})
+	require.Equal(t, exitCodeDegraded, returnCode)
+
+	output := buf.String()
+	require.Contains(t, output, "Consul servers healthy 2/3")
+	require.Contains(t, output, "==> Control Plane Deployments:")
+	require.Contains(t, output, "consul-connect-injector")
+	require.Contains(t, output, "Consul is degraded:")
+	require.Contains(t, output, "2 of 3 Consul servers are ready")
+	require.Contains(t, output, "Deployment consul-connect-injector has 0 of 1 replicas ready")
+}
+
+func TestStatus_JSON(t *testing.T) {
+	nowTime := helmTime.Now()
+	buf := new(bytes.Buffer)
+	c := getInitializedCommand(t, buf)
+	c.kubernetes = fake.NewSimpleClientset()
+	c.dynamic = newDynamicClient()
+	c.helmActionsRunner = &helm.MockActionRunner{
+		CheckForInstallationsFunc: func(options *helm.CheckForInstallationsOptions) (bool, string, string, error) {
+			return true, "consul", "consul", nil
+		},
+		GetStatusFunc: func(status *action.Status, name string) (*helmRelease.Release, error) {
+			return &helmRelease.Release{
+				Name: "consul", Namespace: "consul",
+				Info:   &helmRelease.Info{LastDeployed: nowTime, Status: helmRelease.StatusDeployed},
+				Chart:  &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0", AppVersion: "1.16.0"}},
+				Config: map[string]interface{}{"global": map[string]interface{}{"name": "consul"}}}, nil
+		},
+	}
+	require.NoError(t, createServers("consul-server", "consul", 3, 3, c.kubernetes))
+	require.NoError(t, createDeployment("consul-connect-injector", "consul", 1, 1, c.kubernetes))
+
+	returnCode := c.Run([]string{"-output", "json"})
+	require.Equal(t, 0, returnCode)
+
+	var actual statusReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &actual))
+	require.True(t, actual.Healthy)
+	require.Empty(t, actual.Problems)
+	require.Equal(t, "deployed", actual.Release.Status)
+	require.Equal(t, "1.16.0", actual.Release.AppVersion)
+	require.Equal(t, map[string]interface{}{"global": map[string]interface{}{"name": "consul"}}, actual.Release.Config)
+	require.Equal(t, &serverStatus{Desired: 3, Ready: 3}, actual.Servers)
+	require.Equal(t, []deploymentStatus{{Name: "consul-connect-injector", Desired: 1, Ready: 1}}, actual.Deployments)
+}
+
+func TestNewWebhookStatus(t *testing.T) {
+	now := time.Now()
+	valid := generateCA(t, now.Add(-time.Hour), now.Add(time.Hour))
+	expired := generateCA(t, now.Add(-2*time.Hour), now.Add(-time.Hour))
+	notYetValid := generateCA(t, now.Add(time.Hour), now.Add(2*time.Hour))
+
+	cases := map[string]struct {
+		caBundle        []byte
+		expectedProblem string
+	}{
+		"valid": {
+			caBundle: valid,
+		},
+		"empty": {
+			caBundle:        nil,
+			expectedProblem: "CA bundle is empty",
+		},
+		"not a certificate": {
+			caBundle:        []byte("not a certificate"),
+			expectedProblem: "CA bundle holds no certificates",
+		},
+		"expired": {
+			caBundle:        expired,
+			expectedProblem: "CA certificate expired at",
+		},
+		"expired in a bundle": {
+			caBundle:        append(append([]byte{}, valid...), expired...),
+			expectedProblem: "CA certificate expired at",
+		},
+		"not yet valid": {
+			caBundle:        notYetValid,
+			expectedProblem: "CA certificate is not valid before",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			status := newWebhookStatus("consul-connect-injector", "mutate-servicedefaults.consul.hashicorp.com", tc.caBundle, now)
+			if tc.expectedProblem == "" {
+				require.Empty(t, status.Problem)
+				require.NotNil(t, status.Expires)
+				return
+			}
+			require.Contains(t, status.Problem, tc.expectedProblem)
+		})
+	}
+}
+
+func TestCheckWebhooks(t *testing.T) {
+	now := time.Now()
+	c := getInitializedCommand(t, new(bytes.Buffer))
+	c.kubernetes = fake.NewSimpleClientset(
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "consul-connect-injector",
+				Labels: map[string]string{"app": "consul", "release": "consul"},
+			},
+			Webhooks: []admissionregistrationv1.MutatingWebhook{
+				{Name: "consul-connect-injector.consul.hashicorp.com", ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: generateCA(t, now.Add(-time.Hour), now.Add(time.Hour))}},
+				{Name: "mutate-servicedefaults.consul.hashicorp.com"},
+			},
+		},
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "other",
+				Labels: map[string]string{"app": "other"},
+			},
+			Webhooks: []admissionregistrationv1.MutatingWebhook{{Name: "other.example.com"}},
+		},
+	)
+
+	webhooks, err := c.checkWebhooks("consul", now)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 2)
+	require.Empty(t, webhooks[0].Problem)
+	require.Equal(t, "CA bundle is empty", webhooks[1].Problem)
+}
+
+func TestCheckCustomResources(t *testing.T) {
+	c := getInitializedCommand(t, new(bytes.Buffer))
+	client := fake.NewSimpleClientset()
+	client.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "consul.hashicorp.com/v1alpha1",
+			APIResources: []metav1.APIResource{
+				{Name: "servicedefaults", Kind: "ServiceDefaults"},
+				{Name: "servicedefaults/status", Kind: "ServiceDefaults"},
+				{Name: "peeringacceptors", Kind: "PeeringAcceptor"},
+			},
+		},
+	}
+	c.kubernetes = client
+	c.dynamic = newDynamicClient(
+		newCustomResource("ServiceDefaults", "default", "web", "Synced", "True", ""),
+		newCustomResource("ServiceDefaults", "default", "api", "Synced", "False", "protocol mismatch"),
+		newCustomResource("PeeringAcceptor", "default", "cluster-02", "Synced", "True", ""),
+	)
+
+	failures, peerings, err := c.checkCustomResources()
+	require.NoError(t, err)
+	require.Equal(t, []resourceStatus{
+		{Kind: "ServiceDefaults", Namespace: "default", Name: "api", Synced: "False", Reason: "Reason", Message: "protocol mismatch"},
+	}, failures)
+	require.Equal(t, []resourceStatus{
+		{Kind: "PeeringAcceptor", Namespace: "default", Name: "cluster-02", Synced: "True", Reason: "Reason"},
+	}, peerings)
+}
+
+func TestCheckGateways(t *testing.T) {
+	c := getInitializedCommand(t, new(bytes.Buffer))
+	c.dynamic = newDynamicClient(
+		&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1beta1",
+			"kind":       "GatewayClass",
+			"metadata":   map[string]interface{}{"name": "consul"},
+			"spec":       map[string]interface{}{"controllerName": gatewayControllerName},
+		}},
+		newGateway("default", "programmed", "consul", "True"),
+		newGateway("default", "pending", "consul", ""),
+		newGateway("default", "other", "istio", "True"),
+	)
+
+	gateways, err := c.checkGateways()
+	require.NoError(t, err)
+	require.Equal(t, []gatewayStatus{
+		{Namespace: "default", Name: "pending", Programmed: "Unknown"},
+		{Namespace: "default", Name: "programmed", Programmed: "True", Reason: "Reason"},
+	}, gateways)
+}
+
+func TestCheckDataplanes(t *testing.T) {
+	injected := map[string]string{"consul.hashicorp.com/connect-inject-status": "injected"}
+	c := getInitializedCommand(t, new(bytes.Buffer))
+	c.kubernetes = fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "default", Labels: injected},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "web", Ready: true}, {Name: "consul-dataplane", Ready: true}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready-init", Namespace: "default", Labels: injected},
+			Status: corev1.PodStatus{
+				Phase:                 corev1.PodRunning,
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: "consul-dataplane", Ready: true}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "not-ready", Namespace: "apps", Labels: injected},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "consul-dataplane-web", Ready: true}, {Name: "consul-dataplane-api", Ready: false}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "apps", Labels: injected},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "completed", Namespace: "apps", Labels: injected},
+			Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "not-injected", Namespace: "apps"},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+	)
+
+	dataplanes, err := c.checkDataplanes()
+	require.NoError(t, err)
+	require.Equal(t, dataplaneStatus{Injected: 4, NotReady: []string{"apps/not-ready", "apps/pending"}}, dataplanes)
+}
+
 func TestTaskCreateCommand_AutocompleteFlags(t *testing.T) {
 	t.Parallel()
 	cmd := getInitializedCommand(t, nil)
@@ -284,3 +544,85 @@ func createServers(name, namespace string, replicas, readyReplicas int32, k8s ku
 	_, err := k8s.AppsV1().StatefulSets(namespace).Create(context.Background(), &servers, metav1.CreateOptions{})
 	return err
 }
+
+func createDeployment(name, namespace string, replicas, readyReplicas int32, k8s kubernetes.Interface) error {
+	deployment := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": "consul", "release": "consul"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:      replicas,
+			ReadyReplicas: readyReplicas,
+		},
+	}
+	_, err := k8s.AppsV1().Deployments(namespace).Create(context.Background(), &deployment, metav1.CreateOptions{})
+	return err
+}
+
+// newDynamicClient returns a fake dynamic client which can list the custom
+// resources checked by the command.
+func newDynamicClient(objects ...runtime.Object) *dynamicFake.FakeDynamicClient {
+	return dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		gatewayClassesResource: "GatewayClassList",
+		gatewaysResource:       "GatewayList",
+		{Group: "consul.hashicorp.com", Version: "v1alpha1", Resource: "servicedefaults"}:  "ServiceDefaultsList",
+		{Group: "consul.hashicorp.com", Version: "v1alpha1", Resource: "peeringacceptors"}: "PeeringAcceptorList",
+	}, objects...)
+}
+
+func newCustomResource(kind, namespace, name, conditionType, status, message string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "consul.hashicorp.com/v1alpha1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": conditionType, "status": status, "reason": "Reason", "message": message},
+			},
+		},
+	}}
+}
+
+func newGateway(namespace, name, className, programmed string) *unstructured.Unstructured {
+	gateway := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1beta1",
+		"kind":       "Gateway",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       map[string]interface{}{"gatewayClassName": className},
+	}}
+	if programmed != "" {
+		gateway.Object["status"] = map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Programmed", "status": programmed, "reason": "Reason"},
+			},
+		}
+	}
+	return gateway
+}
+
+// generateCA returns a PEM encoded self-signed CA certificate valid between
+// notBefore and notAfter.
+func generateCA(t *testing.T, notBefore, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Consul Webhook CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
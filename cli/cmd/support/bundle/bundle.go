@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// manifestFile is the name of the file listing the contents of the bundle.
+	manifestFile = "manifest.json"
+
+	// redacted replaces the values of sensitive Helm values.
+	redacted = "<redacted>"
+)
+
+// bundle holds the files collected for a support bundle until it is written.
+type bundle struct {
+	manifest manifest
+	files    []bundleFile
+}
+
+// manifest describes the contents of a support bundle and the problems which
+// were encountered while collecting them.
+type manifest struct {
+	CreatedAt        time.Time       `json:"createdAt"`
+	CLIVersion       string          `json:"cliVersion"`
+	ReleaseName      string          `json:"releaseName,omitempty"`
+	ReleaseNamespace string          `json:"releaseNamespace,omitempty"`
+	Files            []manifestEntry `json:"files"`
+	Errors           []string        `json:"errors,omitempty"`
+}
+
+type manifestEntry struct {
+	Path        string `json:"path"`
+	Description string `json:"description"`
+}
+
+type bundleFile struct {
+	path     string
+	contents []byte
+}
+
+func newBundle(cliVersion string, createdAt time.Time) *bundle {
+	return &bundle{
+		manifest: manifest{
+			CreatedAt:  createdAt,
+			CLIVersion: cliVersion,
+			Files:      []manifestEntry{},
+		},
+	}
+}
+
+// add adds a file to the bundle.
+func (b *bundle) add(filePath, description string, contents []byte) {
+	b.files = append(b.files, bundleFile{path: filePath, contents: contents})
+	b.manifest.Files = append(b.manifest.Files, manifestEntry{Path: filePath, Description: description})
+}
+
+// addYAML adds a file to the bundle with the value marshalled as YAML.
+func (b *bundle) addYAML(filePath, description string, v interface{}) error {
+	contents, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	b.add(filePath, description, contents)
+	return nil
+}
+
+// addJSON adds a file to the bundle with the value marshalled as indented JSON.
+func (b *bundle) addJSON(filePath, description string, v interface{}) error {
+	contents, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	b.add(filePath, description, contents)
+	return nil
+}
+
+// addError records a problem encountered while collecting the bundle.
+func (b *bundle) addError(format string, args ...interface{}) {
+	b.manifest.Errors = append(b.manifest.Errors, fmt.Sprintf(format, args...))
+}
+
+// write writes the bundle as a gzipped tarball. All files are placed in a
+// directory named after the root so that extracting the bundle does not
+// scatter files into the working directory.
+func (b *bundle) write(w io.Writer, root string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest, err := json.MarshalIndent(b.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	files := append([]bundleFile{{path: manifestFile, contents: manifest}}, b.files...)
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    path.Join(root, file.path),
+			Mode:    0600,
+			Size:    int64(len(file.contents)),
+			ModTime: b.manifest.CreatedAt,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.contents); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// sensitiveKeys are the parts of Helm value names which indicate that the value
+// may hold a secret.
+var sensitiveKeys = []string{"token", "password", "clientsecret", "license", "privatekey", "credentials"}
+
+// sensitiveNames are Helm value names, or Consul configuration names within
+// values such as `server.extraConfig`, which hold a secret.
+var sensitiveNames = []string{"encrypt", "key"}
+
+// secretReferenceKeys are the parts of Helm value names which indicate that the
+// value refers to a Kubernetes or Vault secret rather than holding one. They
+// are kept because they help to debug the installation.
+var secretReferenceKeys = []string{"secretname", "secretkey", "secretpath"}
+
+// redactValues returns a copy of the Helm values with the values which may
+// hold secrets replaced. Maps are searched recursively and every value within
+// a map with a sensitive name is redacted, except for references to secrets,
+// which are kept because they help to debug the installation.
+func redactValues(values map[string]interface{}) map[string]interface{} {
+	return redactMap(values, false)
+}
+
+func redactMap(values map[string]interface{}, sensitive bool) map[string]interface{} {
+	out := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		out[key] = redactValue(key, value, sensitive || isSensitive(key))
+	}
+	return out
+}
+
+func redactValue(key string, value interface{}, sensitive bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return redactMap(v, sensitive)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = redactValue(key, item, sensitive)
+		}
+		return out
+	case nil:
+		return nil
+	}
+
+	if sensitive && !isSecretReference(key) {
+		return redacted
+	}
+
+	// Values such as `server.extraConfig` hold Consul configuration as a JSON
+	// string, which is redacted like any other map.
+	var config map[string]interface{}
+	if s, ok := value.(string); ok && strings.HasSuffix(strings.ToLower(key), "extraconfig") && json.Unmarshal([]byte(s), &config) == nil {
+		out, err := json.Marshal(redactValues(config))
+		if err != nil {
+			return redacted
+		}
+		return string(out)
+	}
+
+	return value
+}
+
+func isSensitive(key string) bool {
+	if isSecretReference(key) {
+		return false
+	}
+
+	key = strings.ToLower(key)
+	for _, name := range sensitiveNames {
+		if key == name {
+			return true
+		}
+	}
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSecretReference(key string) bool {
+	key = strings.ToLower(key)
+	for _, reference := range secretReferenceKeys {
+		if strings.HasSuffix(key, reference) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bundle
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactValues(t *testing.T) {
+	values := map[string]interface{}{
+		"global": map[string]interface{}{
+			"name": "consul",
+			"acls": map[string]interface{}{
+				"manageSystemACLs": true,
+				"bootstrapToken": map[string]interface{}{
+					"secretName": "bootstrap-token",
+					"secretKey":  "token",
+				},
+			},
+			"enterpriseLicense": map[string]interface{}{
+				"secretName": "license",
+				"secretKey":  "key",
+			},
+			"gossipEncryption": map[string]interface{}{
+				"autoGenerate": true,
+			},
+			"cloud": map[string]interface{}{
+				"clientSecret": map[string]interface{}{
+					"secretName": "cloud",
+					"secretKey":  "client-secret",
+				},
+			},
+		},
+		"server": map[string]interface{}{
+			"replicas":    3,
+			"extraConfig": `{"encrypt": "c2VjcmV0", "acl": {"tokens": {"agent": "1234"}}, "log_level": "DEBUG"}`,
+		},
+		"client": map[string]interface{}{
+			"extraEnvironmentVars": map[string]interface{}{
+				"CONSUL_HTTP_TOKEN": "1234",
+			},
+		},
+		"apiGateway": map[string]interface{}{
+			"managedGatewayClass": map[string]interface{}{
+				"copyAnnotations": map[string]interface{}{
+					"service": map[string]interface{}{
+						"annotations": []interface{}{"a"},
+					},
+				},
+			},
+		},
+		"meshGateway": map[string]interface{}{
+			"password": nil,
+		},
+	}
+
+	expected := map[string]interface{}{
+		"global": map[string]interface{}{
+			"name": "consul",
+			"acls": map[string]interface{}{
+				"manageSystemACLs": true,
+				"bootstrapToken": map[string]interface{}{
+					"secretName": "bootstrap-token",
+					"secretKey":  "token",
+				},
+			},
+			"enterpriseLicense": map[string]interface{}{
+				"secretName": "license",
+				"secretKey":  "key",
+			},
+			"gossipEncryption": map[string]interface{}{
+				"autoGenerate": true,
+			},
+			"cloud": map[string]interface{}{
+				"clientSecret": map[string]interface{}{
+					"secretName": "cloud",
+					"secretKey":  "client-secret",
+				},
+			},
+		},
+		"server": map[string]interface{}{
+			"replicas":    3,
+			"extraConfig": `{"acl":{"tokens":{"agent":"<redacted>"}},"encrypt":"<redacted>","log_level":"DEBUG"}`,
+		},
+		"client": map[string]interface{}{
+			"extraEnvironmentVars": map[string]interface{}{
+				"CONSUL_HTTP_TOKEN": redacted,
+			},
+		},
+		"apiGateway": map[string]interface{}{
+			"managedGatewayClass": map[string]interface{}{
+				"copyAnnotations": map[string]interface{}{
+					"service": map[string]interface{}{
+						"annotations": []interface{}{"a"},
+					},
+				},
+			},
+		},
+		"meshGateway": map[string]interface{}{
+			"password": nil,
+		},
+	}
+
+	require.Equal(t, expected, redactValues(values))
+}
+
+func TestBundleWrite(t *testing.T) {
+	createdAt := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	b := newBundle("1.2.0", createdAt)
+	b.add("logs/consul/consul-server-0/consul.log", "Logs.", []byte("started"))
+	require.NoError(t, b.addYAML("helm/values.yaml", "Values.", map[string]interface{}{"global": map[string]interface{}{"name": "consul"}}))
+	b.addError("Unable to collect %s", "things")
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, b.write(buf, "bundle"))
+
+	files := readBundle(t, buf.Bytes())
+	require.Equal(t, "started", files["bundle/logs/consul/consul-server-0/consul.log"])
+	require.Equal(t, "global:\n  name: consul\n", files["bundle/helm/values.yaml"])
+	require.JSONEq(t, `{
+		"createdAt": "2023-06-01T12:00:00Z",
+		"cliVersion": "1.2.0",
+		"files": [
+			{"path": "logs/consul/consul-server-0/consul.log", "description": "Logs."},
+			{"path": "helm/values.yaml", "description": "Values."}
+		],
+		"errors": ["Unable to collect things"]
+	}`, files["bundle/manifest.json"])
+}
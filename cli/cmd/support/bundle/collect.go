@@ -0,0 +1,304 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	"github.com/hashicorp/consul-k8s/cli/common/envoy"
+	"github.com/hashicorp/consul-k8s/cli/helm"
+)
+
+// consulGroupSuffix is the suffix of the API groups of Consul custom resources.
+const consulGroupSuffix = "consul.hashicorp.com"
+
+// releaseInfo is the summary of the Helm release written to the bundle.
+type releaseInfo struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Status       string `json:"status"`
+	Revision     int    `json:"revision"`
+	ChartVersion string `json:"chartVersion,omitempty"`
+	AppVersion   string `json:"appVersion,omitempty"`
+	LastDeployed string `json:"lastDeployed,omitempty"`
+}
+
+// collectHelmRelease collects the status of the Consul Helm release and the
+// values it was installed with, with secrets redacted.
+func (c *BundleCommand) collectHelmRelease(b *bundle) error {
+	// Helm library logs are only of interest when debugging the command itself.
+	var debugLog action.DebugLog = func(s string, args ...interface{}) {
+		c.Log.Debug(fmt.Sprintf(s, args...))
+	}
+
+	_, releaseName, namespace, err := c.helmActionsRunner.CheckForInstallations(&helm.CheckForInstallationsOptions{
+		Settings:    c.settings,
+		ReleaseName: common.DefaultReleaseName,
+		DebugLog:    debugLog,
+	})
+	if err != nil {
+		return err
+	}
+	b.manifest.ReleaseName = releaseName
+	b.manifest.ReleaseNamespace = namespace
+
+	statusConfig := new(action.Configuration)
+	statusConfig, err = helm.InitActionConfig(statusConfig, namespace, c.settings, debugLog)
+	if err != nil {
+		return err
+	}
+
+	rel, err := c.helmActionsRunner.GetStatus(action.NewStatus(statusConfig), releaseName)
+	if err != nil {
+		return fmt.Errorf("couldn't get the status of the release: %s", err)
+	}
+
+	info := releaseInfo{
+		Name:      releaseName,
+		Namespace: namespace,
+		Revision:  rel.Version,
+	}
+	if rel.Info != nil {
+		info.Status = string(rel.Info.Status)
+		info.LastDeployed = rel.Info.LastDeployed.String()
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		info.ChartVersion = rel.Chart.Metadata.Version
+		info.AppVersion = rel.Chart.Metadata.AppVersion
+	}
+
+	if err := b.addJSON("helm/release.json", "Status of the Consul Helm release.", info); err != nil {
+		return err
+	}
+	return b.addYAML("helm/values.yaml", "Helm values the release was installed with, with secrets redacted.", redactValues(rel.Config))
+}
+
+// collectCustomResources collects all Consul custom resources, including their
+// status, from all namespaces.
+func (c *BundleCommand) collectCustomResources(b *bundle) error {
+	// Partial results are returned when some API groups cannot be discovered.
+	_, resourceLists, err := c.kubernetes.Discovery().ServerGroupsAndResources()
+	if err != nil && len(resourceLists) == 0 {
+		return err
+	}
+
+	seen := make(map[schema.GroupResource]bool)
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil || !strings.HasSuffix(gv.Group, consulGroupSuffix) {
+			continue
+		}
+
+		for _, resource := range resourceList.APIResources {
+			gr := schema.GroupResource{Group: gv.Group, Resource: resource.Name}
+			// Skip subresources such as status and resources served by multiple versions.
+			if strings.Contains(resource.Name, "/") || seen[gr] || !hasVerb(resource, "list") {
+				continue
+			}
+			seen[gr] = true
+
+			list, err := c.dynamic.Resource(gv.WithResource(resource.Name)).List(c.Ctx, metav1.ListOptions{})
+			if err != nil {
+				c.warn(b, "Unable to list %s: %v", gr, err)
+				continue
+			}
+
+			items := make([]map[string]interface{}, 0, len(list.Items))
+			for _, item := range list.Items {
+				unstructured.RemoveNestedField(item.Object, "metadata", "managedFields")
+				items = append(items, item.Object)
+			}
+
+			err = b.addYAML(fmt.Sprintf("crds/%s.yaml", gr), fmt.Sprintf("All %s with their status.", gr), items)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectLogs collects the logs of the containers of the Consul control plane
+// Pods in the namespace of the Helm release. The logs of the previous instance
+// of restarted containers are collected as well.
+func (c *BundleCommand) collectLogs(b *bundle) error {
+	if b.manifest.ReleaseNamespace == "" {
+		return fmt.Errorf("the namespace of the Consul installation is unknown")
+	}
+
+	pods, err := c.kubernetes.CoreV1().Pods(b.manifest.ReleaseNamespace).List(c.Ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=consul,release=%s", b.manifest.ReleaseName),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods.Items {
+		statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			c.collectContainerLogs(b, pod, status.Name, false)
+			if status.RestartCount > 0 {
+				c.collectContainerLogs(b, pod, status.Name, true)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *BundleCommand) collectContainerLogs(b *bundle, pod corev1.Pod, container string, previous bool) {
+	opts := &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+	}
+	if c.flagSince > 0 {
+		sinceSeconds := int64(c.flagSince.Seconds())
+		opts.SinceSeconds = &sinceSeconds
+	}
+
+	logs, err := c.kubernetes.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).DoRaw(c.Ctx)
+	if err != nil {
+		c.warn(b, "Unable to collect the logs of container %s of Pod %s/%s: %v", container, pod.Namespace, pod.Name, err)
+		return
+	}
+
+	filePath := path.Join("logs", pod.Namespace, pod.Name, container+".log")
+	description := fmt.Sprintf("Logs of container %s of Pod %s/%s.", container, pod.Namespace, pod.Name)
+	if previous {
+		filePath = path.Join("logs", pod.Namespace, pod.Name, container+".previous.log")
+		description = fmt.Sprintf("Logs of the previous instance of container %s of Pod %s/%s.", container, pod.Namespace, pod.Name)
+	}
+	b.add(filePath, description, logs)
+}
+
+// collectEnvoyConfigs collects the config dump and clusters of the Envoy
+// proxies in the selected Pods. They are saved as separate files so that they
+// can be read with `consul-k8s proxy read -config-dump-file`.
+func (c *BundleCommand) collectEnvoyConfigs(b *bundle) error {
+	if c.flagMaxProxies == 0 {
+		return nil
+	}
+
+	var (
+		pods []corev1.Pod
+		err  error
+	)
+	if c.flagProxySelector != "" {
+		targets := common.PodTargets{
+			Namespace:     c.flagProxyNamespace,
+			Selector:      c.flagProxySelector,
+			AllNamespaces: c.flagProxyNamespace == "",
+		}
+		pods, err = targets.ListPods(c.Ctx, c.kubernetes)
+	} else {
+		pods, err = common.ListProxyPods(c.Ctx, c.kubernetes, c.flagProxyNamespace)
+	}
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	if len(pods) > c.flagMaxProxies {
+		c.warn(b, "Collecting the Envoy configuration of %d of %d Pods. Use -max-proxies or -proxy-selector to choose more or other Pods.", c.flagMaxProxies, len(pods))
+		pods = pods[:c.flagMaxProxies]
+	}
+
+	results := common.ForEachPod(c.Ctx, pods, common.DefaultMaxConcurrency, c.fetchConfigs)
+	for _, result := range results {
+		if result.Err != nil {
+			c.warn(b, "Unable to collect the Envoy configuration of Pod %s/%s: %v", result.Pod.Namespace, result.Pod.Name, result.Err)
+			continue
+		}
+
+		for name, config := range result.Value {
+			// The raw configuration holds the responses of both admin endpoints.
+			var raw map[string]json.RawMessage
+			if err := json.Unmarshal(config.JSON(), &raw); err != nil {
+				c.warn(b, "Unable to collect the Envoy configuration of Pod %s/%s: %v", result.Pod.Namespace, result.Pod.Name, err)
+				continue
+			}
+
+			dir := path.Join("envoy", result.Pod.Namespace, result.Pod.Name, name)
+			b.add(path.Join(dir, "config_dump.json"), fmt.Sprintf("Envoy config dump of proxy %s in Pod %s/%s.", name, result.Pod.Namespace, result.Pod.Name), raw["config_dump"])
+			b.add(path.Join(dir, "clusters.json"), fmt.Sprintf("Envoy clusters of proxy %s in Pod %s/%s.", name, result.Pod.Namespace, result.Pod.Name), raw["clusters"])
+		}
+	}
+
+	return nil
+}
+
+// fetchConfigs fetches the configuration of each Envoy proxy in the Pod.
+func (c *BundleCommand) fetchConfigs(ctx context.Context, pod corev1.Pod) (map[string]*envoy.EnvoyConfig, error) {
+	configs := make(map[string]*envoy.EnvoyConfig)
+
+	for name, adminPort := range common.EnvoyAdminPorts(pod) {
+		pf := common.PortForward{
+			Namespace:  pod.Namespace,
+			PodName:    pod.Name,
+			RemotePort: adminPort,
+			KubeClient: c.kubernetes,
+			RestConfig: c.restConfig,
+		}
+
+		config, err := c.fetchConfig(ctx, &pf)
+		if err != nil {
+			return configs, err
+		}
+
+		configs[name] = config
+	}
+
+	return configs, nil
+}
+
+// collectClusterInfo collects the Kubernetes version and the Nodes of the cluster.
+func (c *BundleCommand) collectClusterInfo(b *bundle) error {
+	serverVersion, err := c.kubernetes.Discovery().ServerVersion()
+	if err != nil {
+		return err
+	}
+	if err := b.addJSON("cluster/version.json", "Version of the Kubernetes API server.", serverVersion); err != nil {
+		return err
+	}
+
+	nodes, err := c.kubernetes.CoreV1().Nodes().List(c.Ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range nodes.Items {
+		// The images cached on the Nodes are not of interest and make up most of their size.
+		nodes.Items[i].Status.Images = nil
+		nodes.Items[i].ManagedFields = nil
+	}
+
+	return b.addYAML("cluster/nodes.yaml", "Nodes of the Kubernetes cluster.", nodes.Items)
+}
+
+func hasVerb(resource metav1.APIResource, verb string) bool {
+	for _, v := range resource.Verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
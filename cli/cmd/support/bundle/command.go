@@ -0,0 +1,313 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/posener/complete"
+	helmCLI "helm.sh/helm/v3/pkg/cli"
+	"k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	"github.com/hashicorp/consul-k8s/cli/common/envoy"
+	"github.com/hashicorp/consul-k8s/cli/common/flag"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+	"github.com/hashicorp/consul-k8s/cli/helm"
+	"github.com/hashicorp/consul-k8s/cli/version"
+)
+
+const (
+	flagNameOutputFile     = "output-file"
+	flagNameSince          = "since"
+	flagNameProxySelector  = "proxy-selector"
+	flagNameProxyNamespace = "proxy-namespace"
+	flagNameMaxProxies     = "max-proxies"
+	flagNameKubeConfig     = "kubeconfig"
+	flagNameKubeContext    = "context"
+
+	defaultSince      = 24 * time.Hour
+	defaultMaxProxies = 10
+)
+
+type BundleCommand struct {
+	*common.BaseCommand
+
+	helmActionsRunner helm.HelmActionsRunner
+
+	kubernetes kubernetes.Interface
+	dynamic    dynamic.Interface
+
+	set *flag.Sets
+
+	// Command Flags
+	flagOutputFile     string
+	flagSince          time.Duration
+	flagProxySelector  string
+	flagProxyNamespace string
+	flagMaxProxies     int
+
+	// Global Flags
+	flagKubeConfig  string
+	flagKubeContext string
+
+	fetchConfig func(context.Context, common.PortForwarder) (*envoy.EnvoyConfig, error)
+
+	settings   *helmCLI.EnvSettings
+	restConfig *rest.Config
+
+	once sync.Once
+	help string
+}
+
+func (c *BundleCommand) init() {
+	if c.fetchConfig == nil {
+		c.fetchConfig = envoy.FetchConfig
+	}
+
+	c.set = flag.NewSets()
+	f := c.set.NewSet("Command Options")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameOutputFile,
+		Target:  &c.flagOutputFile,
+		Usage:   "The path to write the support bundle to. Defaults to a file named after the current time in the working directory.",
+		Aliases: []string{"f"},
+	})
+	f.DurationVar(&flag.DurationVar{
+		Name:    flagNameSince,
+		Target:  &c.flagSince,
+		Default: defaultSince,
+		Usage:   "Only collect logs of the Consul control plane newer than the given duration.",
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameProxySelector,
+		Target: &c.flagProxySelector,
+		Usage:  "A label selector for the Pods to collect the Envoy configuration of. Defaults to all Pods running Consul proxies, as listed by `consul-k8s proxy list`.",
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameProxyNamespace,
+		Target: &c.flagProxyNamespace,
+		Usage:  "The namespace to collect the Envoy configuration of proxies from. Defaults to all namespaces.",
+	})
+	f.IntVar(&flag.IntVar{
+		Name:    flagNameMaxProxies,
+		Target:  &c.flagMaxProxies,
+		Default: defaultMaxProxies,
+		Usage:   "The maximum number of Pods to collect the Envoy configuration of.",
+	})
+
+	f = c.set.NewSet("Global Options")
+	f.StringVar(&flag.StringVar{
+		Name:    flagNameKubeConfig,
+		Aliases: []string{"c"},
+		Target:  &c.flagKubeConfig,
+		Usage:   "Set the path to kubeconfig file.",
+	})
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameKubeContext,
+		Target: &c.flagKubeContext,
+		Usage:  "Set the Kubernetes context to use.",
+	})
+
+	c.help = c.set.Help()
+}
+
+// Run collects diagnostics about a Consul installation on Kubernetes into a
+// support bundle.
+func (c *BundleCommand) Run(args []string) int {
+	c.once.Do(c.init)
+	if c.helmActionsRunner == nil {
+		c.helmActionsRunner = &helm.ActionRunner{}
+	}
+
+	c.Log.ResetNamed("bundle")
+	defer common.CloseWithError(c.BaseCommand)
+
+	if err := c.set.Parse(args); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		c.UI.Output("\n" + c.Help())
+		return 1
+	}
+
+	if err := c.validateFlags(); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		c.UI.Output("\n" + c.Help())
+		return 1
+	}
+
+	if err := c.initKubernetes(); err != nil {
+		c.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	createdAt := time.Now().UTC()
+	outputFile := c.flagOutputFile
+	if outputFile == "" {
+		outputFile = fmt.Sprintf("consul-k8s-support-bundle-%s.tar.gz", createdAt.Format("20060102T150405Z"))
+	}
+
+	c.UI.Output("Collecting support bundle", terminal.WithHeaderStyle())
+	b := c.collect(createdAt)
+
+	if err := writeBundle(b, outputFile); err != nil {
+		c.UI.Output(fmt.Sprintf("Error writing support bundle: %v", err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Support bundle written to %s", outputFile), terminal.WithSuccessStyle())
+	if len(b.manifest.Errors) > 0 {
+		c.UI.Output(fmt.Sprintf("%d problems were encountered while collecting the bundle. They are listed in %s.", len(b.manifest.Errors), manifestFile),
+			terminal.WithWarningStyle())
+	}
+
+	return 0
+}
+
+// Help returns a description of the command and how it is used.
+func (c *BundleCommand) Help() string {
+	c.once.Do(c.init)
+	return fmt.Sprintf("%s\n\nUsage: consul-k8s support bundle [flags]\n\n%s", c.Synopsis(), c.help)
+}
+
+// Synopsis returns a one-line command summary.
+func (c *BundleCommand) Synopsis() string {
+	return "Collect diagnostics about a Consul installation on Kubernetes into a support bundle."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *BundleCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		fmt.Sprintf("-%s", flagNameOutputFile):     complete.PredictFiles("*.tar.gz"),
+		fmt.Sprintf("-%s", flagNameSince):          complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameProxySelector):  complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameProxyNamespace): complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameMaxProxies):     complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameKubeConfig):     complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", flagNameKubeContext):    complete.PredictNothing,
+	}
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// Since argument completion is not supported, this will return
+// complete.PredictNothing.
+func (c *BundleCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *BundleCommand) validateFlags() error {
+	if len(c.set.Args()) > 0 {
+		return errors.New("should have no non-flag arguments")
+	}
+	if errs := validation.ValidateNamespaceName(c.flagProxyNamespace, false); c.flagProxyNamespace != "" && len(errs) > 0 {
+		return fmt.Errorf("invalid namespace name passed for -proxy-namespace: %v", strings.Join(errs, "; "))
+	}
+	if c.flagSince < 0 {
+		return errors.New("-since must be a positive duration")
+	}
+	if c.flagMaxProxies < 0 {
+		return errors.New("-max-proxies must not be negative")
+	}
+	return nil
+}
+
+// initKubernetes initializes the Kubernetes clients. The Helm settings are kept
+// so that the Helm SDK targets the same cluster.
+func (c *BundleCommand) initKubernetes() (err error) {
+	c.settings = helmCLI.New()
+
+	if c.flagKubeConfig != "" {
+		c.settings.KubeConfig = c.flagKubeConfig
+	}
+
+	if c.flagKubeContext != "" {
+		c.settings.KubeContext = c.flagKubeContext
+	}
+
+	if c.restConfig == nil {
+		if c.restConfig, err = c.settings.RESTClientGetter().ToRESTConfig(); err != nil {
+			return fmt.Errorf("error creating Kubernetes REST config %v", err)
+		}
+	}
+
+	if c.kubernetes == nil {
+		if c.kubernetes, err = kubernetes.NewForConfig(c.restConfig); err != nil {
+			return fmt.Errorf("error creating Kubernetes client %v", err)
+		}
+	}
+
+	if c.dynamic == nil {
+		if c.dynamic, err = dynamic.NewForConfig(c.restConfig); err != nil {
+			return fmt.Errorf("error creating Kubernetes client %v", err)
+		}
+	}
+
+	return nil
+}
+
+// collect gathers the contents of the support bundle. Problems are recorded in
+// the bundle's manifest rather than stopping the collection so that as much as
+// possible is collected from a broken installation.
+func (c *BundleCommand) collect(createdAt time.Time) *bundle {
+	b := newBundle(version.GetHumanVersion(), createdAt)
+
+	c.UI.Output("Helm release", terminal.WithInfoStyle())
+	if err := c.collectHelmRelease(b); err != nil {
+		c.warn(b, "Unable to collect the Helm release: %v", err)
+	}
+
+	c.UI.Output("Consul custom resources", terminal.WithInfoStyle())
+	if err := c.collectCustomResources(b); err != nil {
+		c.warn(b, "Unable to collect Consul custom resources: %v", err)
+	}
+
+	c.UI.Output("Control plane logs", terminal.WithInfoStyle())
+	if err := c.collectLogs(b); err != nil {
+		c.warn(b, "Unable to collect control plane logs: %v", err)
+	}
+
+	c.UI.Output("Envoy configuration", terminal.WithInfoStyle())
+	if err := c.collectEnvoyConfigs(b); err != nil {
+		c.warn(b, "Unable to collect Envoy configuration: %v", err)
+	}
+
+	c.UI.Output("Cluster information", terminal.WithInfoStyle())
+	if err := c.collectClusterInfo(b); err != nil {
+		c.warn(b, "Unable to collect cluster information: %v", err)
+	}
+
+	return b
+}
+
+// warn records a problem in the bundle and reports it to the user.
+func (c *BundleCommand) warn(b *bundle, format string, args ...interface{}) {
+	b.addError(format, args...)
+	c.UI.Output(fmt.Sprintf(format, args...), terminal.WithWarningStyle())
+}
+
+func writeBundle(b *bundle, outputFile string) error {
+	file, err := os.OpenFile(outputFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	root := strings.TrimSuffix(filepath.Base(outputFile), ".tar.gz")
+	if err := b.write(file, root); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,312 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	helmRelease "helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	"github.com/hashicorp/consul-k8s/cli/common/envoy"
+	cmnFlag "github.com/hashicorp/consul-k8s/cli/common/flag"
+	"github.com/hashicorp/consul-k8s/cli/common/terminal"
+	"github.com/hashicorp/consul-k8s/cli/helm"
+)
+
+func TestFlagParsing(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+		out  int
+	}{
+		"Positional argument passed": {
+			args: []string{"bundle.tar.gz"},
+			out:  1,
+		},
+		"Nonexistent flag passed, -foo bar": {
+			args: []string{"-foo", "bar"},
+			out:  1,
+		},
+		"Invalid argument passed, -proxy-namespace YOLO": {
+			args: []string{"-proxy-namespace", "YOLO"},
+			out:  1,
+		},
+		"Negative -max-proxies": {
+			args: []string{"-max-proxies", "-1"},
+			out:  1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := setupCommand(new(bytes.Buffer))
+			c.kubernetes = fake.NewSimpleClientset()
+
+			out := c.Run(tc.args)
+			require.Equal(t, tc.out, out)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	serviceDefaults := schema.GroupVersionResource{Group: "consul.hashicorp.com", Version: "v1alpha1", Resource: "servicedefaults"}
+
+	client := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "consul-server-0",
+				Namespace: "consul",
+				Labels:    map[string]string{"app": "consul", "release": "consul"},
+			},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: "locality-init"}},
+				ContainerStatuses:     []corev1.ContainerStatus{{Name: "consul", RestartCount: 1}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: "default",
+				Labels:    map[string]string{"consul.hashicorp.com/connect-inject-status": "injected"},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api",
+				Namespace: "default",
+				Labels:    map[string]string{"consul.hashicorp.com/connect-inject-status": "injected"},
+			},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	)
+	client.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: serviceDefaults.GroupVersion().String(),
+			APIResources: []metav1.APIResource{
+				{Name: "servicedefaults", Kind: "ServiceDefaults", Namespaced: true, Verbs: []string{"list", "get"}},
+				{Name: "servicedefaults/status", Kind: "ServiceDefaults", Namespaced: true, Verbs: []string{"get"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: []string{"list"}},
+			},
+		},
+	}
+
+	dynamicClient := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{serviceDefaults: "ServiceDefaultsList"},
+		&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "consul.hashicorp.com/v1alpha1",
+			"kind":       "ServiceDefaults",
+			"metadata": map[string]interface{}{
+				"name":      "web",
+				"namespace": "default",
+			},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Synced", "status": "True"},
+				},
+			},
+		}},
+	)
+
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	c.kubernetes = client
+	c.dynamic = dynamicClient
+	c.restConfig = &rest.Config{}
+	c.helmActionsRunner = &helm.MockActionRunner{
+		CheckForInstallationsFunc: func(options *helm.CheckForInstallationsOptions) (bool, string, string, error) {
+			return true, "consul", "consul", nil
+		},
+		GetStatusFunc: func(status *action.Status, name string) (*helmRelease.Release, error) {
+			return &helmRelease.Release{
+				Name: "consul", Namespace: "consul", Version: 2,
+				Info:   &helmRelease.Info{Status: "deployed"},
+				Chart:  &chart.Chart{Metadata: &chart.Metadata{Version: "1.2.0", AppVersion: "1.16.0"}},
+				Config: map[string]interface{}{"global": map[string]interface{}{"gossipEncryption": map[string]interface{}{"autoGenerate": true}, "acls": map[string]interface{}{"bootstrapToken": "1234"}}},
+			}, nil
+		},
+	}
+	c.fetchConfig = func(_ context.Context, pf common.PortForwarder) (*envoy.EnvoyConfig, error) {
+		if pf.(*common.PortForward).PodName == "api" {
+			return nil, errors.New("connection refused")
+		}
+		config := &envoy.EnvoyConfig{}
+		err := json.Unmarshal([]byte(`{"config_dump": {"configs": []}, "clusters": {"cluster_statuses": []}}`), config)
+		return config, err
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	out := c.Run([]string{"-output-file", outputFile})
+	require.Equal(t, 0, out)
+	require.Contains(t, buf.String(), fmt.Sprintf("Support bundle written to %s", outputFile))
+	require.Contains(t, buf.String(), "Unable to collect the Envoy configuration of Pod default/api: connection refused")
+
+	raw, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	files := readBundle(t, raw)
+
+	require.Contains(t, files["bundle/helm/release.json"], `"chartVersion": "1.2.0"`)
+	require.Contains(t, files["bundle/helm/values.yaml"], "bootstrapToken: <redacted>")
+	require.Contains(t, files["bundle/helm/values.yaml"], "autoGenerate: true")
+	require.Contains(t, files["bundle/crds/servicedefaults.consul.hashicorp.com.yaml"], "type: Synced")
+	require.NotContains(t, files, "bundle/crds/deployments.apps.yaml")
+	require.Contains(t, files, "bundle/logs/consul/consul-server-0/locality-init.log")
+	require.Contains(t, files, "bundle/logs/consul/consul-server-0/consul.log")
+	require.Contains(t, files, "bundle/logs/consul/consul-server-0/consul.previous.log")
+	require.JSONEq(t, `{"configs": []}`, files["bundle/envoy/default/web/web/config_dump.json"])
+	require.JSONEq(t, `{"cluster_statuses": []}`, files["bundle/envoy/default/web/web/clusters.json"])
+	require.Contains(t, files["bundle/cluster/nodes.yaml"], "name: node-1")
+	require.Contains(t, files, "bundle/cluster/version.json")
+
+	var m manifest
+	require.NoError(t, json.Unmarshal([]byte(files["bundle/manifest.json"]), &m))
+	require.Equal(t, "consul", m.ReleaseNamespace)
+	require.Equal(t, []string{"Unable to collect the Envoy configuration of Pod default/api: connection refused"}, m.Errors)
+	require.Len(t, m.Files, len(files)-1)
+}
+
+func TestRun_NoInstallation(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	c.kubernetes = fake.NewSimpleClientset()
+	c.dynamic = dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+	c.restConfig = &rest.Config{}
+	c.helmActionsRunner = &helm.MockActionRunner{
+		CheckForInstallationsFunc: func(options *helm.CheckForInstallationsOptions) (bool, string, string, error) {
+			return false, "", "", errors.New("couldn't find installation named 'consul'")
+		},
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	out := c.Run([]string{"-output-file", outputFile})
+	require.Equal(t, 0, out)
+
+	actual := buf.String()
+	require.Contains(t, actual, "Unable to collect the Helm release: couldn't find installation named 'consul'")
+	require.Contains(t, actual, "Unable to collect control plane logs: the namespace of the Consul installation is unknown")
+	require.Contains(t, actual, "problems were encountered while collecting the bundle")
+	require.FileExists(t, outputFile)
+}
+
+func TestRun_ExistingOutputFile(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	require.NoError(t, os.WriteFile(outputFile, []byte("existing"), 0600))
+
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+	c.kubernetes = fake.NewSimpleClientset()
+	c.dynamic = dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+	c.restConfig = &rest.Config{}
+	c.helmActionsRunner = &helm.MockActionRunner{}
+
+	out := c.Run([]string{"-output-file", outputFile})
+	require.Equal(t, 1, out)
+	require.Contains(t, buf.String(), "Error writing support bundle")
+}
+
+func setupCommand(buf io.Writer) *BundleCommand {
+	// Log at a test level to standard out.
+	log := hclog.New(&hclog.LoggerOptions{
+		Name:   "test",
+		Level:  hclog.Debug,
+		Output: os.Stdout,
+	})
+
+	// Setup and initialize the command struct
+	command := &BundleCommand{
+		BaseCommand: &common.BaseCommand{
+			Ctx: context.Background(),
+			Log: log,
+			UI:  terminal.NewUI(context.Background(), buf),
+		},
+	}
+	command.init()
+
+	return command
+}
+
+// readBundle returns the contents of the files in the gzipped tarball by their path.
+func readBundle(t *testing.T, raw []byte) map[string]string {
+	t.Helper()
+
+	gz, err := gzip.NewReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	files := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		contents, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = string(contents)
+	}
+
+	return files
+}
+
+func TestTaskCreateCommand_AutocompleteFlags(t *testing.T) {
+	t.Parallel()
+	buf := new(bytes.Buffer)
+	cmd := setupCommand(buf)
+
+	predictor := cmd.AutocompleteFlags()
+
+	// Test that we get the expected number of predictions
+	args := complete.Args{Last: "-"}
+	res := predictor.Predict(args)
+
+	// Grab the list of flags from the Flag object
+	flags := make([]string, 0)
+	cmd.set.VisitSets(func(name string, set *cmnFlag.Set) {
+		set.VisitAll(func(flag *flag.Flag) {
+			flags = append(flags, fmt.Sprintf("-%s", flag.Name))
+		})
+	})
+
+	// Verify that there is a prediction for each flag associated with the command
+	assert.Equal(t, len(flags), len(res))
+	assert.ElementsMatch(t, flags, res, "flags and predictions didn't match, make sure to add "+
+		"new flags to the command AutoCompleteFlags function")
+}
+
+func TestTaskCreateCommand_AutocompleteArgs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := setupCommand(buf)
+	c := cmd.AutocompleteArgs()
+	assert.Equal(t, complete.PredictNothing, c)
+}
@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package support
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+	"github.com/mitchellh/cli"
+)
+
+// SupportCommand provides a synopsis for the support subcommands (e.g. bundle).
+type SupportCommand struct {
+	*common.BaseCommand
+}
+
+// Run prints out information about the subcommands.
+func (c *SupportCommand) Run([]string) int {
+	return cli.RunResultHelp
+}
+
+func (c *SupportCommand) Help() string {
+	return fmt.Sprintf("%s\n\nUsage: consul-k8s support <subcommand>", c.Synopsis())
+}
+
+func (c *SupportCommand) Synopsis() string {
+	return "Collect diagnostics about a Consul installation on Kubernetes."
+}
@@ -4,15 +4,21 @@
 package proxy
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"strings"
 	"sync"
 
 	"github.com/hashicorp/consul-k8s/cli/common"
+	"github.com/hashicorp/consul-k8s/cli/common/envoy"
 	"github.com/hashicorp/consul-k8s/cli/common/flag"
 	"github.com/hashicorp/consul-k8s/cli/common/terminal"
 	troubleshoot "github.com/hashicorp/consul/troubleshoot/proxy"
+	"github.com/hashicorp/consul/troubleshoot/validate"
 	"github.com/posener/complete"
 	helmCLI "helm.sh/helm/v3/pkg/cli"
 	"k8s.io/apimachinery/pkg/api/validation"
@@ -28,6 +34,8 @@ const (
 	flagNamePod                 = "pod"
 	flagNameUpstreamEnvoyID     = "upstream-envoy-id"
 	flagNameUpstreamIP          = "upstream-ip"
+	flagNameConfigDumpFile      = "config-dump-file"
+	flagNameClustersFile        = "clusters-file"
 	DebugColor                  = "\033[0;36m%s\033[0m"
 )
 
@@ -45,6 +53,8 @@ type ProxyCommand struct {
 	flagPod             string
 	flagUpstreamEnvoyID string
 	flagUpstreamIP      string
+	flagConfigDumpFile  string
+	flagClustersFile    string
 
 	restConfig *rest.Config
 
@@ -78,6 +88,18 @@ func (c *ProxyCommand) init() {
 		Aliases: []string{"ip"},
 	})
 
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameConfigDumpFile,
+		Target: &c.flagConfigDumpFile,
+		Usage:  "Run the checks against a file saved from the `/config_dump` admin endpoint instead of a pod. No Kubernetes access is needed.",
+	})
+
+	f.StringVar(&flag.StringVar{
+		Name:   flagNameClustersFile,
+		Target: &c.flagClustersFile,
+		Usage:  "A file saved from the `/clusters?format=json` admin endpoint to run the checks against. Requires -config-dump-file.",
+	})
+
 	f = c.set.NewSet("Global Options")
 	f.StringVar(&flag.StringVar{
 		Name:    flagNameKubeConfig,
@@ -121,7 +143,7 @@ func (c *ProxyCommand) Run(args []string) int {
 		return 1
 	}
 
-	if c.kubernetes == nil {
+	if c.kubernetes == nil && c.flagConfigDumpFile == "" {
 		if err := c.initKubernetes(); err != nil {
 			c.UI.Output("Error initializing Kubernetes client: %v", err.Error(), terminal.WithErrorStyle())
 			return 1
@@ -143,8 +165,16 @@ func (c *ProxyCommand) validateFlags() error {
 		return fmt.Errorf("-upstream-envoy-id OR -upstream-ip is required.\n Please run `consul troubleshoot upstreams` to find the corresponding upstream.")
 	}
 
-	if c.flagPod == "" {
-		return fmt.Errorf("-pod flag is required")
+	if c.flagClustersFile != "" && c.flagConfigDumpFile == "" {
+		return fmt.Errorf("-clusters-file requires -config-dump-file")
+	}
+
+	if c.flagPod == "" && c.flagConfigDumpFile == "" {
+		return fmt.Errorf("-pod or -config-dump-file flag is required")
+	}
+
+	if c.flagPod != "" && c.flagConfigDumpFile != "" {
+		return fmt.Errorf("-pod and -config-dump-file cannot be used together")
 	}
 
 	if errs := validation.ValidateNamespaceName(c.flagNamespace, false); c.flagNamespace != "" && len(errs) > 0 {
@@ -186,6 +216,10 @@ func (c *ProxyCommand) initKubernetes() (err error) {
 }
 
 func (c *ProxyCommand) Troubleshoot() error {
+	if c.flagConfigDumpFile != "" {
+		return c.troubleshootFiles()
+	}
+
 	pf := common.PortForward{
 		Namespace:  c.flagNamespace,
 		PodName:    c.flagPod,
@@ -225,6 +259,67 @@ func (c *ProxyCommand) Troubleshoot() error {
 		return err
 	}
 
+	c.outputMessages(messages, nil)
+	return nil
+}
+
+// troubleshootFiles runs the checks against the saved admin endpoint responses
+// served by a FileAdmin. The saved files do not include the certificates and
+// statistics of the proxy, and the clusters file is optional, so the checks
+// which need an input that is missing are skipped and reported as such.
+func (c *ProxyCommand) troubleshootFiles() error {
+	admin := &envoy.FileAdmin{
+		ConfigDumpFile: c.flagConfigDumpFile,
+		ClustersFile:   c.flagClustersFile,
+	}
+
+	endpoint, err := admin.Open(c.Ctx)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+
+	configDump, err := fetchAdminEndpoint(c.Ctx, endpoint, "config_dump")
+	if err != nil {
+		return fmt.Errorf("unable to get Envoy config dump: %w", err)
+	}
+	indexedResources, err := troubleshoot.ParseConfigDump(configDump)
+	if err != nil {
+		return fmt.Errorf("unable to index Envoy resources: %w", err)
+	}
+
+	skipped := []string{
+		"Certificate validation: the certificates of the proxy are not part of the saved files",
+		"Statistics: the statistics of the proxy are not part of the saved files",
+	}
+
+	var messages validate.Messages
+	rawClusters, err := fetchAdminEndpoint(c.Ctx, endpoint, "clusters?format=json")
+	switch {
+	case errors.Is(err, errAdminEndpointNotFound):
+		skipped = append(skipped, fmt.Sprintf("Endpoint validation: pass -%s to validate the endpoints of the upstream", flagNameClustersFile))
+		messages = troubleshoot.Validate(indexedResources, c.flagUpstreamEnvoyID, c.flagUpstreamIP, false, nil)
+	case err != nil:
+		return fmt.Errorf("unable to get Envoy clusters: %w", err)
+	default:
+		clusters, err := troubleshoot.ParseClusters(rawClusters)
+		if err != nil {
+			return fmt.Errorf("unable to parse Envoy clusters: %w", err)
+		}
+		messages = troubleshoot.Validate(indexedResources, c.flagUpstreamEnvoyID, c.flagUpstreamIP, true, clusters)
+	}
+	if len(messages.Errors()) == 0 {
+		messages = append(messages, validate.Message{
+			Success: true,
+			Message: "Upstream resources are valid",
+		})
+	}
+
+	c.outputMessages(messages, skipped)
+	return nil
+}
+
+func (c *ProxyCommand) outputMessages(messages validate.Messages, skipped []string) {
 	c.UI.Output("Validation", terminal.WithHeaderStyle())
 	for _, o := range messages {
 		if o.Success {
@@ -237,7 +332,40 @@ func (c *ProxyCommand) Troubleshoot() error {
 		}
 	}
 
-	return nil
+	if len(skipped) > 0 {
+		c.UI.Output("Skipped", terminal.WithHeaderStyle())
+		for _, s := range skipped {
+			c.UI.Output(s, terminal.WithWarningStyle())
+		}
+	}
+}
+
+// errAdminEndpointNotFound is returned by fetchAdminEndpoint when the admin
+// API does not serve the requested endpoint.
+var errAdminEndpointNotFound = errors.New("admin endpoint not found")
+
+// fetchAdminEndpoint returns the response of the Envoy admin API at the given
+// address for the given path.
+func fetchAdminEndpoint(ctx context.Context, endpoint, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s/%s", endpoint, path), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errAdminEndpointNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from /%s", resp.StatusCode, path)
+	}
+
+	return io.ReadAll(resp.Body)
 }
 
 // AutocompleteFlags returns a mapping of supported flags and autocomplete
@@ -245,9 +373,11 @@ func (c *ProxyCommand) Troubleshoot() error {
 // complete flag such as "-foo" or "--foo".
 func (c *ProxyCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
-		fmt.Sprintf("-%s", flagNameNamespace):   complete.PredictNothing,
-		fmt.Sprintf("-%s", flagNameKubeConfig):  complete.PredictFiles("*"),
-		fmt.Sprintf("-%s", flagNameKubeContext): complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameNamespace):      complete.PredictNothing,
+		fmt.Sprintf("-%s", flagNameConfigDumpFile): complete.PredictFiles("*.json"),
+		fmt.Sprintf("-%s", flagNameClustersFile):   complete.PredictFiles("*.json"),
+		fmt.Sprintf("-%s", flagNameKubeConfig):     complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", flagNameKubeContext):    complete.PredictNothing,
 	}
 }
 
@@ -277,6 +407,7 @@ Usage: consul-k8s troubleshoot proxy [options]
 
   Examples:
     $ consul-k8s troubleshoot proxy -pod pod1 -upstream foo
+    $ consul-k8s troubleshoot proxy -config-dump-file config_dump.json -clusters-file clusters.json -upstream foo
 	
 	where 'pod1' is the pod running a consul proxy and 'foo' is the upstream envoy ID which 
 	can be obtained by running:
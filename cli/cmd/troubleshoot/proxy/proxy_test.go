@@ -42,6 +42,18 @@ func TestFlagParsing(t *testing.T) {
 			args: []string{"-upstream-envoy-id", "-upstream-ip"},
 			out:  1,
 		},
+		"Cannot pass both -pod and -config-dump-file flags, should fail": {
+			args: []string{"-upstream-envoy-id", "1234", "-pod", "pod1", "-config-dump-file", "config_dump.json"},
+			out:  1,
+		},
+		"Cannot pass -clusters-file without -config-dump-file, should fail": {
+			args: []string{"-upstream-envoy-id", "1234", "-clusters-file", "clusters.json"},
+			out:  1,
+		},
+		"Nonexistent -config-dump-file, should fail": {
+			args: []string{"-upstream-envoy-id", "1234", "-config-dump-file", "nonexistent.json"},
+			out:  1,
+		},
 	}
 
 	for name, tc := range cases {
@@ -54,6 +66,20 @@ func TestFlagParsing(t *testing.T) {
 	}
 }
 
+func TestTroubleshootFiles_SkipsMissingInputs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	c := setupCommand(buf)
+
+	out := c.Run([]string{"-upstream-envoy-id", "backend", "-config-dump-file", "../../../common/envoy/testdata/test_config_dump.json"})
+	require.Equal(t, 0, out)
+
+	actual := buf.String()
+	require.Contains(t, actual, "Skipped")
+	require.Contains(t, actual, "Certificate validation: the certificates of the proxy are not part of the saved files")
+	require.Contains(t, actual, "Statistics: the statistics of the proxy are not part of the saved files")
+	require.Contains(t, actual, "Endpoint validation: pass -clusters-file to validate the endpoints of the upstream")
+}
+
 func setupCommand(buf io.Writer) *ProxyCommand {
 	// Log at a test level to standard out.
 	log := hclog.New(&hclog.LoggerOptions{
@@ -7,13 +7,20 @@ import (
 	"context"
 
 	"github.com/hashicorp/consul-k8s/cli/cmd/config"
+	config_delete "github.com/hashicorp/consul-k8s/cli/cmd/config/delete"
+	config_list "github.com/hashicorp/consul-k8s/cli/cmd/config/list"
 	config_read "github.com/hashicorp/consul-k8s/cli/cmd/config/read"
+	config_write "github.com/hashicorp/consul-k8s/cli/cmd/config/write"
 	"github.com/hashicorp/consul-k8s/cli/cmd/install"
 	"github.com/hashicorp/consul-k8s/cli/cmd/proxy"
+	"github.com/hashicorp/consul-k8s/cli/cmd/proxy/diff"
 	"github.com/hashicorp/consul-k8s/cli/cmd/proxy/list"
 	"github.com/hashicorp/consul-k8s/cli/cmd/proxy/loglevel"
 	"github.com/hashicorp/consul-k8s/cli/cmd/proxy/read"
+	"github.com/hashicorp/consul-k8s/cli/cmd/proxy/stats"
 	"github.com/hashicorp/consul-k8s/cli/cmd/status"
+	"github.com/hashicorp/consul-k8s/cli/cmd/support"
+	"github.com/hashicorp/consul-k8s/cli/cmd/support/bundle"
 	"github.com/hashicorp/consul-k8s/cli/cmd/troubleshoot"
 	troubleshoot_proxy "github.com/hashicorp/consul-k8s/cli/cmd/troubleshoot/proxy"
 	"github.com/hashicorp/consul-k8s/cli/cmd/troubleshoot/upstreams"
@@ -66,6 +73,11 @@ func initializeCommands(ctx context.Context, log hclog.Logger) (*common.BaseComm
 				BaseCommand: baseCommand,
 			}, nil
 		},
+		"proxy diff": func() (cli.Command, error) {
+			return &diff.DiffCommand{
+				BaseCommand: baseCommand,
+			}, nil
+		},
 		"proxy list": func() (cli.Command, error) {
 			return &list.ListCommand{
 				BaseCommand: baseCommand,
@@ -81,6 +93,11 @@ func initializeCommands(ctx context.Context, log hclog.Logger) (*common.BaseComm
 				BaseCommand: baseCommand,
 			}, nil
 		},
+		"proxy stats": func() (cli.Command, error) {
+			return &stats.StatsCommand{
+				BaseCommand: baseCommand,
+			}, nil
+		},
 		"config": func() (cli.Command, error) {
 			return &config.ConfigCommand{
 				BaseCommand: baseCommand,
@@ -91,6 +108,31 @@ func initializeCommands(ctx context.Context, log hclog.Logger) (*common.BaseComm
 				BaseCommand: baseCommand,
 			}, nil
 		},
+		"config list": func() (cli.Command, error) {
+			return &config_list.ListCommand{
+				BaseCommand: baseCommand,
+			}, nil
+		},
+		"config write": func() (cli.Command, error) {
+			return &config_write.WriteCommand{
+				BaseCommand: baseCommand,
+			}, nil
+		},
+		"config delete": func() (cli.Command, error) {
+			return &config_delete.DeleteCommand{
+				BaseCommand: baseCommand,
+			}, nil
+		},
+		"support": func() (cli.Command, error) {
+			return &support.SupportCommand{
+				BaseCommand: baseCommand,
+			}, nil
+		},
+		"support bundle": func() (cli.Command, error) {
+			return &bundle.BundleCommand{
+				BaseCommand: baseCommand,
+			}, nil
+		},
 		"troubleshoot": func() (cli.Command, error) {
 			return &troubleshoot.TroubleshootCommand{
 				BaseCommand: baseCommand,
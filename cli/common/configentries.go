@@ -0,0 +1,224 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	apicommon "github.com/hashicorp/consul-k8s/control-plane/api/common"
+	"github.com/hashicorp/consul-k8s/control-plane/api/v1alpha1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+// ConfigEntryGroupVersion is the API group and version of the Consul config
+// entry custom resources.
+var ConfigEntryGroupVersion = schema.GroupVersion{Group: "consul.hashicorp.com", Version: "v1alpha1"}
+
+// configEntryResources maps the kinds of the Consul config entry custom
+// resources to their resource names.
+var configEntryResources = map[string]string{
+	"ControlPlaneRequestLimit": "controlplanerequestlimits",
+	"ExportedServices":         "exportedservices",
+	"IngressGateway":           "ingressgateways",
+	"JWTProvider":              "jwtproviders",
+	"Mesh":                     "meshes",
+	"ProxyDefaults":            "proxydefaults",
+	"SamenessGroup":            "samenessgroups",
+	"ServiceDefaults":          "servicedefaults",
+	"ServiceIntentions":        "serviceintentions",
+	"ServiceResolver":          "serviceresolvers",
+	"ServiceRouter":            "servicerouters",
+	"ServiceSplitter":          "servicesplitters",
+	"TerminatingGateway":       "terminatinggateways",
+}
+
+// configEntryTypes maps the kinds of the Consul config entry custom resources
+// to constructors of their types.
+var configEntryTypes = map[string]func() apicommon.ConfigEntryResource{
+	"ControlPlaneRequestLimit": func() apicommon.ConfigEntryResource { return &v1alpha1.ControlPlaneRequestLimit{} },
+	"ExportedServices":         func() apicommon.ConfigEntryResource { return &v1alpha1.ExportedServices{} },
+	"IngressGateway":           func() apicommon.ConfigEntryResource { return &v1alpha1.IngressGateway{} },
+	"JWTProvider":              func() apicommon.ConfigEntryResource { return &v1alpha1.JWTProvider{} },
+	"Mesh":                     func() apicommon.ConfigEntryResource { return &v1alpha1.Mesh{} },
+	"ProxyDefaults":            func() apicommon.ConfigEntryResource { return &v1alpha1.ProxyDefaults{} },
+	"SamenessGroup":            func() apicommon.ConfigEntryResource { return &v1alpha1.SamenessGroup{} },
+	"ServiceDefaults":          func() apicommon.ConfigEntryResource { return &v1alpha1.ServiceDefaults{} },
+	"ServiceIntentions":        func() apicommon.ConfigEntryResource { return &v1alpha1.ServiceIntentions{} },
+	"ServiceResolver":          func() apicommon.ConfigEntryResource { return &v1alpha1.ServiceResolver{} },
+	"ServiceRouter":            func() apicommon.ConfigEntryResource { return &v1alpha1.ServiceRouter{} },
+	"ServiceSplitter":          func() apicommon.ConfigEntryResource { return &v1alpha1.ServiceSplitter{} },
+	"TerminatingGateway":       func() apicommon.ConfigEntryResource { return &v1alpha1.TerminatingGateway{} },
+}
+
+// ConfigEntryKinds returns the kinds of the Consul config entry custom resources
+// in alphabetical order.
+func ConfigEntryKinds() []string {
+	kinds := make([]string, 0, len(configEntryResources))
+	for kind := range configEntryResources {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// ConfigEntryKind returns the kind of the Consul config entry custom resource
+// named by the given kind or resource name, ignoring case. The resource name
+// may be singular or plural, e.g. "ServiceResolver", "serviceresolver" and
+// "serviceresolvers" all return "ServiceResolver".
+func ConfigEntryKind(name string) (string, bool) {
+	for kind, resource := range configEntryResources {
+		if strings.EqualFold(name, kind) || strings.EqualFold(name, resource) {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+// ConfigEntryResource returns the resource of a Consul config entry kind.
+func ConfigEntryResource(kind string) schema.GroupVersionResource {
+	return ConfigEntryGroupVersion.WithResource(configEntryResources[kind])
+}
+
+// ParseConfigEntries parses Consul config entry custom resources from YAML or
+// JSON. Multiple resources can be separated by `---`.
+func ParseConfigEntries(raw []byte) ([]*unstructured.Unstructured, error) {
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(raw), 4096)
+
+	var entries []*unstructured.Unstructured
+	for {
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		// Skip empty documents.
+		if len(object) == 0 {
+			continue
+		}
+
+		entry := &unstructured.Unstructured{Object: object}
+		if entry.GroupVersionKind().GroupVersion() != ConfigEntryGroupVersion {
+			return nil, fmt.Errorf("%s %q is not a Consul config entry: apiVersion must be %s", entry.GetKind(), entry.GetName(), ConfigEntryGroupVersion)
+		}
+		if _, ok := configEntryResources[entry.GetKind()]; !ok {
+			return nil, fmt.Errorf("%q is not a Consul config entry kind, must be one of %s", entry.GetKind(), strings.Join(ConfigEntryKinds(), ", "))
+		}
+		if entry.GetName() == "" {
+			return nil, fmt.Errorf("%s is missing metadata.name", entry.GetKind())
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("no config entries found")
+	}
+	return entries, nil
+}
+
+// ValidateConfigEntry decodes the config entry into its custom resource type and
+// validates it with the Validate method that the admission webhooks of Consul
+// use. Like the webhooks, the namespace fields are defaulted first.
+func ValidateConfigEntry(entry *unstructured.Unstructured, consulMeta apicommon.ConsulMeta) error {
+	newConfigEntry, ok := configEntryTypes[entry.GetKind()]
+	if !ok {
+		return fmt.Errorf("%q is not a Consul config entry kind, must be one of %s", entry.GetKind(), strings.Join(ConfigEntryKinds(), ", "))
+	}
+
+	configEntry := newConfigEntry()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructuredWithValidation(entry.Object, configEntry, true); err != nil {
+		return err
+	}
+	configEntry.DefaultNamespaceFields(consulMeta)
+	return configEntry.Validate(consulMeta)
+}
+
+// ApplyConfigEntry creates the config entry or replaces it if it exists. When
+// replacing it, the finalizers of the existing resource are kept, such as the one
+// the controller uses to delete the config entry from Consul, and its labels and
+// annotations are merged with those of the given entry. With dryRun, the request
+// is only validated by the Kubernetes API server, which includes the admission
+// webhooks of Consul that validate config entries. It returns "created" or
+// "configured".
+func ApplyConfigEntry(ctx context.Context, client dynamic.Interface, entry *unstructured.Unstructured, dryRun bool) (string, error) {
+	resource := client.Resource(ConfigEntryResource(entry.GetKind())).Namespace(entry.GetNamespace())
+
+	var dryRunOpts []string
+	if dryRun {
+		dryRunOpts = []string{metav1.DryRunAll}
+	}
+
+	existing, err := resource.Get(ctx, entry.GetName(), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = resource.Create(ctx, entry, metav1.CreateOptions{DryRun: dryRunOpts})
+		return "created", err
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_, err = resource.Update(ctx, mergeConfigEntry(existing, entry), metav1.UpdateOptions{DryRun: dryRunOpts})
+	return "configured", err
+}
+
+// mergeConfigEntry returns the existing config entry with the content of entry.
+// The metadata of the existing resource is kept, apart from the labels and
+// annotations of entry which are added to it.
+func mergeConfigEntry(existing, entry *unstructured.Unstructured) *unstructured.Unstructured {
+	merged := existing.DeepCopy()
+	for field := range merged.Object {
+		if _, ok := entry.Object[field]; !ok && field != "metadata" && field != "status" {
+			delete(merged.Object, field)
+		}
+	}
+	for field, value := range entry.DeepCopy().Object {
+		if field != "metadata" && field != "status" {
+			merged.Object[field] = value
+		}
+	}
+
+	merged.SetLabels(mergeStringMaps(existing.GetLabels(), entry.GetLabels()))
+	merged.SetAnnotations(mergeStringMaps(existing.GetAnnotations(), entry.GetAnnotations()))
+	return merged
+}
+
+func mergeStringMaps(existing, added map[string]string) map[string]string {
+	if len(existing) == 0 && len(added) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]string, len(existing)+len(added))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range added {
+		merged[k] = v
+	}
+	return merged
+}
+
+// DeleteConfigEntry deletes the config entry. With dryRun, the request is only
+// validated by the Kubernetes API server.
+func DeleteConfigEntry(ctx context.Context, client dynamic.Interface, kind, namespace, name string, dryRun bool) error {
+	var dryRunOpts []string
+	if dryRun {
+		dryRunOpts = []string{metav1.DryRunAll}
+	}
+
+	return client.Resource(ConfigEntryResource(kind)).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOpts})
+}
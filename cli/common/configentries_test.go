@@ -0,0 +1,325 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apicommon "github.com/hashicorp/consul-k8s/control-plane/api/common"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestConfigEntryKind(t *testing.T) {
+	for _, name := range []string{"ServiceResolver", "serviceresolver", "serviceresolvers", "SERVICERESOLVERS"} {
+		kind, ok := ConfigEntryKind(name)
+		require.True(t, ok, name)
+		require.Equal(t, "ServiceResolver", kind)
+	}
+
+	kind, ok := ConfigEntryKind("meshes")
+	require.True(t, ok)
+	require.Equal(t, "Mesh", kind)
+
+	_, ok = ConfigEntryKind("deployments")
+	require.False(t, ok)
+}
+
+func TestParseConfigEntries(t *testing.T) {
+	cases := map[string]struct {
+		raw         string
+		expected    []string
+		expectedErr string
+	}{
+		"multiple documents": {
+			raw: `
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceDefaults
+metadata:
+  name: web
+spec:
+  protocol: http
+---
+---
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceIntentions
+metadata:
+  name: web
+  namespace: apps
+spec:
+  destination:
+    name: web
+`,
+			expected: []string{"ServiceDefaults//web", "ServiceIntentions/apps/web"},
+		},
+		"json": {
+			raw:      `{"apiVersion": "consul.hashicorp.com/v1alpha1", "kind": "Mesh", "metadata": {"name": "mesh"}}`,
+			expected: []string{"Mesh//mesh"},
+		},
+		"other group": {
+			raw: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`,
+			expectedErr: `Deployment "web" is not a Consul config entry: apiVersion must be consul.hashicorp.com/v1alpha1`,
+		},
+		"other kind": {
+			raw: `
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: PeeringAcceptor
+metadata:
+  name: web
+`,
+			expectedErr: `"PeeringAcceptor" is not a Consul config entry kind, must be one of ControlPlaneRequestLimit, ExportedServices, IngressGateway, JWTProvider, Mesh, ProxyDefaults, SamenessGroup, ServiceDefaults, ServiceIntentions, ServiceResolver, ServiceRouter, ServiceSplitter, TerminatingGateway`,
+		},
+		"missing name": {
+			raw: `
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceDefaults
+spec:
+  protocol: http
+`,
+			expectedErr: "ServiceDefaults is missing metadata.name",
+		},
+		"empty": {
+			raw:         "---\n",
+			expectedErr: "no config entries found",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			entries, err := ParseConfigEntries([]byte(tc.raw))
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			actual := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				actual = append(actual, entry.GetKind()+"/"+entry.GetNamespace()+"/"+entry.GetName())
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestConfigEntryTypes(t *testing.T) {
+	kinds := make([]string, 0, len(configEntryTypes))
+	for kind, newConfigEntry := range configEntryTypes {
+		kinds = append(kinds, kind)
+		require.Equal(t, strings.ToLower(kind), newConfigEntry().KubeKind())
+	}
+	require.ElementsMatch(t, ConfigEntryKinds(), kinds)
+}
+
+func TestValidateConfigEntry(t *testing.T) {
+	cases := map[string]struct {
+		raw         string
+		consulMeta  apicommon.ConsulMeta
+		expectedErr string
+	}{
+		"valid": {
+			raw: `
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceDefaults
+metadata:
+  name: web
+spec:
+  protocol: http
+`,
+		},
+		"invalid value": {
+			raw: `
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceDefaults
+metadata:
+  name: web
+spec:
+  protocol: https
+`,
+			expectedErr: `servicedefaults.consul.hashicorp.com "web" is invalid: spec.protocol: Invalid value: "https": must be one of "tcp", "http", "http2", "grpc"`,
+		},
+		"unknown field": {
+			raw: `
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceDefaults
+metadata:
+  name: web
+spec:
+  protocl: http
+`,
+			expectedErr: `strict decoding error: unknown field "spec.protocl"`,
+		},
+		"missing required field": {
+			raw: `
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceIntentions
+metadata:
+  name: web
+spec:
+  destination:
+    name: web
+`,
+			expectedErr: `serviceintentions.consul.hashicorp.com "web" is invalid: spec.sources: Required value: at least one source must be specified`,
+		},
+		"namespaces disabled": {
+			raw: `
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceIntentions
+metadata:
+  name: web
+spec:
+  destination:
+    name: web
+    namespace: apps
+  sources:
+  - name: api
+    action: allow
+`,
+			expectedErr: `serviceintentions.consul.hashicorp.com "web" is invalid: spec.destination.namespace: Invalid value: "apps": Consul Enterprise namespaces must be enabled to set destination.namespace`,
+		},
+		"namespaces enabled": {
+			raw: `
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceIntentions
+metadata:
+  name: web
+spec:
+  destination:
+    name: web
+    namespace: apps
+  sources:
+  - name: api
+    action: allow
+`,
+			consulMeta: apicommon.ConsulMeta{NamespacesEnabled: true, DestinationNamespace: "default"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			entries, err := ParseConfigEntries([]byte(tc.raw))
+			require.NoError(t, err)
+
+			err = ValidateConfigEntry(entries[0], tc.consulMeta)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestApplyConfigEntry(t *testing.T) {
+	client := newDynamicClient(t)
+
+	entries, err := ParseConfigEntries([]byte(`
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceDefaults
+metadata:
+  name: web
+  namespace: default
+spec:
+  protocol: http
+`))
+	require.NoError(t, err)
+
+	result, err := ApplyConfigEntry(context.Background(), client, entries[0], false)
+	require.NoError(t, err)
+	require.Equal(t, "created", result)
+
+	entries[0].Object["spec"] = map[string]interface{}{"protocol": "grpc"}
+	result, err = ApplyConfigEntry(context.Background(), client, entries[0], false)
+	require.NoError(t, err)
+	require.Equal(t, "configured", result)
+
+	actual, err := client.Resource(ConfigEntryResource("ServiceDefaults")).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"protocol": "grpc"}, actual.Object["spec"])
+}
+
+func TestApplyConfigEntry_KeepsMetadata(t *testing.T) {
+	existing := &unstructured.Unstructured{}
+	existing.SetAPIVersion(ConfigEntryGroupVersion.String())
+	existing.SetKind("ServiceDefaults")
+	existing.SetNamespace("default")
+	existing.SetName("web")
+	existing.SetFinalizers([]string{"finalizers.consul.hashicorp.com"})
+	existing.SetLabels(map[string]string{"team": "payments"})
+	existing.SetAnnotations(map[string]string{"consul.hashicorp.com/synced-at": "now"})
+	existing.Object["spec"] = map[string]interface{}{"protocol": "http"}
+	client := newDynamicClient(t, existing)
+
+	entries, err := ParseConfigEntries([]byte(`
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceDefaults
+metadata:
+  name: web
+  namespace: default
+  labels:
+    env: prod
+spec:
+  protocol: grpc
+`))
+	require.NoError(t, err)
+
+	result, err := ApplyConfigEntry(context.Background(), client, entries[0], false)
+	require.NoError(t, err)
+	require.Equal(t, "configured", result)
+
+	actual, err := client.Resource(ConfigEntryResource("ServiceDefaults")).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"protocol": "grpc"}, actual.Object["spec"])
+	require.Equal(t, []string{"finalizers.consul.hashicorp.com"}, actual.GetFinalizers())
+	require.Equal(t, map[string]string{"team": "payments", "env": "prod"}, actual.GetLabels())
+	require.Equal(t, map[string]string{"consul.hashicorp.com/synced-at": "now"}, actual.GetAnnotations())
+}
+
+func TestDeleteConfigEntry(t *testing.T) {
+	entries, err := ParseConfigEntries([]byte(`
+apiVersion: consul.hashicorp.com/v1alpha1
+kind: ServiceDefaults
+metadata:
+  name: web
+  namespace: default
+`))
+	require.NoError(t, err)
+	client := newDynamicClient(t, entries[0])
+
+	err = DeleteConfigEntry(context.Background(), client, "ServiceDefaults", "default", "web", false)
+	require.NoError(t, err)
+
+	_, err = client.Resource(ConfigEntryResource("ServiceDefaults")).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err))
+
+	err = DeleteConfigEntry(context.Background(), client, "ServiceDefaults", "default", "web", false)
+	require.True(t, k8serrors.IsNotFound(err))
+}
+
+// newDynamicClient returns a fake dynamic client holding the config entries.
+// They are created with the resources of their kinds, since the fake client
+// otherwise guesses their plural names, e.g. servicedefaultses.
+func newDynamicClient(t *testing.T, entries ...*unstructured.Unstructured) *dynamicFake.FakeDynamicClient {
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for _, kind := range ConfigEntryKinds() {
+		listKinds[ConfigEntryResource(kind)] = kind + "List"
+	}
+	client := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	for _, entry := range entries {
+		require.NoError(t, client.Tracker().Create(ConfigEntryResource(entry.GetKind()), entry, entry.GetNamespace()))
+	}
+	return client
+}
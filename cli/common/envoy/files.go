@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envoy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+)
+
+// ReadConfigFiles reads the configuration of an Envoy proxy from the saved
+// responses of its `/config_dump` and `/clusters?format=json` admin endpoints,
+// such as those found in support bundles. The clusters file is optional;
+// without it, clusters will not list their endpoints.
+func ReadConfigFiles(configDumpFile, clustersFile string) (*EnvoyConfig, error) {
+	configDump, clusters, err := readAdminFiles(configDumpFile, clustersFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseConfig(configDump, clusters)
+}
+
+// FileAdmin serves the saved responses of the config dump and clusters endpoints
+// of an Envoy admin API so that tools which need a live admin API can be run
+// against them. It implements common.PortForwarder so that it can be used in
+// place of a port forward to a Pod.
+type FileAdmin struct {
+	ConfigDumpFile string
+	ClustersFile   string
+
+	server *http.Server
+}
+
+// Open starts serving the files on a local port and returns its address.
+// Requests to any other admin endpoint, and to the clusters endpoint if no
+// clusters file was given, are answered with 404 Not Found so that callers can
+// tell which inputs are missing.
+func (f *FileAdmin) Open(ctx context.Context) (string, error) {
+	configDump, clusters, err := readAdminFiles(f.ConfigDumpFile, f.ClustersFile)
+	if err != nil {
+		return "", err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/config_dump", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(configDump)
+	})
+	if f.ClustersFile != "" {
+		mux.HandleFunc("/clusters", func(w http.ResponseWriter, _ *http.Request) {
+			w.Write(clusters)
+		})
+	}
+	mux.HandleFunc("/", http.NotFound)
+
+	f.server = &http.Server{Handler: mux}
+	go func() {
+		// Serve always returns an error once the server is closed.
+		_ = f.server.Serve(listener)
+	}()
+
+	return listener.Addr().String(), nil
+}
+
+// Close stops serving the files.
+func (f *FileAdmin) Close() {
+	if f.server != nil {
+		f.server.Close()
+	}
+}
+
+// readAdminFiles reads and checks the saved responses of the config dump and
+// clusters endpoints.
+func readAdminFiles(configDumpFile, clustersFile string) ([]byte, []byte, error) {
+	configDump, err := os.ReadFile(configDumpFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading config dump: %v", err)
+	}
+	if !json.Valid(configDump) {
+		return nil, nil, fmt.Errorf("config dump %s is not valid JSON", configDumpFile)
+	}
+
+	clusters := []byte("{}")
+	if clustersFile != "" {
+		if clusters, err = os.ReadFile(clustersFile); err != nil {
+			return nil, nil, fmt.Errorf("error reading clusters: %v", err)
+		}
+		if !json.Valid(clusters) {
+			return nil, nil, fmt.Errorf("clusters %s is not valid JSON", clustersFile)
+		}
+	}
+
+	return configDump, clusters, nil
+}
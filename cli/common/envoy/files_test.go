@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envoy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadConfigFiles(t *testing.T) {
+	envoyConfig, err := ReadConfigFiles(fmt.Sprintf("testdata/%s", testConfigDump), fmt.Sprintf("testdata/%s", testClusters))
+	require.NoError(t, err)
+
+	require.Equal(t, testEnvoyConfig.Clusters, envoyConfig.Clusters)
+	require.Equal(t, testEnvoyConfig.Endpoints, envoyConfig.Endpoints)
+	require.Equal(t, testEnvoyConfig.Listeners, envoyConfig.Listeners)
+	require.Equal(t, testEnvoyConfig.Routes, envoyConfig.Routes)
+	require.Equal(t, testEnvoyConfig.Secrets, envoyConfig.Secrets)
+}
+
+func TestReadConfigFiles_WithoutClusters(t *testing.T) {
+	envoyConfig, err := ReadConfigFiles(fmt.Sprintf("testdata/%s", testConfigDump), "")
+	require.NoError(t, err)
+
+	require.Len(t, envoyConfig.Clusters, len(testEnvoyConfig.Clusters))
+	require.Equal(t, testEnvoyConfig.Listeners, envoyConfig.Listeners)
+	require.Equal(t, testEnvoyConfig.Routes, envoyConfig.Routes)
+}
+
+func TestReadConfigFiles_Errors(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte("not json"), 0600))
+
+	_, err := ReadConfigFiles("testdata/missing.json", "")
+	require.ErrorContains(t, err, "error reading config dump")
+
+	_, err = ReadConfigFiles(invalid, "")
+	require.EqualError(t, err, fmt.Sprintf("config dump %s is not valid JSON", invalid))
+
+	_, err = ReadConfigFiles(fmt.Sprintf("testdata/%s", testConfigDump), invalid)
+	require.EqualError(t, err, fmt.Sprintf("clusters %s is not valid JSON", invalid))
+}
+
+func TestFileAdmin(t *testing.T) {
+	admin := &FileAdmin{
+		ConfigDumpFile: fmt.Sprintf("testdata/%s", testConfigDump),
+		ClustersFile:   fmt.Sprintf("testdata/%s", testClusters),
+	}
+
+	envoyConfig, err := FetchConfig(context.Background(), admin)
+	require.NoError(t, err)
+
+	require.Equal(t, testEnvoyConfig.Clusters, envoyConfig.Clusters)
+	require.Equal(t, testEnvoyConfig.Endpoints, envoyConfig.Endpoints)
+	require.Equal(t, testEnvoyConfig.Listeners, envoyConfig.Listeners)
+}
+
+func TestFileAdmin_MissingEndpoints(t *testing.T) {
+	admin := &FileAdmin{
+		ConfigDumpFile: fmt.Sprintf("testdata/%s", testConfigDump),
+	}
+
+	endpoint, err := admin.Open(context.Background())
+	require.NoError(t, err)
+	defer admin.Close()
+
+	for path, expected := range map[string]int{
+		"config_dump":          http.StatusOK,
+		"clusters?format=json": http.StatusNotFound,
+		"certs":                http.StatusNotFound,
+		"stats?format=json":    http.StatusNotFound,
+	} {
+		resp, err := http.Get(fmt.Sprintf("http://%s/%s", endpoint, path))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, expected, resp.StatusCode, path)
+	}
+}
@@ -159,10 +159,16 @@ func FetchConfig(ctx context.Context, portForward common.PortForwarder) (*EnvoyC
 		return nil, err
 	}
 
+	return parseConfig(configDump, clusters)
+}
+
+// parseConfig combines the responses of the config dump and clusters endpoints
+// and parses them into an EnvoyConfig.
+func parseConfig(configDump, clusters []byte) (*EnvoyConfig, error) {
 	config := fmt.Sprintf("{\n\"config_dump\":%s,\n\"clusters\":%s}", string(configDump), string(clusters))
 
 	envoyConfig := &EnvoyConfig{}
-	err = json.Unmarshal([]byte(config), envoyConfig)
+	err := json.Unmarshal([]byte(config), envoyConfig)
 	if err != nil {
 		return nil, err
 	}
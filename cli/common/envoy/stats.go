@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envoy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/consul-k8s/cli/common"
+)
+
+// ProxyStats represents the statistics retrieved from the stats endpoint of the
+// admin API, grouped by cluster and listener.
+type ProxyStats struct {
+	Clusters  []ClusterStats
+	Listeners []ListenerStats
+}
+
+// ClusterStats represents the upstream counters of a cluster in Envoy.
+type ClusterStats struct {
+	Name                string
+	RequestsTotal       uint64
+	Requests5xx         uint64
+	ConnectionsActive   uint64
+	Retries             uint64
+	CircuitBreakerTrips uint64
+	OutlierEjections    uint64
+}
+
+// ListenerStats represents the downstream counters of a listener in Envoy.
+type ListenerStats struct {
+	Name              string
+	ConnectionsTotal  uint64
+	ConnectionsActive uint64
+	RequestsTotal     uint64
+	Requests5xx       uint64
+}
+
+const (
+	clusterStatPrefix  = "cluster."
+	listenerStatPrefix = "listener."
+)
+
+// clusterStats maps the name of a cluster statistic to the field it is counted in.
+// Circuit breaker trips are the sum of the overflow counters of each circuit breaker.
+var clusterStats = map[string]func(*ClusterStats, uint64){
+	"upstream_rq_total":                          func(s *ClusterStats, v uint64) { s.RequestsTotal = v },
+	"upstream_rq_5xx":                            func(s *ClusterStats, v uint64) { s.Requests5xx = v },
+	"upstream_cx_active":                         func(s *ClusterStats, v uint64) { s.ConnectionsActive = v },
+	"upstream_rq_retry":                          func(s *ClusterStats, v uint64) { s.Retries = v },
+	"upstream_cx_overflow":                       func(s *ClusterStats, v uint64) { s.CircuitBreakerTrips += v },
+	"upstream_rq_pending_overflow":               func(s *ClusterStats, v uint64) { s.CircuitBreakerTrips += v },
+	"upstream_rq_retry_overflow":                 func(s *ClusterStats, v uint64) { s.CircuitBreakerTrips += v },
+	"outlier_detection.ejections_enforced_total": func(s *ClusterStats, v uint64) { s.OutlierEjections = v },
+}
+
+// listenerStats maps the name of a listener statistic to the field it is counted in.
+// HTTP statistics are scoped by the stat prefix of the connection manager, so they
+// are summed over all connection managers on the listener.
+var listenerStats = map[string]func(*ListenerStats, uint64){
+	"downstream_cx_total":     func(s *ListenerStats, v uint64) { s.ConnectionsTotal = v },
+	"downstream_cx_active":    func(s *ListenerStats, v uint64) { s.ConnectionsActive = v },
+	"downstream_rq_completed": func(s *ListenerStats, v uint64) { s.RequestsTotal += v },
+	"downstream_rq_5xx":       func(s *ListenerStats, v uint64) { s.Requests5xx += v },
+}
+
+// clusterSubScopes are scopes Envoy nests below a cluster that repeat the
+// statistics of the cluster. They are skipped so they aren't mistaken for clusters.
+var clusterSubScopes = []string{".canary", ".internal", ".external"}
+
+// FetchStats opens a port forward to the Envoy admin API and fetches the
+// statistics from the stats endpoint.
+func FetchStats(ctx context.Context, portForward common.PortForwarder) (*ProxyStats, error) {
+	endpoint, err := portForward.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer portForward.Close()
+
+	response, err := http.Get(fmt.Sprintf("http://%s/stats", endpoint))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach envoy: %v", err)
+	}
+
+	if response.StatusCode >= 400 {
+		return nil, fmt.Errorf("call to envoy failed with status code: %d, and message: %s", response.StatusCode, body)
+	}
+
+	return ParseStats(body)
+}
+
+// ParseStats parses the plain text output of the stats endpoint. Each line has the
+// form `<name>: <value>`. Histograms and statistics which aren't counted per
+// cluster or listener are ignored.
+func ParseStats(raw []byte) (*ProxyStats, error) {
+	clusters := make(map[string]*ClusterStats)
+	listeners := make(map[string]*ListenerStats)
+
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		name, rawValue, found := strings.Cut(scanner.Text(), ": ")
+		if !found {
+			continue
+		}
+		value, err := strconv.ParseUint(strings.TrimSpace(rawValue), 10, 64)
+		if err != nil {
+			// Histograms don't have a single numeric value.
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(name, clusterStatPrefix):
+			parseClusterStat(strings.TrimPrefix(name, clusterStatPrefix), value, clusters)
+		case strings.HasPrefix(name, listenerStatPrefix):
+			parseListenerStat(strings.TrimPrefix(name, listenerStatPrefix), value, listeners)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	stats := &ProxyStats{
+		Clusters:  make([]ClusterStats, 0, len(clusters)),
+		Listeners: make([]ListenerStats, 0, len(listeners)),
+	}
+	for _, cluster := range clusters {
+		stats.Clusters = append(stats.Clusters, *cluster)
+	}
+	for _, listener := range listeners {
+		stats.Listeners = append(stats.Listeners, *listener)
+	}
+	sort.Slice(stats.Clusters, func(i, j int) bool { return stats.Clusters[i].Name < stats.Clusters[j].Name })
+	sort.Slice(stats.Listeners, func(i, j int) bool { return stats.Listeners[i].Name < stats.Listeners[j].Name })
+
+	return stats, nil
+}
+
+// parseClusterStat counts a statistic of the form `<cluster>.<stat>`. Cluster
+// names contain dots, so the cluster is found by trimming the known statistic.
+func parseClusterStat(name string, value uint64, clusters map[string]*ClusterStats) {
+	for stat, count := range clusterStats {
+		clusterName, found := strings.CutSuffix(name, "."+stat)
+		if !found {
+			continue
+		}
+		for _, scope := range clusterSubScopes {
+			if strings.HasSuffix(clusterName, scope) {
+				return
+			}
+		}
+		if strings.Contains(clusterName, ".zone.") {
+			return
+		}
+
+		cluster, ok := clusters[clusterName]
+		if !ok {
+			cluster = &ClusterStats{Name: clusterName}
+			clusters[clusterName] = cluster
+		}
+		count(cluster, value)
+		return
+	}
+}
+
+// parseListenerStat counts a statistic of the form `<address>.<stat>` or
+// `<address>.http.<stat_prefix>.<stat>`. Statistics of the admin listener and
+// per worker statistics are skipped.
+func parseListenerStat(name string, value uint64, listeners map[string]*ListenerStats) {
+	if strings.HasPrefix(name, "admin.") || strings.Contains(name, ".worker_") {
+		return
+	}
+
+	var address, stat string
+	if index := strings.Index(name, ".http."); index >= 0 {
+		address = name[:index]
+		stat = name[strings.LastIndex(name, ".")+1:]
+	} else {
+		index := strings.LastIndex(name, ".")
+		if index < 0 {
+			return
+		}
+		address, stat = name[:index], name[index+1:]
+	}
+
+	count, ok := listenerStats[stat]
+	if !ok {
+		return
+	}
+
+	listener, ok := listeners[address]
+	if !ok {
+		listener = &ListenerStats{Name: address}
+		listeners[address] = listener
+	}
+	count(listener, value)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envoy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testStats = "test_stats.txt"
+
+func TestParseStats(t *testing.T) {
+	raw, err := os.ReadFile(fmt.Sprintf("testdata/%s", testStats))
+	require.NoError(t, err)
+
+	stats, err := ParseStats(raw)
+	require.NoError(t, err)
+
+	require.Equal(t, testProxyStats, stats)
+}
+
+func TestFetchStats(t *testing.T) {
+	raw, err := os.ReadFile(fmt.Sprintf("testdata/%s", testStats))
+	require.NoError(t, err)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stats" {
+			w.Write(raw)
+		}
+	}))
+	defer mockServer.Close()
+
+	mpf := &mockPortForwarder{
+		openBehavior: func(ctx context.Context) (string, error) {
+			return strings.Replace(mockServer.URL, "http://", "", 1), nil
+		},
+	}
+
+	stats, err := FetchStats(context.Background(), mpf)
+	require.NoError(t, err)
+
+	require.Equal(t, testProxyStats, stats)
+}
+
+// testProxyStats is what we expect the stats at `test_stats.txt` to be.
+var testProxyStats = &ProxyStats{
+	Clusters: []ClusterStats{
+		{
+			Name:                "backend.default.dc1.internal.bc3815c2-1a0f-f3ff-a2e9-20d791f08d00.consul",
+			RequestsTotal:       120,
+			Requests5xx:         3,
+			ConnectionsActive:   4,
+			Retries:             5,
+			CircuitBreakerTrips: 3,
+			OutlierEjections:    2,
+		},
+		{
+			Name:              "local_app",
+			RequestsTotal:     80,
+			ConnectionsActive: 1,
+		},
+	},
+	Listeners: []ListenerStats{
+		{
+			Name:              "0.0.0.0_20000",
+			ConnectionsTotal:  10,
+			ConnectionsActive: 2,
+			RequestsTotal:     80,
+			Requests5xx:       1,
+		},
+		{
+			Name:              "127.0.0.1_15001",
+			ConnectionsTotal:  12,
+			ConnectionsActive: 3,
+		},
+	},
+}
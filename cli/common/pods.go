@@ -0,0 +1,206 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultEnvoyAdminPort is the port where the Envoy admin API of a proxy is exposed.
+	// Pods running multiple services expose the admin API of each proxy on
+	// consecutive ports starting at this port.
+	DefaultEnvoyAdminPort = 19000
+
+	// DefaultMaxConcurrency is the number of Pods which are operated on
+	// concurrently when a command targets multiple Pods.
+	DefaultMaxConcurrency = 10
+)
+
+// PodTargets describes the Pods a command operates on. Exactly one of PodName,
+// Selector or Deployment is expected to be set.
+type PodTargets struct {
+	// Namespace is the namespace the Pods are found in. It is ignored if
+	// AllNamespaces is set.
+	Namespace string
+	// PodName is the name of a single Pod.
+	PodName string
+	// Selector is a label selector matching the Pods.
+	Selector string
+	// Deployment is the name of a Deployment whose Pods are targeted.
+	Deployment string
+	// AllNamespaces looks up the Pods matching Selector in all namespaces.
+	AllNamespaces bool
+}
+
+// Validate checks that the targets select Pods in exactly one way.
+func (t PodTargets) Validate() error {
+	var set []string
+	if t.PodName != "" {
+		set = append(set, "<pod-name>")
+	}
+	if t.Selector != "" {
+		set = append(set, "-selector")
+	}
+	if t.Deployment != "" {
+		set = append(set, "-deployment")
+	}
+
+	if len(set) == 0 {
+		return fmt.Errorf("One of <pod-name>, -selector or -deployment is required")
+	}
+	if len(set) > 1 {
+		return fmt.Errorf("Only one of %s may be given", strings.Join(set, ", "))
+	}
+	if t.AllNamespaces && t.Selector == "" {
+		return fmt.Errorf("-all-namespaces can only be used with -selector")
+	}
+
+	return nil
+}
+
+// Multiple returns true if the targets can select more than one Pod.
+func (t PodTargets) Multiple() bool {
+	return t.PodName == ""
+}
+
+// ListPods returns the Pods described by the targets sorted by namespace and name.
+func (t PodTargets) ListPods(ctx context.Context, client kubernetes.Interface) ([]corev1.Pod, error) {
+	if t.PodName != "" {
+		pod, err := client.CoreV1().Pods(t.Namespace).Get(ctx, t.PodName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return []corev1.Pod{*pod}, nil
+	}
+
+	namespace, selector := t.Namespace, t.Selector
+	if t.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	if t.Deployment != "" {
+		deployment, err := client.AppsV1().Deployments(t.Namespace).Get(ctx, t.Deployment, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		labelSelector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector on deployment %s: %v", t.Deployment, err)
+		}
+		selector = labelSelector.String()
+	}
+
+	podList, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	if len(podList.Items) == 0 {
+		return nil, fmt.Errorf("no pods found matching the selector %q", selector)
+	}
+
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+
+	return pods, nil
+}
+
+// proxyPodSelectors are the label selectors matching the Pods which run Consul proxies.
+var proxyPodSelectors = []string{
+	// Gateways deployed by the Helm chart.
+	"component in (ingress-gateway, mesh-gateway, terminating-gateway), chart=consul-helm",
+	// API gateways.
+	"api-gateway.consul.hashicorp.com/managed=true",
+	// Services networked by Consul.
+	"consul.hashicorp.com/connect-inject-status=injected",
+}
+
+// ListProxyPods lists the Pods in the namespace which run Consul proxies. An
+// empty namespace lists the Pods in all namespaces.
+func ListProxyPods(ctx context.Context, client kubernetes.Interface, namespace string) ([]corev1.Pod, error) {
+	var pods []corev1.Pod
+	for _, selector := range proxyPodSelectors {
+		list, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, list.Items...)
+	}
+
+	return pods, nil
+}
+
+// EnvoyAdminPorts returns the admin ports of the Envoy proxies running in the Pod
+// keyed by the name of the proxy. Pods running multiple services have one proxy
+// per service, named after the service. Otherwise, the single proxy is named
+// after the Pod.
+func EnvoyAdminPorts(pod corev1.Pod) map[string]int {
+	adminPorts := make(map[string]int, 0)
+
+	connectService, isMultiport := pod.Annotations["consul.hashicorp.com/connect-service"]
+
+	if !isMultiport {
+		// Return the default port configuration.
+		adminPorts[pod.Name] = DefaultEnvoyAdminPort
+		return adminPorts
+	}
+
+	for index, service := range strings.Split(connectService, ",") {
+		adminPorts[service] = DefaultEnvoyAdminPort + index
+	}
+
+	return adminPorts
+}
+
+// PodResult is the result of running an operation against a single Pod.
+type PodResult[T any] struct {
+	Pod   corev1.Pod
+	Value T
+	Err   error
+}
+
+// ForEachPod runs fn against each of the Pods with at most maxConcurrency
+// running at once. The results are returned in the same order as the Pods.
+func ForEachPod[T any](ctx context.Context, pods []corev1.Pod, maxConcurrency int, fn func(context.Context, corev1.Pod) (T, error)) []PodResult[T] {
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+
+	results := make([]PodResult[T], len(pods))
+	sem := make(chan struct{}, maxConcurrency)
+
+	var wg sync.WaitGroup
+	for i, pod := range pods {
+		wg.Add(1)
+		go func(i int, pod corev1.Pod) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = PodResult[T]{Pod: pod, Err: ctx.Err()}
+				return
+			}
+
+			value, err := fn(ctx, pod)
+			results[i] = PodResult[T]{Pod: pod, Value: value, Err: err}
+		}(i, pod)
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package common
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPodTargets_Validate(t *testing.T) {
+	cases := map[string]struct {
+		targets     PodTargets
+		expectedErr string
+	}{
+		"pod name": {
+			targets: PodTargets{PodName: "pod"},
+		},
+		"selector in all namespaces": {
+			targets: PodTargets{Selector: "app=web", AllNamespaces: true},
+		},
+		"deployment": {
+			targets: PodTargets{Deployment: "web"},
+		},
+		"nothing": {
+			targets:     PodTargets{},
+			expectedErr: "One of <pod-name>, -selector or -deployment is required",
+		},
+		"pod name and selector": {
+			targets:     PodTargets{PodName: "pod", Selector: "app=web"},
+			expectedErr: "Only one of <pod-name>, -selector may be given",
+		},
+		"all namespaces with a deployment": {
+			targets:     PodTargets{Deployment: "web", AllNamespaces: true},
+			expectedErr: "-all-namespaces can only be used with -selector",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.targets.Validate()
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestPodTargets_ListPods(t *testing.T) {
+	webLabels := map[string]string{"app": "web"}
+	client := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-b", Namespace: "default", Labels: webLabels}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-a", Namespace: "default", Labels: webLabels}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-c", Namespace: "other", Labels: webLabels}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", Labels: map[string]string{"app": "api"}}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: webLabels},
+			},
+		},
+	)
+
+	cases := map[string]struct {
+		targets     PodTargets
+		expected    []string
+		expectedErr string
+	}{
+		"pod name": {
+			targets:  PodTargets{Namespace: "default", PodName: "api"},
+			expected: []string{"default/api"},
+		},
+		"selector": {
+			targets:  PodTargets{Namespace: "default", Selector: "app=web"},
+			expected: []string{"default/web-a", "default/web-b"},
+		},
+		"selector in all namespaces": {
+			targets:  PodTargets{Namespace: "default", Selector: "app=web", AllNamespaces: true},
+			expected: []string{"default/web-a", "default/web-b", "other/web-c"},
+		},
+		"deployment": {
+			targets:  PodTargets{Namespace: "default", Deployment: "web"},
+			expected: []string{"default/web-a", "default/web-b"},
+		},
+		"selector without matches": {
+			targets:     PodTargets{Namespace: "default", Selector: "app=missing"},
+			expectedErr: `no pods found matching the selector "app=missing"`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pods, err := tc.targets.ListPods(context.Background(), client)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			actual := make([]string, 0, len(pods))
+			for _, pod := range pods {
+				actual = append(actual, pod.Namespace+"/"+pod.Name)
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestListProxyPods(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"consul.hashicorp.com/connect-inject-status": "injected"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-gateway", Namespace: "other", Labels: map[string]string{"api-gateway.consul.hashicorp.com/managed": "true"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "mesh-gateway", Namespace: "consul", Labels: map[string]string{"component": "mesh-gateway", "chart": "consul-helm"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "consul-server-0", Namespace: "consul", Labels: map[string]string{"component": "server", "chart": "consul-helm"}}},
+	)
+
+	pods, err := ListProxyPods(context.Background(), client, "")
+	require.NoError(t, err)
+
+	actual := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		actual = append(actual, pod.Namespace+"/"+pod.Name)
+	}
+	require.ElementsMatch(t, []string{"default/web", "other/api-gateway", "consul/mesh-gateway"}, actual)
+
+	pods, err = ListProxyPods(context.Background(), client, "default")
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+}
+
+func TestEnvoyAdminPorts(t *testing.T) {
+	require.Equal(t, map[string]int{"pod": 19000}, EnvoyAdminPorts(corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod"},
+	}))
+	require.Equal(t, map[string]int{"web": 19000, "web-admin": 19001}, EnvoyAdminPorts(corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pod",
+			Annotations: map[string]string{"consul.hashicorp.com/connect-service": "web,web-admin"},
+		},
+	}))
+}
+
+func TestForEachPod(t *testing.T) {
+	pods := make([]corev1.Pod, 20)
+	for i := range pods {
+		pods[i] = corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: string(rune('a' + i))}}
+	}
+
+	var running, maxRunning int32
+	results := ForEachPod(context.Background(), pods, 3, func(ctx context.Context, pod corev1.Pod) (string, error) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if pod.Name == "c" {
+			return "", errors.New("failed")
+		}
+		return pod.Name, nil
+	})
+
+	require.LessOrEqual(t, maxRunning, int32(3))
+	require.Len(t, results, len(pods))
+	for i, result := range results {
+		require.Equal(t, pods[i].Name, result.Pod.Name)
+		if result.Pod.Name == "c" {
+			require.EqualError(t, result.Err, "failed")
+			continue
+		}
+		require.NoError(t, result.Err)
+		require.Equal(t, pods[i].Name, result.Value)
+	}
+}
@@ -4,11 +4,14 @@
 package binding
 
 import (
+	"sort"
+
 	mapset "github.com/deckarep/golang-set"
 	"github.com/go-logr/logr"
 	"github.com/hashicorp/consul-k8s/control-plane/api-gateway/common"
 	"github.com/hashicorp/consul-k8s/control-plane/api/v1alpha1"
 	"github.com/hashicorp/consul/api"
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,6 +38,9 @@ type BinderConfig struct {
 	// GatewayClass -- if it is nil we should treat the gateway as deleted
 	// since the gateway is now pointing to an invalid gateway class
 	GatewayClassConfig *v1alpha1.GatewayClassConfig
+	// GatewayParameters are the GatewayParameters referenced by the Gateway, if
+	// the Gateway references any and they exist.
+	GatewayParameters *v1alpha1.GatewayParameters
 	// GatewayClass is the GatewayClass corresponding to the Gateway we want to
 	// bind routes to. It is passed as a pointer because it could be nil. If no
 	// GatewayClass corresponds to a Gateway, we ought to clean up any sort of
@@ -48,6 +54,10 @@ type BinderConfig struct {
 	HTTPRoutes []gwv1beta1.HTTPRoute
 	// TCPRoutes is a list of TCPRoute objects that ought to be bound to the Gateway.
 	TCPRoutes []gwv1alpha2.TCPRoute
+	// GRPCRoutes is a list of GRPCRoute objects that ought to be bound to the Gateway.
+	GRPCRoutes []gwv1alpha2.GRPCRoute
+	// TLSRoutes is a list of TLSRoute objects that ought to be bound to the Gateway.
+	TLSRoutes []gwv1alpha2.TLSRoute
 	// Pods are any pods that are part of the Gateway deployment.
 	Pods []corev1.Pod
 	// Service is the deployed service associated with the Gateway deployment.
@@ -116,6 +126,7 @@ func (b *Binder) Snapshot() *Snapshot {
 
 	var gatewayValidation gatewayValidationResult
 	var listenerValidation listenerValidationResults
+	var parametersErr error
 
 	if !isGatewayDeleted {
 		var updated bool
@@ -131,7 +142,8 @@ func (b *Binder) Snapshot() *Snapshot {
 		}
 
 		// calculate the status for the gateway
-		gatewayValidation = validateGateway(b.config.Gateway, registrationPods, b.config.ConsulGateway)
+		parametersErr = validateGatewayParameters(b.config.Gateway, gatewayClassConfig, b.config.GatewayParameters)
+		gatewayValidation = validateGateway(b.config.Gateway, gatewayClassConfig, b.config.GatewayParameters, parametersErr, b.config.Service, registrationPods, b.config.ConsulGateway)
 		listenerValidation = validateListeners(b.config.Gateway, b.config.Gateway.Spec.Listeners, b.config.Resources)
 	}
 
@@ -150,6 +162,23 @@ func (b *Binder) Snapshot() *Snapshot {
 		b.bindRoute(common.PointerTo(r), boundCounts, snapshot)
 	}
 
+	for _, r := range b.config.GRPCRoutes {
+		b.bindRoute(common.PointerTo(r), boundCounts, snapshot)
+	}
+
+	// only a single TLSRoute can bind to a listener, so bind the oldest
+	// routes first to keep the bound route stable across reconciles
+	tlsRoutes := slices.Clone(b.config.TLSRoutes)
+	sort.SliceStable(tlsRoutes, func(i, j int) bool {
+		if !tlsRoutes[i].CreationTimestamp.Equal(&tlsRoutes[j].CreationTimestamp) {
+			return tlsRoutes[i].CreationTimestamp.Before(&tlsRoutes[j].CreationTimestamp)
+		}
+		return client.ObjectKeyFromObject(&tlsRoutes[i]).String() < client.ObjectKeyFromObject(&tlsRoutes[j]).String()
+	})
+	for _, r := range tlsRoutes {
+		b.bindRoute(common.PointerTo(r), boundCounts, snapshot)
+	}
+
 	// process secrets
 	gatewaySecrets := secretsForGateway(b.config.Gateway, b.config.Resources)
 	if !isGatewayDeleted {
@@ -176,6 +205,11 @@ func (b *Binder) Snapshot() *Snapshot {
 	// if the gateway hasn't been marked for deletion
 	if !isGatewayDeleted {
 		snapshot.GatewayClassConfig = gatewayClassConfig
+		// invalid parameters are reported on the gateway status and the
+		// deployment falls back to the class configuration
+		if parametersErr == nil {
+			snapshot.GatewayParameters = b.config.GatewayParameters
+		}
 		snapshot.UpsertGatewayDeployment = true
 
 		var consulStatus api.ConfigEntryStatus
@@ -291,8 +325,12 @@ func addressesForGateway(service *corev1.Service, pods []corev1.Pod) []gwv1beta1
 		 * nodes that the gateway pods are running on.
 		 * The practitioner will have to understand that they may need to port forward into the
 		 * cluster (in the case of Kind) or open firewall rules (in the case of GKE) in order to
-		 * access the gateway from outside the cluster.
+		 * access the gateway from outside the cluster. If the gateway requested external IPs
+		 * for the service, those are used instead.
 		 */
+		if len(service.Spec.ExternalIPs) > 0 {
+			return addressesFromExternalIPs(service)
+		}
 		return addressesFromPodHosts(pods)
 	}
 
@@ -330,6 +368,19 @@ func addressesFromClusterIP(service *corev1.Service) []gwv1beta1.GatewayAddress
 		})
 	}
 
+	return append(addresses, addressesFromExternalIPs(service)...)
+}
+
+func addressesFromExternalIPs(service *corev1.Service) []gwv1beta1.GatewayAddress {
+	addresses := []gwv1beta1.GatewayAddress{}
+
+	for _, ip := range service.Spec.ExternalIPs {
+		addresses = append(addresses, gwv1beta1.GatewayAddress{
+			Type:  common.PointerTo(gwv1beta1.IPAddressType),
+			Value: ip,
+		})
+	}
+
 	return addresses
 }
 
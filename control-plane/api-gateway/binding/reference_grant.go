@@ -74,6 +74,34 @@ func (rv *referenceValidator) TCPRouteCanReferenceBackend(tcpRoute gwv1alpha2.TC
 	return rv.referenceAllowed(fromGK, fromNS, toGK, toNS, string(backendRef.Name))
 }
 
+func (rv *referenceValidator) GRPCRouteCanReferenceBackend(grpcRoute gwv1alpha2.GRPCRoute, backendRef gwv1beta1.BackendRef) bool {
+	fromNS := grpcRoute.GetNamespace()
+	fromGK := metav1.GroupKind{
+		Group: grpcRoute.GroupVersionKind().Group,
+		Kind:  grpcRoute.GroupVersionKind().Kind,
+	}
+
+	// Kind should default to Service if not set
+	// https://github.com/kubernetes-sigs/gateway-api/blob/v0.6.2/apis/v1beta1/object_reference_types.go#L106
+	toNS, toGK := createValuesFromRef(backendRef.Namespace, backendRef.Group, backendRef.Kind, "", common.KindService)
+
+	return rv.referenceAllowed(fromGK, fromNS, toGK, toNS, string(backendRef.Name))
+}
+
+func (rv *referenceValidator) TLSRouteCanReferenceBackend(tlsRoute gwv1alpha2.TLSRoute, backendRef gwv1beta1.BackendRef) bool {
+	fromNS := tlsRoute.GetNamespace()
+	fromGK := metav1.GroupKind{
+		Group: tlsRoute.GroupVersionKind().Group,
+		Kind:  tlsRoute.GroupVersionKind().Kind,
+	}
+
+	// Kind should default to Service if not set
+	// https://github.com/kubernetes-sigs/gateway-api/blob/v0.6.2/apis/v1beta1/object_reference_types.go#L106
+	toNS, toGK := createValuesFromRef(backendRef.Namespace, backendRef.Group, backendRef.Kind, common.BetaGroup, common.KindService)
+
+	return rv.referenceAllowed(fromGK, fromNS, toGK, toNS, string(backendRef.Name))
+}
+
 func createValuesFromRef(ns *gwv1beta1.Namespace, group *gwv1beta1.Group, kind *gwv1beta1.Kind, defaultGroup, defaultKind string) (string, metav1.GroupKind) {
 	toNS := ""
 	if ns != nil {
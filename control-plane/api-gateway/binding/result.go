@@ -34,6 +34,10 @@ var (
 	errRouteNoMatchingListenerHostname      = errors.New("listener cannot bind route with a non-aligned hostname")
 	errRouteInvalidKind                     = errors.New("invalid backend kind")
 	errRouteBackendNotFound                 = errors.New("backend not found")
+	errRouteBackendProtocol                 = errors.New("backend protocol must be grpc or http2")
+	errRouteUnsupportedValue                = errors.New("unsupported value")
+	errRouteNameConflict                    = errors.New("route name conflicts with another route of the same Consul kind in the same namespace")
+	errRouteNotAllowedByListeners_Bound     = errors.New("listener already has a TLSRoute bound to it")
 )
 
 // routeValidationResult holds the result of validating a route globally, in other
@@ -96,6 +100,13 @@ func (e routeValidationResults) Condition() metav1.Condition {
 					Reason:  "RefNotPermitted",
 					Message: fmt.Sprintf("%s: %s", v.String(), err.Error()),
 				}
+			case errRouteBackendProtocol:
+				return metav1.Condition{
+					Type:    "ResolvedRefs",
+					Status:  metav1.ConditionFalse,
+					Reason:  "UnsupportedProtocol",
+					Message: fmt.Sprintf("%s: %s", v.String(), err.Error()),
+				}
 			default:
 				// this should never happen
 				return metav1.Condition{
@@ -168,6 +179,13 @@ func (b bindResults) Condition() metav1.Condition {
 	// default to the most generic reason in the spec "NotAllowedByListeners"
 	reason := "NotAllowedByListeners"
 
+	// if the route itself can't be supported that is the reason for every listener
+	for _, result := range b {
+		if errors.Is(result.err, errRouteUnsupportedValue) {
+			reason = "UnsupportedValue"
+		}
+	}
+
 	// if we only have a single binding error, we can get more specific
 	if len(b) == 1 {
 		for _, result := range b {
@@ -231,7 +249,8 @@ var (
 	// Below is where any custom generic listener validation errors should go.
 	// We map anything under here to a custom ListenerConditionReason of Invalid on
 	// an Accepted status type.
-	errListenerNoTLSPassthrough = errors.New("TLS passthrough is not supported")
+	errListenerNoTLSPassthrough       = errors.New("TLS passthrough is not supported")
+	errListenerTLSPassthroughRequired = errors.New("TLS listeners only support TLS passthrough")
 )
 
 // listenerValidationResult contains the result of internally validating a single listener
@@ -439,10 +458,17 @@ var (
 	// Each of the below are specified in the Gateway spec under GatewayConditionReason
 	// the general usage is that each error is specified as errGateway* where * corresponds
 	// to the GatewayConditionReason given in the spec.
-	errGatewayUnsupportedAddress = errors.New("gateway does not support specifying addresses")
-	errGatewayListenersNotValid  = errors.New("one or more listeners are invalid")
-	errGatewayPending_Pods       = errors.New("gateway pods are still being scheduled")
-	errGatewayPending_Consul     = errors.New("gateway configuration is not yet synced to Consul")
+	errGatewayUnsupportedAddress                 = errors.New("gateway only supports addresses of type IPAddress and Hostname")
+	errGatewayUnsupportedAddress_NoService       = errors.New("gateway addresses cannot be assigned without a service")
+	errGatewayUnsupportedAddress_LoadBalancerIP  = errors.New("gateway with a LoadBalancer service supports at most one IPAddress address")
+	errGatewayUnsupportedAddress_LoadBalancer    = errors.New("gateway with a LoadBalancer service supports a single address of type IPAddress or Hostname")
+	errGatewayInvalidParameters_NotFound         = errors.New("gateway parameters referenced by the gateway do not exist")
+	errGatewayInvalidParameters_Instances        = errors.New("gateway parameters set a minimum number of instances greater than the maximum")
+	errGatewayInvalidParameters_DefaultInstances = errors.New("gateway parameters set a default number of instances outside of the minimum and maximum")
+	errGatewayListenersNotValid                  = errors.New("one or more listeners are invalid")
+	errGatewayPending_Pods                       = errors.New("gateway pods are still being scheduled")
+	errGatewayPending_Consul                     = errors.New("gateway configuration is not yet synced to Consul")
+	errGatewayAddressNotAssigned                 = errors.New("gateway addresses have not been assigned to the service")
 )
 
 // gatewayValidationResult contains the result of internally validating a gateway.
@@ -467,6 +493,15 @@ func (l gatewayValidationResult) programmedCondition(generation int64) metav1.Co
 			Message:            l.programmedErr.Error(),
 			LastTransitionTime: now,
 		}
+	case errGatewayAddressNotAssigned:
+		return metav1.Condition{
+			Type:               "Programmed",
+			Status:             metav1.ConditionFalse,
+			Reason:             "AddressNotAssigned",
+			ObservedGeneration: generation,
+			Message:            l.programmedErr.Error(),
+			LastTransitionTime: now,
+		}
 	default:
 		return metav1.Condition{
 			Type:               "Programmed",
@@ -508,7 +543,21 @@ func (l gatewayValidationResult) acceptedCondition(generation int64, listenersIn
 		}
 	}
 
-	if l.acceptedErr == errGatewayUnsupportedAddress {
+	if l.acceptedErr == errGatewayInvalidParameters_NotFound || l.acceptedErr == errGatewayInvalidParameters_Instances || l.acceptedErr == errGatewayInvalidParameters_DefaultInstances {
+		return metav1.Condition{
+			Type:               "Accepted",
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidParameters",
+			ObservedGeneration: generation,
+			Message:            l.acceptedErr.Error(),
+			LastTransitionTime: now,
+		}
+	}
+
+	if l.acceptedErr == errGatewayUnsupportedAddress ||
+		l.acceptedErr == errGatewayUnsupportedAddress_NoService ||
+		l.acceptedErr == errGatewayUnsupportedAddress_LoadBalancerIP ||
+		l.acceptedErr == errGatewayUnsupportedAddress_LoadBalancer {
 		return metav1.Condition{
 			Type:               "Accepted",
 			Status:             metav1.ConditionFalse,
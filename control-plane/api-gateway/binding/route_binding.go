@@ -99,6 +99,13 @@ func (r *Binder) bindRoute(route client.Object, boundCount map[gwv1beta1.Section
 	namespace := r.config.Namespaces[route.GetNamespace()]
 	groupKind := route.GetObjectKind().GroupVersionKind().GroupKind()
 
+	// a route with filters we can't translate is not accepted by any listener
+	// rather than having those filters silently dropped
+	routeErr := validateRouteFilters(route)
+	if routeErr == nil && routeNameConflicts(route, r.config.Resources) {
+		routeErr = errRouteNameConflict
+	}
+
 	var results parentBindResults
 
 	for _, ref := range filteredParents {
@@ -137,6 +144,24 @@ func (r *Binder) bindRoute(route client.Object, boundCount map[gwv1beta1.Section
 				continue
 			}
 
+			if routeErr != nil {
+				result = append(result, bindResult{
+					section: listener.Name,
+					err:     routeErr,
+				})
+				continue
+			}
+
+			// passed through connections can't be routed by their SNI hostname in Consul,
+			// so only the first TLSRoute binds to a listener
+			if _, ok := route.(*gwv1alpha2.TLSRoute); ok && boundCount[listener.Name] > 0 {
+				result = append(result, bindResult{
+					section: listener.Name,
+					err:     errRouteNotAllowedByListeners_Bound,
+				})
+				continue
+			}
+
 			result = append(result, bindResult{
 				section: listener.Name,
 			})
@@ -223,6 +248,14 @@ func (r *Binder) dropConsulRouteParent(snapshot *Snapshot, object client.Object,
 			})
 			return entry
 		})
+	case *gwv1alpha2.GRPCRoute:
+		// GRPCRoutes are written to Consul as http-route config entries
+		resources.MutateHTTPRoute(client.ObjectKeyFromObject(object), r.handleRouteSyncStatus(snapshot, object), func(entry api.HTTPRouteConfigEntry) api.HTTPRouteConfigEntry {
+			entry.Parents = common.Filter(entry.Parents, func(parent api.ResourceReference) bool {
+				return consulParentMatches(entry.Namespace, gateway, parent)
+			})
+			return entry
+		})
 	case *gwv1alpha2.TCPRoute:
 		resources.MutateTCPRoute(client.ObjectKeyFromObject(object), r.handleRouteSyncStatus(snapshot, object), func(entry api.TCPRouteConfigEntry) api.TCPRouteConfigEntry {
 			entry.Parents = common.Filter(entry.Parents, func(parent api.ResourceReference) bool {
@@ -230,6 +263,14 @@ func (r *Binder) dropConsulRouteParent(snapshot *Snapshot, object client.Object,
 			})
 			return entry
 		})
+	case *gwv1alpha2.TLSRoute:
+		// TLSRoutes are written to Consul as tcp-route config entries
+		resources.MutateTCPRoute(client.ObjectKeyFromObject(object), r.handleRouteSyncStatus(snapshot, object), func(entry api.TCPRouteConfigEntry) api.TCPRouteConfigEntry {
+			entry.Parents = common.Filter(entry.Parents, func(parent api.ResourceReference) bool {
+				return consulParentMatches(entry.Namespace, gateway, parent)
+			})
+			return entry
+		})
 	}
 }
 
@@ -257,50 +298,58 @@ func (r *Binder) mutateRouteWithBindingResults(snapshot *Snapshot, object client
 		normalized[common.NormalizeMeta(ref)] = ref
 	}
 
-	switch object.(type) {
-	case *gwv1beta1.HTTPRoute:
-		resources.TranslateAndMutateHTTPRoute(key, r.handleRouteSyncStatus(snapshot, object), func(old *api.HTTPRouteConfigEntry, new api.HTTPRouteConfigEntry) api.HTTPRouteConfigEntry {
-			if old != nil {
-				for _, parent := range old.Parents {
-					// drop any references that already exist
-					if parents.Contains(parent) {
-						parents.Remove(parent)
-					}
-					if id, ok := normalized[parent]; ok {
-						parents.Remove(id)
-					}
+	mutateHTTPRoute := func(old *api.HTTPRouteConfigEntry, new api.HTTPRouteConfigEntry) api.HTTPRouteConfigEntry {
+		if old != nil {
+			for _, parent := range old.Parents {
+				// drop any references that already exist
+				if parents.Contains(parent) {
+					parents.Remove(parent)
 				}
-
-				// set the old parent states
-				new.Parents = old.Parents
-				new.Status = old.Status
-			}
-			// and now add what is left
-			for parent := range parents.Iter() {
-				new.Parents = append(new.Parents, parent.(api.ResourceReference))
-			}
-			return new
-		})
-	case *gwv1alpha2.TCPRoute:
-		resources.TranslateAndMutateTCPRoute(key, r.handleRouteSyncStatus(snapshot, object), func(old *api.TCPRouteConfigEntry, new api.TCPRouteConfigEntry) api.TCPRouteConfigEntry {
-			if old != nil {
-				for _, parent := range old.Parents {
-					// drop any references that already exist
-					if parents.Contains(parent) {
-						parents.Remove(parent)
-					}
+				if id, ok := normalized[parent]; ok {
+					parents.Remove(id)
 				}
-
-				// set the old parent states
-				new.Parents = old.Parents
-				new.Status = old.Status
 			}
-			// and now add what is left
-			for parent := range parents.Iter() {
-				new.Parents = append(new.Parents, parent.(api.ResourceReference))
+
+			// set the old parent states
+			new.Parents = old.Parents
+			new.Status = old.Status
+		}
+		// and now add what is left
+		for parent := range parents.Iter() {
+			new.Parents = append(new.Parents, parent.(api.ResourceReference))
+		}
+		return new
+	}
+
+	mutateTCPRoute := func(old *api.TCPRouteConfigEntry, new api.TCPRouteConfigEntry) api.TCPRouteConfigEntry {
+		if old != nil {
+			for _, parent := range old.Parents {
+				// drop any references that already exist
+				if parents.Contains(parent) {
+					parents.Remove(parent)
+				}
 			}
-			return new
-		})
+
+			// set the old parent states
+			new.Parents = old.Parents
+			new.Status = old.Status
+		}
+		// and now add what is left
+		for parent := range parents.Iter() {
+			new.Parents = append(new.Parents, parent.(api.ResourceReference))
+		}
+		return new
+	}
+
+	switch object.(type) {
+	case *gwv1beta1.HTTPRoute:
+		resources.TranslateAndMutateHTTPRoute(key, r.handleRouteSyncStatus(snapshot, object), mutateHTTPRoute)
+	case *gwv1alpha2.GRPCRoute:
+		resources.TranslateAndMutateGRPCRoute(key, r.handleRouteSyncStatus(snapshot, object), mutateHTTPRoute)
+	case *gwv1alpha2.TCPRoute:
+		resources.TranslateAndMutateTCPRoute(key, r.handleRouteSyncStatus(snapshot, object), mutateTCPRoute)
+	case *gwv1alpha2.TLSRoute:
+		resources.TranslateAndMutateTLSRoute(key, r.handleRouteSyncStatus(snapshot, object), mutateTCPRoute)
 	}
 }
 
@@ -308,8 +357,12 @@ func entryKind(object client.Object) string {
 	switch object.(type) {
 	case *gwv1beta1.HTTPRoute:
 		return api.HTTPRoute
+	case *gwv1alpha2.GRPCRoute:
+		return api.HTTPRoute
 	case *gwv1alpha2.TCPRoute:
 		return api.TCPRoute
+	case *gwv1alpha2.TLSRoute:
+		return api.TCPRoute
 	}
 	return ""
 }
@@ -328,16 +381,37 @@ func getRouteHostnames(object client.Object) []gwv1beta1.Hostname {
 	switch v := object.(type) {
 	case *gwv1beta1.HTTPRoute:
 		return v.Spec.Hostnames
+	case *gwv1alpha2.GRPCRoute:
+		return v.Spec.Hostnames
+	case *gwv1alpha2.TLSRoute:
+		return v.Spec.Hostnames
 	}
 	return nil
 }
 
+// routeNameConflicts returns whether the route is a GRPCRoute with the same name as an
+// HTTPRoute, or a TLSRoute with the same name as a TCPRoute, either of which would be
+// written to the same Consul config entry.
+func routeNameConflicts(object client.Object, resources *common.ResourceMap) bool {
+	switch object.(type) {
+	case *gwv1alpha2.GRPCRoute:
+		return resources.HTTPRouteConflicts(client.ObjectKeyFromObject(object))
+	case *gwv1alpha2.TLSRoute:
+		return resources.TCPRouteConflicts(client.ObjectKeyFromObject(object))
+	}
+	return false
+}
+
 func getRouteParents(object client.Object) []gwv1beta1.ParentReference {
 	switch v := object.(type) {
 	case *gwv1beta1.HTTPRoute:
 		return v.Spec.ParentRefs
+	case *gwv1alpha2.GRPCRoute:
+		return v.Spec.ParentRefs
 	case *gwv1alpha2.TCPRoute:
 		return v.Spec.ParentRefs
+	case *gwv1alpha2.TLSRoute:
+		return v.Spec.ParentRefs
 	}
 	return nil
 }
@@ -346,8 +420,12 @@ func getRouteParentsStatus(object client.Object) []gwv1beta1.RouteParentStatus {
 	switch v := object.(type) {
 	case *gwv1beta1.HTTPRoute:
 		return v.Status.RouteStatus.Parents
+	case *gwv1alpha2.GRPCRoute:
+		return v.Status.RouteStatus.Parents
 	case *gwv1alpha2.TCPRoute:
 		return v.Status.RouteStatus.Parents
+	case *gwv1alpha2.TLSRoute:
+		return v.Status.RouteStatus.Parents
 	}
 	return nil
 }
@@ -356,8 +434,12 @@ func setRouteParentsStatus(object client.Object, parents []gwv1beta1.RouteParent
 	switch v := object.(type) {
 	case *gwv1beta1.HTTPRoute:
 		v.Status.RouteStatus.Parents = parents
+	case *gwv1alpha2.GRPCRoute:
+		v.Status.RouteStatus.Parents = parents
 	case *gwv1alpha2.TCPRoute:
 		v.Status.RouteStatus.Parents = parents
+	case *gwv1alpha2.TLSRoute:
+		v.Status.RouteStatus.Parents = parents
 	}
 }
 
@@ -369,10 +451,20 @@ func getRouteBackends(object client.Object) []gwv1beta1.BackendRef {
 				return rule.BackendRef
 			})
 		}))
+	case *gwv1alpha2.GRPCRoute:
+		return common.Flatten(common.ConvertSliceFunc(v.Spec.Rules, func(rule gwv1alpha2.GRPCRouteRule) []gwv1beta1.BackendRef {
+			return common.ConvertSliceFunc(rule.BackendRefs, func(rule gwv1alpha2.GRPCBackendRef) gwv1beta1.BackendRef {
+				return rule.BackendRef
+			})
+		}))
 	case *gwv1alpha2.TCPRoute:
 		return common.Flatten(common.ConvertSliceFunc(v.Spec.Rules, func(rule gwv1alpha2.TCPRouteRule) []gwv1beta1.BackendRef {
 			return rule.BackendRefs
 		}))
+	case *gwv1alpha2.TLSRoute:
+		return common.Flatten(common.ConvertSliceFunc(v.Spec.Rules, func(rule gwv1alpha2.TLSRouteRule) []gwv1beta1.BackendRef {
+			return rule.BackendRefs
+		}))
 	}
 	return nil
 }
@@ -381,8 +473,12 @@ func canReferenceBackend(object client.Object, ref gwv1beta1.BackendRef, resourc
 	switch v := object.(type) {
 	case *gwv1beta1.HTTPRoute:
 		return resources.HTTPRouteCanReferenceBackend(*v, ref)
+	case *gwv1alpha2.GRPCRoute:
+		return resources.GRPCRouteCanReferenceBackend(*v, ref)
 	case *gwv1alpha2.TCPRoute:
 		return resources.TCPRouteCanReferenceBackend(*v, ref)
+	case *gwv1alpha2.TLSRoute:
+		return resources.TLSRouteCanReferenceBackend(*v, ref)
 	}
 	return false
 }
@@ -49,6 +49,9 @@ type Snapshot struct {
 	// a Gateway deployment, if it is not set, a deployment should be
 	// deleted instead of updated
 	GatewayClassConfig *v1alpha1.GatewayClassConfig
+	// GatewayParameters are the per-Gateway overrides of the GatewayClassConfig
+	// to use for determining a Gateway deployment, if any.
+	GatewayParameters *v1alpha1.GatewayParameters
 
 	// UpsertGatewayDeployment determines whether the gateway deployment
 	// objects should be updated, i.e. deployments, roles, services
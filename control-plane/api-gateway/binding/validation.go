@@ -4,11 +4,13 @@
 package binding
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/hashicorp/consul-k8s/control-plane/api-gateway/common"
 	"github.com/hashicorp/consul-k8s/control-plane/api/v1alpha1"
 	"github.com/hashicorp/consul/api"
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klabels "k8s.io/apimachinery/pkg/labels"
@@ -25,19 +27,31 @@ var (
 		gwv1beta1.HTTPProtocolType: {{
 			Group: (*gwv1beta1.Group)(&gwv1beta1.GroupVersion.Group),
 			Kind:  "HTTPRoute",
+		}, {
+			Group: (*gwv1alpha2.Group)(&gwv1alpha2.GroupVersion.Group),
+			Kind:  "GRPCRoute",
 		}},
 		gwv1beta1.HTTPSProtocolType: {{
 			Group: (*gwv1beta1.Group)(&gwv1beta1.GroupVersion.Group),
 			Kind:  "HTTPRoute",
+		}, {
+			Group: (*gwv1alpha2.Group)(&gwv1alpha2.GroupVersion.Group),
+			Kind:  "GRPCRoute",
 		}},
 		gwv1beta1.TCPProtocolType: {{
 			Group: (*gwv1alpha2.Group)(&gwv1alpha2.GroupVersion.Group),
 			Kind:  "TCPRoute",
 		}},
+		gwv1beta1.TLSProtocolType: {{
+			Group: (*gwv1alpha2.Group)(&gwv1alpha2.GroupVersion.Group),
+			Kind:  "TLSRoute",
+		}},
 	}
 	allSupportedRouteKinds = map[gwv1beta1.Kind]struct{}{
 		gwv1beta1.Kind("HTTPRoute"): {},
 		gwv1beta1.Kind("TCPRoute"):  {},
+		gwv1beta1.Kind("GRPCRoute"): {},
+		gwv1beta1.Kind("TLSRoute"):  {},
 	}
 )
 
@@ -94,6 +108,22 @@ func validateRefs(route client.Object, refs []gwv1beta1.BackendRef, resources *c
 			continue
 		}
 
+		if _, ok := route.(*gwv1alpha2.GRPCRoute); ok {
+			consulRef := resources.Service(nsn)
+			if isMeshServiceRef {
+				consulRef = resources.MeshService(nsn)
+			}
+			// Consul can only route gRPC to services with a protocol that supports HTTP/2.
+			if protocol, known := resources.ServiceProtocol(consulRef); known && !protocolSupportsGRPC(protocol) {
+				result = append(result, routeValidationResult{
+					namespace: nsn.Namespace,
+					backend:   ref,
+					err:       errRouteBackendProtocol,
+				})
+				continue
+			}
+		}
+
 		result = append(result, routeValidationResult{
 			namespace: nsn.Namespace,
 			backend:   ref,
@@ -102,17 +132,116 @@ func validateRefs(route client.Object, refs []gwv1beta1.BackendRef, resources *c
 	return result
 }
 
+// protocolSupportsGRPC returns whether a Consul service with the given protocol
+// can be the backend of a GRPCRoute.
+func protocolSupportsGRPC(protocol string) bool {
+	switch strings.ToLower(protocol) {
+	case "grpc", "http2":
+		return true
+	}
+	return false
+}
+
+// validateRouteFilters validates that all of the filters on a route can be translated
+// into the Consul config entry of the route.
+func validateRouteFilters(route client.Object) error {
+	switch v := route.(type) {
+	case *gwv1beta1.HTTPRoute:
+		for _, rule := range v.Spec.Rules {
+			if err := validateHTTPFilters(rule.Filters, rule.Matches); err != nil {
+				return err
+			}
+			for _, ref := range rule.BackendRefs {
+				if err := validateHTTPFilters(ref.Filters, rule.Matches); err != nil {
+					return err
+				}
+			}
+		}
+	case *gwv1alpha2.GRPCRoute:
+		for _, rule := range v.Spec.Rules {
+			if err := validateGRPCFilters(rule.Filters); err != nil {
+				return err
+			}
+			for _, ref := range rule.BackendRefs {
+				if err := validateGRPCFilters(ref.Filters); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateGRPCFilters validates the filters of a GRPCRoute rule or backend reference
+// against the filters supported by the Consul http-route config entry.
+func validateGRPCFilters(filters []gwv1alpha2.GRPCRouteFilter) error {
+	for _, filter := range filters {
+		unsupported := []struct {
+			filterType gwv1alpha2.GRPCRouteFilterType
+			configured bool
+		}{
+			{gwv1alpha2.GRPCRouteFilterResponseHeaderModifier, filter.ResponseHeaderModifier != nil},
+			{gwv1alpha2.GRPCRouteFilterRequestMirror, filter.RequestMirror != nil},
+			{gwv1alpha2.GRPCRouteFilterExtensionRef, filter.ExtensionRef != nil},
+		}
+		for _, u := range unsupported {
+			if u.configured || filter.Type == u.filterType {
+				return fmt.Errorf("%w: %s filter is not supported", errRouteUnsupportedValue, u.filterType)
+			}
+		}
+	}
+	return nil
+}
+
+// validateHTTPFilters validates the filters of an HTTPRoute rule or backend reference
+// against the filters supported by the Consul http-route config entry.
+func validateHTTPFilters(filters []gwv1beta1.HTTPRouteFilter, matches []gwv1beta1.HTTPRouteMatch) error {
+	for _, filter := range filters {
+		unsupported := []struct {
+			filterType gwv1beta1.HTTPRouteFilterType
+			configured bool
+		}{
+			{gwv1beta1.HTTPRouteFilterRequestRedirect, filter.RequestRedirect != nil},
+			{gwv1beta1.HTTPRouteFilterResponseHeaderModifier, filter.ResponseHeaderModifier != nil},
+			{gwv1beta1.HTTPRouteFilterRequestMirror, filter.RequestMirror != nil},
+			{gwv1beta1.HTTPRouteFilterExtensionRef, filter.ExtensionRef != nil},
+		}
+		for _, u := range unsupported {
+			if u.configured || filter.Type == u.filterType {
+				return fmt.Errorf("%w: %s filter is not supported", errRouteUnsupportedValue, u.filterType)
+			}
+		}
+
+		if filter.URLRewrite != nil {
+			if filter.URLRewrite.Hostname != nil {
+				return fmt.Errorf("%w: URLRewrite filter hostname rewrites are not supported", errRouteUnsupportedValue)
+			}
+			if filter.URLRewrite.Path != nil &&
+				filter.URLRewrite.Path.Type == gwv1beta1.FullPathHTTPPathModifier &&
+				!common.CanRewriteFullPath(matches) {
+				return fmt.Errorf("%w: URLRewrite filter full path rewrites are only supported on rules that only match exact paths", errRouteUnsupportedValue)
+			}
+		}
+	}
+	return nil
+}
+
 // validateGateway validates that a gateway is semantically valid given
-// the set of features that we support.
-func validateGateway(gateway gwv1beta1.Gateway, pods []corev1.Pod, consulGateway *api.APIGatewayConfigEntry) gatewayValidationResult {
+// the set of features that we support. The result of validateGatewayParameters
+// is passed in so that the binder only has to compute it once.
+func validateGateway(gateway gwv1beta1.Gateway, gatewayClassConfig *v1alpha1.GatewayClassConfig, gatewayParameters *v1alpha1.GatewayParameters, parametersErr error, service *corev1.Service, pods []corev1.Pod, consulGateway *api.APIGatewayConfigEntry) gatewayValidationResult {
 	var result gatewayValidationResult
 
-	if len(gateway.Spec.Addresses) > 0 {
-		result.acceptedErr = errGatewayUnsupportedAddress
+	if parametersErr != nil {
+		result.acceptedErr = parametersErr
+	} else if err := validateGatewayAddresses(gateway, gatewayClassConfig, gatewayParameters); err != nil {
+		result.acceptedErr = err
 	}
 
 	if len(pods) == 0 {
 		result.programmedErr = errGatewayPending_Pods
+	} else if result.acceptedErr == nil && !gatewayAddressesAssigned(gateway, service) {
+		result.programmedErr = errGatewayAddressNotAssigned
 	} else if consulGateway == nil {
 		result.programmedErr = errGatewayPending_Consul
 	}
@@ -120,6 +249,125 @@ func validateGateway(gateway gwv1beta1.Gateway, pods []corev1.Pod, consulGateway
 	return result
 }
 
+// validateGatewayAddresses validates that the addresses requested by a gateway
+// can be passed through to the Service that is created for it.
+func validateGatewayAddresses(gateway gwv1beta1.Gateway, gatewayClassConfig *v1alpha1.GatewayClassConfig, gatewayParameters *v1alpha1.GatewayParameters) error {
+	ips, hostnames, unsupported := common.GatewayRequestedAddresses(gateway)
+	if len(unsupported) > 0 {
+		return errGatewayUnsupportedAddress
+	}
+	if len(ips) == 0 && len(hostnames) == 0 {
+		return nil
+	}
+
+	var serviceType *corev1.ServiceType
+	if gatewayClassConfig != nil {
+		serviceType = gatewayClassConfig.Spec.ServiceType
+	}
+	if gatewayParameters != nil && gatewayParameters.Spec.ServiceType != nil {
+		serviceType = gatewayParameters.Spec.ServiceType
+	}
+	if serviceType == nil {
+		return errGatewayUnsupportedAddress_NoService
+	}
+	if *serviceType == corev1.ServiceTypeLoadBalancer {
+		// the load balancer reports a single ingress for the requested address, so
+		// requesting more than one address could never be fully assigned
+		if len(ips) > 1 {
+			return errGatewayUnsupportedAddress_LoadBalancerIP
+		}
+		if len(ips)+len(hostnames) > 1 {
+			return errGatewayUnsupportedAddress_LoadBalancer
+		}
+	}
+
+	return nil
+}
+
+// gatewayAddressesAssigned checks whether the addresses requested by a gateway
+// have been assigned to its Service. IP addresses on a LoadBalancer Service must
+// show up in the ingress of the load balancer, for any other Service they must be
+// set as external IPs. Hostnames must either be set on the Service for external-dns
+// or be reported as a hostname in the ingress of the load balancer.
+func gatewayAddressesAssigned(gateway gwv1beta1.Gateway, service *corev1.Service) bool {
+	ips, hostnames, _ := common.GatewayRequestedAddresses(gateway)
+	if len(ips) == 0 && len(hostnames) == 0 {
+		return true
+	}
+	if service == nil {
+		return false
+	}
+
+	assignedIPs := service.Spec.ExternalIPs
+	assignedHostnames := common.ServiceHostnames(service)
+	if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		assignedIPs = nil
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				assignedIPs = append(assignedIPs, ingress.IP)
+			}
+			if ingress.Hostname != "" {
+				assignedHostnames = append(assignedHostnames, ingress.Hostname)
+			}
+		}
+	}
+	for _, ip := range ips {
+		if !slices.Contains(assignedIPs, ip) {
+			return false
+		}
+	}
+	for _, hostname := range hostnames {
+		if !slices.Contains(assignedHostnames, hostname) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateGatewayParameters validates that the GatewayParameters referenced by
+// a gateway exist and that, layered over the GatewayClassConfig, they result in
+// a valid number of instances with the default falling between the minimum and
+// the maximum.
+func validateGatewayParameters(gateway gwv1beta1.Gateway, gatewayClassConfig *v1alpha1.GatewayClassConfig, gatewayParameters *v1alpha1.GatewayParameters) error {
+	if _, ok := gateway.Annotations[common.AnnotationGatewayParameters]; !ok {
+		return nil
+	}
+
+	if gatewayParameters == nil {
+		return errGatewayInvalidParameters_NotFound
+	}
+
+	var defaultInstances, minInstances, maxInstances *int32
+	if gatewayClassConfig != nil {
+		defaultInstances = gatewayClassConfig.Spec.DeploymentSpec.DefaultInstances
+		minInstances = gatewayClassConfig.Spec.DeploymentSpec.MinInstances
+		maxInstances = gatewayClassConfig.Spec.DeploymentSpec.MaxInstances
+	}
+	if gatewayParameters.Spec.DefaultInstances != nil {
+		defaultInstances = gatewayParameters.Spec.DefaultInstances
+	}
+	if gatewayParameters.Spec.MinInstances != nil {
+		minInstances = gatewayParameters.Spec.MinInstances
+	}
+	if gatewayParameters.Spec.MaxInstances != nil {
+		maxInstances = gatewayParameters.Spec.MaxInstances
+	}
+	if minInstances != nil && maxInstances != nil && *minInstances > *maxInstances {
+		return errGatewayInvalidParameters_Instances
+	}
+	if defaultInstances != nil {
+		if minInstances != nil && *defaultInstances < *minInstances {
+			return errGatewayInvalidParameters_DefaultInstances
+		}
+		if maxInstances != nil && *defaultInstances > *maxInstances {
+			return errGatewayInvalidParameters_DefaultInstances
+		}
+	}
+
+	return nil
+}
+
 // mergedListener associates a listener with its indexed position
 // in the gateway spec, it's used to re-associate a status with
 // a listener after we merge compatible listeners together and then
@@ -204,6 +452,16 @@ func validateTLS(gateway gwv1beta1.Gateway, tls *gwv1beta1.GatewayTLSConfig, res
 	return nil, err
 }
 
+// validateTLSPassthrough validates the TLS configuration of a TLS listener. The TLS
+// connections are passed through to the backends of its TLSRoutes, so the listener
+// must be in Passthrough mode and has no certificates of its own.
+func validateTLSPassthrough(tls *gwv1beta1.GatewayTLSConfig) error {
+	if tls == nil || tls.Mode == nil || *tls.Mode != gwv1beta1.TLSModePassthrough {
+		return errListenerTLSPassthroughRequired
+	}
+	return nil
+}
+
 func validateCertificateData(secret corev1.Secret) error {
 	_, _, err := common.ParseCertificateData(secret)
 	if err != nil {
@@ -227,7 +485,12 @@ func validateListeners(gateway gwv1beta1.Gateway, listeners []gwv1beta1.Listener
 	for i, listener := range listeners {
 		var result listenerValidationResult
 
-		err, refErr := validateTLS(gateway, listener.TLS, resources)
+		var err, refErr error
+		if listener.Protocol == gwv1beta1.TLSProtocolType {
+			err = validateTLSPassthrough(listener.TLS)
+		} else {
+			err, refErr = validateTLS(gateway, listener.TLS, resources)
+		}
 		result.refErr = refErr
 		if err != nil {
 			result.acceptedErr = err
@@ -244,6 +507,10 @@ func validateListeners(gateway gwv1beta1.Gateway, listeners []gwv1beta1.Listener
 
 		if err := merged[listener.Port].validateProtocol(); err != nil {
 			result.conflictedErr = err
+		} else if listener.Protocol == gwv1beta1.TLSProtocolType && len(merged[listener.Port]) > 1 {
+			// passed through connections can't be routed by their SNI hostname in
+			// Consul, so TLS listeners can't share a port
+			result.conflictedErr = errListenerHostnameConflict
 		} else {
 			result.conflictedErr = merged[listener.Port].validateHostname(i, listener)
 		}
@@ -9,12 +9,14 @@ import (
 	logrtest "github.com/go-logr/logr/testing"
 	"github.com/hashicorp/consul-k8s/control-plane/api-gateway/common"
 	"github.com/hashicorp/consul-k8s/control-plane/api/v1alpha1"
+	"github.com/hashicorp/consul/api"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
@@ -207,12 +209,62 @@ func TestValidateRefs(t *testing.T) {
 	}
 }
 
+func TestValidateRefs_GRPCRouteBackendProtocol(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		protocol      string
+		knownProtocol bool
+		expectedError error
+	}{
+		"grpc":             {protocol: "grpc", knownProtocol: true},
+		"http2":            {protocol: "http2", knownProtocol: true},
+		"http":             {protocol: "http", knownProtocol: true, expectedError: errRouteBackendProtocol},
+		"tcp":              {protocol: "tcp", knownProtocol: true, expectedError: errRouteBackendProtocol},
+		"unknown protocol": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			route := &gwv1alpha2.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "test"},
+				Spec: gwv1alpha2.GRPCRouteSpec{
+					Rules: []gwv1alpha2.GRPCRouteRule{{
+						BackendRefs: []gwv1alpha2.GRPCBackendRef{{
+							BackendRef: gwv1beta1.BackendRef{BackendObjectReference: gwv1beta1.BackendObjectReference{Name: "backend"}},
+						}},
+					}},
+				},
+			}
+			resources := common.NewResourceMap(common.ResourceTranslator{}, NewReferenceValidator(nil), logrtest.NewTestLogger(t))
+			id := types.NamespacedName{Name: "backend", Namespace: "test"}
+			resources.AddService(id, id.Name)
+			if tt.knownProtocol {
+				resources.SetServiceProtocol(resources.Service(id), tt.protocol)
+			}
+
+			actual := validateRefs(route, getRouteBackends(route), resources)
+			require.Len(t, actual, 1)
+			require.Equal(t, tt.expectedError, actual[0].err)
+			if tt.expectedError != nil {
+				require.Equal(t, "UnsupportedProtocol", actual.Condition().Reason)
+			}
+		})
+	}
+}
+
 func TestValidateGateway(t *testing.T) {
 	t.Parallel()
 
+	loadBalancer := &v1alpha1.GatewayClassConfig{Spec: v1alpha1.GatewayClassConfigSpec{
+		ServiceType: common.PointerTo(corev1.ServiceTypeLoadBalancer),
+	}}
+	clusterIP := &v1alpha1.GatewayClassConfig{Spec: v1alpha1.GatewayClassConfigSpec{
+		ServiceType: common.PointerTo(corev1.ServiceTypeClusterIP),
+	}}
+
 	for name, tt := range map[string]struct {
-		object   gwv1beta1.Gateway
-		expected error
+		object             gwv1beta1.Gateway
+		gatewayClassConfig *v1alpha1.GatewayClassConfig
+		expected           error
 	}{
 		"valid": {
 			object:   gwv1beta1.Gateway{},
@@ -220,13 +272,238 @@ func TestValidateGateway(t *testing.T) {
 		},
 		"invalid": {
 			object: gwv1beta1.Gateway{Spec: gwv1beta1.GatewaySpec{Addresses: []gwv1beta1.GatewayAddress{
-				{Value: "1"},
+				{Type: common.PointerTo(gwv1beta1.NamedAddressType), Value: "1"},
+			}}},
+			gatewayClassConfig: loadBalancer,
+			expected:           errGatewayUnsupportedAddress,
+		},
+		"address without a service": {
+			object: gwv1beta1.Gateway{Spec: gwv1beta1.GatewaySpec{Addresses: []gwv1beta1.GatewayAddress{
+				{Value: "10.0.0.1"},
+			}}},
+			expected: errGatewayUnsupportedAddress_NoService,
+		},
+		"single load balancer ip": {
+			object: gwv1beta1.Gateway{Spec: gwv1beta1.GatewaySpec{Addresses: []gwv1beta1.GatewayAddress{
+				{Type: common.PointerTo(gwv1beta1.IPAddressType), Value: "10.0.0.1"},
 			}}},
-			expected: errGatewayUnsupportedAddress,
+			gatewayClassConfig: loadBalancer,
+			expected:           nil,
+		},
+		"single load balancer hostname": {
+			object: gwv1beta1.Gateway{Spec: gwv1beta1.GatewaySpec{Addresses: []gwv1beta1.GatewayAddress{
+				{Type: common.PointerTo(gwv1beta1.HostnameAddressType), Value: "gateway.example.com"},
+			}}},
+			gatewayClassConfig: loadBalancer,
+			expected:           nil,
+		},
+		"load balancer ip and hostname": {
+			object: gwv1beta1.Gateway{Spec: gwv1beta1.GatewaySpec{Addresses: []gwv1beta1.GatewayAddress{
+				{Type: common.PointerTo(gwv1beta1.IPAddressType), Value: "10.0.0.1"},
+				{Type: common.PointerTo(gwv1beta1.HostnameAddressType), Value: "gateway.example.com"},
+			}}},
+			gatewayClassConfig: loadBalancer,
+			expected:           errGatewayUnsupportedAddress_LoadBalancer,
+		},
+		"multiple load balancer hostnames": {
+			object: gwv1beta1.Gateway{Spec: gwv1beta1.GatewaySpec{Addresses: []gwv1beta1.GatewayAddress{
+				{Type: common.PointerTo(gwv1beta1.HostnameAddressType), Value: "one.example.com"},
+				{Type: common.PointerTo(gwv1beta1.HostnameAddressType), Value: "two.example.com"},
+			}}},
+			gatewayClassConfig: loadBalancer,
+			expected:           errGatewayUnsupportedAddress_LoadBalancer,
+		},
+		"multiple load balancer ips and a hostname": {
+			object: gwv1beta1.Gateway{Spec: gwv1beta1.GatewaySpec{Addresses: []gwv1beta1.GatewayAddress{
+				{Value: "10.0.0.1"},
+				{Value: "10.0.0.2"},
+				{Type: common.PointerTo(gwv1beta1.HostnameAddressType), Value: "gateway.example.com"},
+			}}},
+			gatewayClassConfig: loadBalancer,
+			expected:           errGatewayUnsupportedAddress_LoadBalancerIP,
+		},
+		"multiple load balancer ips": {
+			object: gwv1beta1.Gateway{Spec: gwv1beta1.GatewaySpec{Addresses: []gwv1beta1.GatewayAddress{
+				{Value: "10.0.0.1"},
+				{Value: "10.0.0.2"},
+			}}},
+			gatewayClassConfig: loadBalancer,
+			expected:           errGatewayUnsupportedAddress_LoadBalancerIP,
+		},
+		"multiple external ips": {
+			object: gwv1beta1.Gateway{Spec: gwv1beta1.GatewaySpec{Addresses: []gwv1beta1.GatewayAddress{
+				{Value: "10.0.0.1"},
+				{Value: "10.0.0.2"},
+			}}},
+			gatewayClassConfig: clusterIP,
+			expected:           nil,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.expected, validateGateway(tt.object, tt.gatewayClassConfig, nil, nil, nil, nil, nil).acceptedErr)
+		})
+	}
+}
+
+func TestValidateGateway_AddressNotAssigned(t *testing.T) {
+	t.Parallel()
+
+	gatewayClassConfig := &v1alpha1.GatewayClassConfig{Spec: v1alpha1.GatewayClassConfigSpec{
+		ServiceType: common.PointerTo(corev1.ServiceTypeLoadBalancer),
+	}}
+	ip := gwv1beta1.GatewayAddress{Type: common.PointerTo(gwv1beta1.IPAddressType), Value: "10.0.0.1"}
+	hostname := gwv1beta1.GatewayAddress{Type: common.PointerTo(gwv1beta1.HostnameAddressType), Value: "gateway.example.com"}
+	pods := []corev1.Pod{{}}
+	consulGateway := &api.APIGatewayConfigEntry{}
+
+	for name, tt := range map[string]struct {
+		address  gwv1beta1.GatewayAddress
+		service  *corev1.Service
+		expected error
+	}{
+		"no service": {
+			address:  ip,
+			service:  nil,
+			expected: errGatewayAddressNotAssigned,
+		},
+		"load balancer ip not yet assigned": {
+			address: ip,
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, LoadBalancerIP: "10.0.0.1"},
+			},
+			expected: errGatewayAddressNotAssigned,
+		},
+		"load balancer ip assigned": {
+			address: ip,
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, LoadBalancerIP: "10.0.0.1"},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+				}},
+			},
+			expected: nil,
+		},
+		"hostname missing": {
+			address: hostname,
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+				}},
+			},
+			expected: errGatewayAddressNotAssigned,
+		},
+		"hostname set for external-dns": {
+			address: hostname,
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{common.AnnotationExternalDNSHostname: "gateway.example.com"},
+				},
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			},
+			expected: nil,
+		},
+		"hostname assigned by the load balancer": {
+			address: hostname,
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{Hostname: "gateway.example.com"}},
+				}},
+			},
+			expected: nil,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gateway := gwv1beta1.Gateway{Spec: gwv1beta1.GatewaySpec{Addresses: []gwv1beta1.GatewayAddress{tt.address}}}
+			result := validateGateway(gateway, gatewayClassConfig, nil, nil, tt.service, pods, consulGateway)
+			require.NoError(t, result.acceptedErr)
+			require.Equal(t, tt.expected, result.programmedErr)
+		})
+	}
+}
+
+func TestValidateGatewayParameters(t *testing.T) {
+	t.Parallel()
+
+	referencing := gwv1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{common.AnnotationGatewayParameters: "params"},
+		},
+	}
+	gatewayClassConfig := &v1alpha1.GatewayClassConfig{
+		Spec: v1alpha1.GatewayClassConfigSpec{
+			DeploymentSpec: v1alpha1.DeploymentSpec{
+				MinInstances: common.PointerTo(int32(1)),
+				MaxInstances: common.PointerTo(int32(8)),
+			},
+		},
+	}
+
+	for name, tt := range map[string]struct {
+		gateway           gwv1beta1.Gateway
+		gatewayParameters *v1alpha1.GatewayParameters
+		expected          error
+	}{
+		"no reference": {
+			gateway:  gwv1beta1.Gateway{},
+			expected: nil,
+		},
+		"reference to missing parameters": {
+			gateway:  referencing,
+			expected: errGatewayInvalidParameters_NotFound,
+		},
+		"valid parameters": {
+			gateway: referencing,
+			gatewayParameters: &v1alpha1.GatewayParameters{Spec: v1alpha1.GatewayParametersSpec{
+				ServiceType:  common.PointerTo(corev1.ServiceTypeLoadBalancer),
+				MinInstances: common.PointerTo(int32(2)),
+				MaxInstances: common.PointerTo(int32(4)),
+			}},
+			expected: nil,
+		},
+		"minimum greater than the maximum of the parameters": {
+			gateway: referencing,
+			gatewayParameters: &v1alpha1.GatewayParameters{Spec: v1alpha1.GatewayParametersSpec{
+				MinInstances: common.PointerTo(int32(4)),
+				MaxInstances: common.PointerTo(int32(2)),
+			}},
+			expected: errGatewayInvalidParameters_Instances,
+		},
+		"minimum greater than the maximum of the class": {
+			gateway: referencing,
+			gatewayParameters: &v1alpha1.GatewayParameters{Spec: v1alpha1.GatewayParametersSpec{
+				MinInstances: common.PointerTo(int32(10)),
+			}},
+			expected: errGatewayInvalidParameters_Instances,
+		},
+		"default below the minimum": {
+			gateway: referencing,
+			gatewayParameters: &v1alpha1.GatewayParameters{Spec: v1alpha1.GatewayParametersSpec{
+				DefaultInstances: common.PointerTo(int32(1)),
+				MinInstances:     common.PointerTo(int32(2)),
+			}},
+			expected: errGatewayInvalidParameters_DefaultInstances,
+		},
+		"default above the maximum of the class": {
+			gateway: referencing,
+			gatewayParameters: &v1alpha1.GatewayParameters{Spec: v1alpha1.GatewayParametersSpec{
+				DefaultInstances: common.PointerTo(int32(9)),
+			}},
+			expected: errGatewayInvalidParameters_DefaultInstances,
+		},
+		"default within the range": {
+			gateway: referencing,
+			gatewayParameters: &v1alpha1.GatewayParameters{Spec: v1alpha1.GatewayParametersSpec{
+				DefaultInstances: common.PointerTo(int32(4)),
+			}},
+			expected: nil,
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
-			require.Equal(t, tt.expected, validateGateway(tt.object, nil, nil).acceptedErr)
+			err := validateGatewayParameters(tt.gateway, gatewayClassConfig, tt.gatewayParameters)
+			require.Equal(t, tt.expected, err)
+			require.Equal(t, tt.expected, validateGateway(tt.gateway, gatewayClassConfig, tt.gatewayParameters, err, nil, nil, nil).acceptedErr)
 		})
 	}
 }
@@ -526,6 +803,175 @@ func TestValidateTLS(t *testing.T) {
 	}
 }
 
+func TestValidateRouteFilters(t *testing.T) {
+	t.Parallel()
+
+	exactMatch := []gwv1beta1.HTTPRouteMatch{{
+		Path: &gwv1beta1.HTTPPathMatch{Type: common.PointerTo(gwv1beta1.PathMatchExact), Value: common.PointerTo("/v1")},
+	}}
+	prefixMatch := []gwv1beta1.HTTPRouteMatch{{
+		Path: &gwv1beta1.HTTPPathMatch{Type: common.PointerTo(gwv1beta1.PathMatchPathPrefix), Value: common.PointerTo("/v1")},
+	}}
+
+	for name, tt := range map[string]struct {
+		matches        []gwv1beta1.HTTPRouteMatch
+		filter         gwv1beta1.HTTPRouteFilter
+		onBackend      bool
+		expectedErrMsg string
+	}{
+		"request header modifier": {
+			filter: gwv1beta1.HTTPRouteFilter{
+				Type:                  gwv1beta1.HTTPRouteFilterRequestHeaderModifier,
+				RequestHeaderModifier: &gwv1beta1.HTTPHeaderFilter{Remove: []string{"foo"}},
+			},
+		},
+		"prefix rewrite": {
+			matches: prefixMatch,
+			filter: gwv1beta1.HTTPRouteFilter{
+				Type: gwv1beta1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gwv1beta1.HTTPURLRewriteFilter{
+					Path: &gwv1beta1.HTTPPathModifier{Type: gwv1beta1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: common.PointerTo("/v2")},
+				},
+			},
+		},
+		"full path rewrite on exact match": {
+			matches: exactMatch,
+			filter: gwv1beta1.HTTPRouteFilter{
+				Type: gwv1beta1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gwv1beta1.HTTPURLRewriteFilter{
+					Path: &gwv1beta1.HTTPPathModifier{Type: gwv1beta1.FullPathHTTPPathModifier, ReplaceFullPath: common.PointerTo("/v2")},
+				},
+			},
+		},
+		"full path rewrite on prefix match": {
+			matches: prefixMatch,
+			filter: gwv1beta1.HTTPRouteFilter{
+				Type: gwv1beta1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gwv1beta1.HTTPURLRewriteFilter{
+					Path: &gwv1beta1.HTTPPathModifier{Type: gwv1beta1.FullPathHTTPPathModifier, ReplaceFullPath: common.PointerTo("/v2")},
+				},
+			},
+			expectedErrMsg: "unsupported value: URLRewrite filter full path rewrites are only supported on rules that only match exact paths",
+		},
+		"hostname rewrite": {
+			filter: gwv1beta1.HTTPRouteFilter{
+				Type:       gwv1beta1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gwv1beta1.HTTPURLRewriteFilter{Hostname: common.PointerTo[gwv1beta1.PreciseHostname]("example.com")},
+			},
+			expectedErrMsg: "unsupported value: URLRewrite filter hostname rewrites are not supported",
+		},
+		"request redirect": {
+			filter: gwv1beta1.HTTPRouteFilter{
+				Type:            gwv1beta1.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gwv1beta1.HTTPRequestRedirectFilter{Scheme: common.PointerTo("https")},
+			},
+			expectedErrMsg: "unsupported value: RequestRedirect filter is not supported",
+		},
+		"response header modifier on backend": {
+			filter: gwv1beta1.HTTPRouteFilter{
+				Type:                   gwv1beta1.HTTPRouteFilterResponseHeaderModifier,
+				ResponseHeaderModifier: &gwv1beta1.HTTPHeaderFilter{Remove: []string{"foo"}},
+			},
+			onBackend:      true,
+			expectedErrMsg: "unsupported value: ResponseHeaderModifier filter is not supported",
+		},
+		"request mirror": {
+			filter: gwv1beta1.HTTPRouteFilter{
+				Type:          gwv1beta1.HTTPRouteFilterRequestMirror,
+				RequestMirror: &gwv1beta1.HTTPRequestMirrorFilter{BackendRef: gwv1beta1.BackendObjectReference{Name: "mirror"}},
+			},
+			expectedErrMsg: "unsupported value: RequestMirror filter is not supported",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rule := gwv1beta1.HTTPRouteRule{Matches: tt.matches}
+			if tt.onBackend {
+				rule.BackendRefs = []gwv1beta1.HTTPBackendRef{{Filters: []gwv1beta1.HTTPRouteFilter{tt.filter}}}
+			} else {
+				rule.Filters = []gwv1beta1.HTTPRouteFilter{tt.filter}
+			}
+			route := &gwv1beta1.HTTPRoute{Spec: gwv1beta1.HTTPRouteSpec{Rules: []gwv1beta1.HTTPRouteRule{rule}}}
+
+			err := validateRouteFilters(route)
+			if tt.expectedErrMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, errRouteUnsupportedValue)
+			require.EqualError(t, err, tt.expectedErrMsg)
+		})
+	}
+}
+
+func TestValidateRouteFilters_GRPCRoute(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range map[string]struct {
+		filter         gwv1alpha2.GRPCRouteFilter
+		onBackend      bool
+		expectedErrMsg string
+	}{
+		"request header modifier": {
+			filter: gwv1alpha2.GRPCRouteFilter{
+				Type:                  gwv1alpha2.GRPCRouteFilterRequestHeaderModifier,
+				RequestHeaderModifier: &gwv1beta1.HTTPHeaderFilter{Remove: []string{"foo"}},
+			},
+		},
+		"response header modifier": {
+			filter: gwv1alpha2.GRPCRouteFilter{
+				Type:                   gwv1alpha2.GRPCRouteFilterResponseHeaderModifier,
+				ResponseHeaderModifier: &gwv1beta1.HTTPHeaderFilter{Remove: []string{"foo"}},
+			},
+			expectedErrMsg: "unsupported value: ResponseHeaderModifier filter is not supported",
+		},
+		"request mirror on backend": {
+			filter: gwv1alpha2.GRPCRouteFilter{
+				Type:          gwv1alpha2.GRPCRouteFilterRequestMirror,
+				RequestMirror: &gwv1beta1.HTTPRequestMirrorFilter{BackendRef: gwv1beta1.BackendObjectReference{Name: "mirror"}},
+			},
+			onBackend:      true,
+			expectedErrMsg: "unsupported value: RequestMirror filter is not supported",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rule := gwv1alpha2.GRPCRouteRule{}
+			if tt.onBackend {
+				rule.BackendRefs = []gwv1alpha2.GRPCBackendRef{{Filters: []gwv1alpha2.GRPCRouteFilter{tt.filter}}}
+			} else {
+				rule.Filters = []gwv1alpha2.GRPCRouteFilter{tt.filter}
+			}
+			route := &gwv1alpha2.GRPCRoute{Spec: gwv1alpha2.GRPCRouteSpec{Rules: []gwv1alpha2.GRPCRouteRule{rule}}}
+
+			err := validateRouteFilters(route)
+			if tt.expectedErrMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, errRouteUnsupportedValue)
+			require.EqualError(t, err, tt.expectedErrMsg)
+		})
+	}
+}
+
+func TestBindResults_ConditionUnsupportedValue(t *testing.T) {
+	t.Parallel()
+
+	err := validateHTTPFilters([]gwv1beta1.HTTPRouteFilter{{
+		Type:            gwv1beta1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &gwv1beta1.HTTPRequestRedirectFilter{},
+	}}, nil)
+	results := bindResults{
+		{section: "listener-1", err: errRouteNotAllowedByListeners_Protocol},
+		{section: "listener-2", err: err},
+	}
+
+	condition := results.Condition()
+	require.Equal(t, "Accepted", condition.Type)
+	require.Equal(t, metav1.ConditionFalse, condition.Status)
+	require.Equal(t, "UnsupportedValue", condition.Reason)
+	require.Equal(t, "listener-1: listener does not support route protocol; listener-2: unsupported value: RequestRedirect filter is not supported", condition.Message)
+}
+
 func TestValidateListeners(t *testing.T) {
 	t.Parallel()
 
@@ -563,6 +1009,24 @@ func TestValidateListeners(t *testing.T) {
 			},
 			expectedAcceptedErr: errListenerPortUnavailable,
 		},
+		"valid protocol TLS with passthrough": {
+			listeners: []gwv1beta1.Listener{
+				{Protocol: gwv1beta1.TLSProtocolType, TLS: &gwv1beta1.GatewayTLSConfig{Mode: common.PointerTo(gwv1beta1.TLSModePassthrough)}},
+			},
+			expectedAcceptedErr: nil,
+		},
+		"invalid protocol TLS with terminate": {
+			listeners: []gwv1beta1.Listener{
+				{Protocol: gwv1beta1.TLSProtocolType, TLS: &gwv1beta1.GatewayTLSConfig{Mode: common.PointerTo(gwv1beta1.TLSModeTerminate)}},
+			},
+			expectedAcceptedErr: errListenerTLSPassthroughRequired,
+		},
+		"invalid protocol TLS without tls config": {
+			listeners: []gwv1beta1.Listener{
+				{Protocol: gwv1beta1.TLSProtocolType},
+			},
+			expectedAcceptedErr: errListenerTLSPassthroughRequired,
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			require.Equal(t, tt.expectedAcceptedErr, validateListeners(gatewayWithFinalizer(gwv1beta1.GatewaySpec{}), tt.listeners, nil)[0].acceptedErr)
@@ -570,6 +1034,22 @@ func TestValidateListeners(t *testing.T) {
 	}
 }
 
+func TestValidateListeners_TLSPortConflict(t *testing.T) {
+	t.Parallel()
+
+	passthrough := &gwv1beta1.GatewayTLSConfig{Mode: common.PointerTo(gwv1beta1.TLSModePassthrough)}
+	listeners := []gwv1beta1.Listener{
+		{Name: "one", Protocol: gwv1beta1.TLSProtocolType, Port: 443, Hostname: common.PointerTo[gwv1beta1.Hostname]("one.example.com"), TLS: passthrough},
+		{Name: "two", Protocol: gwv1beta1.TLSProtocolType, Port: 443, Hostname: common.PointerTo[gwv1beta1.Hostname]("two.example.com"), TLS: passthrough},
+		{Name: "three", Protocol: gwv1beta1.TLSProtocolType, Port: 8443, Hostname: common.PointerTo[gwv1beta1.Hostname]("three.example.com"), TLS: passthrough},
+	}
+
+	results := validateListeners(gatewayWithFinalizer(gwv1beta1.GatewaySpec{}), listeners, nil)
+	require.Equal(t, errListenerHostnameConflict, results[0].conflictedErr)
+	require.Equal(t, errListenerHostnameConflict, results[1].conflictedErr)
+	require.NoError(t, results[2].conflictedErr)
+}
+
 func TestRouteAllowedForListenerNamespaces(t *testing.T) {
 	t.Parallel()
 
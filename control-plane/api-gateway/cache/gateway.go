@@ -66,6 +66,26 @@ func (r *GatewayCache) FetchServicesFor(ctx context.Context, ref api.ResourceRef
 	return common.DerefAll(services), nil
 }
 
+// FetchServiceProtocol returns the protocol of the service that is referenced by ref,
+// as resolved by its discovery chain from its service-defaults and the proxy-defaults.
+func (r *GatewayCache) FetchServiceProtocol(ctx context.Context, ref api.ResourceReference) (string, error) {
+	client, err := consul.NewClientFromConnMgr(r.config.ConsulClientConfig, r.serverMgr)
+	if err != nil {
+		return "", err
+	}
+
+	opts := &api.QueryOptions{}
+	if r.config.NamespacesEnabled && ref.Namespace != "" {
+		opts.Namespace = ref.Namespace
+	}
+
+	response, _, err := client.DiscoveryChain().Get(ref.Name, nil, opts.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	return response.Chain.Protocol, nil
+}
+
 func (r *GatewayCache) EnsureSubscribed(ref api.ResourceReference, resource types.NamespacedName) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
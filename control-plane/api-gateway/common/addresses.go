@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package common
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// AnnotationExternalDNSHostname is the annotation external-dns reads the hostnames
+// to create DNS records for from a Service.
+const AnnotationExternalDNSHostname = "external-dns.alpha.kubernetes.io/hostname"
+
+// GatewayRequestedAddresses returns the IP addresses and hostnames requested in
+// the spec of the gateway. Addresses of any other type are returned as unsupported.
+func GatewayRequestedAddresses(gateway gwv1beta1.Gateway) (ips, hostnames []string, unsupported []gwv1beta1.GatewayAddress) {
+	for _, address := range gateway.Spec.Addresses {
+		switch DerefStringOr(address.Type, gwv1beta1.IPAddressType) {
+		case string(gwv1beta1.IPAddressType):
+			ips = append(ips, address.Value)
+		case string(gwv1beta1.HostnameAddressType):
+			hostnames = append(hostnames, address.Value)
+		default:
+			unsupported = append(unsupported, address)
+		}
+	}
+	return ips, hostnames, unsupported
+}
+
+// ServiceHostnames returns the hostnames set on the Service for external-dns.
+func ServiceHostnames(service *corev1.Service) []string {
+	value, ok := service.Annotations[AnnotationExternalDNSHostname]
+	if !ok || value == "" {
+		return nil
+	}
+
+	var hostnames []string
+	for _, hostname := range strings.Split(value, ",") {
+		hostnames = append(hostnames, strings.TrimSpace(hostname))
+	}
+	return hostnames
+}
@@ -7,4 +7,5 @@ const (
 	GatewayClassControllerName = "consul.hashicorp.com/gateway-controller"
 
 	AnnotationGatewayClassConfig = "consul.hashicorp.com/gateway-class-config"
+	AnnotationGatewayParameters  = "consul.hashicorp.com/gateway-parameters"
 )
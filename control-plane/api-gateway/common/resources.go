@@ -64,6 +64,8 @@ type ReferenceValidator interface {
 	GatewayCanReferenceSecret(gateway gwv1beta1.Gateway, secretRef gwv1beta1.SecretObjectReference) bool
 	HTTPRouteCanReferenceBackend(httproute gwv1beta1.HTTPRoute, backendRef gwv1beta1.BackendRef) bool
 	TCPRouteCanReferenceBackend(tcpRoute gwv1alpha2.TCPRoute, backendRef gwv1beta1.BackendRef) bool
+	GRPCRouteCanReferenceBackend(grpcRoute gwv1alpha2.GRPCRoute, backendRef gwv1beta1.BackendRef) bool
+	TLSRouteCanReferenceBackend(tlsRoute gwv1alpha2.TLSRoute, backendRef gwv1beta1.BackendRef) bool
 }
 
 type certificate struct {
@@ -81,6 +83,16 @@ type tcpRoute struct {
 	gateways mapset.Set
 }
 
+type grpcRoute struct {
+	route    gwv1alpha2.GRPCRoute
+	gateways mapset.Set
+}
+
+type tlsRoute struct {
+	route    gwv1alpha2.TLSRoute
+	gateways mapset.Set
+}
+
 type consulHTTPRoute struct {
 	route    api.HTTPRouteConfigEntry
 	gateways mapset.Set
@@ -94,6 +106,8 @@ type consulTCPRoute struct {
 type resourceSet struct {
 	httpRoutes   mapset.Set
 	tcpRoutes    mapset.Set
+	grpcRoutes   mapset.Set
+	tlsRoutes    mapset.Set
 	certificates mapset.Set
 
 	consulObjects *ReferenceSet
@@ -108,15 +122,23 @@ type ResourceMap struct {
 	meshServices map[types.NamespacedName]api.ResourceReference
 	certificates mapset.Set
 
+	// serviceProtocols holds the protocol of the Consul services that
+	// are the backends of GRPCRoutes, keyed by their Consul reference.
+	serviceProtocols map[api.ResourceReference]string
+
 	// this acts a a secondary store of what has not yet
 	// been processed for the sake of garbage collection.
 	processedCertificates mapset.Set
 	certificateGateways   map[api.ResourceReference]*certificate
 	tcpRouteGateways      map[api.ResourceReference]*tcpRoute
 	httpRouteGateways     map[api.ResourceReference]*httpRoute
+	grpcRouteGateways     map[api.ResourceReference]*grpcRoute
+	tlsRouteGateways      map[api.ResourceReference]*tlsRoute
 	gatewayResources      map[api.ResourceReference]*resourceSet
 
-	// consul resources for a gateway
+	// consul resources for a gateway, GRPCRoutes are written to Consul
+	// as http-route config entries so they are tracked as consulHTTPRoutes
+	// and TLSRoutes as tcp-route config entries tracked as consulTCPRoutes
 	consulTCPRoutes  map[api.ResourceReference]*consulTCPRoute
 	consulHTTPRoutes map[api.ResourceReference]*consulHTTPRoute
 
@@ -132,12 +154,15 @@ func NewResourceMap(translator ResourceTranslator, validator ReferenceValidator,
 		processedCertificates: mapset.NewSet(),
 		services:              make(map[types.NamespacedName]api.ResourceReference),
 		meshServices:          make(map[types.NamespacedName]api.ResourceReference),
+		serviceProtocols:      make(map[api.ResourceReference]string),
 		certificates:          mapset.NewSet(),
 		consulTCPRoutes:       make(map[api.ResourceReference]*consulTCPRoute),
 		consulHTTPRoutes:      make(map[api.ResourceReference]*consulHTTPRoute),
 		certificateGateways:   make(map[api.ResourceReference]*certificate),
 		tcpRouteGateways:      make(map[api.ResourceReference]*tcpRoute),
 		httpRouteGateways:     make(map[api.ResourceReference]*httpRoute),
+		grpcRouteGateways:     make(map[api.ResourceReference]*grpcRoute),
+		tlsRouteGateways:      make(map[api.ResourceReference]*tlsRoute),
 		gatewayResources:      make(map[api.ResourceReference]*resourceSet),
 	}
 }
@@ -181,6 +206,18 @@ func (s *ResourceMap) HasMeshService(id types.NamespacedName) bool {
 	return ok
 }
 
+// SetServiceProtocol records the protocol of the Consul service that is referenced by ref.
+func (s *ResourceMap) SetServiceProtocol(ref api.ResourceReference, protocol string) {
+	s.serviceProtocols[ref] = protocol
+}
+
+// ServiceProtocol returns the protocol of the Consul service that is referenced by ref
+// and whether it is known.
+func (s *ResourceMap) ServiceProtocol(ref api.ResourceReference) (string, bool) {
+	protocol, ok := s.serviceProtocols[ref]
+	return protocol, ok
+}
+
 func (s *ResourceMap) Certificate(key types.NamespacedName) *corev1.Secret {
 	if !s.certificates.Contains(key) {
 		return nil
@@ -211,6 +248,8 @@ func (s *ResourceMap) ReferenceCountGateway(gateway gwv1beta1.Gateway) {
 	set := &resourceSet{
 		httpRoutes:    mapset.NewSet(),
 		tcpRoutes:     mapset.NewSet(),
+		grpcRoutes:    mapset.NewSet(),
+		tlsRoutes:     mapset.NewSet(),
 		certificates:  mapset.NewSet(),
 		consulObjects: NewReferenceSet(),
 	}
@@ -383,6 +422,44 @@ func (s *ResourceMap) ReferenceCountTCPRoute(route gwv1alpha2.TCPRoute) {
 	s.tcpRouteGateways[consulKey] = set
 }
 
+func (s *ResourceMap) ReferenceCountGRPCRoute(route gwv1alpha2.GRPCRoute) {
+	key := client.ObjectKeyFromObject(&route)
+	consulKey := NormalizeMeta(s.toConsulReference(api.HTTPRoute, key))
+
+	set := &grpcRoute{
+		route:    route,
+		gateways: mapset.NewSet(),
+	}
+
+	for gatewayKey := range s.gatewaysForRoute(route.Namespace, route.Spec.ParentRefs).Iter() {
+		set.gateways.Add(gatewayKey.(api.ResourceReference))
+
+		gateway := s.gatewayResources[gatewayKey.(api.ResourceReference)]
+		gateway.grpcRoutes.Add(consulKey)
+	}
+
+	s.grpcRouteGateways[consulKey] = set
+}
+
+func (s *ResourceMap) ReferenceCountTLSRoute(route gwv1alpha2.TLSRoute) {
+	key := client.ObjectKeyFromObject(&route)
+	consulKey := NormalizeMeta(s.toConsulReference(api.TCPRoute, key))
+
+	set := &tlsRoute{
+		route:    route,
+		gateways: mapset.NewSet(),
+	}
+
+	for gatewayKey := range s.gatewaysForRoute(route.Namespace, route.Spec.ParentRefs).Iter() {
+		set.gateways.Add(gatewayKey.(api.ResourceReference))
+
+		gateway := s.gatewayResources[gatewayKey.(api.ResourceReference)]
+		gateway.tlsRoutes.Add(consulKey)
+	}
+
+	s.tlsRouteGateways[consulKey] = set
+}
+
 func (s *ResourceMap) gatewaysForRoute(namespace string, refs []gwv1beta1.ParentReference) mapset.Set {
 	gateways := mapset.NewSet()
 
@@ -464,9 +541,60 @@ func (s *ResourceMap) CanGCHTTPRouteOnUnbind(id api.ResourceReference) bool {
 	if set := s.httpRouteGateways[NormalizeMeta(id)]; set != nil {
 		return set.gateways.Cardinality() <= 1
 	}
+	if set := s.grpcRouteGateways[NormalizeMeta(id)]; set != nil {
+		return set.gateways.Cardinality() <= 1
+	}
 	return true
 }
 
+// HTTPRouteConflicts returns whether an HTTPRoute referencing one of the tracked
+// gateways has the same name as the given GRPCRoute. Both are written to Consul
+// as http-route config entries, so only one of them can be synced.
+func (s *ResourceMap) HTTPRouteConflicts(key types.NamespacedName) bool {
+	_, ok := s.httpRouteGateways[NormalizeMeta(s.toConsulReference(api.HTTPRoute, key))]
+	return ok
+}
+
+func (s *ResourceMap) TranslateAndMutateGRPCRoute(key types.NamespacedName, onUpdate func(error, api.ConfigEntryStatus), mutateFn func(old *api.HTTPRouteConfigEntry, new api.HTTPRouteConfigEntry) api.HTTPRouteConfigEntry) {
+	consulKey := NormalizeMeta(s.toConsulReference(api.HTTPRoute, key))
+
+	route, ok := s.grpcRouteGateways[consulKey]
+	if !ok {
+		return
+	}
+
+	translated := s.translator.ToGRPCRoute(route.route, s)
+
+	consulRoute, ok := s.consulHTTPRoutes[consulKey]
+	if ok {
+		mutated := mutateFn(&consulRoute.route, *translated)
+		if len(mutated.Parents) != 0 {
+			// if we don't have any parents set, we keep this around to allow the route
+			// to be GC'd.
+			delete(s.consulHTTPRoutes, consulKey)
+			s.consulMutations = append(s.consulMutations, &ConsulUpdateOperation{
+				Entry: &mutated,
+				OnUpdate: func(err error) {
+					onUpdate(err, mutated.Status)
+				},
+			})
+		}
+		return
+	}
+	mutated := mutateFn(nil, *translated)
+	if len(mutated.Parents) != 0 {
+		// if we don't have any parents set, we keep this around to allow the route
+		// to be GC'd.
+		delete(s.consulHTTPRoutes, consulKey)
+		s.consulMutations = append(s.consulMutations, &ConsulUpdateOperation{
+			Entry: &mutated,
+			OnUpdate: func(err error) {
+				onUpdate(err, mutated.Status)
+			},
+		})
+	}
+}
+
 func (s *ResourceMap) TranslateAndMutateTCPRoute(key types.NamespacedName, onUpdate func(error, api.ConfigEntryStatus), mutateFn func(*api.TCPRouteConfigEntry, api.TCPRouteConfigEntry) api.TCPRouteConfigEntry) {
 	consulKey := NormalizeMeta(s.toConsulReference(api.TCPRoute, key))
 
@@ -531,9 +659,60 @@ func (s *ResourceMap) CanGCTCPRouteOnUnbind(id api.ResourceReference) bool {
 	if set := s.tcpRouteGateways[NormalizeMeta(id)]; set != nil {
 		return set.gateways.Cardinality() <= 1
 	}
+	if set := s.tlsRouteGateways[NormalizeMeta(id)]; set != nil {
+		return set.gateways.Cardinality() <= 1
+	}
 	return true
 }
 
+// TCPRouteConflicts returns whether a TCPRoute referencing one of the tracked
+// gateways has the same name as the given TLSRoute. Both are written to Consul
+// as tcp-route config entries, so only one of them can be synced.
+func (s *ResourceMap) TCPRouteConflicts(key types.NamespacedName) bool {
+	_, ok := s.tcpRouteGateways[NormalizeMeta(s.toConsulReference(api.TCPRoute, key))]
+	return ok
+}
+
+func (s *ResourceMap) TranslateAndMutateTLSRoute(key types.NamespacedName, onUpdate func(error, api.ConfigEntryStatus), mutateFn func(*api.TCPRouteConfigEntry, api.TCPRouteConfigEntry) api.TCPRouteConfigEntry) {
+	consulKey := NormalizeMeta(s.toConsulReference(api.TCPRoute, key))
+
+	route, ok := s.tlsRouteGateways[consulKey]
+	if !ok {
+		return
+	}
+
+	translated := s.translator.ToTLSRoute(route.route, s)
+
+	consulRoute, ok := s.consulTCPRoutes[consulKey]
+	if ok {
+		mutated := mutateFn(&consulRoute.route, *translated)
+		if len(mutated.Parents) != 0 {
+			// if we don't have any parents set, we keep this around to allow the route
+			// to be GC'd.
+			delete(s.consulTCPRoutes, consulKey)
+			s.consulMutations = append(s.consulMutations, &ConsulUpdateOperation{
+				Entry: &mutated,
+				OnUpdate: func(err error) {
+					onUpdate(err, mutated.Status)
+				},
+			})
+		}
+		return
+	}
+	mutated := mutateFn(nil, *translated)
+	if len(mutated.Parents) != 0 {
+		// if we don't have any parents set, we keep this around to allow the route
+		// to be GC'd.
+		delete(s.consulTCPRoutes, consulKey)
+		s.consulMutations = append(s.consulMutations, &ConsulUpdateOperation{
+			Entry: &mutated,
+			OnUpdate: func(err error) {
+				onUpdate(err, mutated.Status)
+			},
+		})
+	}
+}
+
 func (s *ResourceMap) TranslateInlineCertificate(key types.NamespacedName) error {
 	consulKey := s.toConsulReference(api.InlineCertificate, key)
 
@@ -608,3 +787,11 @@ func (s *ResourceMap) HTTPRouteCanReferenceBackend(route gwv1beta1.HTTPRoute, re
 func (s *ResourceMap) TCPRouteCanReferenceBackend(route gwv1alpha2.TCPRoute, ref gwv1beta1.BackendRef) bool {
 	return s.referenceValidator.TCPRouteCanReferenceBackend(route, ref)
 }
+
+func (s *ResourceMap) GRPCRouteCanReferenceBackend(route gwv1alpha2.GRPCRoute, ref gwv1beta1.BackendRef) bool {
+	return s.referenceValidator.GRPCRouteCanReferenceBackend(route, ref)
+}
+
+func (s *ResourceMap) TLSRouteCanReferenceBackend(route gwv1alpha2.TLSRoute, ref gwv1beta1.BackendRef) bool {
+	return s.referenceValidator.TLSRouteCanReferenceBackend(route, ref)
+}
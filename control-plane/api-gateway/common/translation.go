@@ -4,6 +4,7 @@
 package common
 
 import (
+	"regexp"
 	"strings"
 
 	"github.com/hashicorp/consul-k8s/control-plane/api/v1alpha1"
@@ -74,10 +75,13 @@ func (t ResourceTranslator) ToAPIGateway(gateway gwv1beta1.Gateway, resources *R
 	}
 }
 
+// TLS listeners pass the TLS connections through, which Consul
+// does with a tcp listener that has no certificates.
 var listenerProtocolMap = map[string]string{
 	"https": "http",
 	"http":  "http",
 	"tcp":   "tcp",
+	"tls":   "tcp",
 }
 
 func (t ResourceTranslator) toAPIGatewayListener(gateway gwv1beta1.Gateway, listener gwv1beta1.Listener, resources *ResourceMap) (api.APIGatewayListener, bool) {
@@ -85,7 +89,7 @@ func (t ResourceTranslator) toAPIGatewayListener(gateway gwv1beta1.Gateway, list
 
 	var certificates []api.ResourceReference
 
-	if listener.TLS != nil {
+	if listener.TLS != nil && !DerefEqual(listener.TLS.Mode, string(gwv1beta1.TLSModePassthrough)) {
 		for _, ref := range listener.TLS.CertificateRefs {
 			if !resources.GatewayCanReferenceSecret(gateway, ref) {
 				return api.APIGatewayListener{}, false
@@ -140,7 +144,7 @@ func (t ResourceTranslator) ToHTTPRoute(route gwv1beta1.HTTPRoute, resources *Re
 
 func (t ResourceTranslator) translateHTTPRouteRule(route gwv1beta1.HTTPRoute, rule gwv1beta1.HTTPRouteRule, resources *ResourceMap) (api.HTTPRouteRule, bool) {
 	services := ConvertSliceFuncIf(rule.BackendRefs, func(ref gwv1beta1.HTTPBackendRef) (api.HTTPService, bool) {
-		return t.translateHTTPBackendRef(route, ref, resources)
+		return t.translateHTTPBackendRef(route, rule.Matches, ref, resources)
 	})
 
 	if len(services) == 0 {
@@ -148,7 +152,7 @@ func (t ResourceTranslator) translateHTTPRouteRule(route gwv1beta1.HTTPRoute, ru
 	}
 
 	matches := ConvertSliceFunc(rule.Matches, t.translateHTTPMatch)
-	filters := t.translateHTTPFilters(rule.Filters)
+	filters := t.translateHTTPFilters(rule.Filters, rule.Matches)
 
 	return api.HTTPRouteRule{
 		Services: services,
@@ -157,7 +161,7 @@ func (t ResourceTranslator) translateHTTPRouteRule(route gwv1beta1.HTTPRoute, ru
 	}, true
 }
 
-func (t ResourceTranslator) translateHTTPBackendRef(route gwv1beta1.HTTPRoute, ref gwv1beta1.HTTPBackendRef, resources *ResourceMap) (api.HTTPService, bool) {
+func (t ResourceTranslator) translateHTTPBackendRef(route gwv1beta1.HTTPRoute, matches []gwv1beta1.HTTPRouteMatch, ref gwv1beta1.HTTPBackendRef, resources *ResourceMap) (api.HTTPService, bool) {
 	id := types.NamespacedName{
 		Name:      string(ref.Name),
 		Namespace: DerefStringOr(ref.Namespace, route.Namespace),
@@ -166,7 +170,7 @@ func (t ResourceTranslator) translateHTTPBackendRef(route gwv1beta1.HTTPRoute, r
 	isServiceRef := NilOrEqual(ref.Group, "") && NilOrEqual(ref.Kind, "Service")
 
 	if isServiceRef && resources.HasService(id) && resources.HTTPRouteCanReferenceBackend(route, ref.BackendRef) {
-		filters := t.translateHTTPFilters(ref.Filters)
+		filters := t.translateHTTPFilters(ref.Filters, matches)
 		service := resources.Service(id)
 
 		return api.HTTPService{
@@ -180,7 +184,7 @@ func (t ResourceTranslator) translateHTTPBackendRef(route gwv1beta1.HTTPRoute, r
 
 	isMeshServiceRef := DerefEqual(ref.Group, v1alpha1.ConsulHashicorpGroup) && DerefEqual(ref.Kind, v1alpha1.MeshServiceKind)
 	if isMeshServiceRef && resources.HasMeshService(id) && resources.HTTPRouteCanReferenceBackend(route, ref.BackendRef) {
-		filters := t.translateHTTPFilters(ref.Filters)
+		filters := t.translateHTTPFilters(ref.Filters, matches)
 		service := resources.MeshService(id)
 
 		return api.HTTPService{
@@ -246,7 +250,11 @@ func (t ResourceTranslator) translateHTTPQueryMatch(match gwv1beta1.HTTPQueryPar
 	}
 }
 
-func (t ResourceTranslator) translateHTTPFilters(filters []gwv1beta1.HTTPRouteFilter) api.HTTPFilters {
+// translateHTTPFilters translates the filters of a route rule or backend reference. The
+// RequestRedirect, ResponseHeaderModifier, RequestMirror and ExtensionRef filters have no
+// equivalent in the Consul http-route config entry, so they are dropped here and the binder
+// rejects any route that uses them.
+func (t ResourceTranslator) translateHTTPFilters(filters []gwv1beta1.HTTPRouteFilter, matches []gwv1beta1.HTTPRouteMatch) api.HTTPFilters {
 	var urlRewrite *api.URLRewrite
 	consulFilter := api.HTTPHeaderFilter{
 		Add: make(map[string]string),
@@ -254,21 +262,28 @@ func (t ResourceTranslator) translateHTTPFilters(filters []gwv1beta1.HTTPRouteFi
 	}
 
 	for _, filter := range filters {
-		consulFilter.Remove = append(consulFilter.Remove, filter.RequestHeaderModifier.Remove...)
+		if filter.RequestHeaderModifier != nil {
+			consulFilter.Remove = append(consulFilter.Remove, filter.RequestHeaderModifier.Remove...)
 
-		for _, toAdd := range filter.RequestHeaderModifier.Add {
-			consulFilter.Add[string(toAdd.Name)] = toAdd.Value
-		}
+			for _, toAdd := range filter.RequestHeaderModifier.Add {
+				consulFilter.Add[string(toAdd.Name)] = toAdd.Value
+			}
 
-		for _, toSet := range filter.RequestHeaderModifier.Set {
-			consulFilter.Set[string(toSet.Name)] = toSet.Value
+			for _, toSet := range filter.RequestHeaderModifier.Set {
+				consulFilter.Set[string(toSet.Name)] = toSet.Value
+			}
 		}
 
-		// we drop any path rewrites that are not prefix matches as we don't support those
-		if filter.URLRewrite != nil &&
-			filter.URLRewrite.Path != nil &&
-			filter.URLRewrite.Path.Type == gwv1beta1.PrefixMatchHTTPPathModifier {
-			urlRewrite = &api.URLRewrite{Path: DerefStringOr(filter.URLRewrite.Path.ReplacePrefixMatch, "")}
+		if filter.URLRewrite != nil && filter.URLRewrite.Path != nil {
+			switch filter.URLRewrite.Path.Type {
+			case gwv1beta1.PrefixMatchHTTPPathModifier:
+				urlRewrite = &api.URLRewrite{Path: DerefStringOr(filter.URLRewrite.Path.ReplacePrefixMatch, "")}
+			case gwv1beta1.FullPathHTTPPathModifier:
+				// we drop any full path rewrites that we can't express as a prefix rewrite
+				if CanRewriteFullPath(matches) {
+					urlRewrite = &api.URLRewrite{Path: DerefStringOr(filter.URLRewrite.Path.ReplaceFullPath, "")}
+				}
+			}
 		}
 	}
 	return api.HTTPFilters{
@@ -277,6 +292,185 @@ func (t ResourceTranslator) translateHTTPFilters(filters []gwv1beta1.HTTPRouteFi
 	}
 }
 
+// CanRewriteFullPath returns whether a full path rewrite can be applied to a rule with the
+// given matches. Consul only rewrites the matched prefix of a path, which is the full path
+// if every match of the rule is an exact path match.
+func CanRewriteFullPath(matches []gwv1beta1.HTTPRouteMatch) bool {
+	if len(matches) == 0 {
+		return false
+	}
+	for _, match := range matches {
+		if match.Path == nil || !DerefEqual(match.Path.Type, string(gwv1beta1.PathMatchExact)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ToGRPCRoute translates a kubernetes GRPCRoute into a Consul HTTPRoute Config Entry. Consul
+// has no gRPC specific route, gRPC requests are HTTP/2 requests to the "/<service>/<method>"
+// path, so the method matches of the route are expressed as path matches instead.
+func (t ResourceTranslator) ToGRPCRoute(route gwv1alpha2.GRPCRoute, resources *ResourceMap) *api.HTTPRouteConfigEntry {
+	namespace := t.Namespace(route.Namespace)
+
+	// we don't translate parent refs
+
+	hostnames := StringLikeSlice(route.Spec.Hostnames)
+	rules := ConvertSliceFuncIf(route.Spec.Rules, func(rule gwv1alpha2.GRPCRouteRule) (api.HTTPRouteRule, bool) {
+		return t.translateGRPCRouteRule(route, rule, resources)
+	})
+
+	return &api.HTTPRouteConfigEntry{
+		Kind:      api.HTTPRoute,
+		Name:      route.Name,
+		Namespace: namespace,
+		Partition: t.ConsulPartition,
+		Meta: t.addDatacenterToMeta(map[string]string{
+			constants.MetaKeyKubeNS:   route.Namespace,
+			constants.MetaKeyKubeName: route.Name,
+		}),
+		Hostnames: hostnames,
+		Rules:     rules,
+	}
+}
+
+func (t ResourceTranslator) translateGRPCRouteRule(route gwv1alpha2.GRPCRoute, rule gwv1alpha2.GRPCRouteRule, resources *ResourceMap) (api.HTTPRouteRule, bool) {
+	services := ConvertSliceFuncIf(rule.BackendRefs, func(ref gwv1alpha2.GRPCBackendRef) (api.HTTPService, bool) {
+		return t.translateGRPCBackendRef(route, ref, resources)
+	})
+
+	if len(services) == 0 {
+		return api.HTTPRouteRule{}, false
+	}
+
+	matches := ConvertSliceFunc(rule.Matches, t.translateGRPCMatch)
+	filters := t.translateGRPCFilters(rule.Filters)
+
+	return api.HTTPRouteRule{
+		Services: services,
+		Matches:  matches,
+		Filters:  filters,
+	}, true
+}
+
+func (t ResourceTranslator) translateGRPCBackendRef(route gwv1alpha2.GRPCRoute, ref gwv1alpha2.GRPCBackendRef, resources *ResourceMap) (api.HTTPService, bool) {
+	id := types.NamespacedName{
+		Name:      string(ref.Name),
+		Namespace: DerefStringOr(ref.Namespace, route.Namespace),
+	}
+
+	isServiceRef := NilOrEqual(ref.Group, "") && NilOrEqual(ref.Kind, "Service")
+
+	if isServiceRef && resources.HasService(id) && resources.GRPCRouteCanReferenceBackend(route, ref.BackendRef) {
+		filters := t.translateGRPCFilters(ref.Filters)
+		service := resources.Service(id)
+
+		return api.HTTPService{
+			Name:      service.Name,
+			Namespace: service.Namespace,
+			Partition: t.ConsulPartition,
+			Filters:   filters,
+			Weight:    DerefIntOr(ref.Weight, 1),
+		}, true
+	}
+
+	isMeshServiceRef := DerefEqual(ref.Group, v1alpha1.ConsulHashicorpGroup) && DerefEqual(ref.Kind, v1alpha1.MeshServiceKind)
+	if isMeshServiceRef && resources.HasMeshService(id) && resources.GRPCRouteCanReferenceBackend(route, ref.BackendRef) {
+		filters := t.translateGRPCFilters(ref.Filters)
+		service := resources.MeshService(id)
+
+		return api.HTTPService{
+			Name:      service.Name,
+			Namespace: service.Namespace,
+			Partition: t.ConsulPartition,
+			Filters:   filters,
+			Weight:    DerefIntOr(ref.Weight, 1),
+		}, true
+	}
+
+	return api.HTTPService{}, false
+}
+
+func (t ResourceTranslator) translateGRPCMatch(match gwv1alpha2.GRPCRouteMatch) api.HTTPMatch {
+	headers := ConvertSliceFunc(match.Headers, t.translateGRPCHeaderMatch)
+
+	return api.HTTPMatch{
+		Headers: headers,
+		Path:    DerefConvertFunc(match.Method, t.translateGRPCMethodMatch),
+	}
+}
+
+// translateGRPCMethodMatch translates a method match into a match on the "/<service>/<method>"
+// path of the request, an omitted service or method matches any of them.
+func (t ResourceTranslator) translateGRPCMethodMatch(match gwv1alpha2.GRPCMethodMatch) api.HTTPPathMatch {
+	service := DerefStringOr(match.Service, "")
+	method := DerefStringOr(match.Method, "")
+
+	if DerefEqual(match.Type, string(gwv1alpha2.GRPCMethodMatchRegularExpression)) {
+		return api.HTTPPathMatch{
+			Match: api.HTTPPathMatchRegularExpression,
+			Value: "/" + orDefault(service, "[^/]+") + "/" + orDefault(method, "[^/]+"),
+		}
+	}
+
+	switch {
+	case service != "" && method != "":
+		return api.HTTPPathMatch{
+			Match: api.HTTPPathMatchExact,
+			Value: "/" + service + "/" + method,
+		}
+	case service != "":
+		return api.HTTPPathMatch{
+			Match: api.HTTPPathMatchPrefix,
+			Value: "/" + service + "/",
+		}
+	case method != "":
+		return api.HTTPPathMatch{
+			Match: api.HTTPPathMatchRegularExpression,
+			Value: "/[^/]+/" + regexp.QuoteMeta(method),
+		}
+	}
+	return api.HTTPPathMatch{
+		Match: api.HTTPPathMatchPrefix,
+		Value: "/",
+	}
+}
+
+func (t ResourceTranslator) translateGRPCHeaderMatch(match gwv1alpha2.GRPCHeaderMatch) api.HTTPHeaderMatch {
+	return api.HTTPHeaderMatch{
+		Name:  string(match.Name),
+		Value: match.Value,
+		Match: DerefLookup(match.Type, headerMatchTypeTranslation),
+	}
+}
+
+// translateGRPCFilters translates the filters of a route rule or backend reference. Only the
+// RequestHeaderModifier filter has an equivalent in the Consul http-route config entry, the
+// binder rejects any route that uses the other filters.
+func (t ResourceTranslator) translateGRPCFilters(filters []gwv1alpha2.GRPCRouteFilter) api.HTTPFilters {
+	consulFilter := api.HTTPHeaderFilter{
+		Add: make(map[string]string),
+		Set: make(map[string]string),
+	}
+
+	for _, filter := range filters {
+		if filter.RequestHeaderModifier != nil {
+			consulFilter.Remove = append(consulFilter.Remove, filter.RequestHeaderModifier.Remove...)
+
+			for _, toAdd := range filter.RequestHeaderModifier.Add {
+				consulFilter.Add[string(toAdd.Name)] = toAdd.Value
+			}
+
+			for _, toSet := range filter.RequestHeaderModifier.Set {
+				consulFilter.Set[string(toSet.Name)] = toSet.Value
+			}
+		}
+	}
+	return api.HTTPFilters{
+		Headers: []api.HTTPHeaderFilter{consulFilter},
+	}
+}
+
 func (t ResourceTranslator) ToTCPRoute(route gwv1alpha2.TCPRoute, resources *ResourceMap) *api.TCPRouteConfigEntry {
 	namespace := t.Namespace(route.Namespace)
 
@@ -332,6 +526,64 @@ func (t ResourceTranslator) translateTCPRouteRule(route gwv1alpha2.TCPRoute, ref
 	return api.TCPService{}, false
 }
 
+// ToTLSRoute translates a kubernetes TLSRoute into a Consul TCPRoute Config Entry. The
+// TLS connections are passed through to the backends, the SNI hostnames of the route
+// are only used for binding it to the gateway listeners.
+func (t ResourceTranslator) ToTLSRoute(route gwv1alpha2.TLSRoute, resources *ResourceMap) *api.TCPRouteConfigEntry {
+	namespace := t.Namespace(route.Namespace)
+
+	// we don't translate parent refs
+
+	backendRefs := ConvertSliceFunc(route.Spec.Rules, func(rule gwv1alpha2.TLSRouteRule) []gwv1beta1.BackendRef { return rule.BackendRefs })
+	flattenedRefs := Flatten(backendRefs)
+	services := ConvertSliceFuncIf(flattenedRefs, func(ref gwv1beta1.BackendRef) (api.TCPService, bool) {
+		return t.translateTLSRouteRule(route, ref, resources)
+	})
+
+	return &api.TCPRouteConfigEntry{
+		Kind:      api.TCPRoute,
+		Name:      route.Name,
+		Namespace: namespace,
+		Partition: t.ConsulPartition,
+		Meta: t.addDatacenterToMeta(map[string]string{
+			constants.MetaKeyKubeNS:   route.Namespace,
+			constants.MetaKeyKubeName: route.Name,
+		}),
+		Services: services,
+	}
+}
+
+func (t ResourceTranslator) translateTLSRouteRule(route gwv1alpha2.TLSRoute, ref gwv1beta1.BackendRef, resources *ResourceMap) (api.TCPService, bool) {
+	// we ignore weight for now
+
+	id := types.NamespacedName{
+		Name:      string(ref.Name),
+		Namespace: DerefStringOr(ref.Namespace, route.Namespace),
+	}
+
+	isServiceRef := NilOrEqual(ref.Group, "") && NilOrEqual(ref.Kind, "Service")
+	if isServiceRef && resources.HasService(id) && resources.TLSRouteCanReferenceBackend(route, ref) {
+		service := resources.Service(id)
+
+		return api.TCPService{
+			Name:      service.Name,
+			Namespace: service.Namespace,
+		}, true
+	}
+
+	isMeshServiceRef := DerefEqual(ref.Group, v1alpha1.ConsulHashicorpGroup) && DerefEqual(ref.Kind, v1alpha1.MeshServiceKind)
+	if isMeshServiceRef && resources.HasMeshService(id) && resources.TLSRouteCanReferenceBackend(route, ref) {
+		service := resources.MeshService(id)
+
+		return api.TCPService{
+			Name:      service.Name,
+			Namespace: service.Namespace,
+		}, true
+	}
+
+	return api.TCPService{}, false
+}
+
 func (t ResourceTranslator) ToInlineCertificate(secret corev1.Secret) (*api.InlineCertificateConfigEntry, error) {
 	certificate, privateKey, err := ParseCertificateData(secret)
 	if err != nil {
@@ -44,6 +44,14 @@ func (v fakeReferenceValidator) TCPRouteCanReferenceBackend(tcpRoute gwv1alpha2.
 	return true
 }
 
+func (v fakeReferenceValidator) GRPCRouteCanReferenceBackend(grpcRoute gwv1alpha2.GRPCRoute, backendRef gwv1beta1.BackendRef) bool {
+	return true
+}
+
+func (v fakeReferenceValidator) TLSRouteCanReferenceBackend(tlsRoute gwv1alpha2.TLSRoute, backendRef gwv1beta1.BackendRef) bool {
+	return true
+}
+
 func TestTranslator_Namespace(t *testing.T) {
 	testCases := []struct {
 		EnableConsulNamespaces bool
@@ -1188,6 +1196,90 @@ func TestTranslator_ToHTTPRoute(t *testing.T) {
 				Namespace: "k8s-ns",
 			},
 		},
+		"full path rewrites on exact path matches": {
+			args: args{
+				k8sHTTPRoute: gwv1beta1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "k8s-http-route",
+						Namespace: "k8s-ns",
+					},
+					Spec: gwv1beta1.HTTPRouteSpec{
+						Rules: []gwv1beta1.HTTPRouteRule{
+							{
+								Matches: []gwv1beta1.HTTPRouteMatch{
+									{
+										Path: &gwv1beta1.HTTPPathMatch{
+											Type:  PointerTo(gwv1beta1.PathMatchExact),
+											Value: PointerTo("/v1"),
+										},
+									},
+								},
+								Filters: []gwv1beta1.HTTPRouteFilter{
+									{
+										Type: gwv1beta1.HTTPRouteFilterURLRewrite,
+										URLRewrite: &gwv1beta1.HTTPURLRewriteFilter{
+											Path: &gwv1beta1.HTTPPathModifier{
+												Type:            gwv1beta1.FullPathHTTPPathModifier,
+												ReplaceFullPath: PointerTo("/v2"),
+											},
+										},
+									},
+								},
+								BackendRefs: []gwv1beta1.HTTPBackendRef{
+									{
+										BackendRef: gwv1beta1.BackendRef{
+											BackendObjectReference: gwv1beta1.BackendObjectReference{
+												Name:      "service one",
+												Namespace: PointerTo(gwv1beta1.Namespace("some ns")),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				services: []types.NamespacedName{
+					{Name: "service one", Namespace: "some ns"},
+				},
+			},
+			want: api.HTTPRouteConfigEntry{
+				Kind: api.HTTPRoute,
+				Name: "k8s-http-route",
+				Rules: []api.HTTPRouteRule{
+					{
+						Filters: api.HTTPFilters{
+							Headers:    []api.HTTPHeaderFilter{{Add: map[string]string{}, Set: map[string]string{}}},
+							URLRewrite: &api.URLRewrite{Path: "/v2"},
+						},
+						Matches: []api.HTTPMatch{
+							{
+								Headers: []api.HTTPHeaderMatch{},
+								Path: api.HTTPPathMatch{
+									Match: api.HTTPPathMatchExact,
+									Value: "/v1",
+								},
+								Query: []api.HTTPQueryMatch{},
+							},
+						},
+						Services: []api.HTTPService{
+							{
+								Name:      "service one",
+								Namespace: "some ns",
+								Weight:    1,
+								Filters:   api.HTTPFilters{Headers: []api.HTTPHeaderFilter{{Add: map[string]string{}, Set: map[string]string{}}}},
+							},
+						},
+					},
+				},
+				Hostnames: []string{},
+				Meta: map[string]string{
+					constants.MetaKeyKubeNS:   "k8s-ns",
+					constants.MetaKeyKubeName: "k8s-http-route",
+				},
+				Namespace: "k8s-ns",
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -1325,6 +1417,294 @@ func TestTranslator_ToTCPRoute(t *testing.T) {
 	}
 }
 
+func TestTranslator_ToTLSRoute(t *testing.T) {
+	t.Parallel()
+	tr := ResourceTranslator{
+		EnableConsulNamespaces: true,
+		EnableK8sMirroring:     true,
+	}
+
+	resources := NewResourceMap(tr, fakeReferenceValidator{}, logrtest.NewTestLogger(t))
+	resources.AddService(types.NamespacedName{Name: "some-service", Namespace: "svc-ns"}, "some-service")
+	resources.AddMeshService(v1alpha1.MeshService{ObjectMeta: metav1.ObjectMeta{Name: "some-mesh-service", Namespace: "svc-ns"}, Spec: v1alpha1.MeshServiceSpec{Name: "some-override"}})
+
+	route := gwv1alpha2.TLSRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tls-route",
+			Namespace: "k8s-ns",
+		},
+		Spec: gwv1alpha2.TLSRouteSpec{
+			Hostnames: []gwv1alpha2.Hostname{"tls.example.com"},
+			Rules: []gwv1alpha2.TLSRouteRule{
+				{
+					BackendRefs: []gwv1beta1.BackendRef{
+						{
+							BackendObjectReference: gwv1beta1.BackendObjectReference{
+								Name:      "some-service",
+								Namespace: PointerTo(gwv1beta1.Namespace("svc-ns")),
+							},
+						},
+						{
+							BackendObjectReference: gwv1beta1.BackendObjectReference{
+								Group:     PointerTo(gwv1beta1.Group(v1alpha1.ConsulHashicorpGroup)),
+								Kind:      PointerTo(gwv1beta1.Kind(v1alpha1.MeshServiceKind)),
+								Name:      "some-mesh-service",
+								Namespace: PointerTo(gwv1beta1.Namespace("svc-ns")),
+							},
+						},
+						{
+							BackendObjectReference: gwv1beta1.BackendObjectReference{
+								Name: "missing-service",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	want := &api.TCPRouteConfigEntry{
+		Kind:      api.TCPRoute,
+		Name:      "tls-route",
+		Namespace: "k8s-ns",
+		Services: []api.TCPService{
+			{Name: "some-service", Namespace: "svc-ns"},
+			{Name: "some-override", Namespace: "svc-ns"},
+		},
+		Meta: map[string]string{
+			constants.MetaKeyKubeNS:   "k8s-ns",
+			constants.MetaKeyKubeName: "tls-route",
+		},
+	}
+
+	if diff := cmp.Diff(want, tr.ToTLSRoute(route, resources)); diff != "" {
+		t.Errorf("Translator.ToTLSRoute() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTranslator_ToGRPCRoute(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		k8sRoute     gwv1alpha2.GRPCRoute
+		services     []types.NamespacedName
+		meshServices []v1alpha1.MeshService
+	}
+	tests := map[string]struct {
+		args args
+		want api.HTTPRouteConfigEntry
+	}{
+		"base test": {
+			args: args{
+				k8sRoute: gwv1alpha2.GRPCRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "grpc-route",
+						Namespace: "k8s-ns",
+					},
+					Spec: gwv1alpha2.GRPCRouteSpec{
+						Hostnames: []gwv1alpha2.Hostname{"host-name.example.com"},
+						Rules: []gwv1alpha2.GRPCRouteRule{
+							{
+								Matches: []gwv1alpha2.GRPCRouteMatch{
+									{
+										Method: &gwv1alpha2.GRPCMethodMatch{
+											Service: PointerTo("helloworld.Greeter"),
+											Method:  PointerTo("SayHello"),
+										},
+										Headers: []gwv1alpha2.GRPCHeaderMatch{
+											{
+												Type:  PointerTo(gwv1beta1.HeaderMatchExact),
+												Name:  "x-version",
+												Value: "v1",
+											},
+										},
+									},
+									{
+										Method: &gwv1alpha2.GRPCMethodMatch{
+											Service: PointerTo("helloworld.Farewell"),
+										},
+									},
+								},
+								Filters: []gwv1alpha2.GRPCRouteFilter{
+									{
+										Type: gwv1alpha2.GRPCRouteFilterRequestHeaderModifier,
+										RequestHeaderModifier: &gwv1beta1.HTTPHeaderFilter{
+											Set:    []gwv1beta1.HTTPHeader{{Name: "x-set", Value: "set"}},
+											Add:    []gwv1beta1.HTTPHeader{{Name: "x-add", Value: "add"}},
+											Remove: []string{"x-remove"},
+										},
+									},
+								},
+								BackendRefs: []gwv1alpha2.GRPCBackendRef{
+									{
+										BackendRef: gwv1beta1.BackendRef{
+											BackendObjectReference: gwv1beta1.BackendObjectReference{
+												Name:      "service-one",
+												Namespace: PointerTo(gwv1beta1.Namespace("svc-ns")),
+											},
+											Weight: PointerTo(int32(45)),
+										},
+									},
+									{
+										BackendRef: gwv1beta1.BackendRef{
+											BackendObjectReference: gwv1beta1.BackendObjectReference{
+												Group:     PointerTo(gwv1beta1.Group(v1alpha1.ConsulHashicorpGroup)),
+												Kind:      PointerTo(gwv1beta1.Kind(v1alpha1.MeshServiceKind)),
+												Name:      "service-two",
+												Namespace: PointerTo(gwv1beta1.Namespace("svc-ns")),
+											},
+										},
+									},
+								},
+							},
+							{
+								BackendRefs: []gwv1alpha2.GRPCBackendRef{
+									{
+										BackendRef: gwv1beta1.BackendRef{
+											BackendObjectReference: gwv1beta1.BackendObjectReference{
+												Name: "service-missing",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				services: []types.NamespacedName{
+					{Name: "service-one", Namespace: "svc-ns"},
+				},
+				meshServices: []v1alpha1.MeshService{
+					{ObjectMeta: metav1.ObjectMeta{Name: "service-two", Namespace: "svc-ns"}, Spec: v1alpha1.MeshServiceSpec{Name: "some-override"}},
+				},
+			},
+			want: api.HTTPRouteConfigEntry{
+				Kind:      api.HTTPRoute,
+				Name:      "grpc-route",
+				Namespace: "k8s-ns",
+				Hostnames: []string{"host-name.example.com"},
+				Rules: []api.HTTPRouteRule{
+					{
+						Matches: []api.HTTPMatch{
+							{
+								Path: api.HTTPPathMatch{
+									Match: api.HTTPPathMatchExact,
+									Value: "/helloworld.Greeter/SayHello",
+								},
+								Headers: []api.HTTPHeaderMatch{
+									{
+										Match: api.HTTPHeaderMatchExact,
+										Name:  "x-version",
+										Value: "v1",
+									},
+								},
+							},
+							{
+								Headers: []api.HTTPHeaderMatch{},
+								Path: api.HTTPPathMatch{
+									Match: api.HTTPPathMatchPrefix,
+									Value: "/helloworld.Farewell/",
+								},
+							},
+						},
+						Filters: api.HTTPFilters{
+							Headers: []api.HTTPHeaderFilter{
+								{
+									Add:    map[string]string{"x-add": "add"},
+									Set:    map[string]string{"x-set": "set"},
+									Remove: []string{"x-remove"},
+								},
+							},
+						},
+						Services: []api.HTTPService{
+							{
+								Name:      "service-one",
+								Namespace: "svc-ns",
+								Weight:    45,
+								Filters: api.HTTPFilters{
+									Headers: []api.HTTPHeaderFilter{{Add: map[string]string{}, Set: map[string]string{}}},
+								},
+							},
+							{
+								Name:      "some-override",
+								Namespace: "svc-ns",
+								Weight:    1,
+								Filters: api.HTTPFilters{
+									Headers: []api.HTTPHeaderFilter{{Add: map[string]string{}, Set: map[string]string{}}},
+								},
+							},
+						},
+					},
+				},
+				Meta: map[string]string{
+					constants.MetaKeyKubeNS:   "k8s-ns",
+					constants.MetaKeyKubeName: "grpc-route",
+				},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tr := ResourceTranslator{
+				EnableConsulNamespaces: true,
+				EnableK8sMirroring:     true,
+			}
+
+			resources := NewResourceMap(tr, fakeReferenceValidator{}, logrtest.NewTestLogger(t))
+			for _, service := range tt.args.services {
+				resources.AddService(service, service.Name)
+			}
+			for _, service := range tt.args.meshServices {
+				resources.AddMeshService(service)
+			}
+
+			got := tr.ToGRPCRoute(tt.args.k8sRoute, resources)
+			if diff := cmp.Diff(&tt.want, got); diff != "" {
+				t.Errorf("Translator.ToGRPCRoute() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTranslator_translateGRPCMethodMatch(t *testing.T) {
+	t.Parallel()
+	regex := PointerTo(gwv1alpha2.GRPCMethodMatchRegularExpression)
+
+	tests := map[string]struct {
+		match gwv1alpha2.GRPCMethodMatch
+		want  api.HTTPPathMatch
+	}{
+		"service and method": {
+			match: gwv1alpha2.GRPCMethodMatch{Service: PointerTo("pkg.Service"), Method: PointerTo("Method")},
+			want:  api.HTTPPathMatch{Match: api.HTTPPathMatchExact, Value: "/pkg.Service/Method"},
+		},
+		"service only": {
+			match: gwv1alpha2.GRPCMethodMatch{Service: PointerTo("pkg.Service")},
+			want:  api.HTTPPathMatch{Match: api.HTTPPathMatchPrefix, Value: "/pkg.Service/"},
+		},
+		"method only": {
+			match: gwv1alpha2.GRPCMethodMatch{Method: PointerTo("Method")},
+			want:  api.HTTPPathMatch{Match: api.HTTPPathMatchRegularExpression, Value: "/[^/]+/Method"},
+		},
+		"neither": {
+			match: gwv1alpha2.GRPCMethodMatch{},
+			want:  api.HTTPPathMatch{Match: api.HTTPPathMatchPrefix, Value: "/"},
+		},
+		"regular expression": {
+			match: gwv1alpha2.GRPCMethodMatch{Type: regex, Service: PointerTo(`pkg\.v[0-9]+\.Service`), Method: PointerTo("Get.*")},
+			want:  api.HTTPPathMatch{Match: api.HTTPPathMatchRegularExpression, Value: `/pkg\.v[0-9]+\.Service/Get.*`},
+		},
+		"regular expression method only": {
+			match: gwv1alpha2.GRPCMethodMatch{Type: regex, Method: PointerTo("Get.*")},
+			want:  api.HTTPPathMatch{Match: api.HTTPPathMatchRegularExpression, Value: "/[^/]+/Get.*"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, ResourceTranslator{}.translateGRPCMethodMatch(tt.match))
+		})
+	}
+}
+
 func generateTestCertificate(t *testing.T, namespace, name string) corev1.Secret {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
 	require.NoError(t, err)
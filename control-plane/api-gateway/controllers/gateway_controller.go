@@ -14,7 +14,9 @@ import (
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -97,6 +99,13 @@ func (r *GatewayController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
+	// get the gateway parameters
+	gatewayParameters, err := r.getParametersForGateway(ctx, gateway)
+	if err != nil {
+		log.Error(err, "error fetching the gateway parameters")
+		return ctrl.Result{}, err
+	}
+
 	// get all namespaces
 	namespaces, err := r.getNamespaces(ctx)
 	if err != nil {
@@ -161,7 +170,21 @@ func (r *GatewayController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
-	if err := r.fetchServicesForRoutes(ctx, resources, tcpRoutes, httpRoutes); err != nil {
+	// get all grpc routes referencing this gateway
+	grpcRoutes, err := r.getRelatedGRPCRoutes(ctx, req.NamespacedName, resources)
+	if err != nil {
+		log.Error(err, "unable to list GRPCRoutes")
+		return ctrl.Result{}, err
+	}
+
+	// get all tls routes referencing this gateway
+	tlsRoutes, err := r.getRelatedTLSRoutes(ctx, req.NamespacedName, resources)
+	if err != nil {
+		log.Error(err, "unable to list TLSRoutes")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.fetchServicesForRoutes(ctx, resources, tcpRoutes, httpRoutes, grpcRoutes, tlsRoutes); err != nil {
 		log.Error(err, "unable to fetch services for routes")
 		return ctrl.Result{}, err
 	}
@@ -178,12 +201,15 @@ func (r *GatewayController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		ControllerName:        common.GatewayClassControllerName,
 		Namespaces:            namespaces,
 		GatewayClassConfig:    gatewayClassConfig,
+		GatewayParameters:     gatewayParameters,
 		GatewayClass:          gatewayClass,
 		Gateway:               gateway,
 		Pods:                  pods,
 		Service:               service,
 		HTTPRoutes:            httpRoutes,
 		TCPRoutes:             tcpRoutes,
+		GRPCRoutes:            grpcRoutes,
+		TLSRoutes:             tlsRoutes,
 		Resources:             resources,
 		ConsulGateway:         consulGateway,
 		ConsulGatewayServices: consulServices,
@@ -197,7 +223,7 @@ func (r *GatewayController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			return ctrl.Result{}, err
 		}
 
-		err := r.updateGatekeeperResources(ctx, log, &gateway, updates.GatewayClassConfig)
+		err := r.updateGatekeeperResources(ctx, log, &gateway, updates.GatewayClassConfig, updates.GatewayParameters)
 		if err != nil {
 			log.Error(err, "unable to update gateway resources")
 			return ctrl.Result{}, err
@@ -331,9 +357,9 @@ func (r *GatewayController) deleteGatekeeperResources(ctx context.Context, log l
 	return nil
 }
 
-func (r *GatewayController) updateGatekeeperResources(ctx context.Context, log logr.Logger, gw *gwv1beta1.Gateway, gwcc *v1alpha1.GatewayClassConfig) error {
+func (r *GatewayController) updateGatekeeperResources(ctx context.Context, log logr.Logger, gw *gwv1beta1.Gateway, gwcc *v1alpha1.GatewayClassConfig, params *v1alpha1.GatewayParameters) error {
 	gk := gatekeeper.New(log, r.Client)
-	err := gk.Upsert(ctx, *gw, *gwcc, r.HelmConfig)
+	err := gk.Upsert(ctx, *gw, *gwcc, params, r.HelmConfig)
 	if err != nil {
 		return err
 	}
@@ -381,6 +407,8 @@ func SetupGatewayControllerWithManager(ctx context.Context, mgr ctrl.Manager, co
 	return c, ctrl.NewControllerManagedBy(mgr).
 		For(&gwv1beta1.Gateway{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.Pod{}).
 		Watches(
@@ -399,10 +427,22 @@ func SetupGatewayControllerWithManager(ctx context.Context, mgr ctrl.Manager, co
 			source.NewKindWithCache(&gwv1alpha2.TCPRoute{}, mgr.GetCache()),
 			handler.EnqueueRequestsFromMapFunc(r.transformTCPRoute(ctx)),
 		).
+		Watches(
+			source.NewKindWithCache(&gwv1alpha2.GRPCRoute{}, mgr.GetCache()),
+			handler.EnqueueRequestsFromMapFunc(r.transformGRPCRoute(ctx)),
+		).
+		Watches(
+			source.NewKindWithCache(&gwv1alpha2.TLSRoute{}, mgr.GetCache()),
+			handler.EnqueueRequestsFromMapFunc(r.transformTLSRoute(ctx)),
+		).
 		Watches(
 			source.NewKindWithCache(&corev1.Secret{}, mgr.GetCache()),
 			handler.EnqueueRequestsFromMapFunc(r.transformSecret(ctx)),
 		).
+		Watches(
+			source.NewKindWithCache(&v1alpha1.GatewayParameters{}, mgr.GetCache()),
+			handler.EnqueueRequestsFromMapFunc(r.transformGatewayParameters(ctx)),
+		).
 		Watches(
 			source.NewKindWithCache(&v1alpha1.MeshService{}, mgr.GetCache()),
 			handler.EnqueueRequestsFromMapFunc(r.transformMeshService(ctx)),
@@ -453,6 +493,20 @@ func (r *GatewayController) transformGatewayClass(ctx context.Context) func(o cl
 	}
 }
 
+// transformGatewayParameters will check the list of Gateway objects for those
+// referencing the GatewayParameters, then return a list of reconcile Requests for them.
+func (r *GatewayController) transformGatewayParameters(ctx context.Context) func(o client.Object) []reconcile.Request {
+	return func(o client.Object) []reconcile.Request {
+		gatewayList := &gwv1beta1.GatewayList{}
+		if err := r.Client.List(ctx, gatewayList, &client.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector(Gateway_GatewayParametersIndex, client.ObjectKeyFromObject(o).String()),
+		}); err != nil {
+			return nil
+		}
+		return common.ObjectsToReconcileRequests(pointersOf(gatewayList.Items))
+	}
+}
+
 // transformHTTPRoute will check the HTTPRoute object for a matching
 // class, then return a list of reconcile Requests for Gateways referring to it.
 func (r *GatewayController) transformHTTPRoute(ctx context.Context) func(o client.Object) []reconcile.Request {
@@ -481,6 +535,34 @@ func (r *GatewayController) transformTCPRoute(ctx context.Context) func(o client
 	}
 }
 
+// transformGRPCRoute will check the GRPCRoute object for a matching
+// class, then return a list of reconcile Requests for Gateways referring to it.
+func (r *GatewayController) transformGRPCRoute(ctx context.Context) func(o client.Object) []reconcile.Request {
+	return func(o client.Object) []reconcile.Request {
+		route := o.(*gwv1alpha2.GRPCRoute)
+
+		refs := refsToRequests(common.ParentRefs(common.BetaGroup, common.KindGateway, route.Namespace, route.Spec.ParentRefs))
+		statusRefs := refsToRequests(common.ParentRefs(common.BetaGroup, common.KindGateway, route.Namespace, common.ConvertSliceFunc(route.Status.Parents, func(parentStatus gwv1beta1.RouteParentStatus) gwv1beta1.ParentReference {
+			return parentStatus.ParentRef
+		})))
+		return append(refs, statusRefs...)
+	}
+}
+
+// transformTLSRoute will check the TLSRoute object for a matching
+// class, then return a list of reconcile Requests for Gateways referring to it.
+func (r *GatewayController) transformTLSRoute(ctx context.Context) func(o client.Object) []reconcile.Request {
+	return func(o client.Object) []reconcile.Request {
+		route := o.(*gwv1alpha2.TLSRoute)
+
+		refs := refsToRequests(common.ParentRefs(common.BetaGroup, common.KindGateway, route.Namespace, route.Spec.ParentRefs))
+		statusRefs := refsToRequests(common.ParentRefs(common.BetaGroup, common.KindGateway, route.Namespace, common.ConvertSliceFunc(route.Status.Parents, func(parentStatus gwv1beta1.RouteParentStatus) gwv1beta1.ParentReference {
+			return parentStatus.ParentRef
+		})))
+		return append(refs, statusRefs...)
+	}
+}
+
 // transformSecret will check the Secret object for a matching
 // class, then return a list of reconcile Requests for Gateways referring to it.
 func (r *GatewayController) transformSecret(ctx context.Context) func(o client.Object) []reconcile.Request {
@@ -513,13 +595,13 @@ func (r *GatewayController) transformReferenceGrant(ctx context.Context) func(o
 }
 
 // transformMeshService will return a list of gateways that are referenced
-// by a TCPRoute or HTTPRoute that references the mesh service.
+// by a TCPRoute, HTTPRoute, GRPCRoute or TLSRoute that references the mesh service.
 func (r *GatewayController) transformMeshService(ctx context.Context) func(o client.Object) []reconcile.Request {
 	return func(o client.Object) []reconcile.Request {
 		service := o.(*v1alpha1.MeshService)
 		key := client.ObjectKeyFromObject(service).String()
 
-		return r.gatewaysForRoutesReferencing(ctx, TCPRoute_MeshServiceIndex, HTTPRoute_MeshServiceIndex, key)
+		return r.gatewaysForRoutesReferencing(ctx, meshServiceRouteIndexes, key)
 	}
 }
 
@@ -620,7 +702,7 @@ func (r *GatewayController) transformPods(ctx context.Context) func(o client.Obj
 }
 
 // transformEndpoints will return a list of gateways that are referenced
-// by a TCPRoute or HTTPRoute that references the service.
+// by a TCPRoute, HTTPRoute, GRPCRoute or TLSRoute that references the service.
 func (r *GatewayController) transformEndpoints(ctx context.Context) func(o client.Object) []reconcile.Request {
 	return func(o client.Object) []reconcile.Request {
 		key := client.ObjectKeyFromObject(o)
@@ -630,18 +712,42 @@ func (r *GatewayController) transformEndpoints(ctx context.Context) func(o clien
 			return nil
 		}
 
-		return r.gatewaysForRoutesReferencing(ctx, TCPRoute_ServiceIndex, HTTPRoute_ServiceIndex, key.String())
+		return r.gatewaysForRoutesReferencing(ctx, serviceRouteIndexes, key.String())
 	}
 }
 
+// routeIndexes holds the name of the index of each kind of route that
+// gatewaysForRoutesReferencing looks up a backend in.
+type routeIndexes struct {
+	tcp  string
+	http string
+	grpc string
+	tls  string
+}
+
+var (
+	serviceRouteIndexes = routeIndexes{
+		tcp:  TCPRoute_ServiceIndex,
+		http: HTTPRoute_ServiceIndex,
+		grpc: GRPCRoute_ServiceIndex,
+		tls:  TLSRoute_ServiceIndex,
+	}
+	meshServiceRouteIndexes = routeIndexes{
+		tcp:  TCPRoute_MeshServiceIndex,
+		http: HTTPRoute_MeshServiceIndex,
+		grpc: GRPCRoute_MeshServiceIndex,
+		tls:  TLSRoute_MeshServiceIndex,
+	}
+)
+
 // gatewaysForRoutesReferencing returns a mapping of all gateways that are referenced by routes that
-// have a backend associated with the given key and index.
-func (r *GatewayController) gatewaysForRoutesReferencing(ctx context.Context, tcpIndex, httpIndex, key string) []reconcile.Request {
+// have a backend associated with the given key in the given indexes.
+func (r *GatewayController) gatewaysForRoutesReferencing(ctx context.Context, indexes routeIndexes, key string) []reconcile.Request {
 	requestSet := make(map[types.NamespacedName]struct{})
 
 	tcpRouteList := &gwv1alpha2.TCPRouteList{}
 	if err := r.Client.List(ctx, tcpRouteList, &client.ListOptions{
-		FieldSelector: fields.OneTermEqualSelector(tcpIndex, key),
+		FieldSelector: fields.OneTermEqualSelector(indexes.tcp, key),
 	}); err != nil {
 		r.Log.Error(err, "unable to list TCPRoutes")
 	}
@@ -653,7 +759,7 @@ func (r *GatewayController) gatewaysForRoutesReferencing(ctx context.Context, tc
 
 	httpRouteList := &gwv1beta1.HTTPRouteList{}
 	if err := r.Client.List(ctx, httpRouteList, &client.ListOptions{
-		FieldSelector: fields.OneTermEqualSelector(httpIndex, key),
+		FieldSelector: fields.OneTermEqualSelector(indexes.http, key),
 	}); err != nil {
 		r.Log.Error(err, "unable to list HTTPRoutes")
 	}
@@ -663,6 +769,30 @@ func (r *GatewayController) gatewaysForRoutesReferencing(ctx context.Context, tc
 		}
 	}
 
+	grpcRouteList := &gwv1alpha2.GRPCRouteList{}
+	if err := r.Client.List(ctx, grpcRouteList, &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(indexes.grpc, key),
+	}); err != nil {
+		r.Log.Error(err, "unable to list GRPCRoutes")
+	}
+	for _, route := range grpcRouteList.Items {
+		for _, ref := range common.ParentRefs(common.BetaGroup, common.KindGateway, route.Namespace, route.Spec.ParentRefs) {
+			requestSet[ref] = struct{}{}
+		}
+	}
+
+	tlsRouteList := &gwv1alpha2.TLSRouteList{}
+	if err := r.Client.List(ctx, tlsRouteList, &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(indexes.tls, key),
+	}); err != nil {
+		r.Log.Error(err, "unable to list TLSRoutes")
+	}
+	for _, route := range tlsRouteList.Items {
+		for _, ref := range common.ParentRefs(common.BetaGroup, common.KindGateway, route.Namespace, route.Spec.ParentRefs) {
+			requestSet[ref] = struct{}{}
+		}
+	}
+
 	requests := []reconcile.Request{}
 	for request := range requestSet {
 		requests = append(requests, reconcile.Request{NamespacedName: request})
@@ -777,6 +907,38 @@ func (c *GatewayController) getRelatedTCPRoutes(ctx context.Context, gateway typ
 	return list.Items, nil
 }
 
+func (c *GatewayController) getRelatedGRPCRoutes(ctx context.Context, gateway types.NamespacedName, resources *common.ResourceMap) ([]gwv1alpha2.GRPCRoute, error) {
+	var list gwv1alpha2.GRPCRouteList
+
+	if err := c.Client.List(ctx, &list, &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(GRPCRoute_GatewayIndex, gateway.String()),
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, route := range list.Items {
+		resources.ReferenceCountGRPCRoute(route)
+	}
+
+	return list.Items, nil
+}
+
+func (c *GatewayController) getRelatedTLSRoutes(ctx context.Context, gateway types.NamespacedName, resources *common.ResourceMap) ([]gwv1alpha2.TLSRoute, error) {
+	var list gwv1alpha2.TLSRouteList
+
+	if err := c.Client.List(ctx, &list, &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(TLSRoute_GatewayIndex, gateway.String()),
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, route := range list.Items {
+		resources.ReferenceCountTLSRoute(route)
+	}
+
+	return list.Items, nil
+}
+
 func (c *GatewayController) getConfigForGatewayClass(ctx context.Context, gatewayClassConfig *gwv1beta1.GatewayClass) (*v1alpha1.GatewayClassConfig, error) {
 	if gatewayClassConfig == nil {
 		// if we don't have a gateway class we can't fetch the corresponding config
@@ -799,6 +961,25 @@ func (c *GatewayController) getConfigForGatewayClass(ctx context.Context, gatewa
 	return config, nil
 }
 
+// getParametersForGateway returns the GatewayParameters referenced by the gateway. It
+// returns nil if the gateway doesn't reference any or if they don't exist, which is
+// reported on the gateway's status.
+func (c *GatewayController) getParametersForGateway(ctx context.Context, gateway gwv1beta1.Gateway) (*v1alpha1.GatewayParameters, error) {
+	name, ok := gateway.Annotations[common.AnnotationGatewayParameters]
+	if !ok {
+		return nil, nil
+	}
+
+	params := &v1alpha1.GatewayParameters{}
+	if err := c.Client.Get(ctx, types.NamespacedName{Namespace: gateway.Namespace, Name: name}, params); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return params, nil
+}
+
 func (c *GatewayController) getGatewayClassForGateway(ctx context.Context, gateway gwv1beta1.Gateway) (*gwv1beta1.GatewayClass, error) {
 	var gatewayClass gwv1beta1.GatewayClass
 	if err := c.Client.Get(ctx, types.NamespacedName{Name: string(gateway.Spec.GatewayClassName)}, &gatewayClass); err != nil {
@@ -868,7 +1049,7 @@ func (c *GatewayController) fetchSecret(ctx context.Context, resources *common.R
 	return nil
 }
 
-func (c *GatewayController) fetchServicesForRoutes(ctx context.Context, resources *common.ResourceMap, tcpRoutes []gwv1alpha2.TCPRoute, httpRoutes []gwv1beta1.HTTPRoute) error {
+func (c *GatewayController) fetchServicesForRoutes(ctx context.Context, resources *common.ResourceMap, tcpRoutes []gwv1alpha2.TCPRoute, httpRoutes []gwv1beta1.HTTPRoute, grpcRoutes []gwv1alpha2.GRPCRoute, tlsRoutes []gwv1alpha2.TLSRoute) error {
 	serviceBackends := mapset.NewSet()
 	meshServiceBackends := mapset.NewSet()
 
@@ -898,6 +1079,32 @@ func (c *GatewayController) fetchServicesForRoutes(ctx context.Context, resource
 		}
 	}
 
+	for _, route := range grpcRoutes {
+		for _, rule := range route.Spec.Rules {
+			for _, backend := range rule.BackendRefs {
+				if common.DerefEqual(backend.Group, v1alpha1.ConsulHashicorpGroup) &&
+					common.DerefEqual(backend.Kind, v1alpha1.MeshServiceKind) {
+					meshServiceBackends.Add(common.IndexedNamespacedNameWithDefault(backend.Name, backend.Namespace, route.Namespace))
+				} else if common.NilOrEqual(backend.Group, "") && common.NilOrEqual(backend.Kind, "Service") {
+					serviceBackends.Add(common.IndexedNamespacedNameWithDefault(backend.Name, backend.Namespace, route.Namespace))
+				}
+			}
+		}
+	}
+
+	for _, route := range tlsRoutes {
+		for _, rule := range route.Spec.Rules {
+			for _, backend := range rule.BackendRefs {
+				if common.DerefEqual(backend.Group, v1alpha1.ConsulHashicorpGroup) &&
+					common.DerefEqual(backend.Kind, v1alpha1.MeshServiceKind) {
+					meshServiceBackends.Add(common.IndexedNamespacedNameWithDefault(backend.Name, backend.Namespace, route.Namespace))
+				} else if common.NilOrEqual(backend.Group, "") && common.NilOrEqual(backend.Kind, "Service") {
+					serviceBackends.Add(common.IndexedNamespacedNameWithDefault(backend.Name, backend.Namespace, route.Namespace))
+				}
+			}
+		}
+	}
+
 	for key := range meshServiceBackends.Iter() {
 		if err := c.fetchMeshService(ctx, resources, key.(types.NamespacedName)); err != nil {
 			return err
@@ -909,6 +1116,45 @@ func (c *GatewayController) fetchServicesForRoutes(ctx context.Context, resource
 			return err
 		}
 	}
+
+	return c.fetchGRPCBackendProtocols(ctx, resources, grpcRoutes)
+}
+
+// fetchGRPCBackendProtocols looks up the protocol of the Consul services that are the
+// backends of the GRPCRoutes so that backends that can't serve gRPC are rejected.
+func (c *GatewayController) fetchGRPCBackendProtocols(ctx context.Context, resources *common.ResourceMap, grpcRoutes []gwv1alpha2.GRPCRoute) error {
+	for _, route := range grpcRoutes {
+		for _, rule := range route.Spec.Rules {
+			for _, backend := range rule.BackendRefs {
+				key := common.IndexedNamespacedNameWithDefault(backend.Name, backend.Namespace, route.Namespace)
+
+				var ref api.ResourceReference
+				if common.DerefEqual(backend.Group, v1alpha1.ConsulHashicorpGroup) &&
+					common.DerefEqual(backend.Kind, v1alpha1.MeshServiceKind) {
+					if !resources.HasMeshService(key) {
+						continue
+					}
+					ref = resources.MeshService(key)
+				} else if common.NilOrEqual(backend.Group, "") && common.NilOrEqual(backend.Kind, "Service") {
+					if !resources.HasService(key) {
+						continue
+					}
+					ref = resources.Service(key)
+				} else {
+					continue
+				}
+
+				if _, ok := resources.ServiceProtocol(ref); ok {
+					continue
+				}
+				protocol, err := c.gatewayCache.FetchServiceProtocol(ctx, ref)
+				if err != nil {
+					return err
+				}
+				resources.SetServiceProtocol(ref, protocol)
+			}
+		}
+	}
 	return nil
 }
 
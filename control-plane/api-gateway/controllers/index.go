@@ -6,6 +6,7 @@ package controllers
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
@@ -20,12 +21,19 @@ const (
 	GatewayClass_GatewayClassConfigIndex = "__gatewayclass_referencing_gatewayclassconfig"
 	GatewayClass_ControllerNameIndex     = "__gatewayclass_controller_name"
 	Gateway_GatewayClassIndex            = "__gateway_referencing_gatewayclass"
+	Gateway_GatewayParametersIndex       = "__gateway_referencing_gatewayparameters"
 	HTTPRoute_GatewayIndex               = "__httproute_referencing_gateway"
 	HTTPRoute_ServiceIndex               = "__httproute_referencing_service"
 	HTTPRoute_MeshServiceIndex           = "__httproute_referencing_mesh_service"
 	TCPRoute_GatewayIndex                = "__tcproute_referencing_gateway"
 	TCPRoute_ServiceIndex                = "__tcproute_referencing_service"
 	TCPRoute_MeshServiceIndex            = "__tcproute_referencing_mesh_service"
+	GRPCRoute_GatewayIndex               = "__grpcroute_referencing_gateway"
+	GRPCRoute_ServiceIndex               = "__grpcroute_referencing_service"
+	GRPCRoute_MeshServiceIndex           = "__grpcroute_referencing_mesh_service"
+	TLSRoute_GatewayIndex                = "__tlsroute_referencing_gateway"
+	TLSRoute_ServiceIndex                = "__tlsroute_referencing_service"
+	TLSRoute_MeshServiceIndex            = "__tlsroute_referencing_mesh_service"
 	MeshService_PeerIndex                = "__meshservice_referencing_peer"
 	Secret_GatewayIndex                  = "__secret_referencing_gateway"
 )
@@ -64,6 +72,11 @@ var indexes = []index{
 		target:      &gwv1beta1.Gateway{},
 		indexerFunc: gatewayClassForGateway,
 	},
+	{
+		name:        Gateway_GatewayParametersIndex,
+		target:      &gwv1beta1.Gateway{},
+		indexerFunc: gatewayParametersForGateway,
+	},
 	{
 		name:        Secret_GatewayIndex,
 		target:      &gwv1beta1.Gateway{},
@@ -99,6 +112,36 @@ var indexes = []index{
 		target:      &gwv1alpha2.TCPRoute{},
 		indexerFunc: meshServicesForTCPRoute,
 	},
+	{
+		name:        GRPCRoute_GatewayIndex,
+		target:      &gwv1alpha2.GRPCRoute{},
+		indexerFunc: gatewaysForGRPCRoute,
+	},
+	{
+		name:        GRPCRoute_ServiceIndex,
+		target:      &gwv1alpha2.GRPCRoute{},
+		indexerFunc: servicesForGRPCRoute,
+	},
+	{
+		name:        GRPCRoute_MeshServiceIndex,
+		target:      &gwv1alpha2.GRPCRoute{},
+		indexerFunc: meshServicesForGRPCRoute,
+	},
+	{
+		name:        TLSRoute_GatewayIndex,
+		target:      &gwv1alpha2.TLSRoute{},
+		indexerFunc: gatewaysForTLSRoute,
+	},
+	{
+		name:        TLSRoute_ServiceIndex,
+		target:      &gwv1alpha2.TLSRoute{},
+		indexerFunc: servicesForTLSRoute,
+	},
+	{
+		name:        TLSRoute_MeshServiceIndex,
+		target:      &gwv1alpha2.TLSRoute{},
+		indexerFunc: meshServicesForTLSRoute,
+	},
 	{
 		name:        MeshService_PeerIndex,
 		target:      &v1alpha1.MeshService{},
@@ -142,6 +185,15 @@ func peersForMeshService(o client.Object) []string {
 	return nil
 }
 
+func gatewayParametersForGateway(o client.Object) []string {
+	gateway := o.(*gwv1beta1.Gateway)
+	name, ok := gateway.Annotations[common.AnnotationGatewayParameters]
+	if !ok {
+		return nil
+	}
+	return []string{types.NamespacedName{Namespace: gateway.Namespace, Name: name}.String()}
+}
+
 func gatewayForSecret(o client.Object) []string {
 	gateway := o.(*gwv1beta1.Gateway)
 	var secretReferences []string
@@ -175,6 +227,22 @@ func gatewaysForTCPRoute(o client.Object) []string {
 	return gatewaysForRoute(route.Namespace, route.Spec.ParentRefs, statusRefs)
 }
 
+func gatewaysForGRPCRoute(o client.Object) []string {
+	route := o.(*gwv1alpha2.GRPCRoute)
+	statusRefs := common.ConvertSliceFunc(route.Status.Parents, func(parentStatus gwv1beta1.RouteParentStatus) gwv1beta1.ParentReference {
+		return parentStatus.ParentRef
+	})
+	return gatewaysForRoute(route.Namespace, route.Spec.ParentRefs, statusRefs)
+}
+
+func gatewaysForTLSRoute(o client.Object) []string {
+	route := o.(*gwv1alpha2.TLSRoute)
+	statusRefs := common.ConvertSliceFunc(route.Status.Parents, func(parentStatus gwv1beta1.RouteParentStatus) gwv1beta1.ParentReference {
+		return parentStatus.ParentRef
+	})
+	return gatewaysForRoute(route.Namespace, route.Spec.ParentRefs, statusRefs)
+}
+
 func servicesForHTTPRoute(o client.Object) []string {
 	route := o.(*gwv1beta1.HTTPRoute)
 	refs := []string{}
@@ -255,6 +323,86 @@ func meshServicesForTCPRoute(o client.Object) []string {
 	return refs
 }
 
+func servicesForGRPCRoute(o client.Object) []string {
+	route := o.(*gwv1alpha2.GRPCRoute)
+	refs := []string{}
+	for _, rule := range route.Spec.Rules {
+	BACKEND_LOOP:
+		for _, ref := range rule.BackendRefs {
+			if common.NilOrEqual(ref.Group, "") && common.NilOrEqual(ref.Kind, common.KindService) {
+				backendRef := common.IndexedNamespacedNameWithDefault(ref.Name, ref.Namespace, route.Namespace).String()
+				for _, member := range refs {
+					if member == backendRef {
+						continue BACKEND_LOOP
+					}
+				}
+				refs = append(refs, backendRef)
+			}
+		}
+	}
+	return refs
+}
+
+func meshServicesForGRPCRoute(o client.Object) []string {
+	route := o.(*gwv1alpha2.GRPCRoute)
+	refs := []string{}
+	for _, rule := range route.Spec.Rules {
+	BACKEND_LOOP:
+		for _, ref := range rule.BackendRefs {
+			if common.DerefEqual(ref.Group, v1alpha1.ConsulHashicorpGroup) && common.DerefEqual(ref.Kind, v1alpha1.MeshServiceKind) {
+				backendRef := common.IndexedNamespacedNameWithDefault(ref.Name, ref.Namespace, route.Namespace).String()
+				for _, member := range refs {
+					if member == backendRef {
+						continue BACKEND_LOOP
+					}
+				}
+				refs = append(refs, backendRef)
+			}
+		}
+	}
+	return refs
+}
+
+func servicesForTLSRoute(o client.Object) []string {
+	route := o.(*gwv1alpha2.TLSRoute)
+	refs := []string{}
+	for _, rule := range route.Spec.Rules {
+	BACKEND_LOOP:
+		for _, ref := range rule.BackendRefs {
+			if common.NilOrEqual(ref.Group, "") && common.NilOrEqual(ref.Kind, common.KindService) {
+				backendRef := common.IndexedNamespacedNameWithDefault(ref.Name, ref.Namespace, route.Namespace).String()
+				for _, member := range refs {
+					if member == backendRef {
+						continue BACKEND_LOOP
+					}
+				}
+				refs = append(refs, backendRef)
+			}
+		}
+	}
+	return refs
+}
+
+func meshServicesForTLSRoute(o client.Object) []string {
+	route := o.(*gwv1alpha2.TLSRoute)
+	refs := []string{}
+	for _, rule := range route.Spec.Rules {
+	BACKEND_LOOP:
+		for _, ref := range rule.BackendRefs {
+			if common.DerefEqual(ref.Group, v1alpha1.ConsulHashicorpGroup) && common.DerefEqual(ref.Kind, v1alpha1.MeshServiceKind) {
+				backendRef := common.IndexedNamespacedNameWithDefault(ref.Name, ref.Namespace, route.Namespace).String()
+				for _, member := range refs {
+					if member == backendRef {
+						continue BACKEND_LOOP
+					}
+				}
+				refs = append(refs, backendRef)
+			}
+		}
+	}
+	return refs
+}
+
 func gatewaysForRoute(namespace string, refs []gwv1beta1.ParentReference, statusRefs []gwv1beta1.ParentReference) []string {
 	var references []string
 	for _, parent := range refs {
@@ -12,6 +12,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -94,6 +95,9 @@ func (g *Gatekeeper) deployment(gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayC
 	if err != nil {
 		return nil, err
 	}
+	if gcc.Spec.DeploymentSpec.Resources != nil {
+		container.Resources = *gcc.Spec.DeploymentSpec.Resources.DeepCopy()
+	}
 
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -108,10 +112,8 @@ func (g *Gatekeeper) deployment(gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayC
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: common.LabelsForGateway(&gateway),
-					Annotations: map[string]string{
-						"consul.hashicorp.com/connect-inject": "false",
-					},
+					Labels:      podLabels(gateway, gcc),
+					Annotations: podAnnotations(gcc),
 				},
 				Spec: corev1.PodSpec{
 					Volumes: []corev1.Volume{
@@ -128,28 +130,82 @@ func (g *Gatekeeper) deployment(gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayC
 					Containers: []corev1.Container{
 						container,
 					},
-					Affinity: &corev1.Affinity{
-						PodAntiAffinity: &corev1.PodAntiAffinity{
-							PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
-								{
-									Weight: 1,
-									PodAffinityTerm: corev1.PodAffinityTerm{
-										LabelSelector: &metav1.LabelSelector{
-											MatchLabels: common.LabelsForGateway(&gateway),
-										},
-										TopologyKey: "kubernetes.io/hostname",
-									},
-								},
-							},
+					Affinity:                  podAffinity(gateway, gcc),
+					TopologySpreadConstraints: topologySpreadConstraints(gateway, gcc),
+					PriorityClassName:         gcc.Spec.PriorityClassName,
+					NodeSelector:              gcc.Spec.NodeSelector,
+					Tolerations:               gcc.Spec.Tolerations,
+					ServiceAccountName:        g.serviceAccountName(gateway, config),
+				},
+			},
+		},
+	}, nil
+}
+
+// podLabels returns the labels of the gateway pods. The labels the pods are
+// selected with take precedence over the extra labels of the GatewayClassConfig.
+func podLabels(gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig) map[string]string {
+	labels := make(map[string]string)
+	for k, v := range gcc.Spec.PodLabels {
+		labels[k] = v
+	}
+	for k, v := range common.LabelsForGateway(&gateway) {
+		labels[k] = v
+	}
+	return labels
+}
+
+// podAnnotations returns the annotations of the gateway pods. The gateway pods
+// must never be injected, so the extra annotations of the GatewayClassConfig
+// cannot enable injection.
+func podAnnotations(gcc v1alpha1.GatewayClassConfig) map[string]string {
+	annotations := make(map[string]string)
+	for k, v := range gcc.Spec.PodAnnotations {
+		annotations[k] = v
+	}
+	annotations["consul.hashicorp.com/connect-inject"] = "false"
+	return annotations
+}
+
+// podAffinity returns the affinity of the GatewayClassConfig if it is set,
+// otherwise it prefers scheduling the pods of a gateway on different nodes.
+func podAffinity(gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig) *corev1.Affinity {
+	if gcc.Spec.Affinity != nil {
+		return gcc.Spec.Affinity.DeepCopy()
+	}
+
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 1,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: common.LabelsForGateway(&gateway),
 						},
+						TopologyKey: "kubernetes.io/hostname",
 					},
-					NodeSelector:       gcc.Spec.NodeSelector,
-					Tolerations:        gcc.Spec.Tolerations,
-					ServiceAccountName: g.serviceAccountName(gateway, config),
 				},
 			},
 		},
-	}, nil
+	}
+}
+
+// topologySpreadConstraints returns the topology spread constraints of the
+// GatewayClassConfig, selecting the pods of the gateway in any constraint
+// that does not have a label selector.
+func topologySpreadConstraints(gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig) []corev1.TopologySpreadConstraint {
+	var constraints []corev1.TopologySpreadConstraint
+	for _, constraint := range gcc.Spec.TopologySpreadConstraints {
+		constraint := *constraint.DeepCopy()
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = &metav1.LabelSelector{
+				MatchLabels: common.LabelsForGateway(&gateway),
+			}
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints
 }
 
 func mergeDeployments(gcc v1alpha1.GatewayClassConfig, a, b *appsv1.Deployment) *appsv1.Deployment {
@@ -169,6 +225,12 @@ func compareDeployments(a, b *appsv1.Deployment) bool {
 		return false
 	}
 	for i, container := range a.Spec.Template.Spec.Containers {
+		if !resourceListContains(b.Spec.Template.Spec.Containers[i].Resources.Limits, container.Resources.Limits) {
+			return false
+		}
+		if !resourceListContains(b.Spec.Template.Spec.Containers[i].Resources.Requests, container.Resources.Requests) {
+			return false
+		}
 		otherPorts := b.Spec.Template.Spec.Containers[i].Ports
 		if len(container.Ports) != len(otherPorts) {
 			return false
@@ -184,6 +246,24 @@ func compareDeployments(a, b *appsv1.Deployment) bool {
 		}
 	}
 
+	// the scheduling configuration and pod metadata come from the GatewayClassConfig,
+	// labels and annotations may be added to the pods by other tools
+	if !equality.Semantic.DeepEqual(a.Spec.Template.Spec.Affinity, b.Spec.Template.Spec.Affinity) {
+		return false
+	}
+	if !equality.Semantic.DeepEqual(a.Spec.Template.Spec.TopologySpreadConstraints, b.Spec.Template.Spec.TopologySpreadConstraints) {
+		return false
+	}
+	if a.Spec.Template.Spec.PriorityClassName != b.Spec.Template.Spec.PriorityClassName {
+		return false
+	}
+	if !mapContains(b.Spec.Template.Labels, a.Spec.Template.Labels) {
+		return false
+	}
+	if !mapContains(b.Spec.Template.Annotations, a.Spec.Template.Annotations) {
+		return false
+	}
+
 	if b.Spec.Replicas == nil && a.Spec.Replicas == nil {
 		return true
 	} else if b.Spec.Replicas == nil {
@@ -195,6 +275,29 @@ func compareDeployments(a, b *appsv1.Deployment) bool {
 	return *b.Spec.Replicas == *a.Spec.Replicas
 }
 
+// resourceListContains checks that every quantity in expected is set to the same
+// value in actual. Quantities that are only in actual may have been defaulted
+// by a LimitRange.
+func resourceListContains(actual, expected corev1.ResourceList) bool {
+	for name, quantity := range expected {
+		actualQuantity, ok := actual[name]
+		if !ok || actualQuantity.Cmp(quantity) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// mapContains checks that every key in expected is set to the same value in actual.
+func mapContains(actual, expected map[string]string) bool {
+	for k, v := range expected {
+		if actualValue, ok := actual[k]; !ok || actualValue != v {
+			return false
+		}
+	}
+	return true
+}
+
 func newDeploymentMutator(deployment, mutated *appsv1.Deployment, gcc v1alpha1.GatewayClassConfig, gateway gwv1beta1.Gateway, scheme *runtime.Scheme) resourceMutator {
 	return func() error {
 		mutated = mergeDeployments(gcc, deployment, mutated)
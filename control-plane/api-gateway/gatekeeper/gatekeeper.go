@@ -30,10 +30,13 @@ func New(log logr.Logger, client client.Client) *Gatekeeper {
 }
 
 // Upsert creates or updates the resources for handling routing of network traffic.
-// This is done in order based on dependencies between resources.
-func (g *Gatekeeper) Upsert(ctx context.Context, gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig, config common.HelmConfig) error {
+// This is done in order based on dependencies between resources. The values set on
+// the GatewayParameters of the Gateway, if any, override those of the GatewayClassConfig.
+func (g *Gatekeeper) Upsert(ctx context.Context, gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig, params *v1alpha1.GatewayParameters, config common.HelmConfig) error {
 	g.Log.Info(fmt.Sprintf("Upsert Gateway Deployment %s/%s", gateway.Namespace, gateway.Name))
 
+	gcc = withGatewayParameters(gcc, params)
+
 	if err := g.upsertRole(ctx, gateway, gcc, config); err != nil {
 		return err
 	}
@@ -46,7 +49,7 @@ func (g *Gatekeeper) Upsert(ctx context.Context, gateway gwv1beta1.Gateway, gcc
 		return err
 	}
 
-	if err := g.upsertService(ctx, gateway, gcc, config); err != nil {
+	if err := g.upsertService(ctx, gateway, gcc, params, config); err != nil {
 		return err
 	}
 
@@ -54,6 +57,14 @@ func (g *Gatekeeper) Upsert(ctx context.Context, gateway gwv1beta1.Gateway, gcc
 		return err
 	}
 
+	if err := g.upsertPodDisruptionBudget(ctx, gateway, gcc); err != nil {
+		return err
+	}
+
+	if err := g.upsertHorizontalPodAutoscaler(ctx, gateway, gcc); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -62,6 +73,14 @@ func (g *Gatekeeper) Upsert(ctx context.Context, gateway gwv1beta1.Gateway, gcc
 func (g *Gatekeeper) Delete(ctx context.Context, gatewayName types.NamespacedName) error {
 	g.Log.Info(fmt.Sprintf("Delete Gateway Deployment %s/%s", gatewayName.Namespace, gatewayName.Name))
 
+	if err := g.deleteHorizontalPodAutoscaler(ctx, gatewayName); err != nil {
+		return err
+	}
+
+	if err := g.deletePodDisruptionBudget(ctx, gatewayName); err != nil {
+		return err
+	}
+
 	if err := g.deleteDeployment(ctx, gatewayName); err != nil {
 		return err
 	}
@@ -13,12 +13,17 @@ import (
 	"github.com/hashicorp/consul-k8s/control-plane/api/v1alpha1"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
@@ -35,6 +40,16 @@ var (
 		createdAtLabelKey:                        createdAtLabelValue,
 		"gateway.consul.hashicorp.com/managed":   "true",
 	}
+	requestsPerSecondMetric = autoscalingv2.MetricSpec{
+		Type: autoscalingv2.PodsMetricSourceType,
+		Pods: &autoscalingv2.PodsMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{Name: "envoy_http_downstream_rq_total"},
+			Target: autoscalingv2.MetricTarget{
+				Type:         autoscalingv2.AverageValueMetricType,
+				AverageValue: resource.NewQuantity(100, resource.DecimalSI),
+			},
+		},
+	}
 	listeners = []gwv1beta1.Listener{
 		{
 			Name:     "Listener 1",
@@ -52,18 +67,22 @@ var (
 type testCase struct {
 	gateway            gwv1beta1.Gateway
 	gatewayClassConfig v1alpha1.GatewayClassConfig
+	gatewayParameters  *v1alpha1.GatewayParameters
 	helmConfig         common.HelmConfig
 
 	initialResources resources
 	finalResources   resources
+	deletedResources resources
 }
 
 type resources struct {
-	deployments     []*appsv1.Deployment
-	roles           []*rbac.Role
-	roleBindings    []*rbac.RoleBinding
-	services        []*corev1.Service
-	serviceAccounts []*corev1.ServiceAccount
+	deployments              []*appsv1.Deployment
+	horizontalPodAutoscalers []*autoscalingv2.HorizontalPodAutoscaler
+	podDisruptionBudgets     []*policyv1.PodDisruptionBudget
+	roles                    []*rbac.Role
+	roleBindings             []*rbac.RoleBinding
+	services                 []*corev1.Service
+	serviceAccounts          []*corev1.ServiceAccount
 }
 
 func TestUpsert(t *testing.T) {
@@ -596,36 +615,7 @@ func TestUpsert(t *testing.T) {
 				serviceAccounts: []*corev1.ServiceAccount{},
 			},
 		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			s := runtime.NewScheme()
-			require.NoError(t, gwv1beta1.Install(s))
-			require.NoError(t, v1alpha1.AddToScheme(s))
-			require.NoError(t, rbac.AddToScheme(s))
-			require.NoError(t, corev1.AddToScheme(s))
-			require.NoError(t, appsv1.AddToScheme(s))
-
-			log := logrtest.New(t)
-
-			objs := append(joinResources(tc.initialResources), &tc.gateway, &tc.gatewayClassConfig)
-			client := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
-
-			gatekeeper := New(log, client)
-
-			err := gatekeeper.Upsert(context.Background(), tc.gateway, tc.gatewayClassConfig, tc.helmConfig)
-			require.NoError(t, err)
-			require.NoError(t, validateResourcesExist(t, client, tc.finalResources))
-		})
-	}
-}
-
-func TestDelete(t *testing.T) {
-	t.Parallel()
-
-	cases := map[string]testCase{
-		"delete a gateway deployment with only Deployment": {
+		"create a new gateway deployment with a HorizontalPodAutoscaler": {
 			gateway: gwv1beta1.Gateway{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      name,
@@ -642,8 +632,58 @@ func TestDelete(t *testing.T) {
 				Spec: v1alpha1.GatewayClassConfigSpec{
 					DeploymentSpec: v1alpha1.DeploymentSpec{
 						DefaultInstances: common.PointerTo(int32(3)),
-						MaxInstances:     common.PointerTo(int32(3)),
-						MinInstances:     common.PointerTo(int32(1)),
+						MaxInstances:     common.PointerTo(int32(20)),
+						MinInstances:     common.PointerTo(int32(2)),
+						Autoscaling: &v1alpha1.AutoscalingSpec{
+							TargetCPUUtilizationPercentage:    common.PointerTo(int32(70)),
+							TargetMemoryUtilizationPercentage: common.PointerTo(int32(80)),
+							Metrics:                           []autoscalingv2.MetricSpec{requestsPerSecondMetric},
+						},
+					},
+					CopyAnnotations: v1alpha1.CopyAnnotationsSpec{},
+					ServiceType:     (*corev1.ServiceType)(common.PointerTo("NodePort")),
+				},
+			},
+			helmConfig:       common.HelmConfig{},
+			initialResources: resources{},
+			finalResources: resources{
+				deployments: []*appsv1.Deployment{
+					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
+				},
+				horizontalPodAutoscalers: []*autoscalingv2.HorizontalPodAutoscaler{
+					configureHorizontalPodAutoscaler(name, namespace, labels, 2, 20, []autoscalingv2.MetricSpec{
+						resourceUtilizationMetric(corev1.ResourceCPU, 70),
+						resourceUtilizationMetric(corev1.ResourceMemory, 80),
+						requestsPerSecondMetric,
+					}, "1"),
+				},
+				roles:           []*rbac.Role{},
+				services:        []*corev1.Service{},
+				serviceAccounts: []*corev1.ServiceAccount{},
+			},
+		},
+		"update a gateway HorizontalPodAutoscaler when the instance bounds change": {
+			gateway: gwv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: gwv1beta1.GatewaySpec{
+					Listeners: listeners,
+				},
+			},
+			gatewayClassConfig: v1alpha1.GatewayClassConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "consul-gatewayclassconfig",
+				},
+				Spec: v1alpha1.GatewayClassConfigSpec{
+					DeploymentSpec: v1alpha1.DeploymentSpec{
+						DefaultInstances: common.PointerTo(int32(3)),
+						MaxInstances:     common.PointerTo(int32(10)),
+						MinInstances:     common.PointerTo(int32(3)),
+						Autoscaling: &v1alpha1.AutoscalingSpec{
+							TargetCPUUtilizationPercentage: common.PointerTo(int32(50)),
+						},
 					},
 					CopyAnnotations: v1alpha1.CopyAnnotationsSpec{},
 					ServiceType:     (*corev1.ServiceType)(common.PointerTo("NodePort")),
@@ -654,15 +694,27 @@ func TestDelete(t *testing.T) {
 				deployments: []*appsv1.Deployment{
 					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
 				},
+				horizontalPodAutoscalers: []*autoscalingv2.HorizontalPodAutoscaler{
+					configureHorizontalPodAutoscaler(name, namespace, labels, 1, 5, []autoscalingv2.MetricSpec{
+						resourceUtilizationMetric(corev1.ResourceCPU, 50),
+					}, "1"),
+				},
 			},
 			finalResources: resources{
-				deployments:     []*appsv1.Deployment{},
+				deployments: []*appsv1.Deployment{
+					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
+				},
+				horizontalPodAutoscalers: []*autoscalingv2.HorizontalPodAutoscaler{
+					configureHorizontalPodAutoscaler(name, namespace, labels, 3, 10, []autoscalingv2.MetricSpec{
+						resourceUtilizationMetric(corev1.ResourceCPU, 50),
+					}, "2"),
+				},
 				roles:           []*rbac.Role{},
 				services:        []*corev1.Service{},
 				serviceAccounts: []*corev1.ServiceAccount{},
 			},
 		},
-		"delete a gateway deployment with a managed Service": {
+		"update a gateway deployment by disabling autoscaling": {
 			gateway: gwv1beta1.Gateway{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      name,
@@ -679,8 +731,8 @@ func TestDelete(t *testing.T) {
 				Spec: v1alpha1.GatewayClassConfigSpec{
 					DeploymentSpec: v1alpha1.DeploymentSpec{
 						DefaultInstances: common.PointerTo(int32(3)),
-						MaxInstances:     common.PointerTo(int32(3)),
-						MinInstances:     common.PointerTo(int32(1)),
+						MaxInstances:     common.PointerTo(int32(10)),
+						MinInstances:     common.PointerTo(int32(3)),
 					},
 					CopyAnnotations: v1alpha1.CopyAnnotationsSpec{},
 					ServiceType:     (*corev1.ServiceType)(common.PointerTo("NodePort")),
@@ -691,9 +743,78 @@ func TestDelete(t *testing.T) {
 				deployments: []*appsv1.Deployment{
 					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
 				},
+				horizontalPodAutoscalers: []*autoscalingv2.HorizontalPodAutoscaler{
+					configureHorizontalPodAutoscaler(name, namespace, labels, 3, 10, []autoscalingv2.MetricSpec{
+						resourceUtilizationMetric(corev1.ResourceCPU, 50),
+					}, "1"),
+				},
+			},
+			finalResources: resources{
+				deployments: []*appsv1.Deployment{
+					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
+				},
+				roles:           []*rbac.Role{},
+				services:        []*corev1.Service{},
+				serviceAccounts: []*corev1.ServiceAccount{},
+			},
+			deletedResources: resources{
+				horizontalPodAutoscalers: []*autoscalingv2.HorizontalPodAutoscaler{
+					configureHorizontalPodAutoscaler(name, namespace, labels, 3, 10, nil, "1"),
+				},
+			},
+		},
+		"create a new gateway deployment with GatewayParameters": {
+			gateway: gwv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+					Annotations: map[string]string{
+						common.AnnotationGatewayParameters: "params",
+					},
+				},
+				Spec: gwv1beta1.GatewaySpec{
+					Listeners: listeners,
+				},
+			},
+			gatewayClassConfig: v1alpha1.GatewayClassConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "consul-gatewayclassconfig",
+				},
+				Spec: v1alpha1.GatewayClassConfigSpec{
+					DeploymentSpec: v1alpha1.DeploymentSpec{
+						DefaultInstances: common.PointerTo(int32(1)),
+						MaxInstances:     common.PointerTo(int32(3)),
+						MinInstances:     common.PointerTo(int32(1)),
+					},
+					CopyAnnotations: v1alpha1.CopyAnnotationsSpec{},
+					ServiceType:     (*corev1.ServiceType)(common.PointerTo("NodePort")),
+				},
+			},
+			gatewayParameters: &v1alpha1.GatewayParameters{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "params",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.GatewayParametersSpec{
+					ServiceType: (*corev1.ServiceType)(common.PointerTo("LoadBalancer")),
+					ServiceAnnotations: map[string]string{
+						"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+					},
+					DefaultInstances: common.PointerTo(int32(5)),
+					MaxInstances:     common.PointerTo(int32(6)),
+				},
+			},
+			helmConfig:       common.HelmConfig{},
+			initialResources: resources{},
+			finalResources: resources{
+				deployments: []*appsv1.Deployment{
+					configureDeployment(name, namespace, labels, 5, nil, nil, "", "1"),
+				},
 				roles: []*rbac.Role{},
 				services: []*corev1.Service{
-					configureService(name, namespace, labels, nil, (corev1.ServiceType)("NodePort"), []corev1.ServicePort{
+					configureService(name, namespace, labels, map[string]string{
+						"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+					}, (corev1.ServiceType)("LoadBalancer"), []corev1.ServicePort{
 						{
 							Name:     "Listener 1",
 							Protocol: "TCP",
@@ -708,14 +829,8 @@ func TestDelete(t *testing.T) {
 				},
 				serviceAccounts: []*corev1.ServiceAccount{},
 			},
-			finalResources: resources{
-				deployments:     []*appsv1.Deployment{},
-				roles:           []*rbac.Role{},
-				services:        []*corev1.Service{},
-				serviceAccounts: []*corev1.ServiceAccount{},
-			},
 		},
-		"delete a gateway deployment with managed Service and ACLs": {
+		"create a new gateway deployment with requested addresses": {
 			gateway: gwv1beta1.Gateway{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      name,
@@ -723,6 +838,10 @@ func TestDelete(t *testing.T) {
 				},
 				Spec: gwv1beta1.GatewaySpec{
 					Listeners: listeners,
+					Addresses: []gwv1beta1.GatewayAddress{
+						{Type: common.PointerTo(gwv1beta1.IPAddressType), Value: "10.0.0.1"},
+						{Type: common.PointerTo(gwv1beta1.HostnameAddressType), Value: "gateway.example.com"},
+					},
 				},
 			},
 			gatewayClassConfig: v1alpha1.GatewayClassConfig{
@@ -731,29 +850,25 @@ func TestDelete(t *testing.T) {
 				},
 				Spec: v1alpha1.GatewayClassConfigSpec{
 					DeploymentSpec: v1alpha1.DeploymentSpec{
-						DefaultInstances: common.PointerTo(int32(3)),
+						DefaultInstances: common.PointerTo(int32(1)),
 						MaxInstances:     common.PointerTo(int32(3)),
 						MinInstances:     common.PointerTo(int32(1)),
 					},
 					CopyAnnotations: v1alpha1.CopyAnnotationsSpec{},
-					ServiceType:     (*corev1.ServiceType)(common.PointerTo("NodePort")),
+					ServiceType:     (*corev1.ServiceType)(common.PointerTo("LoadBalancer")),
 				},
 			},
-			helmConfig: common.HelmConfig{
-				AuthMethod: "method",
-			},
-			initialResources: resources{
+			helmConfig:       common.HelmConfig{},
+			initialResources: resources{},
+			finalResources: resources{
 				deployments: []*appsv1.Deployment{
-					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
-				},
-				roles: []*rbac.Role{
-					configureRole(name, namespace, labels, "1"),
-				},
-				roleBindings: []*rbac.RoleBinding{
-					configureRoleBinding(name, namespace, labels, "1"),
+					configureDeployment(name, namespace, labels, 1, nil, nil, "", "1"),
 				},
+				roles: []*rbac.Role{},
 				services: []*corev1.Service{
-					configureService(name, namespace, labels, nil, (corev1.ServiceType)("NodePort"), []corev1.ServicePort{
+					withLoadBalancerIP(configureService(name, namespace, labels, map[string]string{
+						common.AnnotationExternalDNSHostname: "gateway.example.com",
+					}, (corev1.ServiceType)("LoadBalancer"), []corev1.ServicePort{
 						{
 							Name:     "Listener 1",
 							Protocol: "TCP",
@@ -764,78 +879,642 @@ func TestDelete(t *testing.T) {
 							Protocol: "TCP",
 							Port:     8081,
 						},
-					}, "1"),
+					}, "1"), "10.0.0.1"),
 				},
-				serviceAccounts: []*corev1.ServiceAccount{
-					configureServiceAccount(name, namespace, labels, "1"),
+				serviceAccounts: []*corev1.ServiceAccount{},
+			},
+		},
+		"create a new gateway deployment with a PodDisruptionBudget": {
+			gateway: gwv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: gwv1beta1.GatewaySpec{
+					Listeners: listeners,
+				},
+			},
+			gatewayClassConfig: v1alpha1.GatewayClassConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "consul-gatewayclassconfig",
+				},
+				Spec: v1alpha1.GatewayClassConfigSpec{
+					DeploymentSpec: v1alpha1.DeploymentSpec{
+						DefaultInstances: common.PointerTo(int32(3)),
+						MaxInstances:     common.PointerTo(int32(3)),
+						MinInstances:     common.PointerTo(int32(1)),
+						PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetSpec{
+							MinAvailable: common.PointerTo(intstr.FromInt(1)),
+						},
+					},
+					CopyAnnotations: v1alpha1.CopyAnnotationsSpec{},
+					ServiceType:     (*corev1.ServiceType)(common.PointerTo("NodePort")),
 				},
 			},
+			helmConfig:       common.HelmConfig{},
+			initialResources: resources{},
 			finalResources: resources{
-				deployments:     []*appsv1.Deployment{},
+				deployments: []*appsv1.Deployment{
+					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
+				},
+				podDisruptionBudgets: []*policyv1.PodDisruptionBudget{
+					configurePodDisruptionBudget(name, namespace, labels, common.PointerTo(intstr.FromInt(1)), nil, "1"),
+				},
 				roles:           []*rbac.Role{},
 				services:        []*corev1.Service{},
 				serviceAccounts: []*corev1.ServiceAccount{},
 			},
 		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			s := runtime.NewScheme()
-			require.NoError(t, gwv1beta1.Install(s))
-			require.NoError(t, v1alpha1.AddToScheme(s))
-			require.NoError(t, rbac.AddToScheme(s))
-			require.NoError(t, corev1.AddToScheme(s))
-			require.NoError(t, appsv1.AddToScheme(s))
-
-			log := logrtest.New(t)
-
-			objs := append(joinResources(tc.initialResources), &tc.gateway, &tc.gatewayClassConfig)
-			client := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
-
-			gatekeeper := New(log, client)
-
-			err := gatekeeper.Delete(context.Background(), types.NamespacedName{
-				Namespace: tc.gateway.Namespace,
-				Name:      tc.gateway.Name,
-			})
-			require.NoError(t, err)
-			require.NoError(t, validateResourcesExist(t, client, tc.finalResources))
-			require.NoError(t, validateResourcesAreDeleted(t, client, tc.initialResources))
-		})
-	}
-}
-
-func joinResources(resources resources) (objs []client.Object) {
-	for _, deployment := range resources.deployments {
-		objs = append(objs, deployment)
-	}
-
-	for _, role := range resources.roles {
-		objs = append(objs, role)
-	}
-
-	for _, roleBinding := range resources.roleBindings {
-		objs = append(objs, roleBinding)
-	}
-
-	for _, service := range resources.services {
-		objs = append(objs, service)
-	}
-
-	for _, serviceAccount := range resources.serviceAccounts {
-		objs = append(objs, serviceAccount)
-	}
-
-	return objs
-}
-
-func validateResourcesExist(t *testing.T, client client.Client, resources resources) error {
-	t.Helper()
-
-	for _, expected := range resources.deployments {
-		actual := &appsv1.Deployment{}
-		err := client.Get(context.Background(), types.NamespacedName{
+		"update a gateway PodDisruptionBudget": {
+			gateway: gwv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: gwv1beta1.GatewaySpec{
+					Listeners: listeners,
+				},
+			},
+			gatewayClassConfig: v1alpha1.GatewayClassConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "consul-gatewayclassconfig",
+				},
+				Spec: v1alpha1.GatewayClassConfigSpec{
+					DeploymentSpec: v1alpha1.DeploymentSpec{
+						DefaultInstances: common.PointerTo(int32(3)),
+						MaxInstances:     common.PointerTo(int32(3)),
+						MinInstances:     common.PointerTo(int32(1)),
+						PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetSpec{
+							MaxUnavailable: common.PointerTo(intstr.FromString("50%")),
+						},
+					},
+					CopyAnnotations: v1alpha1.CopyAnnotationsSpec{},
+					ServiceType:     (*corev1.ServiceType)(common.PointerTo("NodePort")),
+				},
+			},
+			helmConfig: common.HelmConfig{},
+			initialResources: resources{
+				deployments: []*appsv1.Deployment{
+					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
+				},
+				podDisruptionBudgets: []*policyv1.PodDisruptionBudget{
+					configurePodDisruptionBudget(name, namespace, labels, common.PointerTo(intstr.FromInt(1)), nil, "1"),
+				},
+			},
+			finalResources: resources{
+				deployments: []*appsv1.Deployment{
+					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
+				},
+				podDisruptionBudgets: []*policyv1.PodDisruptionBudget{
+					configurePodDisruptionBudget(name, namespace, labels, nil, common.PointerTo(intstr.FromString("50%")), "2"),
+				},
+				roles:           []*rbac.Role{},
+				services:        []*corev1.Service{},
+				serviceAccounts: []*corev1.ServiceAccount{},
+			},
+		},
+		"update a gateway deployment by removing its PodDisruptionBudget": {
+			gateway: gwv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: gwv1beta1.GatewaySpec{
+					Listeners: listeners,
+				},
+			},
+			gatewayClassConfig: v1alpha1.GatewayClassConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "consul-gatewayclassconfig",
+				},
+				Spec: v1alpha1.GatewayClassConfigSpec{
+					DeploymentSpec: v1alpha1.DeploymentSpec{
+						DefaultInstances: common.PointerTo(int32(3)),
+						MaxInstances:     common.PointerTo(int32(3)),
+						MinInstances:     common.PointerTo(int32(1)),
+					},
+					CopyAnnotations: v1alpha1.CopyAnnotationsSpec{},
+					ServiceType:     (*corev1.ServiceType)(common.PointerTo("NodePort")),
+				},
+			},
+			helmConfig: common.HelmConfig{},
+			initialResources: resources{
+				deployments: []*appsv1.Deployment{
+					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
+				},
+				podDisruptionBudgets: []*policyv1.PodDisruptionBudget{
+					configurePodDisruptionBudget(name, namespace, labels, common.PointerTo(intstr.FromInt(1)), nil, "1"),
+				},
+			},
+			finalResources: resources{
+				deployments: []*appsv1.Deployment{
+					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
+				},
+				roles:           []*rbac.Role{},
+				services:        []*corev1.Service{},
+				serviceAccounts: []*corev1.ServiceAccount{},
+			},
+			deletedResources: resources{
+				podDisruptionBudgets: []*policyv1.PodDisruptionBudget{
+					configurePodDisruptionBudget(name, namespace, labels, common.PointerTo(intstr.FromInt(1)), nil, "1"),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := runtime.NewScheme()
+			require.NoError(t, gwv1beta1.Install(s))
+			require.NoError(t, v1alpha1.AddToScheme(s))
+			require.NoError(t, rbac.AddToScheme(s))
+			require.NoError(t, corev1.AddToScheme(s))
+			require.NoError(t, appsv1.AddToScheme(s))
+			require.NoError(t, autoscalingv2.AddToScheme(s))
+			require.NoError(t, policyv1.AddToScheme(s))
+
+			log := logrtest.New(t)
+
+			objs := append(joinResources(tc.initialResources), &tc.gateway, &tc.gatewayClassConfig)
+			client := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+
+			gatekeeper := New(log, client)
+
+			err := gatekeeper.Upsert(context.Background(), tc.gateway, tc.gatewayClassConfig, tc.gatewayParameters, tc.helmConfig)
+			require.NoError(t, err)
+			require.NoError(t, validateResourcesExist(t, client, tc.finalResources))
+			require.NoError(t, validateResourcesAreDeleted(t, client, tc.deletedResources))
+		})
+	}
+}
+
+func TestUpsert_DoesNotDeleteMissingResources(t *testing.T) {
+	t.Parallel()
+
+	s := runtime.NewScheme()
+	require.NoError(t, gwv1beta1.Install(s))
+	require.NoError(t, v1alpha1.AddToScheme(s))
+	require.NoError(t, rbac.AddToScheme(s))
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, appsv1.AddToScheme(s))
+	require.NoError(t, autoscalingv2.AddToScheme(s))
+	require.NoError(t, policyv1.AddToScheme(s))
+
+	gateway := gwv1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: gwv1beta1.GatewaySpec{
+			Listeners: listeners,
+		},
+	}
+	gatewayClassConfig := v1alpha1.GatewayClassConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "consul-gatewayclassconfig",
+		},
+		Spec: v1alpha1.GatewayClassConfigSpec{
+			DeploymentSpec: v1alpha1.DeploymentSpec{
+				DefaultInstances: common.PointerTo(int32(1)),
+			},
+		},
+	}
+
+	client := &deleteRecordingClient{
+		Client: fake.NewClientBuilder().WithScheme(s).WithObjects(&gateway, &gatewayClassConfig).Build(),
+	}
+	gatekeeper := New(logrtest.New(t), client)
+
+	require.NoError(t, gatekeeper.Upsert(context.Background(), gateway, gatewayClassConfig, nil, common.HelmConfig{}))
+	for _, object := range client.deleted {
+		_, isHPA := object.(*autoscalingv2.HorizontalPodAutoscaler)
+		require.False(t, isHPA, "unexpected delete of HorizontalPodAutoscaler")
+		_, isPDB := object.(*policyv1.PodDisruptionBudget)
+		require.False(t, isPDB, "unexpected delete of PodDisruptionBudget")
+	}
+}
+
+// deleteRecordingClient records the objects that are deleted through it.
+type deleteRecordingClient struct {
+	client.Client
+	deleted []client.Object
+}
+
+func (c *deleteRecordingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.deleted = append(c.deleted, obj)
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]testCase{
+		"delete a gateway deployment with only Deployment": {
+			gateway: gwv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: gwv1beta1.GatewaySpec{
+					Listeners: listeners,
+				},
+			},
+			gatewayClassConfig: v1alpha1.GatewayClassConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "consul-gatewayclassconfig",
+				},
+				Spec: v1alpha1.GatewayClassConfigSpec{
+					DeploymentSpec: v1alpha1.DeploymentSpec{
+						DefaultInstances: common.PointerTo(int32(3)),
+						MaxInstances:     common.PointerTo(int32(3)),
+						MinInstances:     common.PointerTo(int32(1)),
+					},
+					CopyAnnotations: v1alpha1.CopyAnnotationsSpec{},
+					ServiceType:     (*corev1.ServiceType)(common.PointerTo("NodePort")),
+				},
+			},
+			helmConfig: common.HelmConfig{},
+			initialResources: resources{
+				deployments: []*appsv1.Deployment{
+					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
+				},
+			},
+			finalResources: resources{
+				deployments:     []*appsv1.Deployment{},
+				roles:           []*rbac.Role{},
+				services:        []*corev1.Service{},
+				serviceAccounts: []*corev1.ServiceAccount{},
+			},
+		},
+		"delete a gateway deployment with a managed Service": {
+			gateway: gwv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: gwv1beta1.GatewaySpec{
+					Listeners: listeners,
+				},
+			},
+			gatewayClassConfig: v1alpha1.GatewayClassConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "consul-gatewayclassconfig",
+				},
+				Spec: v1alpha1.GatewayClassConfigSpec{
+					DeploymentSpec: v1alpha1.DeploymentSpec{
+						DefaultInstances: common.PointerTo(int32(3)),
+						MaxInstances:     common.PointerTo(int32(3)),
+						MinInstances:     common.PointerTo(int32(1)),
+					},
+					CopyAnnotations: v1alpha1.CopyAnnotationsSpec{},
+					ServiceType:     (*corev1.ServiceType)(common.PointerTo("NodePort")),
+				},
+			},
+			helmConfig: common.HelmConfig{},
+			initialResources: resources{
+				deployments: []*appsv1.Deployment{
+					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
+				},
+				roles: []*rbac.Role{},
+				services: []*corev1.Service{
+					configureService(name, namespace, labels, nil, (corev1.ServiceType)("NodePort"), []corev1.ServicePort{
+						{
+							Name:     "Listener 1",
+							Protocol: "TCP",
+							Port:     8080,
+						},
+						{
+							Name:     "Listener 2",
+							Protocol: "TCP",
+							Port:     8081,
+						},
+					}, "1"),
+				},
+				serviceAccounts: []*corev1.ServiceAccount{},
+			},
+			finalResources: resources{
+				deployments:     []*appsv1.Deployment{},
+				roles:           []*rbac.Role{},
+				services:        []*corev1.Service{},
+				serviceAccounts: []*corev1.ServiceAccount{},
+			},
+		},
+		"delete a gateway deployment with managed Service and ACLs": {
+			gateway: gwv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: gwv1beta1.GatewaySpec{
+					Listeners: listeners,
+				},
+			},
+			gatewayClassConfig: v1alpha1.GatewayClassConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "consul-gatewayclassconfig",
+				},
+				Spec: v1alpha1.GatewayClassConfigSpec{
+					DeploymentSpec: v1alpha1.DeploymentSpec{
+						DefaultInstances: common.PointerTo(int32(3)),
+						MaxInstances:     common.PointerTo(int32(3)),
+						MinInstances:     common.PointerTo(int32(1)),
+					},
+					CopyAnnotations: v1alpha1.CopyAnnotationsSpec{},
+					ServiceType:     (*corev1.ServiceType)(common.PointerTo("NodePort")),
+				},
+			},
+			helmConfig: common.HelmConfig{
+				AuthMethod: "method",
+			},
+			initialResources: resources{
+				deployments: []*appsv1.Deployment{
+					configureDeployment(name, namespace, labels, 3, nil, nil, "", "1"),
+				},
+				roles: []*rbac.Role{
+					configureRole(name, namespace, labels, "1"),
+				},
+				roleBindings: []*rbac.RoleBinding{
+					configureRoleBinding(name, namespace, labels, "1"),
+				},
+				services: []*corev1.Service{
+					configureService(name, namespace, labels, nil, (corev1.ServiceType)("NodePort"), []corev1.ServicePort{
+						{
+							Name:     "Listener 1",
+							Protocol: "TCP",
+							Port:     8080,
+						},
+						{
+							Name:     "Listener 2",
+							Protocol: "TCP",
+							Port:     8081,
+						},
+					}, "1"),
+				},
+				serviceAccounts: []*corev1.ServiceAccount{
+					configureServiceAccount(name, namespace, labels, "1"),
+				},
+				horizontalPodAutoscalers: []*autoscalingv2.HorizontalPodAutoscaler{
+					configureHorizontalPodAutoscaler(name, namespace, labels, 1, 3, []autoscalingv2.MetricSpec{
+						resourceUtilizationMetric(corev1.ResourceCPU, 50),
+					}, "1"),
+				},
+				podDisruptionBudgets: []*policyv1.PodDisruptionBudget{
+					configurePodDisruptionBudget(name, namespace, labels, common.PointerTo(intstr.FromInt(1)), nil, "1"),
+				},
+			},
+			finalResources: resources{
+				deployments:     []*appsv1.Deployment{},
+				roles:           []*rbac.Role{},
+				services:        []*corev1.Service{},
+				serviceAccounts: []*corev1.ServiceAccount{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := runtime.NewScheme()
+			require.NoError(t, gwv1beta1.Install(s))
+			require.NoError(t, v1alpha1.AddToScheme(s))
+			require.NoError(t, rbac.AddToScheme(s))
+			require.NoError(t, corev1.AddToScheme(s))
+			require.NoError(t, appsv1.AddToScheme(s))
+			require.NoError(t, autoscalingv2.AddToScheme(s))
+			require.NoError(t, policyv1.AddToScheme(s))
+
+			log := logrtest.New(t)
+
+			objs := append(joinResources(tc.initialResources), &tc.gateway, &tc.gatewayClassConfig)
+			client := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+
+			gatekeeper := New(log, client)
+
+			err := gatekeeper.Delete(context.Background(), types.NamespacedName{
+				Namespace: tc.gateway.Namespace,
+				Name:      tc.gateway.Name,
+			})
+			require.NoError(t, err)
+			require.NoError(t, validateResourcesExist(t, client, tc.finalResources))
+			require.NoError(t, validateResourcesAreDeleted(t, client, tc.initialResources))
+		})
+	}
+}
+
+func TestDeployment_PodConfiguration(t *testing.T) {
+	t.Parallel()
+
+	gateway := gwv1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: gwv1beta1.GatewaySpec{
+			Listeners: listeners,
+		},
+	}
+	gatewayLabels := common.LabelsForGateway(&gateway)
+	dataplaneResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+		},
+	}
+	affinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "node-role", Operator: corev1.NodeSelectorOpIn, Values: []string{"gateway"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	gcc := v1alpha1.GatewayClassConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "consul-gatewayclassconfig",
+		},
+		Spec: v1alpha1.GatewayClassConfigSpec{
+			DeploymentSpec: v1alpha1.DeploymentSpec{
+				Resources: &dataplaneResources,
+			},
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+				{
+					MaxSkew:           1,
+					TopologyKey:       "topology.kubernetes.io/zone",
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+				},
+				{
+					MaxSkew:           2,
+					TopologyKey:       "kubernetes.io/hostname",
+					WhenUnsatisfiable: corev1.ScheduleAnyway,
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"team": "edge"},
+					},
+				},
+			},
+			Affinity:          affinity,
+			PriorityClassName: "gateway-critical",
+			PodLabels: map[string]string{
+				"team":                              "edge",
+				"gateway.consul.hashicorp.com/name": "not-the-gateway",
+			},
+			PodAnnotations: map[string]string{
+				"prometheus.io/scrape":                "true",
+				"consul.hashicorp.com/connect-inject": "true",
+			},
+		},
+	}
+
+	gatekeeper := New(logrtest.New(t), nil)
+	deployment, err := gatekeeper.deployment(gateway, gcc, common.HelmConfig{}, nil)
+	require.NoError(t, err)
+
+	podSpec := deployment.Spec.Template.Spec
+	require.Equal(t, dataplaneResources, podSpec.Containers[0].Resources)
+	require.Equal(t, affinity, podSpec.Affinity)
+	require.Equal(t, "gateway-critical", podSpec.PriorityClassName)
+	require.Equal(t, []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: gatewayLabels,
+			},
+		},
+		{
+			MaxSkew:           2,
+			TopologyKey:       "kubernetes.io/hostname",
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "edge"},
+			},
+		},
+	}, podSpec.TopologySpreadConstraints)
+
+	// The labels the pods are selected with and the injection annotation cannot be overridden.
+	require.Equal(t, "edge", deployment.Spec.Template.Labels["team"])
+	for k, v := range gatewayLabels {
+		require.Equal(t, v, deployment.Spec.Template.Labels[k])
+	}
+	require.Equal(t, map[string]string{
+		"prometheus.io/scrape":                "true",
+		"consul.hashicorp.com/connect-inject": "false",
+	}, deployment.Spec.Template.Annotations)
+
+	// Without an affinity on the GatewayClassConfig, the pods of a gateway prefer different nodes.
+	gcc.Spec.Affinity = nil
+	deployment, err = gatekeeper.deployment(gateway, gcc, common.HelmConfig{}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, deployment.Spec.Template.Spec.Affinity.PodAntiAffinity)
+}
+
+func TestCompareDeployments(t *testing.T) {
+	t.Parallel()
+
+	withResources := func(resources corev1.ResourceRequirements) *appsv1.Deployment {
+		deployment := configureDeployment(name, namespace, labels, 1, nil, nil, "", "1")
+		deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: name, Resources: resources}}
+		return deployment
+	}
+
+	cases := map[string]struct {
+		desired  *appsv1.Deployment
+		existing *appsv1.Deployment
+		equal    bool
+	}{
+		"equal resources": {
+			desired:  withResources(corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}}),
+			existing: withResources(corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0.1")}}),
+			equal:    true,
+		},
+		"resources defaulted by a LimitRange": {
+			desired: withResources(corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}}),
+			existing: withResources(corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}),
+			equal: true,
+		},
+		"changed resources": {
+			desired:  withResources(corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")}}),
+			existing: withResources(corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}}),
+			equal:    false,
+		},
+		"pod annotation added by another tool": {
+			desired: configureDeployment(name, namespace, labels, 1, nil, nil, "", "1"),
+			existing: func() *appsv1.Deployment {
+				deployment := configureDeployment(name, namespace, labels, 1, nil, nil, "", "1")
+				deployment.Spec.Template.Annotations = map[string]string{
+					"consul.hashicorp.com/connect-inject": "false",
+					"kubectl.kubernetes.io/restartedAt":   "2023-01-01T00:00:00Z",
+				}
+				return deployment
+			}(),
+			equal: true,
+		},
+		"changed priority class": {
+			desired: func() *appsv1.Deployment {
+				deployment := configureDeployment(name, namespace, labels, 1, nil, nil, "", "1")
+				deployment.Spec.Template.Spec.PriorityClassName = "gateway-critical"
+				return deployment
+			}(),
+			existing: configureDeployment(name, namespace, labels, 1, nil, nil, "", "1"),
+			equal:    false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.equal, compareDeployments(tc.desired, tc.existing))
+		})
+	}
+}
+
+func joinResources(resources resources) (objs []client.Object) {
+	for _, deployment := range resources.deployments {
+		objs = append(objs, deployment)
+	}
+
+	for _, hpa := range resources.horizontalPodAutoscalers {
+		objs = append(objs, hpa)
+	}
+
+	for _, pdb := range resources.podDisruptionBudgets {
+		objs = append(objs, pdb)
+	}
+
+	for _, role := range resources.roles {
+		objs = append(objs, role)
+	}
+
+	for _, roleBinding := range resources.roleBindings {
+		objs = append(objs, roleBinding)
+	}
+
+	for _, service := range resources.services {
+		objs = append(objs, service)
+	}
+
+	for _, serviceAccount := range resources.serviceAccounts {
+		objs = append(objs, serviceAccount)
+	}
+
+	return objs
+}
+
+func validateResourcesExist(t *testing.T, client client.Client, resources resources) error {
+	t.Helper()
+
+	for _, expected := range resources.deployments {
+		actual := &appsv1.Deployment{}
+		err := client.Get(context.Background(), types.NamespacedName{
 			Name:      expected.Name,
 			Namespace: expected.Namespace,
 		}, actual)
@@ -858,6 +1537,42 @@ func validateResourcesExist(t *testing.T, client client.Client, resources resour
 		}
 	}
 
+	for _, expected := range resources.horizontalPodAutoscalers {
+		actual := &autoscalingv2.HorizontalPodAutoscaler{}
+		err := client.Get(context.Background(), types.NamespacedName{
+			Name:      expected.Name,
+			Namespace: expected.Namespace,
+		}, actual)
+		if err != nil {
+			return err
+		}
+
+		// Patch the createdAt label
+		actual.Labels[createdAtLabelKey] = createdAtLabelValue
+
+		// Metric targets hold resource.Quantity values, which have to be compared semantically.
+		require.Equal(t, expected.TypeMeta, actual.TypeMeta)
+		require.Equal(t, expected.ObjectMeta, actual.ObjectMeta)
+		require.True(t, equality.Semantic.DeepEqual(expected.Spec, actual.Spec), "expected %+v, got %+v", expected.Spec, actual.Spec)
+	}
+
+	for _, expected := range resources.podDisruptionBudgets {
+		actual := &policyv1.PodDisruptionBudget{}
+		err := client.Get(context.Background(), types.NamespacedName{
+			Name:      expected.Name,
+			Namespace: expected.Namespace,
+		}, actual)
+		if err != nil {
+			return err
+		}
+
+		// Patch the createdAt label
+		actual.Labels[createdAtLabelKey] = createdAtLabelValue
+		actual.Spec.Selector.MatchLabels[createdAtLabelKey] = createdAtLabelValue
+
+		require.Equal(t, expected, actual)
+	}
+
 	for _, expected := range resources.roles {
 		actual := &rbac.Role{}
 		err := client.Get(context.Background(), types.NamespacedName{
@@ -941,6 +1656,30 @@ func validateResourcesAreDeleted(t *testing.T, k8sClient client.Client, resource
 		require.Error(t, err)
 	}
 
+	for _, expected := range resources.horizontalPodAutoscalers {
+		actual := &autoscalingv2.HorizontalPodAutoscaler{}
+		err := k8sClient.Get(context.Background(), types.NamespacedName{
+			Name:      expected.Name,
+			Namespace: expected.Namespace,
+		}, actual)
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("expected horizontal pod autoscaler %s to be deleted", expected.Name)
+		}
+		require.Error(t, err)
+	}
+
+	for _, expected := range resources.podDisruptionBudgets {
+		actual := &policyv1.PodDisruptionBudget{}
+		err := k8sClient.Get(context.Background(), types.NamespacedName{
+			Name:      expected.Name,
+			Namespace: expected.Namespace,
+		}, actual)
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("expected pod disruption budget %s to be deleted", expected.Name)
+		}
+		require.Error(t, err)
+	}
+
 	for _, expected := range resources.roles {
 		actual := &rbac.Role{}
 		err := k8sClient.Get(context.Background(), types.NamespacedName{
@@ -1111,6 +1850,84 @@ func configureRoleBinding(name, namespace string, labels map[string]string, reso
 	}
 }
 
+func configureHorizontalPodAutoscaler(name, namespace string, labels map[string]string, minReplicas, maxReplicas int32, metrics []autoscalingv2.MetricSpec, resourceVersion string) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "autoscaling/v2",
+			Kind:       "HorizontalPodAutoscaler",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			Labels:          labels,
+			ResourceVersion: resourceVersion,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         "gateway.networking.k8s.io/v1beta1",
+					Kind:               "Gateway",
+					Name:               name,
+					Controller:         common.PointerTo(true),
+					BlockOwnerDeletion: common.PointerTo(true),
+				},
+			},
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       name,
+			},
+			MinReplicas: common.PointerTo(minReplicas),
+			MaxReplicas: maxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
+
+func configurePodDisruptionBudget(name, namespace string, labels map[string]string, minAvailable, maxUnavailable *intstr.IntOrString, resourceVersion string) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "policy/v1",
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			Labels:          labels,
+			ResourceVersion: resourceVersion,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         "gateway.networking.k8s.io/v1beta1",
+					Kind:               "Gateway",
+					Name:               name,
+					Controller:         common.PointerTo(true),
+					BlockOwnerDeletion: common.PointerTo(true),
+				},
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			MinAvailable:   minAvailable,
+			MaxUnavailable: maxUnavailable,
+		},
+	}
+}
+
+func resourceUtilizationMetric(name corev1.ResourceName, utilization int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: common.PointerTo(utilization),
+			},
+		},
+	}
+}
+
 func configureService(name, namespace string, labels, annotations map[string]string, serviceType corev1.ServiceType, ports []corev1.ServicePort, resourceVersion string) *corev1.Service {
 	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -1141,6 +1958,11 @@ func configureService(name, namespace string, labels, annotations map[string]str
 	}
 }
 
+func withLoadBalancerIP(service *corev1.Service, ip string) *corev1.Service {
+	service.Spec.LoadBalancerIP = ip
+	return service
+}
+
 func configureServiceAccount(name, namespace string, labels map[string]string, resourceVersion string) *corev1.ServiceAccount {
 	return &corev1.ServiceAccount{
 		TypeMeta: metav1.TypeMeta{
@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package gatekeeper
+
+import (
+	"context"
+
+	"github.com/hashicorp/consul-k8s/control-plane/api-gateway/common"
+	"github.com/hashicorp/consul-k8s/control-plane/api/v1alpha1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func (g *Gatekeeper) upsertHorizontalPodAutoscaler(ctx context.Context, gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig) error {
+	if gcc.Spec.DeploymentSpec.Autoscaling == nil {
+		return g.deleteHorizontalPodAutoscaler(ctx, g.namespacedName(gateway))
+	}
+
+	hpa := g.horizontalPodAutoscaler(gateway, gcc)
+
+	mutated := hpa.DeepCopy()
+	mutator := newHorizontalPodAutoscalerMutator(hpa, mutated, gateway, g.Client.Scheme())
+
+	result, err := controllerutil.CreateOrUpdate(ctx, g.Client, mutated, mutator)
+	if err != nil {
+		return err
+	}
+
+	switch result {
+	case controllerutil.OperationResultCreated:
+		g.Log.Info("Created HorizontalPodAutoscaler")
+	case controllerutil.OperationResultUpdated:
+		g.Log.Info("Updated HorizontalPodAutoscaler")
+	case controllerutil.OperationResultNone:
+		g.Log.Info("No change to HorizontalPodAutoscaler")
+	}
+
+	return nil
+}
+
+func (g *Gatekeeper) deleteHorizontalPodAutoscaler(ctx context.Context, gwName types.NamespacedName) error {
+	// Most gateways don't autoscale, so look the HorizontalPodAutoscaler up in the
+	// cache first rather than sending a delete to the API server on every reconcile.
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	if err := g.Client.Get(ctx, gwName, hpa); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := g.Client.Delete(ctx, hpa); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (g *Gatekeeper) horizontalPodAutoscaler(gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig) *autoscalingv2.HorizontalPodAutoscaler {
+	// The autoscaler is bounded the same way deploymentReplicas bounds the
+	// Deployment, so that the two never fight over the number of replicas.
+	minReplicas := defaultInstances
+	if gcc.Spec.DeploymentSpec.MinInstances != nil {
+		minReplicas = *gcc.Spec.DeploymentSpec.MinInstances
+	}
+	maxReplicas := minReplicas
+	if gcc.Spec.DeploymentSpec.MaxInstances != nil && *gcc.Spec.DeploymentSpec.MaxInstances > minReplicas {
+		maxReplicas = *gcc.Spec.DeploymentSpec.MaxInstances
+	}
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gateway.Name,
+			Namespace: gateway.Namespace,
+			Labels:    common.LabelsForGateway(&gateway),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       gateway.Name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+			Metrics:     autoscalingMetrics(*gcc.Spec.DeploymentSpec.Autoscaling),
+		},
+	}
+}
+
+// autoscalingMetrics returns the resource metrics for the CPU and memory targets
+// followed by any additional metrics of the autoscaling configuration.
+func autoscalingMetrics(autoscaling v1alpha1.AutoscalingSpec) []autoscalingv2.MetricSpec {
+	var metrics []autoscalingv2.MetricSpec
+
+	resourceMetric := func(name corev1.ResourceName, utilization int32) autoscalingv2.MetricSpec {
+		return autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: name,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: common.PointerTo(utilization),
+				},
+			},
+		}
+	}
+
+	if autoscaling.TargetCPUUtilizationPercentage != nil {
+		metrics = append(metrics, resourceMetric(corev1.ResourceCPU, *autoscaling.TargetCPUUtilizationPercentage))
+	}
+	if autoscaling.TargetMemoryUtilizationPercentage != nil {
+		metrics = append(metrics, resourceMetric(corev1.ResourceMemory, *autoscaling.TargetMemoryUtilizationPercentage))
+	}
+
+	for _, metric := range autoscaling.Metrics {
+		metrics = append(metrics, *metric.DeepCopy())
+	}
+
+	return metrics
+}
+
+// mergeHorizontalPodAutoscalers is used to keep the spec from the `from`
+// HorizontalPodAutoscaler on the `to` HorizontalPodAutoscaler, leaving the
+// status and defaults that Kubernetes sets untouched.
+func mergeHorizontalPodAutoscalers(from, to *autoscalingv2.HorizontalPodAutoscaler) *autoscalingv2.HorizontalPodAutoscaler {
+	if compareHorizontalPodAutoscalers(from, to) {
+		return to
+	}
+
+	to.Spec.ScaleTargetRef = from.Spec.ScaleTargetRef
+	to.Spec.MinReplicas = from.Spec.MinReplicas
+	to.Spec.MaxReplicas = from.Spec.MaxReplicas
+	to.Spec.Metrics = from.Spec.Metrics
+
+	return to
+}
+
+func compareHorizontalPodAutoscalers(a, b *autoscalingv2.HorizontalPodAutoscaler) bool {
+	if a.Spec.ScaleTargetRef != b.Spec.ScaleTargetRef {
+		return false
+	}
+	if !equality.Semantic.DeepEqual(a.Spec.MinReplicas, b.Spec.MinReplicas) {
+		return false
+	}
+	if a.Spec.MaxReplicas != b.Spec.MaxReplicas {
+		return false
+	}
+
+	return equality.Semantic.DeepEqual(a.Spec.Metrics, b.Spec.Metrics)
+}
+
+func newHorizontalPodAutoscalerMutator(hpa, mutated *autoscalingv2.HorizontalPodAutoscaler, gateway gwv1beta1.Gateway, scheme *runtime.Scheme) resourceMutator {
+	return func() error {
+		mutated = mergeHorizontalPodAutoscalers(hpa, mutated)
+		return ctrl.SetControllerReference(&gateway, mutated, scheme)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package gatekeeper
+
+import (
+	"github.com/hashicorp/consul-k8s/control-plane/api/v1alpha1"
+)
+
+// withGatewayParameters returns a copy of the GatewayClassConfig with the values
+// that are set on the GatewayParameters layered over it. The Service annotations
+// of the GatewayParameters have no counterpart on the GatewayClassConfig and are
+// applied when building the Service.
+func withGatewayParameters(gcc v1alpha1.GatewayClassConfig, params *v1alpha1.GatewayParameters) v1alpha1.GatewayClassConfig {
+	if params == nil {
+		return gcc
+	}
+
+	layered := gcc.DeepCopy()
+	if params.Spec.ServiceType != nil {
+		layered.Spec.ServiceType = params.Spec.ServiceType
+	}
+	if params.Spec.DefaultInstances != nil {
+		layered.Spec.DeploymentSpec.DefaultInstances = params.Spec.DefaultInstances
+	}
+	if params.Spec.MaxInstances != nil {
+		layered.Spec.DeploymentSpec.MaxInstances = params.Spec.MaxInstances
+	}
+	if params.Spec.MinInstances != nil {
+		layered.Spec.DeploymentSpec.MinInstances = params.Spec.MinInstances
+	}
+
+	return *layered
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package gatekeeper
+
+import (
+	"context"
+
+	"github.com/hashicorp/consul-k8s/control-plane/api-gateway/common"
+	"github.com/hashicorp/consul-k8s/control-plane/api/v1alpha1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func (g *Gatekeeper) upsertPodDisruptionBudget(ctx context.Context, gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig) error {
+	if gcc.Spec.DeploymentSpec.PodDisruptionBudget == nil {
+		return g.deletePodDisruptionBudget(ctx, g.namespacedName(gateway))
+	}
+
+	pdb := g.podDisruptionBudget(gateway, gcc)
+
+	mutated := pdb.DeepCopy()
+	mutator := newPodDisruptionBudgetMutator(pdb, mutated, gateway, g.Client.Scheme())
+
+	result, err := controllerutil.CreateOrUpdate(ctx, g.Client, mutated, mutator)
+	if err != nil {
+		return err
+	}
+
+	switch result {
+	case controllerutil.OperationResultCreated:
+		g.Log.Info("Created PodDisruptionBudget")
+	case controllerutil.OperationResultUpdated:
+		g.Log.Info("Updated PodDisruptionBudget")
+	case controllerutil.OperationResultNone:
+		g.Log.Info("No change to PodDisruptionBudget")
+	}
+
+	return nil
+}
+
+func (g *Gatekeeper) deletePodDisruptionBudget(ctx context.Context, gwName types.NamespacedName) error {
+	// Most gateways don't have a PodDisruptionBudget, so look it up in the cache
+	// first rather than sending a delete to the API server on every reconcile.
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := g.Client.Get(ctx, gwName, pdb); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := g.Client.Delete(ctx, pdb); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (g *Gatekeeper) podDisruptionBudget(gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig) *policyv1.PodDisruptionBudget {
+	spec := gcc.Spec.DeploymentSpec.PodDisruptionBudget.DeepCopy()
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gateway.Name,
+			Namespace: gateway.Namespace,
+			Labels:    common.LabelsForGateway(&gateway),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: common.LabelsForGateway(&gateway),
+			},
+			MinAvailable:   spec.MinAvailable,
+			MaxUnavailable: spec.MaxUnavailable,
+		},
+	}
+}
+
+// mergePodDisruptionBudgets is used to keep the spec from the `from`
+// PodDisruptionBudget on the `to` PodDisruptionBudget.
+func mergePodDisruptionBudgets(from, to *policyv1.PodDisruptionBudget) *policyv1.PodDisruptionBudget {
+	if comparePodDisruptionBudgets(from, to) {
+		return to
+	}
+
+	to.Spec.Selector = from.Spec.Selector
+	to.Spec.MinAvailable = from.Spec.MinAvailable
+	to.Spec.MaxUnavailable = from.Spec.MaxUnavailable
+
+	return to
+}
+
+func comparePodDisruptionBudgets(a, b *policyv1.PodDisruptionBudget) bool {
+	if !equality.Semantic.DeepEqual(a.Spec.Selector, b.Spec.Selector) {
+		return false
+	}
+	if !equality.Semantic.DeepEqual(a.Spec.MinAvailable, b.Spec.MinAvailable) {
+		return false
+	}
+
+	return equality.Semantic.DeepEqual(a.Spec.MaxUnavailable, b.Spec.MaxUnavailable)
+}
+
+func newPodDisruptionBudgetMutator(pdb, mutated *policyv1.PodDisruptionBudget, gateway gwv1beta1.Gateway, scheme *runtime.Scheme) resourceMutator {
+	return func() error {
+		mutated = mergePodDisruptionBudgets(pdb, mutated)
+		return ctrl.SetControllerReference(&gateway, mutated, scheme)
+	}
+}
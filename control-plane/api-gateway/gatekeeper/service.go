@@ -5,6 +5,7 @@ package gatekeeper
 
 import (
 	"context"
+	"strings"
 
 	"github.com/hashicorp/consul-k8s/control-plane/api-gateway/common"
 	"github.com/hashicorp/consul-k8s/control-plane/api/v1alpha1"
@@ -22,16 +23,16 @@ import (
 
 var (
 	defaultServiceAnnotations = []string{
-		"external-dns.alpha.kubernetes.io/hostname",
+		common.AnnotationExternalDNSHostname,
 	}
 )
 
-func (g *Gatekeeper) upsertService(ctx context.Context, gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig, config common.HelmConfig) error {
+func (g *Gatekeeper) upsertService(ctx context.Context, gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig, params *v1alpha1.GatewayParameters, config common.HelmConfig) error {
 	if gcc.Spec.ServiceType == nil {
 		return g.deleteService(ctx, types.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Name})
 	}
 
-	service := g.service(gateway, gcc)
+	service := g.service(gateway, gcc, params)
 
 	mutated := service.DeepCopy()
 	mutator := newServiceMutator(service, mutated, gateway, g.Client.Scheme())
@@ -64,7 +65,7 @@ func (g *Gatekeeper) deleteService(ctx context.Context, gwName types.NamespacedN
 	return nil
 }
 
-func (g *Gatekeeper) service(gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig) *corev1.Service {
+func (g *Gatekeeper) service(gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClassConfig, params *v1alpha1.GatewayParameters) *corev1.Service {
 	ports := []corev1.ServicePort{}
 	for _, listener := range gateway.Spec.Listeners {
 		ports = append(ports, corev1.ServicePort{
@@ -86,8 +87,21 @@ func (g *Gatekeeper) service(gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClas
 			annotations[allowedAnnotation] = value
 		}
 	}
+	if params != nil {
+		for k, v := range params.Spec.ServiceAnnotations {
+			annotations[k] = v
+		}
+	}
+
+	// Pass the addresses requested on the Gateway through to the Service. Hostnames
+	// are handed to external-dns, IP addresses are assigned by the load balancer
+	// provider or routed to the Service as external IPs.
+	ips, hostnames, _ := common.GatewayRequestedAddresses(gateway)
+	if len(hostnames) > 0 {
+		annotations[common.AnnotationExternalDNSHostname] = strings.Join(hostnames, ",")
+	}
 
-	return &corev1.Service{
+	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        gateway.Name,
 			Namespace:   gateway.Namespace,
@@ -100,10 +114,26 @@ func (g *Gatekeeper) service(gateway gwv1beta1.Gateway, gcc v1alpha1.GatewayClas
 			Ports:    ports,
 		},
 	}
+
+	// spec.loadBalancerIP is deprecated and not every provider honours it. Providers
+	// that read the requested IP from an annotation instead, such as
+	// metallb.universe.tf/loadBalancerIPs or
+	// service.beta.kubernetes.io/azure-load-balancer-ipv4, are configured through
+	// the ServiceAnnotations of the GatewayParameters. Either way the address is only
+	// reported as assigned once it shows up in the status of the load balancer.
+	if len(ips) > 0 {
+		if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			service.Spec.LoadBalancerIP = ips[0]
+		} else {
+			service.Spec.ExternalIPs = ips
+		}
+	}
+
+	return service
 }
 
-// mergeService is used to keep annotations and ports from the `from` Service
-// to the `to` service. This prevents an infinite reconciliation loop when
+// mergeService is used to keep annotations, ports and requested addresses from
+// the `from` Service to the `to` service. This prevents an infinite reconciliation loop when
 // Kubernetes adds this configuration back in.
 func mergeService(from, to *corev1.Service) *corev1.Service {
 	if areServicesEqual(from, to) {
@@ -112,6 +142,8 @@ func mergeService(from, to *corev1.Service) *corev1.Service {
 
 	to.Annotations = from.Annotations
 	to.Spec.Ports = from.Spec.Ports
+	to.Spec.LoadBalancerIP = from.Spec.LoadBalancerIP
+	to.Spec.ExternalIPs = from.Spec.ExternalIPs
 
 	return to
 }
@@ -120,6 +152,12 @@ func areServicesEqual(a, b *corev1.Service) bool {
 	if !equality.Semantic.DeepEqual(a.Annotations, b.Annotations) {
 		return false
 	}
+	if a.Spec.LoadBalancerIP != b.Spec.LoadBalancerIP {
+		return false
+	}
+	if !equality.Semantic.DeepEqual(a.Spec.ExternalIPs, b.Spec.ExternalIPs) {
+		return false
+	}
 	if len(b.Spec.Ports) != len(a.Spec.Ports) {
 		return false
 	}
@@ -4,17 +4,21 @@
 package v1alpha1
 
 import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
 	GatewayClassConfigKind = "GatewayClassConfig"
+	GatewayParametersKind  = "GatewayParameters"
 	MeshServiceKind        = "MeshService"
 )
 
 func init() {
 	SchemeBuilder.Register(&GatewayClassConfig{}, &GatewayClassConfigList{})
+	SchemeBuilder.Register(&GatewayParameters{}, &GatewayParametersList{})
 	SchemeBuilder.Register(&MeshService{}, &MeshServiceList{})
 }
 
@@ -51,6 +55,31 @@ type GatewayClassConfigSpec struct {
 	// More Info: https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration/
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// TopologySpreadConstraints control how gateway pods are spread across the topology domains of the cluster.
+	// Constraints without a labelSelector select the pods of the same gateway.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// Affinity replaces the default pod anti-affinity of the gateway pods, which prefers
+	// scheduling the pods of a gateway on different nodes.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#affinity-and-anti-affinity
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass of the gateway pods.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// PodLabels are additional labels to set on the gateway pods. They cannot
+	// override the labels the gateway pods are selected with.
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+
+	// PodAnnotations are additional annotations to set on the gateway pods.
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+
 	// Deployment defines the deployment configuration for the gateway.
 	DeploymentSpec DeploymentSpec `json:"deployment,omitempty"`
 
@@ -65,20 +94,55 @@ type GatewayClassConfigSpec struct {
 
 type DeploymentSpec struct {
 	// +kubebuilder:default:=1
-	// +kubebuilder:validation:Maximum=8
+	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:validation:Minimum=1
 	// Number of gateway instances that should be deployed by default
 	DefaultInstances *int32 `json:"defaultInstances,omitempty"`
 	// +kubebuilder:default:=8
-	// +kubebuilder:validation:Maximum=8
+	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:validation:Minimum=1
 	// Max allowed number of gateway instances
 	MaxInstances *int32 `json:"maxInstances,omitempty"`
 	// +kubebuilder:default:=1
-	// +kubebuilder:validation:Maximum=8
+	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:validation:Minimum=1
 	// Minimum allowed number of gateway instances
 	MinInstances *int32 `json:"minInstances,omitempty"`
+	// Autoscaling configures a HorizontalPodAutoscaler for each gateway Deployment,
+	// scaling it between MinInstances and MaxInstances.
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+	// Compute resources of the consul-dataplane container of the gateway pods
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// PodDisruptionBudget configures a PodDisruptionBudget for the pods of each gateway
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// PodDisruptionBudgetSpec defines the disruptions allowed for the pods of a gateway.
+// At most one of MinAvailable and MaxUnavailable may be set.
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
+type PodDisruptionBudgetSpec struct {
+	// Number or percentage of the gateway pods that must remain available during a voluntary disruption
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// Number or percentage of the gateway pods that may be unavailable during a voluntary disruption
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// AutoscalingSpec defines the metrics a gateway Deployment is scaled on.
+type AutoscalingSpec struct {
+	// +kubebuilder:validation:Minimum=1
+	// Target average CPU utilization of the gateway pods, as a percentage of their CPU request
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// Target average memory utilization of the gateway pods, as a percentage of their memory request
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// Additional metrics to scale on, in the format of the autoscaling/v2 HorizontalPodAutoscaler metrics
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
 }
 
 //+kubebuilder:object:generate=true
@@ -103,6 +167,59 @@ type GatewayClassConfigList struct {
 // +genclient
 // +kubebuilder:object:root=true
 
+// GatewayParameters defines the values that may be set on a single Gateway for Consul API Gateway.
+// A Gateway references the GatewayParameters in its namespace with the
+// consul.hashicorp.com/gateway-parameters annotation. The values that are set override the
+// values of the GatewayClassConfig of the Gateway's GatewayClass.
+type GatewayParameters struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of GatewayParameters.
+	Spec GatewayParametersSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GatewayParametersSpec specifies the desired state of the GatewayParameters CRD.
+type GatewayParametersSpec struct {
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	ServiceType *corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// ServiceAnnotations are additional annotations to set on the gateway Service,
+	// such as the annotations that configure a cloud load balancer. Load balancer
+	// providers that no longer read the deprecated spec.loadBalancerIP field take
+	// the IP address requested in the Gateway addresses from one of their annotations.
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=1
+	// Number of gateway instances that should be deployed by default
+	DefaultInstances *int32 `json:"defaultInstances,omitempty"`
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=1
+	// Max allowed number of gateway instances
+	MaxInstances *int32 `json:"maxInstances,omitempty"`
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=1
+	// Minimum allowed number of gateway instances
+	MinInstances *int32 `json:"minInstances,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GatewayParametersList is a list of GatewayParameters resources.
+type GatewayParametersList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []GatewayParameters `json:"items"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+
 // MeshService holds a reference to an externally managed Consul Service Mesh service.
 type MeshService struct {
 	metav1.TypeMeta `json:",inline"`
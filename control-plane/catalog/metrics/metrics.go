@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package metrics contains the Prometheus metrics of the catalog sync.
+//
+// The Default metrics are registered with the controller-runtime metrics registry,
+// which also holds the metrics of the named workqueues of the
+// helper/controller.Controller, so that a single handler serves all of them.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// ResultSuccess and ResultError are the values of the result label.
+	ResultSuccess = "success"
+	ResultError   = "error"
+
+	// DirectionToConsul and DirectionToK8S are the values of the direction
+	// label.
+	DirectionToConsul = "to-consul"
+	DirectionToK8S    = "to-k8s"
+
+	// QueueToConsul and QueueToK8S are the names of the workqueues of the
+	// controllers of each sync direction.
+	QueueToConsul = "sync-catalog-to-consul"
+	QueueToK8S    = "sync-catalog-to-k8s"
+)
+
+// Default holds the metrics that are registered with the controller-runtime
+// metrics registry. It's used by the syncers unless they're given their own
+// Metrics.
+var Default = New(ctrlmetrics.Registry)
+
+// Metrics holds the metrics of the catalog sync.
+type Metrics struct {
+	// Registrations counts the service instances registered in Consul.
+	Registrations *prometheus.CounterVec
+
+	// Deregistrations counts the stale service instances deregistered from
+	// Consul.
+	Deregistrations *prometheus.CounterVec
+
+	// Reaps counts the instances of services that are no longer synced from
+	// Kubernetes that were deregistered from Consul.
+	Reaps *prometheus.CounterVec
+
+	// SyncFullDuration observes how long a full sync to Consul takes.
+	SyncFullDuration prometheus.Histogram
+
+	// SyncedServices is the number of services that are synced in each
+	// direction.
+	SyncedServices *prometheus.GaugeVec
+
+	// K8SWrites counts the writes of Services and EndpointSlices to
+	// Kubernetes.
+	K8SWrites *prometheus.CounterVec
+
+	// WatchErrors counts the failed queries of the Consul watches.
+	WatchErrors *prometheus.CounterVec
+}
+
+// New creates the metrics of the catalog sync and registers them with reg.
+// It panics if they are already registered with reg.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		Registrations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "consul_sync_catalog_registrations_total",
+			Help: "Number of service instance registrations in Consul.",
+		}, []string{"result"}),
+		Deregistrations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "consul_sync_catalog_deregistrations_total",
+			Help: "Number of stale service instance deregistrations from Consul.",
+		}, []string{"result"}),
+		Reaps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "consul_sync_catalog_reaps_total",
+			Help: "Number of service instances deregistered from Consul because their service is no longer synced.",
+		}, []string{"result"}),
+		SyncFullDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "consul_sync_catalog_sync_full_duration_seconds",
+			Help:    "Duration of a full sync of the services to Consul.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+		}),
+		SyncedServices: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "consul_sync_catalog_synced_services",
+			Help: "Number of services that are synced.",
+		}, []string{"direction"}),
+		K8SWrites: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "consul_sync_catalog_k8s_writes_total",
+			Help: "Number of writes of synced Consul services to Kubernetes.",
+		}, []string{"resource", "operation", "result"}),
+		WatchErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "consul_sync_catalog_watch_errors_total",
+			Help: "Number of failed Consul queries of the sync watches.",
+		}, []string{"direction", "watch"}),
+	}
+	reg.MustRegister(
+		m.Registrations,
+		m.Deregistrations,
+		m.Reaps,
+		m.SyncFullDuration,
+		m.SyncedServices,
+		m.K8SWrites,
+		m.WatchErrors,
+	)
+	return m
+}
+
+// OrDefault returns m, or Default if m is nil.
+func (m *Metrics) OrDefault() *Metrics {
+	if m == nil {
+		return Default
+	}
+	return m
+}
+
+// Result returns the result label value for err.
+func Result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
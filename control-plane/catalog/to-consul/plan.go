@@ -0,0 +1,243 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package catalog
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// SyncPlan is the set of changes that the ConsulSyncer would make to Consul.
+// It is only computed if the ConsulSyncer runs in dry-run mode.
+type SyncPlan struct {
+	// Register are the service instances that would be registered because
+	// they don't exist in Consul yet.
+	Register []PlannedService `json:"register"`
+
+	// Update are the service instances that exist in Consul but would be
+	// re-registered because they differ from what is synced.
+	Update []PlannedService `json:"update"`
+
+	// Deregister are the stale instances of synced services that would be
+	// deregistered.
+	Deregister []PlannedService `json:"deregister"`
+
+	// Reap are the instances of services that are no longer synced from
+	// Kubernetes and would be deregistered.
+	Reap []PlannedService `json:"reap"`
+}
+
+// PlannedService is a service instance in a SyncPlan.
+type PlannedService struct {
+	Node        string `json:"node"`
+	ServiceID   string `json:"serviceID"`
+	ServiceName string `json:"serviceName,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+
+	// Changes are the fields of an updated service instance that differ
+	// from Consul.
+	Changes []string `json:"changes,omitempty"`
+}
+
+// Plan returns the latest SyncPlan. It is empty unless DryRun is true.
+func (s *ConsulSyncer) Plan() SyncPlan {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.plan
+}
+
+// planLocked computes the SyncPlan from the registrations that would be
+// made and the deregistrations scheduled by the watchers, and logs it if it
+// changed since the last time.
+//
+// Precondition: lock must be held.
+func (s *ConsulSyncer) planLocked(consulClient *api.Client) {
+	// Look up the services and health checks that are already registered so
+	// that new services are planned for registration and services that
+	// differ are planned for update.
+	opts := &api.QueryOptions{AllowStale: true}
+	if s.EnableNamespaces {
+		opts.Namespace = "*"
+	}
+	existing := make(map[string]map[string]*api.AgentService)
+	nodeServices, _, err := consulClient.Catalog().NodeServiceList(s.ConsulNodeName, opts)
+	if err != nil {
+		s.Log.Warn("error querying services, will retry", "err", err)
+		return
+	}
+	if nodeServices != nil {
+		for _, svc := range nodeServices.Services {
+			ns := s.planNamespace(svc.Namespace)
+			if existing[ns] == nil {
+				existing[ns] = make(map[string]*api.AgentService)
+			}
+			existing[ns][svc.ID] = svc
+		}
+	}
+	existingChecks := make(map[string]map[string]*api.HealthCheck)
+	nodeChecks, _, err := consulClient.Health().Node(s.ConsulNodeName, opts)
+	if err != nil {
+		s.Log.Warn("error querying health checks, will retry", "err", err)
+		return
+	}
+	for _, check := range nodeChecks {
+		ns := s.planNamespace(check.Namespace)
+		if existingChecks[ns] == nil {
+			existingChecks[ns] = make(map[string]*api.HealthCheck)
+		}
+		existingChecks[ns][check.CheckID] = check
+	}
+
+	plan := SyncPlan{
+		Register:   []PlannedService{},
+		Update:     []PlannedService{},
+		Deregister: []PlannedService{},
+		Reap:       []PlannedService{},
+	}
+	for ns, services := range s.namespaces {
+		for id, r := range services {
+			planned := PlannedService{
+				Node:        r.Node,
+				ServiceID:   r.Service.ID,
+				ServiceName: r.Service.Service,
+				Namespace:   r.Service.Namespace,
+			}
+			svc, ok := existing[ns][id]
+			if !ok {
+				plan.Register = append(plan.Register, planned)
+				continue
+			}
+			var check *api.HealthCheck
+			if r.Check != nil {
+				check = existingChecks[ns][r.Check.CheckID]
+			}
+			if changes := registrationChanges(r, svc, check); len(changes) > 0 {
+				planned.Changes = changes
+				plan.Update = append(plan.Update, planned)
+			}
+		}
+	}
+
+	// Deregistrations aren't cleared in dry-run mode because the watchers
+	// only schedule them when Consul changes. Ones that no longer apply
+	// because the service is synced again are dropped.
+	for id, r := range s.deregs {
+		if _, ok := s.namespaces[r.Namespace][id]; ok {
+			delete(s.deregs, id)
+			delete(s.reaps, id)
+			continue
+		}
+
+		planned := PlannedService{
+			Node:      r.Node,
+			ServiceID: r.ServiceID,
+			Namespace: r.Namespace,
+		}
+		if _, ok := s.reaps[id]; ok {
+			plan.Reap = append(plan.Reap, planned)
+		} else {
+			plan.Deregister = append(plan.Deregister, planned)
+		}
+	}
+
+	sortPlannedServices(plan.Register)
+	sortPlannedServices(plan.Update)
+	sortPlannedServices(plan.Deregister)
+	sortPlannedServices(plan.Reap)
+
+	if reflect.DeepEqual(plan, s.plan) {
+		return
+	}
+	s.plan = plan
+
+	s.Log.Info("[dry-run] sync plan changed", "register", len(plan.Register),
+		"update", len(plan.Update), "deregister", len(plan.Deregister), "reap", len(plan.Reap))
+	for _, p := range plan.Register {
+		s.Log.Info("[dry-run] would register service", "node-name", p.Node,
+			"service-id", p.ServiceID, "service-name", p.ServiceName, "service-consul-namespace", p.Namespace)
+	}
+	for _, p := range plan.Update {
+		s.Log.Info("[dry-run] would update service", "node-name", p.Node,
+			"service-id", p.ServiceID, "service-name", p.ServiceName, "service-consul-namespace", p.Namespace,
+			"changes", p.Changes)
+	}
+	for _, p := range plan.Deregister {
+		s.Log.Info("[dry-run] would deregister service", "node-name", p.Node,
+			"service-id", p.ServiceID, "service-consul-namespace", p.Namespace)
+	}
+	for _, p := range plan.Reap {
+		s.Log.Info("[dry-run] would reap service", "node-name", p.Node,
+			"service-id", p.ServiceID, "service-consul-namespace", p.Namespace)
+	}
+}
+
+// sortPlannedServices sorts planned services by namespace and service ID.
+func sortPlannedServices(services []PlannedService) {
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].Namespace != services[j].Namespace {
+			return services[i].Namespace < services[j].Namespace
+		}
+		return services[i].ServiceID < services[j].ServiceID
+	})
+}
+
+// planNamespace returns the namespace that s.namespaces uses for a service in
+// the given Consul namespace.
+func (s *ConsulSyncer) planNamespace(ns string) string {
+	if !s.EnableNamespaces {
+		return ""
+	}
+	return ns
+}
+
+// registrationChanges returns the fields of the registration that differ from
+// the service and its health check in Consul. The check is nil if the
+// registration has no check or it doesn't exist in Consul.
+func registrationChanges(r *api.CatalogRegistration, svc *api.AgentService, check *api.HealthCheck) []string {
+	var changes []string
+	if r.Service.Service != svc.Service {
+		changes = append(changes, "service")
+	}
+	if r.Service.Address != svc.Address {
+		changes = append(changes, "address")
+	}
+	if r.Service.Port != svc.Port {
+		changes = append(changes, "port")
+	}
+	if !equalStrings(r.Service.Tags, svc.Tags) {
+		changes = append(changes, "tags")
+	}
+	if !equalStringMaps(r.Service.Meta, svc.Meta) {
+		changes = append(changes, "meta")
+	}
+	// Consul sets default weights if none are registered, so they are only
+	// compared if they are set.
+	if r.Service.Weights.Passing != 0 && r.Service.Weights != svc.Weights {
+		changes = append(changes, "weights")
+	}
+	if r.Check != nil && (check == nil || r.Check.Status != check.Status || r.Check.Output != check.Output) {
+		changes = append(changes, "check")
+	}
+	return changes
+}
+
+// equalStrings returns whether a and b hold the same strings in the same
+// order, treating nil and empty as equal.
+func equalStrings(a, b []string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// equalStringMaps returns whether a and b hold the same entries, treating nil
+// and empty as equal.
+func equalStringMaps(a, b map[string]string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -6,6 +6,8 @@ package catalog
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -17,6 +19,7 @@ import (
 	consulapi "github.com/hashicorp/consul/api"
 	"github.com/hashicorp/go-hclog"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -41,8 +44,9 @@ const (
 	// consulKubernetesCheckType is the type of health check in Consul for Kubernetes readiness status.
 	consulKubernetesCheckType = "kubernetes-readiness"
 	// consulKubernetesCheckName is the name of health check in Consul for Kubernetes readiness status.
-	consulKubernetesCheckName  = "Kubernetes Readiness Check"
-	kubernetesSuccessReasonMsg = "Kubernetes health checks passing"
+	consulKubernetesCheckName      = "Kubernetes Readiness Check"
+	kubernetesSuccessReasonMsg     = "Kubernetes health checks passing"
+	kubernetesTerminatingReasonMsg = "Kubernetes endpoint is terminating"
 )
 
 type NodePortSyncType string
@@ -143,9 +147,22 @@ type ServiceResource struct {
 	// in the form <kube namespace>/<kube svc name>.
 	serviceMap map[string]*corev1.Service
 
-	// endpointsMap uses the same keys as serviceMap but maps to the endpoints
-	// of each service.
-	endpointsMap map[string]*corev1.Endpoints
+	// endpointSlicesMap uses the same keys as serviceMap but maps to the
+	// EndpointSlices of each service. The inner map is keyed by
+	// <kube namespace>/<kube endpointslice name> so that each slice can be
+	// updated on its own as it changes.
+	endpointSlicesMap map[string]map[string]*discoveryv1.EndpointSlice
+
+	// endpointRegistrationsMap uses the same keys as endpointSlicesMap but
+	// maps to the registrations generated from each EndpointSlice, so that a
+	// change to one slice only regenerates the registrations of that slice.
+	endpointRegistrationsMap map[string]map[string][]*consulapi.CatalogRegistration
+
+	// endpointRegistrationBaseMap uses the same keys as serviceMap but maps
+	// to what is needed to generate the registrations of a single
+	// EndpointSlice of the service. It is only set for services that are
+	// registered from their endpoints.
+	endpointRegistrationBaseMap map[string]endpointRegistrationBase
 
 	// EnableIngress enables syncing of the hostname from an Ingress resource
 	// to the service registration if an Ingress rule matches the service.
@@ -174,6 +191,17 @@ type serviceAddress struct {
 	port     int32
 }
 
+// endpointRegistrationBase holds what generateRegistrations computes from a
+// service to generate the registrations of its endpoints.
+type endpointRegistrationBase struct {
+	node               consulapi.CatalogRegistration
+	service            consulapi.AgentService
+	overridePortName   string
+	overridePortNumber int
+	useHostname        bool
+	nodePort           bool
+}
+
 // Informer implements the controller.Resource interface.
 func (t *ServiceResource) Informer() cache.SharedIndexInformer {
 	// Watch all k8s namespaces. Events will be filtered out as appropriate
@@ -225,21 +253,25 @@ func (t *ServiceResource) Upsert(key string, raw interface{}) error {
 	t.serviceMap[key] = service
 	t.Log.Debug("[ServiceResource.Upsert] adding service to serviceMap", "key", key, "service", service)
 
-	// If we care about endpoints, we should do the initial endpoints load.
+	// If we care about endpoints, we should do the initial endpoint slices load.
 	if t.shouldTrackEndpoints(key) {
-		endpoints, err := t.Client.CoreV1().
-			Endpoints(service.Namespace).
-			Get(t.Ctx, service.Name, metav1.GetOptions{})
+		endpointSliceList, err := t.Client.DiscoveryV1().
+			EndpointSlices(service.Namespace).
+			List(t.Ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, service.Name)})
 		if err != nil {
-			t.Log.Warn("error loading initial endpoints",
+			t.Log.Warn("error loading initial endpoint slices",
 				"key", key,
 				"err", err)
 		} else {
-			if t.endpointsMap == nil {
-				t.endpointsMap = make(map[string]*corev1.Endpoints)
+			if t.endpointSlicesMap == nil {
+				t.endpointSlicesMap = make(map[string]map[string]*discoveryv1.EndpointSlice)
+			}
+			t.endpointSlicesMap[key] = make(map[string]*discoveryv1.EndpointSlice)
+			for i := range endpointSliceList.Items {
+				endpointSlice := &endpointSliceList.Items[i]
+				t.endpointSlicesMap[key][fmt.Sprintf("%s/%s", endpointSlice.Namespace, endpointSlice.Name)] = endpointSlice
 			}
-			t.endpointsMap[key] = endpoints
-			t.Log.Debug("[ServiceResource.Upsert] adding service's endpoints to endpointsMap", "key", key, "service", service, "endpoints", endpoints)
+			t.Log.Debug("[ServiceResource.Upsert] adding service's endpoint slices to endpointSlicesMap", "key", key, "service", service, "endpointSlices", len(endpointSliceList.Items))
 		}
 	}
 
@@ -265,8 +297,10 @@ func (t *ServiceResource) Delete(key string, _ interface{}) error {
 func (t *ServiceResource) doDelete(key string) {
 	delete(t.serviceMap, key)
 	t.Log.Debug("[doDelete] deleting service from serviceMap", "key", key)
-	delete(t.endpointsMap, key)
-	t.Log.Debug("[doDelete] deleting endpoints from endpointsMap", "key", key)
+	delete(t.endpointSlicesMap, key)
+	delete(t.endpointRegistrationsMap, key)
+	delete(t.endpointRegistrationBaseMap, key)
+	t.Log.Debug("[doDelete] deleting endpoint slices from endpointSlicesMap", "key", key)
 	// If there were registrations related to this service, then
 	// delete them and sync.
 	if _, ok := t.consulMap[key]; ok {
@@ -278,13 +312,13 @@ func (t *ServiceResource) doDelete(key string) {
 // Run implements the controller.Backgrounder interface.
 func (t *ServiceResource) Run(ch <-chan struct{}) {
 	t.Log.Info("starting runner for endpoints")
-	// Register a controller for Endpoints which subsequently registers a
+	// Register a controller for EndpointSlices which subsequently registers a
 	// controller for the Ingress resource.
 	(&controller.Controller{
 		Resource: &serviceEndpointsResource{
 			Service: t,
 			Ctx:     t.Ctx,
-			Log:     t.Log.Named("controller/endpoints"),
+			Log:     t.Log.Named("controller/endpointslices"),
 			Resource: &serviceIngressResource{
 				Service:             t,
 				Ctx:                 t.Ctx,
@@ -380,6 +414,8 @@ func (t *ServiceResource) generateRegistrations(key string) {
 	// Begin by always clearing the old value out since we'll regenerate
 	// a new one if there is one.
 	delete(t.consulMap, key)
+	delete(t.endpointRegistrationsMap, key)
+	delete(t.endpointRegistrationBaseMap, key)
 
 	// baseNode and baseService are the base that should be modified with
 	// service-type specific changes. These are not pointers, they should be
@@ -537,7 +573,12 @@ func (t *ServiceResource) generateRegistrations(key string) {
 	// If LoadBalancerEndpointsSync is true sync LB endpoints instead of loadbalancer ingress.
 	case corev1.ServiceTypeLoadBalancer:
 		if t.LoadBalancerEndpointsSync {
-			t.registerServiceInstance(baseNode, baseService, key, overridePortName, overridePortNumber, false)
+			t.generateEndpointRegistrations(key, endpointRegistrationBase{
+				node:               baseNode,
+				service:            baseService,
+				overridePortName:   overridePortName,
+				overridePortNumber: overridePortNumber,
+			})
 		} else {
 			seen := map[string]struct{}{}
 			for _, ingress := range svc.Status.LoadBalancer.Ingress {
@@ -582,189 +623,341 @@ func (t *ServiceResource) generateRegistrations(key string) {
 	// pods are running on. This way we don't register _every_ K8S
 	// node as part of the service.
 	case corev1.ServiceTypeNodePort:
-		if t.endpointsMap == nil {
-			return
-		}
+		t.generateEndpointRegistrations(key, endpointRegistrationBase{
+			node:     baseNode,
+			service:  baseService,
+			nodePort: true,
+		})
 
-		endpoints := t.endpointsMap[key]
-		if endpoints == nil {
-			return
-		}
+	// For ClusterIP services, we register a service instance
+	// for each endpoint.
+	case corev1.ServiceTypeClusterIP:
+		t.generateEndpointRegistrations(key, endpointRegistrationBase{
+			node:               baseNode,
+			service:            baseService,
+			overridePortName:   overridePortName,
+			overridePortNumber: overridePortNumber,
+			useHostname:        true,
+		})
+	}
+}
 
-		for _, subset := range endpoints.Subsets {
-			for _, subsetAddr := range subset.Addresses {
-				// Check that the node name exists
-				// subsetAddr.NodeName is of type *string
-				if subsetAddr.NodeName == nil {
-					continue
-				}
+// generateEndpointRegistrations generates the registrations for every
+// EndpointSlice of the service with the given key. The base is kept so that
+// the registrations of a single slice can be regenerated when it changes.
+//
+// Precondition: the lock t.lock is held.
+func (t *ServiceResource) generateEndpointRegistrations(key string, base endpointRegistrationBase) {
+	if t.endpointRegistrationBaseMap == nil {
+		t.endpointRegistrationBaseMap = make(map[string]endpointRegistrationBase)
+	}
+	t.endpointRegistrationBaseMap[key] = base
 
-				// Look up the node's ip address by getting node info
-				node, err := t.Client.CoreV1().Nodes().Get(t.Ctx, *subsetAddr.NodeName, metav1.GetOptions{})
-				if err != nil {
-					t.Log.Warn("error getting node info", "error", err)
-					continue
-				}
+	if t.endpointRegistrationsMap == nil {
+		t.endpointRegistrationsMap = make(map[string]map[string][]*consulapi.CatalogRegistration)
+	}
+	t.endpointRegistrationsMap[key] = make(map[string][]*consulapi.CatalogRegistration)
+	for sliceKey, endpointSlice := range t.endpointSlicesMap[key] {
+		t.endpointRegistrationsMap[key][sliceKey] = t.endpointSliceRegistrations(key, base, endpointSlice)
+	}
 
-				// Set the expected node address type
-				var expectedType corev1.NodeAddressType
-				if t.NodePortSync == InternalOnly {
-					expectedType = corev1.NodeInternalIP
-				} else {
-					expectedType = corev1.NodeExternalIP
-				}
+	t.mergeEndpointRegistrations(key)
+}
 
-				// Find the ip address for the node and
-				// create the Consul service using it
-				var found bool
-				for _, address := range node.Status.Addresses {
-					if address.Type == expectedType {
-						found = true
-						r := baseNode
-						rs := baseService
-						r.Service = &rs
-						r.Service.ID = serviceID(r.Service.Service, subsetAddr.IP)
-						r.Service.Address = address.Address
-
-						t.consulMap[key] = append(t.consulMap[key], &r)
-						// Only consider the first address that matches. In some cases
-						// there will be multiple addresses like when using AWS CNI.
-						// In those cases, Kubernetes will ensure eth0 is always the first
-						// address in the list.
-						// See https://github.com/kubernetes/kubernetes/blob/b559434c02f903dbcd46ee7d6c78b216d3f0aca0/staging/src/k8s.io/legacy-cloud-providers/aws/aws.go#L1462-L1464
-						break
-					}
-				}
+// updateEndpointRegistrations regenerates the registrations of the
+// EndpointSlice with the given slice key, or removes them if the slice is no
+// longer tracked. The registrations of the other slices of the service are
+// left as they are. It returns whether the registrations of the service
+// changed, so that a slice update that doesn't change any instance doesn't
+// trigger a sync.
+//
+// Precondition: the lock t.lock is held.
+func (t *ServiceResource) updateEndpointRegistrations(key, sliceKey string) bool {
+	base, ok := t.endpointRegistrationBaseMap[key]
+	if !ok {
+		// The service isn't registered from its endpoints or its
+		// registrations haven't been generated yet.
+		return false
+	}
 
-				// If an ExternalIP wasn't found, and ExternalFirst is set,
-				// use an InternalIP
-				if t.NodePortSync == ExternalFirst && !found {
-					for _, address := range node.Status.Addresses {
-						if address.Type == corev1.NodeInternalIP {
-							r := baseNode
-							rs := baseService
-							r.Service = &rs
-							r.Service.ID = serviceID(r.Service.Service, subsetAddr.IP)
-							r.Service.Address = address.Address
-
-							t.consulMap[key] = append(t.consulMap[key], &r)
-							// Only consider the first address that matches. In some cases
-							// there will be multiple addresses like when using AWS CNI.
-							// In those cases, Kubernetes will ensure eth0 is always the first
-							// address in the list.
-							// See https://github.com/kubernetes/kubernetes/blob/b559434c02f903dbcd46ee7d6c78b216d3f0aca0/staging/src/k8s.io/legacy-cloud-providers/aws/aws.go#L1462-L1464
-							break
-						}
-					}
-				}
-			}
-		}
+	previous := t.endpointRegistrationsMap[key][sliceKey]
+	var current []*consulapi.CatalogRegistration
+	if endpointSlice, ok := t.endpointSlicesMap[key][sliceKey]; ok {
+		current = t.endpointSliceRegistrations(key, base, endpointSlice)
+		t.endpointRegistrationsMap[key][sliceKey] = current
+	} else {
+		delete(t.endpointRegistrationsMap[key], sliceKey)
+	}
 
-	// For ClusterIP services, we register a service instance
-	// for each endpoint.
-	case corev1.ServiceTypeClusterIP:
-		t.registerServiceInstance(baseNode, baseService, key, overridePortName, overridePortNumber, true)
+	changed, removed := diffRegistrations(previous, current)
+	if len(changed) == 0 && len(removed) == 0 {
+		t.Log.Debug("[updateEndpointRegistrations] endpoint slice has no changed instances", "key", key, "endpointSlice", sliceKey)
+		return false
 	}
+	t.Log.Debug("[updateEndpointRegistrations] endpoint slice has changed instances",
+		"key", key,
+		"endpointSlice", sliceKey,
+		"changed", changed,
+		"removed", removed)
+
+	t.mergeEndpointRegistrations(key)
+	return true
 }
 
-func (t *ServiceResource) registerServiceInstance(
-	baseNode consulapi.CatalogRegistration,
-	baseService consulapi.AgentService,
-	key string,
-	overridePortName string,
-	overridePortNumber int,
-	useHostname bool) {
+// mergeEndpointRegistrations sets the registrations of the service with the
+// given key from the registrations of each of its EndpointSlices. Slices are
+// merged in the order of their keys so that registrations are in a stable
+// order.
+//
+// Precondition: the lock t.lock is held.
+func (t *ServiceResource) mergeEndpointRegistrations(key string) {
+	sliceKeys := make([]string, 0, len(t.endpointRegistrationsMap[key]))
+	for sliceKey := range t.endpointRegistrationsMap[key] {
+		sliceKeys = append(sliceKeys, sliceKey)
+	}
+	sort.Strings(sliceKeys)
 
-	if t.endpointsMap == nil {
-		return
+	// An endpoint can briefly appear in more than one slice while
+	// Kubernetes rebalances them so we maintain a set to prevent
+	// duplicates.
+	seen := map[string]struct{}{}
+	var registrations []*consulapi.CatalogRegistration
+	for _, sliceKey := range sliceKeys {
+		for _, r := range t.endpointRegistrationsMap[key][sliceKey] {
+			if _, ok := seen[r.Service.ID]; ok {
+				continue
+			}
+			seen[r.Service.ID] = struct{}{}
+			registrations = append(registrations, r)
+		}
 	}
 
-	endpoints := t.endpointsMap[key]
-	if endpoints == nil {
-		return
+	delete(t.consulMap, key)
+	if len(registrations) > 0 {
+		t.consulMap[key] = registrations
 	}
+}
 
-	seen := map[string]struct{}{}
-	for _, subset := range endpoints.Subsets {
-		// For ClusterIP services and if LoadBalancerEndpointsSync is true, we use the endpoint port instead
-		// of the service port because we're registering each endpoint
-		// as a separate service instance.
-		epPort := baseService.Port
-		if overridePortName != "" {
-			// If we're supposed to use a specific named port, find it.
-			for _, p := range subset.Ports {
-				if overridePortName == p.Name {
-					epPort = int(p.Port)
+// endpointSliceRegistrations generates the registrations for the endpoints
+// of a single EndpointSlice of the service with the given key.
+//
+// Precondition: the lock t.lock is held.
+func (t *ServiceResource) endpointSliceRegistrations(key string, base endpointRegistrationBase, endpointSlice *discoveryv1.EndpointSlice) []*consulapi.CatalogRegistration {
+	if base.nodePort {
+		return t.nodePortRegistrations(base, endpointSlice)
+	}
+	return t.serviceInstanceRegistrations(key, base, endpointSlice)
+}
+
+// nodePortRegistrations creates a service instance for each endpoint of the
+// EndpointSlice, which corresponds to the nodes the service's pods are
+// running on.
+func (t *ServiceResource) nodePortRegistrations(base endpointRegistrationBase, endpointSlice *discoveryv1.EndpointSlice) []*consulapi.CatalogRegistration {
+	var registrations []*consulapi.CatalogRegistration
+	for _, endpoint := range endpointSlice.Endpoints {
+		// Only ready endpoints are registered for NodePort services
+		// since the node, not the endpoint, is the service instance.
+		if !isEndpointReady(endpoint.Conditions) || len(endpoint.Addresses) == 0 {
+			continue
+		}
+
+		// Check that the node name exists
+		// endpoint.NodeName is of type *string
+		if endpoint.NodeName == nil {
+			continue
+		}
+
+		// Look up the node's ip address by getting node info
+		node, err := t.Client.CoreV1().Nodes().Get(t.Ctx, *endpoint.NodeName, metav1.GetOptions{})
+		if err != nil {
+			t.Log.Warn("error getting node info", "error", err)
+			continue
+		}
+
+		// Set the expected node address type
+		var expectedType corev1.NodeAddressType
+		if t.NodePortSync == InternalOnly {
+			expectedType = corev1.NodeInternalIP
+		} else {
+			expectedType = corev1.NodeExternalIP
+		}
+
+		// Find the ip address for the node and
+		// create the Consul service using it
+		var found bool
+		for _, address := range node.Status.Addresses {
+			if address.Type == expectedType {
+				found = true
+				r := base.node
+				rs := base.service
+				r.Service = &rs
+				r.Service.ID = serviceID(r.Service.Service, endpoint.Addresses[0])
+				r.Service.Address = address.Address
+
+				registrations = append(registrations, &r)
+				// Only consider the first address that matches. In some cases
+				// there will be multiple addresses like when using AWS CNI.
+				// In those cases, Kubernetes will ensure eth0 is always the first
+				// address in the list.
+				// See https://github.com/kubernetes/kubernetes/blob/b559434c02f903dbcd46ee7d6c78b216d3f0aca0/staging/src/k8s.io/legacy-cloud-providers/aws/aws.go#L1462-L1464
+				break
+			}
+		}
+
+		// If an ExternalIP wasn't found, and ExternalFirst is set,
+		// use an InternalIP
+		if t.NodePortSync == ExternalFirst && !found {
+			for _, address := range node.Status.Addresses {
+				if address.Type == corev1.NodeInternalIP {
+					r := base.node
+					rs := base.service
+					r.Service = &rs
+					r.Service.ID = serviceID(r.Service.Service, endpoint.Addresses[0])
+					r.Service.Address = address.Address
+
+					registrations = append(registrations, &r)
+					// Only consider the first address that matches. In some cases
+					// there will be multiple addresses like when using AWS CNI.
+					// In those cases, Kubernetes will ensure eth0 is always the first
+					// address in the list.
+					// See https://github.com/kubernetes/kubernetes/blob/b559434c02f903dbcd46ee7d6c78b216d3f0aca0/staging/src/k8s.io/legacy-cloud-providers/aws/aws.go#L1462-L1464
 					break
 				}
 			}
-		} else if overridePortNumber == 0 {
-			// Otherwise we'll just use the first port in the list
-			// (unless the port number was overridden by an annotation).
-			for _, p := range subset.Ports {
-				epPort = int(p.Port)
+		}
+	}
+	return registrations
+}
+
+// serviceInstanceRegistrations creates a service instance for each endpoint
+// of the EndpointSlice.
+func (t *ServiceResource) serviceInstanceRegistrations(key string, base endpointRegistrationBase, endpointSlice *discoveryv1.EndpointSlice) []*consulapi.CatalogRegistration {
+	// FQDN slices can only be registered when hostnames are allowed.
+	if endpointSlice.AddressType == discoveryv1.AddressTypeFQDN && !base.useHostname {
+		return nil
+	}
+
+	// For ClusterIP services and if LoadBalancerEndpointsSync is true, we use the endpoint port instead
+	// of the service port because we're registering each endpoint
+	// as a separate service instance.
+	epPort := base.service.Port
+	if base.overridePortName != "" {
+		// If we're supposed to use a specific named port, find it.
+		for _, p := range endpointSlice.Ports {
+			if p.Name != nil && base.overridePortName == *p.Name && p.Port != nil {
+				epPort = int(*p.Port)
 				break
 			}
 		}
-		for _, subsetAddr := range subset.Addresses {
-			var addr string
-			// Use the address and port from the Ingress resource if
-			// ingress-sync is enabled and the service has an ingress
-			// resource that references it.
-			if t.EnableIngress && t.isIngressService(key) {
-				addr = t.serviceHostnameMap[key].hostName
-				epPort = int(t.serviceHostnameMap[key].port)
-			} else {
-				addr = subsetAddr.IP
-				if addr == "" && useHostname {
-					addr = subsetAddr.Hostname
-				}
-				if addr == "" {
-					continue
-				}
+	} else if base.overridePortNumber == 0 {
+		// Otherwise we'll just use the first port in the list
+		// (unless the port number was overridden by an annotation).
+		for _, p := range endpointSlice.Ports {
+			if p.Port != nil {
+				epPort = int(*p.Port)
 			}
+			break
+		}
+	}
 
-			// Its not clear whether K8S guarantees ready addresses to
-			// be unique so we maintain a set to prevent duplicates just
-			// in case.
-			if _, ok := seen[addr]; ok {
-				continue
-			}
-			seen[addr] = struct{}{}
+	var registrations []*consulapi.CatalogRegistration
+	seen := map[string]struct{}{}
+	for _, endpoint := range endpointSlice.Endpoints {
+		// Terminating endpoints are registered as critical so that Consul
+		// stops routing to them before they are removed from the slice.
+		// Endpoints that are neither ready nor terminating are not registered.
+		healthStatus := consulapi.HealthPassing
+		healthOutput := kubernetesSuccessReasonMsg
+		if endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating {
+			healthStatus = consulapi.HealthCritical
+			healthOutput = kubernetesTerminatingReasonMsg
+		} else if !isEndpointReady(endpoint.Conditions) {
+			continue
+		}
 
-			r := baseNode
-			rs := baseService
-			r.Service = &rs
-			r.Service.ID = serviceID(r.Service.Service, addr)
-			r.Service.Address = addr
-			r.Service.Port = epPort
-			r.Service.Meta = make(map[string]string)
-			// Deepcopy baseService.Meta into r.Service.Meta as baseService is shared
-			// between all nodes of a service
-			for k, v := range baseService.Meta {
-				r.Service.Meta[k] = v
+		var addr string
+		// Use the address and port from the Ingress resource if
+		// ingress-sync is enabled and the service has an ingress
+		// resource that references it.
+		if t.EnableIngress && t.isIngressService(key) {
+			addr = t.serviceHostnameMap[key].hostName
+			epPort = int(t.serviceHostnameMap[key].port)
+		} else {
+			if len(endpoint.Addresses) > 0 {
+				addr = endpoint.Addresses[0]
 			}
-			if subsetAddr.TargetRef != nil {
-				r.Service.Meta[ConsulK8SRefValue] = subsetAddr.TargetRef.Name
-				r.Service.Meta[ConsulK8SRefKind] = subsetAddr.TargetRef.Kind
+			if addr == "" && base.useHostname && endpoint.Hostname != nil {
+				addr = *endpoint.Hostname
 			}
-			if subsetAddr.NodeName != nil {
-				r.Service.Meta[ConsulK8SNodeName] = *subsetAddr.NodeName
+			if addr == "" {
+				continue
 			}
+		}
 
-			r.Check = &consulapi.AgentCheck{
-				CheckID:   consulHealthCheckID(endpoints.Namespace, serviceID(r.Service.Service, addr)),
-				Name:      consulKubernetesCheckName,
-				Namespace: baseService.Namespace,
-				Type:      consulKubernetesCheckType,
-				Status:    consulapi.HealthPassing,
-				ServiceID: serviceID(r.Service.Service, addr),
-				Output:    kubernetesSuccessReasonMsg,
-			}
+		// Its not clear whether K8S guarantees ready addresses to
+		// be unique so we maintain a set to prevent duplicates just
+		// in case.
+		if _, ok := seen[addr]; ok {
+			continue
+		}
+		seen[addr] = struct{}{}
+
+		r := base.node
+		rs := base.service
+		r.Service = &rs
+		r.Service.ID = serviceID(r.Service.Service, addr)
+		r.Service.Address = addr
+		r.Service.Port = epPort
+		r.Service.Meta = make(map[string]string)
+		// Deepcopy baseService.Meta into r.Service.Meta as baseService is shared
+		// between all nodes of a service
+		for k, v := range base.service.Meta {
+			r.Service.Meta[k] = v
+		}
+		if endpoint.TargetRef != nil {
+			r.Service.Meta[ConsulK8SRefValue] = endpoint.TargetRef.Name
+			r.Service.Meta[ConsulK8SRefKind] = endpoint.TargetRef.Kind
+		}
+		if endpoint.NodeName != nil {
+			r.Service.Meta[ConsulK8SNodeName] = *endpoint.NodeName
+		}
 
-			t.consulMap[key] = append(t.consulMap[key], &r)
+		r.Check = &consulapi.AgentCheck{
+			CheckID:   consulHealthCheckID(endpointSlice.Namespace, serviceID(r.Service.Service, addr)),
+			Name:      consulKubernetesCheckName,
+			Namespace: base.service.Namespace,
+			Type:      consulKubernetesCheckType,
+			Status:    healthStatus,
+			ServiceID: serviceID(r.Service.Service, addr),
+			Output:    healthOutput,
 		}
+
+		registrations = append(registrations, &r)
 	}
+	return registrations
+}
+
+// diffRegistrations returns the service IDs of the registrations in current
+// that are new or differ from previous, and of those in previous that are no
+// longer in current.
+func diffRegistrations(previous, current []*consulapi.CatalogRegistration) (changed, removed []string) {
+	previousByID := make(map[string]*consulapi.CatalogRegistration, len(previous))
+	for _, r := range previous {
+		previousByID[r.Service.ID] = r
+	}
+	currentIDs := make(map[string]struct{}, len(current))
+	for _, r := range current {
+		currentIDs[r.Service.ID] = struct{}{}
+		if p, ok := previousByID[r.Service.ID]; !ok || !reflect.DeepEqual(p, r) {
+			changed = append(changed, r.Service.ID)
+		}
+	}
+	for _, r := range previous {
+		if _, ok := currentIDs[r.Service.ID]; !ok {
+			removed = append(removed, r.Service.ID)
+		}
+	}
+	return changed, removed
 }
 
 // sync calls the Syncer.Sync function from the generated registrations.
@@ -785,7 +978,7 @@ func (t *ServiceResource) sync() {
 }
 
 // serviceEndpointsResource implements controller.Resource and starts
-// a background watcher on endpoints that is used by the ServiceResource
+// a background watcher on endpoint slices that is used by the ServiceResource
 // to keep track of changing endpoints for registered services.
 type serviceEndpointsResource struct {
 	Service  *ServiceResource
@@ -811,18 +1004,18 @@ func (t *serviceEndpointsResource) Informer() cache.SharedIndexInformer {
 	return cache.NewSharedIndexInformer(
 		&cache.ListWatch{
 			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
-				return t.Service.Client.CoreV1().
-					Endpoints(metav1.NamespaceAll).
+				return t.Service.Client.DiscoveryV1().
+					EndpointSlices(metav1.NamespaceAll).
 					List(t.Ctx, options)
 			},
 
 			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-				return t.Service.Client.CoreV1().
-					Endpoints(metav1.NamespaceAll).
+				return t.Service.Client.DiscoveryV1().
+					EndpointSlices(metav1.NamespaceAll).
 					Watch(t.Ctx, options)
 			},
 		},
-		&corev1.Endpoints{},
+		&discoveryv1.EndpointSlice{},
 		0,
 		cache.Indexers{},
 	)
@@ -830,7 +1023,7 @@ func (t *serviceEndpointsResource) Informer() cache.SharedIndexInformer {
 
 func (t *serviceEndpointsResource) Upsert(key string, raw interface{}) error {
 	svc := t.Service
-	endpoints, ok := raw.(*corev1.Endpoints)
+	endpointSlice, ok := raw.(*discoveryv1.EndpointSlice)
 	if !ok {
 		svc.Log.Warn("upsert got invalid type", "raw", raw)
 		return nil
@@ -839,43 +1032,69 @@ func (t *serviceEndpointsResource) Upsert(key string, raw interface{}) error {
 	svc.serviceLock.Lock()
 	defer svc.serviceLock.Unlock()
 
-	// Check if we care about endpoints for this service
-	if !svc.shouldTrackEndpoints(key) {
+	// Check if we care about endpoints for the service this slice belongs to
+	svcKey := serviceKeyForEndpointSlice(endpointSlice)
+	if svcKey == "" || !svc.shouldTrackEndpoints(svcKey) {
 		return nil
 	}
 
-	// We are tracking this service so let's keep track of the endpoints
-	if svc.endpointsMap == nil {
-		svc.endpointsMap = make(map[string]*corev1.Endpoints)
+	// We are tracking this service so let's keep track of this slice. Only
+	// this slice is replaced; the other slices of the service are kept as is.
+	if svc.endpointSlicesMap == nil {
+		svc.endpointSlicesMap = make(map[string]map[string]*discoveryv1.EndpointSlice)
+	}
+	if svc.endpointSlicesMap[svcKey] == nil {
+		svc.endpointSlicesMap[svcKey] = make(map[string]*discoveryv1.EndpointSlice)
 	}
-	svc.endpointsMap[key] = endpoints
+	svc.endpointSlicesMap[svcKey][key] = endpointSlice
 
-	// Update the registration and trigger a sync
-	svc.generateRegistrations(key)
-	svc.sync()
-	svc.Log.Info("upsert endpoint", "key", key)
+	// Update the registrations of this slice and trigger a sync if any
+	// instance of the service changed
+	if svc.updateEndpointRegistrations(svcKey, key) {
+		svc.sync()
+	}
+	svc.Log.Info("upsert endpoint slice", "key", key, "service", svcKey)
 	return nil
 }
 
-func (t *serviceEndpointsResource) Delete(key string, _ interface{}) error {
+func (t *serviceEndpointsResource) Delete(key string, raw interface{}) error {
 	t.Service.serviceLock.Lock()
 	defer t.Service.serviceLock.Unlock()
 
+	endpointSlice, ok := raw.(*discoveryv1.EndpointSlice)
+	if !ok {
+		t.Service.Log.Warn("delete got invalid type", "raw", raw)
+		return nil
+	}
+
 	// This is a bit of an optimization. We only want to force a resync
-	// if we were tracking this endpoint to begin with and that endpoint
-	// had associated registrations.
-	if _, ok := t.Service.endpointsMap[key]; ok {
-		delete(t.Service.endpointsMap, key)
-		if _, ok := t.Service.consulMap[key]; ok {
-			delete(t.Service.consulMap, key)
-			t.Service.sync()
+	// if we were tracking this endpoint slice to begin with and that slice
+	// had associated registrations. Only the registrations of this slice
+	// are removed.
+	svcKey := serviceKeyForEndpointSlice(endpointSlice)
+	if endpointSlices, ok := t.Service.endpointSlicesMap[svcKey]; ok {
+		if _, ok := endpointSlices[key]; ok {
+			delete(endpointSlices, key)
+			if t.Service.updateEndpointRegistrations(svcKey, key) {
+				t.Service.sync()
+			}
 		}
 	}
 
-	t.Service.Log.Info("delete endpoint", "key", key)
+	t.Service.Log.Info("delete endpoint slice", "key", key, "service", svcKey)
 	return nil
 }
 
+// serviceKeyForEndpointSlice returns the serviceMap key of the service an
+// EndpointSlice belongs to, or an empty string if it isn't owned by a service.
+func serviceKeyForEndpointSlice(endpointSlice *discoveryv1.EndpointSlice) string {
+	svcName, ok := endpointSlice.Labels[discoveryv1.LabelServiceName]
+	if !ok || svcName == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", endpointSlice.Namespace, svcName)
+}
+
 // serviceIngressResource implements controller.Resource and starts
 // a background watcher on ingress resources that is used by the ServiceResource
 // to keep track of changing ingress for registered services.
@@ -1026,6 +1245,13 @@ func consulHealthCheckID(k8sNS string, serviceID string) string {
 	return fmt.Sprintf("%s/%s", k8sNS, serviceID)
 }
 
+// isEndpointReady returns whether an EndpointSlice endpoint is ready. As
+// recommended by the EndpointSlice API, an unknown ready condition is
+// interpreted as ready.
+func isEndpointReady(conditions discoveryv1.EndpointConditions) bool {
+	return conditions.Ready == nil || *conditions.Ready
+}
+
 // Calculates the passing service weight.
 func getServiceWeight(weight string) (int, error) {
 	// error validation if the input param is a number.
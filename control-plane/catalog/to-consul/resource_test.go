@@ -5,6 +5,7 @@ package catalog
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	mapset "github.com/deckarep/golang-set"
@@ -14,11 +15,13 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
 )
 
 const nodeName1 = "ip-10-11-12-13.ec2.internal"
@@ -759,25 +762,27 @@ func TestServiceResource_lbRegisterEndpoints(t *testing.T) {
 
 	node1, _ := createNodes(t, client)
 
-	// Insert the endpoints
-	_, err := client.CoreV1().Endpoints(metav1.NamespaceDefault).Create(
+	// Insert the endpoint slice
+	_, err := client.DiscoveryV1().EndpointSlices(metav1.NamespaceDefault).Create(
 		context.Background(),
-		&corev1.Endpoints{
+		&discoveryv1.EndpointSlice{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: "foo",
+				Name:      "foo-abcde",
+				Namespace: metav1.NamespaceDefault,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "foo"},
 			},
-
-			Subsets: []corev1.EndpointSubset{
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
 				{
-					Addresses: []corev1.EndpointAddress{
-						{NodeName: &node1.Name, IP: "8.8.8.8"},
-					},
-					Ports: []corev1.EndpointPort{
-						{Name: "http", Port: 8080},
-						{Name: "rpc", Port: 2000},
-					},
+					Addresses:  []string{"8.8.8.8"},
+					Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+					NodeName:   &node1.Name,
 				},
 			},
+			Ports: []discoveryv1.EndpointPort{
+				{Name: pointer.String("http"), Port: pointer.Int32(8080)},
+				{Name: pointer.String("rpc"), Port: pointer.Int32(2000)},
+			},
 		},
 		metav1.CreateOptions{})
 	require.NoError(t, err)
@@ -894,25 +899,27 @@ func TestServiceResource_nodePort_singleEndpoint(t *testing.T) {
 
 	node1, _ := createNodes(t, client)
 
-	// Insert the endpoints
-	_, err := client.CoreV1().Endpoints(metav1.NamespaceDefault).Create(
+	// Insert the endpoint slice
+	_, err := client.DiscoveryV1().EndpointSlices(metav1.NamespaceDefault).Create(
 		context.Background(),
-		&corev1.Endpoints{
+		&discoveryv1.EndpointSlice{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: "foo",
+				Name:      "foo-abcde",
+				Namespace: metav1.NamespaceDefault,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "foo"},
 			},
-
-			Subsets: []corev1.EndpointSubset{
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
 				{
-					Addresses: []corev1.EndpointAddress{
-						{NodeName: &node1.Name, IP: "1.2.3.4"},
-					},
-					Ports: []corev1.EndpointPort{
-						{Name: "http", Port: 8080},
-						{Name: "rpc", Port: 2000},
-					},
+					Addresses:  []string{"1.2.3.4"},
+					Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+					NodeName:   &node1.Name,
 				},
 			},
+			Ports: []discoveryv1.EndpointPort{
+				{Name: pointer.String("http"), Port: pointer.Int32(8080)},
+				{Name: pointer.String("rpc"), Port: pointer.Int32(2000)},
+			},
 		},
 		metav1.CreateOptions{})
 	require.NoError(t, err)
@@ -1180,6 +1187,165 @@ func TestServiceResource_clusterIP_healthCheck(t *testing.T) {
 	})
 }
 
+// Test that terminating endpoints are registered as critical and that endpoints
+// which are neither ready nor terminating are not registered.
+func TestServiceResource_clusterIP_terminatingEndpoints(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+	syncer := newTestSyncer()
+	serviceResource := defaultServiceResource(client, syncer)
+	serviceResource.ClusterIPSync = true
+
+	// Start the controller
+	closer := controller.TestControllerRun(&serviceResource)
+	defer closer()
+
+	// Insert the service
+	svc := clusterIPService("foo", metav1.NamespaceDefault)
+	_, err := client.CoreV1().Services(metav1.NamespaceDefault).Create(context.Background(), svc, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// Insert the endpoint slice
+	createEndpointSlice(t, client, "foo", metav1.NamespaceDefault, "foo-abcde", []discoveryv1.Endpoint{
+		{
+			Addresses:  []string{"1.1.1.1"},
+			Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true), Serving: pointer.Bool(true), Terminating: pointer.Bool(false)},
+		},
+		{
+			Addresses:  []string{"2.2.2.2"},
+			Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(false), Serving: pointer.Bool(true), Terminating: pointer.Bool(true)},
+		},
+		{
+			Addresses:  []string{"3.3.3.3"},
+			Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(false), Serving: pointer.Bool(false), Terminating: pointer.Bool(false)},
+		},
+	})
+
+	// Verify what we got
+	retry.Run(t, func(r *retry.R) {
+		syncer.Lock()
+		defer syncer.Unlock()
+		actual := syncer.Registrations
+		require.Len(r, actual, 2)
+		require.Equal(r, "1.1.1.1", actual[0].Service.Address)
+		require.Equal(r, consulapi.HealthPassing, actual[0].Check.Status)
+		require.Equal(r, kubernetesSuccessReasonMsg, actual[0].Check.Output)
+		require.Equal(r, "2.2.2.2", actual[1].Service.Address)
+		require.Equal(r, consulapi.HealthCritical, actual[1].Check.Status)
+		require.Equal(r, kubernetesTerminatingReasonMsg, actual[1].Check.Output)
+	})
+}
+
+// Test that endpoint slices are tracked individually so that deleting one
+// slice only removes the registrations for its endpoints.
+func TestServiceResource_clusterIP_deleteEndpointSlice(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+	syncer := newTestSyncer()
+	serviceResource := defaultServiceResource(client, syncer)
+	serviceResource.ClusterIPSync = true
+
+	// Start the controller
+	closer := controller.TestControllerRun(&serviceResource)
+	defer closer()
+
+	// Insert the service
+	svc := clusterIPService("foo", metav1.NamespaceDefault)
+	_, err := client.CoreV1().Services(metav1.NamespaceDefault).Create(context.Background(), svc, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// Insert the endpoint slices
+	createEndpoints(t, client, "foo", metav1.NamespaceDefault)
+
+	retry.Run(t, func(r *retry.R) {
+		syncer.Lock()
+		defer syncer.Unlock()
+		require.Len(r, syncer.Registrations, 2)
+	})
+
+	// Delete one of the endpoint slices
+	err = client.DiscoveryV1().EndpointSlices(metav1.NamespaceDefault).Delete(context.Background(), "foo-1", metav1.DeleteOptions{})
+	require.NoError(t, err)
+
+	// Verify what we got
+	retry.Run(t, func(r *retry.R) {
+		syncer.Lock()
+		defer syncer.Unlock()
+		actual := syncer.Registrations
+		require.Len(r, actual, 1)
+		require.Equal(r, "2.2.2.2", actual[0].Service.Address)
+	})
+}
+
+// Test that a change to one endpoint slice only regenerates the registrations
+// of that slice, and that a change that doesn't affect any instance is ignored.
+func TestServiceResource_updateEndpointRegistrations(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+	serviceResource := defaultServiceResource(client, newTestSyncer())
+	serviceResource.ClusterIPSync = true
+
+	key := "default/foo"
+	newSlice := func(name, address string) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "foo"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				{
+					Addresses:  []string{address},
+					Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+				},
+			},
+		}
+	}
+	serviceResource.serviceMap = map[string]*corev1.Service{key: clusterIPService("foo", metav1.NamespaceDefault)}
+	serviceResource.endpointSlicesMap = map[string]map[string]*discoveryv1.EndpointSlice{
+		key: {
+			"default/foo-1": newSlice("foo-1", "1.1.1.1"),
+			"default/foo-2": newSlice("foo-2", "2.2.2.2"),
+		},
+	}
+	serviceResource.generateRegistrations(key)
+	require.Len(t, serviceResource.consulMap[key], 2)
+	unchangedRegistration := serviceResource.endpointRegistrationsMap[key]["default/foo-2"][0]
+
+	// An update to a slice that doesn't change its endpoints doesn't change
+	// the registrations.
+	serviceResource.endpointSlicesMap[key]["default/foo-1"] = newSlice("foo-1", "1.1.1.1")
+	require.False(t, serviceResource.updateEndpointRegistrations(key, "default/foo-1"))
+
+	// Only the registrations of the updated slice are regenerated.
+	serviceResource.endpointSlicesMap[key]["default/foo-1"] = newSlice("foo-1", "3.3.3.3")
+	require.True(t, serviceResource.updateEndpointRegistrations(key, "default/foo-1"))
+	require.Len(t, serviceResource.consulMap[key], 2)
+	require.Equal(t, "3.3.3.3", serviceResource.consulMap[key][0].Service.Address)
+	require.Same(t, unchangedRegistration, serviceResource.consulMap[key][1])
+
+	// Removing a slice only removes its registrations.
+	delete(serviceResource.endpointSlicesMap[key], "default/foo-1")
+	require.True(t, serviceResource.updateEndpointRegistrations(key, "default/foo-1"))
+	require.Len(t, serviceResource.consulMap[key], 1)
+	require.Same(t, unchangedRegistration, serviceResource.consulMap[key][0])
+}
+
+func TestDiffRegistrations(t *testing.T) {
+	t.Parallel()
+	registration := func(id, address string) *consulapi.CatalogRegistration {
+		return &consulapi.CatalogRegistration{Service: &consulapi.AgentService{ID: id, Address: address}}
+	}
+
+	changed, removed := diffRegistrations(
+		[]*consulapi.CatalogRegistration{registration("a", "1.1.1.1"), registration("b", "2.2.2.2"), registration("c", "3.3.3.3")},
+		[]*consulapi.CatalogRegistration{registration("a", "1.1.1.1"), registration("b", "4.4.4.4"), registration("d", "5.5.5.5")},
+	)
+	require.Equal(t, []string{"b", "d"}, changed)
+	require.Equal(t, []string{"c"}, removed)
+}
+
 // Test clusterIP with prefix.
 func TestServiceResource_clusterIPPrefix(t *testing.T) {
 	t.Parallel()
@@ -2066,39 +2232,46 @@ func createNodes(t *testing.T, client *fake.Clientset) (*corev1.Node, *corev1.No
 	return node1, node2
 }
 
-// createEndpoints calls the fake k8s client to create two endpoints on two nodes.
+// createEndpoints calls the fake k8s client to create two endpoints on two nodes,
+// each in its own endpoint slice.
 func createEndpoints(t *testing.T, client *fake.Clientset, serviceName string, namespace string) {
 	node1 := nodeName1
 	node2 := nodeName2
 	targetRef := corev1.ObjectReference{Kind: "pod", Name: "foobar"}
-	_, err := client.CoreV1().Endpoints(namespace).Create(
+
+	createEndpointSlice(t, client, serviceName, namespace, fmt.Sprintf("%s-1", serviceName), []discoveryv1.Endpoint{
+		{
+			Addresses:  []string{"1.1.1.1"},
+			Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+			NodeName:   &node1,
+			TargetRef:  &targetRef,
+		},
+	})
+	createEndpointSlice(t, client, serviceName, namespace, fmt.Sprintf("%s-2", serviceName), []discoveryv1.Endpoint{
+		{
+			Addresses:  []string{"2.2.2.2"},
+			Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+			NodeName:   &node2,
+		},
+	})
+}
+
+// createEndpointSlice calls the fake k8s client to create an endpoint slice
+// for the service with the given endpoints.
+func createEndpointSlice(t *testing.T, client *fake.Clientset, serviceName, namespace, name string, endpoints []discoveryv1.Endpoint) {
+	_, err := client.DiscoveryV1().EndpointSlices(namespace).Create(
 		context.Background(),
-		&corev1.Endpoints{
+		&discoveryv1.EndpointSlice{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      serviceName,
+				Name:      name,
 				Namespace: namespace,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: serviceName},
 			},
-
-			Subsets: []corev1.EndpointSubset{
-				{
-					Addresses: []corev1.EndpointAddress{
-						{NodeName: &node1, IP: "1.1.1.1", TargetRef: &targetRef},
-					},
-					Ports: []corev1.EndpointPort{
-						{Name: "http", Port: 8080},
-						{Name: "rpc", Port: 2000},
-					},
-				},
-
-				{
-					Addresses: []corev1.EndpointAddress{
-						{NodeName: &node2, IP: "2.2.2.2"},
-					},
-					Ports: []corev1.EndpointPort{
-						{Name: "http", Port: 8080},
-						{Name: "rpc", Port: 2000},
-					},
-				},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints:   endpoints,
+			Ports: []discoveryv1.EndpointPort{
+				{Name: pointer.String("http"), Port: pointer.Int32(8080)},
+				{Name: pointer.String("rpc"), Port: pointer.Int32(2000)},
 			},
 		},
 		metav1.CreateOptions{})
@@ -11,10 +11,12 @@ import (
 
 	"github.com/cenkalti/backoff"
 	mapset "github.com/deckarep/golang-set"
+	"github.com/hashicorp/consul-k8s/control-plane/catalog/metrics"
 	"github.com/hashicorp/consul-k8s/control-plane/consul"
 	"github.com/hashicorp/consul-k8s/control-plane/namespaces"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/go-hclog"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -78,6 +80,15 @@ type ConsulSyncer struct {
 	// The Consul node name to register services with.
 	ConsulNodeName string
 
+	// DryRun disables all writes to Consul. Instead, the registrations and
+	// deregistrations that would be made are logged and recorded in a
+	// SyncPlan that is returned by Plan.
+	DryRun bool
+
+	// Metrics records the metrics of the sync. If it is nil, metrics.Default
+	// is used.
+	Metrics *metrics.Metrics
+
 	lock sync.Mutex
 	once sync.Once
 
@@ -98,6 +109,14 @@ type ConsulSyncer struct {
 	namespaces map[string]map[string]*api.CatalogRegistration
 	deregs     map[string]*api.CatalogDeregistration
 
+	// reaps is the set of service IDs in deregs that were scheduled because
+	// their service is no longer synced at all, as opposed to a single
+	// instance of a synced service being stale. It's only used for the plan.
+	reaps map[string]struct{}
+
+	// plan is the latest SyncPlan computed if DryRun is true.
+	plan SyncPlan
+
 	// watchers is all namespaces mapped to a map of Consul service
 	// names mapped to a cancel function for watcher routines
 	watchers map[string]map[string]context.CancelFunc
@@ -133,6 +152,12 @@ func (s *ConsulSyncer) Sync(rs []*api.CatalogRegistration) {
 		s.Log.Debug("[Sync] adding service to namespaces map", "service", r.Service)
 	}
 
+	var synced int
+	for _, names := range s.serviceNames {
+		synced += names.Cardinality()
+	}
+	s.Metrics.OrDefault().SyncedServices.WithLabelValues(metrics.DirectionToConsul).Set(float64(synced))
+
 	// Signal that the initial sync is complete and our maps have been populated.
 	// We can now safely reap untracked services.
 	s.initialSyncOnce.Do(func() { close(s.initialSync) })
@@ -204,6 +229,7 @@ func (s *ConsulSyncer) watchReapableServices(ctx context.Context) {
 		}, backoff.WithContext(backoff.NewExponentialBackOff(), ctx))
 
 		if err != nil {
+			s.Metrics.OrDefault().WatchErrors.WithLabelValues(metrics.DirectionToConsul, "reapable-services").Inc()
 			s.Log.Warn("error querying services, will retry", "err", err)
 		} else {
 			s.Log.Debug("[watchReapableServices] services returned from catalog",
@@ -250,6 +276,7 @@ func (s *ConsulSyncer) watchReapableServices(ctx context.Context) {
 			s.Log.Info("invalid service found, scheduling for delete",
 				"service-name", service.Service, "service-id", service.ID, "service-consul-namespace", svcNs)
 			if err = s.scheduleReapServiceLocked(service.Service, svcNs); err != nil {
+				s.Metrics.OrDefault().WatchErrors.WithLabelValues(metrics.DirectionToConsul, "reap").Inc()
 				s.Log.Info("error querying service for delete",
 					"service-name", service.Service,
 					"service-consul-namespace", svcNs,
@@ -299,6 +326,7 @@ func (s *ConsulSyncer) watchService(ctx context.Context, name, namespace string)
 			return err
 		}, backoff.WithContext(backoff.NewExponentialBackOff(), ctx))
 		if err != nil {
+			s.Metrics.OrDefault().WatchErrors.WithLabelValues(metrics.DirectionToConsul, "service").Inc()
 			s.Log.Warn("error querying service, will retry",
 				"service-name", name,
 				"service-namespace", namespace, // will be "" if namespaces aren't enabled
@@ -369,6 +397,7 @@ func (s *ConsulSyncer) scheduleReapServiceLocked(name, namespace string) error {
 		if s.EnableNamespaces {
 			s.deregs[svc.ServiceID].Namespace = namespace
 		}
+		s.reaps[svc.ServiceID] = struct{}{}
 		s.Log.Debug("[scheduleReapServiceLocked] service being scheduled for deregistration",
 			"namespace", namespace,
 			"service name", svc.ServiceName,
@@ -383,6 +412,8 @@ func (s *ConsulSyncer) scheduleReapServiceLocked(name, namespace string) error {
 // calls to sync the data with Consul. This may also start background
 // watchers for specific services.
 func (s *ConsulSyncer) syncFull(ctx context.Context) {
+	defer prometheus.NewTimer(s.Metrics.OrDefault().SyncFullDuration).ObserveDuration()
+
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -427,13 +458,24 @@ func (s *ConsulSyncer) syncFull(ctx context.Context) {
 		}
 	}
 
+	// In dry-run mode we only record what would be written.
+	if s.DryRun {
+		s.planLocked(consulClient)
+		return
+	}
+
 	// Do all deregistrations first.
-	for _, r := range s.deregs {
+	for id, r := range s.deregs {
 		s.Log.Info("deregistering service",
 			"node-name", r.Node,
 			"service-id", r.ServiceID,
 			"service-consul-namespace", r.Namespace)
 		_, err = consulClient.Catalog().Deregister(r, nil)
+		if _, ok := s.reaps[id]; ok {
+			s.Metrics.OrDefault().Reaps.WithLabelValues(metrics.Result(err)).Inc()
+		} else {
+			s.Metrics.OrDefault().Deregistrations.WithLabelValues(metrics.Result(err)).Inc()
+		}
 		if err != nil {
 			s.Log.Warn("error deregistering service",
 				"node-name", r.Node,
@@ -445,6 +487,7 @@ func (s *ConsulSyncer) syncFull(ctx context.Context) {
 
 	// Always clear deregistrations, they'll repopulate if we had errors
 	s.deregs = make(map[string]*api.CatalogDeregistration)
+	s.reaps = make(map[string]struct{})
 
 	// Register all the services. This will overwrite any changes that
 	// may have been made to the registered services.
@@ -464,6 +507,7 @@ func (s *ConsulSyncer) syncFull(ctx context.Context) {
 
 			// Register the service.
 			_, err = consulClient.Catalog().Register(r, nil)
+			s.Metrics.OrDefault().Registrations.WithLabelValues(metrics.Result(err)).Inc()
 			if err != nil {
 				s.Log.Warn("error registering service",
 					"node-name", r.Node,
@@ -494,6 +538,9 @@ func (s *ConsulSyncer) init() {
 	if s.deregs == nil {
 		s.deregs = make(map[string]*api.CatalogDeregistration)
 	}
+	if s.reaps == nil {
+		s.reaps = make(map[string]struct{})
+	}
 	if s.watchers == nil {
 		s.watchers = make(map[string]map[string]context.CancelFunc)
 	}
@@ -13,11 +13,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/consul-k8s/control-plane/catalog/metrics"
 	"github.com/hashicorp/consul-k8s/control-plane/consul"
 	"github.com/hashicorp/consul-k8s/control-plane/helper/test"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/go-hclog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -62,6 +65,118 @@ func TestConsulSyncer_register(t *testing.T) {
 	require.Equal(t, "127.0.0.1", service.Address)
 }
 
+// Test that a full sync records its metrics.
+func TestConsulSyncer_metrics(t *testing.T) {
+	t.Parallel()
+
+	testClient := test.TestServerWithMockConnMgrWatcher(t, nil)
+	client := testClient.APIClient
+
+	// Don't run the syncer so that exactly one full sync is done.
+	m := metrics.New(prometheus.NewRegistry())
+	s := &ConsulSyncer{
+		ConsulClientConfig:  testClient.Cfg,
+		ConsulServerConnMgr: testClient.Watcher,
+		Log:                 hclog.Default(),
+		ConsulK8STag:        TestConsulK8STag,
+		ConsulNodeName:      ConsulSyncNodeName,
+		Metrics:             m,
+	}
+	s.init()
+	s.Sync([]*api.CatalogRegistration{
+		testRegistration(ConsulSyncNodeName, "bar", "default"),
+		testRegistration(ConsulSyncNodeName, "baz", "default"),
+	})
+	require.Equal(t, float64(2), testutil.ToFloat64(m.SyncedServices.WithLabelValues(metrics.DirectionToConsul)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.syncFull(ctx)
+
+	services, _, err := client.Catalog().Service("bar", "", nil)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+
+	require.Equal(t, float64(2), testutil.ToFloat64(m.Registrations.WithLabelValues(metrics.ResultSuccess)))
+	require.Equal(t, float64(0), testutil.ToFloat64(m.Registrations.WithLabelValues(metrics.ResultError)))
+	require.Equal(t, float64(0), testutil.ToFloat64(m.Deregistrations.WithLabelValues(metrics.ResultSuccess)))
+}
+
+// Test that in dry-run mode the syncer plans registrations without
+// writing them to Consul.
+func TestConsulSyncer_dryRun(t *testing.T) {
+	t.Parallel()
+
+	testClient := test.TestServerWithMockConnMgrWatcher(t, nil)
+	client := testClient.APIClient
+
+	s, closer := testConsulSyncerWithConfig(testClient, func(s *ConsulSyncer) {
+		s.DryRun = true
+	})
+	defer closer()
+
+	s.Sync([]*api.CatalogRegistration{
+		testRegistration(ConsulSyncNodeName, "bar", "default"),
+	})
+
+	retry.Run(t, func(r *retry.R) {
+		plan := s.Plan()
+		require.Equal(r, []PlannedService{
+			{
+				Node:        ConsulSyncNodeName,
+				ServiceID:   serviceID(ConsulSyncNodeName, "bar"),
+				ServiceName: "bar",
+			},
+		}, plan.Register)
+	})
+
+	// Wait for another sync period and verify that nothing was registered.
+	time.Sleep(500 * time.Millisecond)
+	services, _, err := client.Catalog().Service("bar", "", nil)
+	require.NoError(t, err)
+	require.Empty(t, services)
+}
+
+// Test that the dry-run plan lists registrations that differ from Consul as
+// updates.
+func TestConsulSyncer_dryRunUpdate(t *testing.T) {
+	t.Parallel()
+
+	testClient := test.TestServerWithMockConnMgrWatcher(t, nil)
+	client := testClient.APIClient
+
+	_, err := client.Catalog().Register(testRegistration(ConsulSyncNodeName, "bar", "default"), nil)
+	require.NoError(t, err)
+
+	s, closer := testConsulSyncerWithConfig(testClient, func(s *ConsulSyncer) {
+		s.DryRun = true
+	})
+	defer closer()
+
+	reg := testRegistration(ConsulSyncNodeName, "bar", "default")
+	reg.Service.Port = 8080
+	s.Sync([]*api.CatalogRegistration{reg})
+
+	retry.Run(t, func(r *retry.R) {
+		plan := s.Plan()
+		require.Empty(r, plan.Register)
+		require.Equal(r, []PlannedService{
+			{
+				Node:        ConsulSyncNodeName,
+				ServiceID:   serviceID(ConsulSyncNodeName, "bar"),
+				ServiceName: "bar",
+				Changes:     []string{"port"},
+			},
+		}, plan.Update)
+	})
+
+	// Verify that the service in Consul was not updated.
+	services, _, err := client.Catalog().Service("bar", "", nil)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	require.Equal(t, 0, services[0].ServicePort)
+}
+
 // Test that the syncer reaps individual invalid service instances.
 func TestConsulSyncer_reapServiceInstance(t *testing.T) {
 	t.Parallel()
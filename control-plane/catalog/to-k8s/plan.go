@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package catalog
+
+import (
+	"reflect"
+	"sort"
+
+	apiv1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+// SinkPlan is the set of changes that the K8SSink would make to Kubernetes.
+// It is only computed if the K8SSink runs in dry-run mode.
+type SinkPlan struct {
+	// Namespace is the Kubernetes namespace the changes would be made in.
+	Namespace string `json:"namespace"`
+
+	CreateServices []string `json:"createServices"`
+	UpdateServices []string `json:"updateServices"`
+	DeleteServices []string `json:"deleteServices"`
+
+	// The EndpointSlice changes are only planned if SyncEndpoints is true.
+	CreateEndpointSlices []string `json:"createEndpointSlices,omitempty"`
+	UpdateEndpointSlices []string `json:"updateEndpointSlices,omitempty"`
+	DeleteEndpointSlices []string `json:"deleteEndpointSlices,omitempty"`
+}
+
+// Plan returns the latest SinkPlan. It is empty unless DryRun is true.
+func (s *K8SSink) Plan() SinkPlan {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	plan := s.plan
+	plan.Namespace = s.namespace()
+	return plan
+}
+
+// planServices records the services that would be created, updated, and
+// deleted, and logs them if they changed since the last time.
+func (s *K8SSink) planServices(create, update []*apiv1.Service, delete []string) {
+	createNames := serviceNames(create)
+	updateNames := serviceNames(update)
+	deleteNames := append([]string{}, delete...)
+	sort.Strings(deleteNames)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if reflect.DeepEqual(createNames, s.plan.CreateServices) &&
+		reflect.DeepEqual(updateNames, s.plan.UpdateServices) &&
+		reflect.DeepEqual(deleteNames, s.plan.DeleteServices) {
+		return
+	}
+	s.plan.CreateServices = createNames
+	s.plan.UpdateServices = updateNames
+	s.plan.DeleteServices = deleteNames
+
+	s.Log.Info("[dry-run] service plan changed", "create", len(createNames),
+		"update", len(updateNames), "delete", len(deleteNames))
+	for _, name := range createNames {
+		s.Log.Info("[dry-run] would create service", "name", name)
+	}
+	for _, name := range updateNames {
+		s.Log.Info("[dry-run] would update service", "name", name)
+	}
+	for _, name := range deleteNames {
+		s.Log.Info("[dry-run] would delete service", "name", name)
+	}
+}
+
+// planEndpointSlices records the EndpointSlices that would be created,
+// updated, and deleted, and logs them if they changed since the last time.
+func (s *K8SSink) planEndpointSlices(create, update []*discoveryv1.EndpointSlice, remove []string) {
+	createNames := endpointSliceNames(create)
+	updateNames := endpointSliceNames(update)
+	removeNames := append([]string{}, remove...)
+	sort.Strings(removeNames)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if reflect.DeepEqual(createNames, s.plan.CreateEndpointSlices) &&
+		reflect.DeepEqual(updateNames, s.plan.UpdateEndpointSlices) &&
+		reflect.DeepEqual(removeNames, s.plan.DeleteEndpointSlices) {
+		return
+	}
+	s.plan.CreateEndpointSlices = createNames
+	s.plan.UpdateEndpointSlices = updateNames
+	s.plan.DeleteEndpointSlices = removeNames
+
+	s.Log.Info("[dry-run] endpoint slice plan changed", "create", len(createNames),
+		"update", len(updateNames), "delete", len(removeNames))
+	for _, name := range createNames {
+		s.Log.Info("[dry-run] would create endpoint slice", "name", name)
+	}
+	for _, name := range updateNames {
+		s.Log.Info("[dry-run] would update endpoint slice", "name", name)
+	}
+	for _, name := range removeNames {
+		s.Log.Info("[dry-run] would delete endpoint slice", "name", name)
+	}
+}
+
+// serviceNames returns the sorted names of the services.
+func serviceNames(services []*apiv1.Service) []string {
+	names := make([]string, 0, len(services))
+	for _, svc := range services {
+		names = append(names, svc.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// endpointSliceNames returns the sorted names of the EndpointSlices.
+func endpointSliceNames(slices []*discoveryv1.EndpointSlice) []string {
+	names := make([]string, 0, len(slices))
+	for _, slice := range slices {
+		names = append(names, slice.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -5,18 +5,25 @@ package catalog
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/hashicorp/consul-k8s/control-plane/catalog/metrics"
 	"github.com/hashicorp/consul-k8s/control-plane/helper/coalesce"
 	"github.com/hashicorp/go-hclog"
 	apiv1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
 )
 
 const (
@@ -26,6 +33,15 @@ const (
 	// K8SMaxPeriod is the maximum time to wait before forcing a sync, even
 	// if there are active changes going on.
 	K8SMaxPeriod = 5 * time.Second
+
+	// endpointSliceManagedBy is the value of the managed-by label on the
+	// EndpointSlices created by the sink.
+	endpointSliceManagedBy = "sync-catalog.consul.hashicorp.com"
+
+	// maxEndpointsPerSlice is the maximum number of endpoints in each
+	// EndpointSlice created by the sink. It matches the default of the
+	// Kubernetes EndpointSlice controller.
+	maxEndpointsPerSlice = 100
 )
 
 // Sink is the destination where services are registered.
@@ -37,6 +53,17 @@ type Sink interface {
 	// The key is the service name and the destination is the external DNS
 	// entry to point to.
 	SetServices(map[string]string)
+
+	// SetServiceInstances is called with the passing instances of each
+	// service. The key is the service name as given to SetServices.
+	SetServiceInstances(map[string][]ServiceInstance)
+}
+
+// ServiceInstance is the address and port of a passing instance of a
+// Consul service.
+type ServiceInstance struct {
+	Address string
+	Port    int
 }
 
 // K8SSink is a Sink implementation that registers services with Kubernetes.
@@ -54,6 +81,21 @@ type K8SSink struct {
 	// done if there are no changes.
 	SyncPeriod time.Duration
 
+	// SyncEndpoints creates headless Services without a selector instead of
+	// ExternalName Services, along with EndpointSlices that hold the
+	// addresses and ports of the passing instances of each Consul service.
+	// This lets pods resolve the services without going through Consul DNS.
+	SyncEndpoints bool
+
+	// DryRun disables all writes to Kubernetes. Instead, the changes that
+	// would be made are logged and recorded in a SinkPlan that is returned
+	// by Plan.
+	DryRun bool
+
+	// Metrics records the metrics of the sync. If it is nil, metrics.Default
+	// is used.
+	Metrics *metrics.Metrics
+
 	// Ctx is used to cancel the Sink.
 	Ctx context.Context
 
@@ -67,6 +109,11 @@ type K8SSink struct {
 	// because Kube names must be lowercase.
 	sourceServices map[string]string
 
+	// sourceInstances maps from Consul service names to the passing instances
+	// of the service. The service names are lowercased in the same way as
+	// sourceServices. It's only used if SyncEndpoints is true.
+	sourceInstances map[string][]ServiceInstance
+
 	// keyToName maps from Kube controller keys to Kube service names.
 	// Controller keys are in the form <kube namespace>/<kube svc name>
 	// e.g. default/foo, and are the keys Kube uses to inform that something
@@ -83,6 +130,9 @@ type K8SSink struct {
 	// It's populated from Kubernetes data.
 	serviceMapConsul map[string]*apiv1.Service
 	triggerCh        chan struct{}
+
+	// plan is the latest SinkPlan computed if DryRun is true.
+	plan SinkPlan
 }
 
 // SetServices implements Sink.
@@ -105,6 +155,23 @@ func (s *K8SSink) SetServices(svcs map[string]string) {
 	s.trigger() // Any service change probably requires syncing
 }
 
+// SetServiceInstances implements Sink.
+func (s *K8SSink) SetServiceInstances(instances map[string][]ServiceInstance) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Lowercase the service names so that they match sourceServices.
+	lowercasedInstances := make(map[string][]ServiceInstance)
+	for consulName, svcInstances := range instances {
+		lowercasedInstances[strings.ToLower(consulName)] = svcInstances
+	}
+
+	s.sourceInstances = lowercasedInstances
+	if s.SyncEndpoints {
+		s.trigger()
+	}
+}
+
 // Informer implements the controller.Resource interface.
 // It tells Kubernetes that we want to watch for changes to Services.
 func (s *K8SSink) Informer() cache.SharedIndexInformer {
@@ -228,15 +295,27 @@ func (s *K8SSink) Run(ch <-chan struct{}) {
 		s.lock.Unlock()
 		s.Log.Debug("sync triggered", "create", len(create), "update", len(update), "delete", len(delete))
 
+		// In dry-run mode we only record what would be written.
+		if s.DryRun {
+			s.planServices(create, update, delete)
+			if s.SyncEndpoints {
+				s.syncEndpointSlices()
+			}
+			continue
+		}
+
 		svcClient := s.Client.CoreV1().Services(s.namespace())
 		for _, name := range delete {
-			if err := svcClient.Delete(s.Ctx, name, metav1.DeleteOptions{}); err != nil {
+			err := svcClient.Delete(s.Ctx, name, metav1.DeleteOptions{})
+			s.Metrics.OrDefault().K8SWrites.WithLabelValues("service", "delete", metrics.Result(err)).Inc()
+			if err != nil {
 				s.Log.Warn("error deleting service", "name", name, "error", err)
 			}
 		}
 
 		for _, svc := range update {
 			_, err := svcClient.Update(s.Ctx, svc, metav1.UpdateOptions{})
+			s.Metrics.OrDefault().K8SWrites.WithLabelValues("service", "update", metrics.Result(err)).Inc()
 			if err != nil {
 				s.Log.Warn("error updating service", "name", svc.Name, "error", err)
 			}
@@ -244,10 +323,62 @@ func (s *K8SSink) Run(ch <-chan struct{}) {
 
 		for _, svc := range create {
 			_, err := svcClient.Create(s.Ctx, svc, metav1.CreateOptions{})
+			s.Metrics.OrDefault().K8SWrites.WithLabelValues("service", "create", metrics.Result(err)).Inc()
 			if err != nil {
 				s.Log.Warn("error creating service", "name", svc.Name, "error", err)
 			}
 		}
+
+		if s.SyncEndpoints {
+			s.syncEndpointSlices()
+		}
+	}
+}
+
+// syncEndpointSlices creates, updates, and deletes the EndpointSlices of the
+// synced services so that they hold the passing Consul service instances.
+func (s *K8SSink) syncEndpointSlices() {
+	sliceClient := s.Client.DiscoveryV1().EndpointSlices(s.namespace())
+	list, err := sliceClient.List(s.Ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelManagedBy, endpointSliceManagedBy),
+	})
+	if err != nil {
+		s.Log.Warn("error listing endpoint slices", "error", err)
+		return
+	}
+
+	s.lock.Lock()
+	create, update, remove := s.endpointSliceCrudList(list.Items)
+	s.lock.Unlock()
+	s.Log.Debug("endpoint slice sync triggered", "create", len(create), "update", len(update), "delete", len(remove))
+
+	if s.DryRun {
+		s.planEndpointSlices(create, update, remove)
+		return
+	}
+
+	for _, name := range remove {
+		err := sliceClient.Delete(s.Ctx, name, metav1.DeleteOptions{})
+		s.Metrics.OrDefault().K8SWrites.WithLabelValues("endpointslice", "delete", metrics.Result(err)).Inc()
+		if err != nil {
+			s.Log.Warn("error deleting endpoint slice", "name", name, "error", err)
+		}
+	}
+
+	for _, slice := range update {
+		_, err := sliceClient.Update(s.Ctx, slice, metav1.UpdateOptions{})
+		s.Metrics.OrDefault().K8SWrites.WithLabelValues("endpointslice", "update", metrics.Result(err)).Inc()
+		if err != nil {
+			s.Log.Warn("error updating endpoint slice", "name", slice.Name, "error", err)
+		}
+	}
+
+	for _, slice := range create {
+		_, err := sliceClient.Create(s.Ctx, slice, metav1.CreateOptions{})
+		s.Metrics.OrDefault().K8SWrites.WithLabelValues("endpointslice", "create", metrics.Result(err)).Inc()
+		if err != nil {
+			s.Log.Warn("error creating endpoint slice", "name", slice.Name, "error", err)
+		}
 	}
 }
 
@@ -259,17 +390,17 @@ func (s *K8SSink) crudList() ([]*apiv1.Service, []*apiv1.Service, []string) {
 	// Determine what needs to be created or updated
 	for consulName, consulDNS := range s.sourceServices {
 		// If this is an already registered service, then update it
+		spec := s.serviceSpec(consulName, consulDNS)
 		if s.serviceMapConsul != nil {
 			if svc, ok := s.serviceMapConsul[consulName]; ok {
-				if svc.Spec.ExternalName == consulDNS {
+				if serviceSpecMatches(svc.Spec, spec) {
 					// Matching service, no update required.
 					continue
 				}
 
-				svc.Spec = apiv1.ServiceSpec{
-					Type:         apiv1.ServiceTypeExternalName,
-					ExternalName: consulDNS,
-				}
+				// Copy the service since it's owned by the informer cache.
+				svc = svc.DeepCopy()
+				svc.Spec = spec
 
 				update = append(update, svc)
 				continue
@@ -293,10 +424,7 @@ func (s *K8SSink) crudList() ([]*apiv1.Service, []*apiv1.Service, []string) {
 				},
 			},
 
-			Spec: apiv1.ServiceSpec{
-				Type:         apiv1.ServiceTypeExternalName,
-				ExternalName: consulDNS,
-			},
+			Spec: spec,
 		})
 	}
 
@@ -310,6 +438,253 @@ func (s *K8SSink) crudList() ([]*apiv1.Service, []*apiv1.Service, []string) {
 	return create, update, delete
 }
 
+// endpointSliceCrudList returns the EndpointSlices to create, update, and
+// delete (respectively) given the EndpointSlices that were created by the sink.
+//
+// Precondition: lock must be held.
+func (s *K8SSink) endpointSliceCrudList(existing []discoveryv1.EndpointSlice) ([]*discoveryv1.EndpointSlice, []*discoveryv1.EndpointSlice, []string) {
+	var create, update []*discoveryv1.EndpointSlice
+	var remove []string
+
+	desired := make(map[string]*discoveryv1.EndpointSlice)
+	for consulName := range s.sourceServices {
+		// If this is a registered K8S service, its endpoints are managed by
+		// Kubernetes and we must not add to them.
+		if _, ok := s.serviceMap[consulName]; ok {
+			if _, ok := s.serviceMapConsul[consulName]; !ok {
+				continue
+			}
+		}
+
+		for _, slice := range endpointSlices(consulName, s.sourceInstances[consulName]) {
+			desired[slice.Name] = slice
+		}
+	}
+
+	// Determine what needs to be updated or deleted
+	for i := range existing {
+		slice := &existing[i]
+		expected, ok := desired[slice.Name]
+		if !ok || expected.AddressType != slice.AddressType {
+			// The address type of an EndpointSlice is immutable so the slice
+			// is recreated if it changes.
+			remove = append(remove, slice.Name)
+			continue
+		}
+		delete(desired, slice.Name)
+
+		if endpointSliceMatches(slice, expected) {
+			// Matching endpoint slice, no update required.
+			continue
+		}
+
+		slice.Labels = expected.Labels
+		slice.Endpoints = expected.Endpoints
+		slice.Ports = expected.Ports
+		update = append(update, slice)
+	}
+
+	// Whatever is left needs to be created
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		create = append(create, desired[name])
+	}
+
+	return create, update, remove
+}
+
+// serviceSpec returns the spec of the Kubernetes service for a Consul service.
+//
+// Precondition: lock must be held.
+func (s *K8SSink) serviceSpec(consulName, consulDNS string) apiv1.ServiceSpec {
+	if !s.SyncEndpoints {
+		return apiv1.ServiceSpec{
+			Type:         apiv1.ServiceTypeExternalName,
+			ExternalName: consulDNS,
+		}
+	}
+
+	// The Service has no selector, so Kubernetes doesn't manage its
+	// endpoints and uses the EndpointSlices we create instead. Services
+	// whose instances have no port are still resolvable through DNS
+	// since headless Services don't need any ports.
+	var ports []apiv1.ServicePort
+	for _, port := range instancePorts(s.sourceInstances[consulName]) {
+		ports = append(ports, apiv1.ServicePort{
+			Name:       endpointPortName(port),
+			Protocol:   apiv1.ProtocolTCP,
+			Port:       int32(port),
+			TargetPort: intstr.FromInt(port),
+		})
+	}
+	return apiv1.ServiceSpec{
+		Type:      apiv1.ServiceTypeClusterIP,
+		ClusterIP: apiv1.ClusterIPNone,
+		Ports:     ports,
+	}
+}
+
+// serviceSpecMatches returns true if the fields of the actual service spec
+// that are managed by the sink match the expected spec.
+func serviceSpecMatches(actual, expected apiv1.ServiceSpec) bool {
+	if actual.Type != expected.Type ||
+		actual.ExternalName != expected.ExternalName ||
+		actual.ClusterIP != expected.ClusterIP ||
+		len(actual.Ports) != len(expected.Ports) {
+		return false
+	}
+	for i := range actual.Ports {
+		if actual.Ports[i].Name != expected.Ports[i].Name ||
+			actual.Ports[i].Port != expected.Ports[i].Port ||
+			actual.Ports[i].Protocol != expected.Ports[i].Protocol {
+			return false
+		}
+	}
+	return true
+}
+
+// endpointSlices returns the EndpointSlices for the passing instances of a
+// Consul service. Instances are grouped into slices by address type and port
+// since every endpoint in a slice shares them. Each group is split into
+// slices of at most maxEndpointsPerSlice endpoints.
+func endpointSlices(consulName string, instances []ServiceInstance) []*discoveryv1.EndpointSlice {
+	type sliceKey struct {
+		addressType discoveryv1.AddressType
+		port        int
+	}
+	groups := make(map[sliceKey][]string)
+	seen := make(map[ServiceInstance]struct{})
+	for _, instance := range instances {
+		// Only IP addresses can be used for endpoints. Instances whose
+		// address is a hostname are skipped.
+		ip := net.ParseIP(instance.Address)
+		if ip == nil {
+			continue
+		}
+		if _, ok := seen[instance]; ok {
+			continue
+		}
+		seen[instance] = struct{}{}
+
+		key := sliceKey{addressType: discoveryv1.AddressTypeIPv6, port: instance.Port}
+		if ip.To4() != nil {
+			key.addressType = discoveryv1.AddressTypeIPv4
+		}
+		groups[key] = append(groups[key], instance.Address)
+	}
+
+	keys := make([]sliceKey, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].addressType != keys[j].addressType {
+			return keys[i].addressType < keys[j].addressType
+		}
+		return keys[i].port < keys[j].port
+	})
+
+	var slices []*discoveryv1.EndpointSlice
+	for _, key := range keys {
+		addresses := groups[key]
+		sort.Strings(addresses)
+
+		var ports []discoveryv1.EndpointPort
+		if key.port != 0 {
+			protocol := apiv1.ProtocolTCP
+			ports = []discoveryv1.EndpointPort{{
+				Name:     pointer.String(endpointPortName(key.port)),
+				Protocol: &protocol,
+				Port:     pointer.Int32(int32(key.port)),
+			}}
+		}
+
+		for i := 0; i < len(addresses); i += maxEndpointsPerSlice {
+			end := i + maxEndpointsPerSlice
+			if end > len(addresses) {
+				end = len(addresses)
+			}
+
+			endpoints := make([]discoveryv1.Endpoint, 0, end-i)
+			for _, address := range addresses[i:end] {
+				endpoints = append(endpoints, discoveryv1.Endpoint{
+					Addresses:  []string{address},
+					Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+				})
+			}
+
+			slices = append(slices, &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("%s-%s-%d-%d",
+						consulName, strings.ToLower(string(key.addressType)), key.port, i/maxEndpointsPerSlice),
+					Labels: map[string]string{
+						"consul":                     "true",
+						discoveryv1.LabelServiceName: consulName,
+						discoveryv1.LabelManagedBy:   endpointSliceManagedBy,
+					},
+				},
+				AddressType: key.addressType,
+				Endpoints:   endpoints,
+				Ports:       ports,
+			})
+		}
+	}
+	return slices
+}
+
+// endpointSliceMatches returns true if the fields of the actual EndpointSlice
+// that are managed by the sink match the expected EndpointSlice.
+func endpointSliceMatches(actual, expected *discoveryv1.EndpointSlice) bool {
+	for k, v := range expected.Labels {
+		if actual.Labels[k] != v {
+			return false
+		}
+	}
+	if len(actual.Endpoints) != len(expected.Endpoints) || len(actual.Ports) != len(expected.Ports) {
+		return false
+	}
+	for i := range actual.Endpoints {
+		if len(actual.Endpoints[i].Addresses) != 1 ||
+			actual.Endpoints[i].Addresses[0] != expected.Endpoints[i].Addresses[0] ||
+			!pointer.BoolDeref(actual.Endpoints[i].Conditions.Ready, true) {
+			return false
+		}
+	}
+	for i := range actual.Ports {
+		if pointer.StringDeref(actual.Ports[i].Name, "") != pointer.StringDeref(expected.Ports[i].Name, "") ||
+			pointer.Int32Deref(actual.Ports[i].Port, 0) != pointer.Int32Deref(expected.Ports[i].Port, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// instancePorts returns the sorted unique non-zero ports of the instances.
+func instancePorts(instances []ServiceInstance) []int {
+	seen := make(map[int]struct{})
+	var ports []int
+	for _, instance := range instances {
+		if _, ok := seen[instance.Port]; ok || instance.Port == 0 {
+			continue
+		}
+		seen[instance.Port] = struct{}{}
+		ports = append(ports, instance.Port)
+	}
+	sort.Ints(ports)
+	return ports
+}
+
+// endpointPortName returns the name of the Service and EndpointSlice port
+// for a Consul service port. Both names must match for Kubernetes to
+// associate the endpoints with the Service port.
+func endpointPortName(port int) string {
+	return fmt.Sprintf("port-%d", port)
+}
+
 // namespace returns the K8S namespace to setup the resource watchers in.
 func (s *K8SSink) namespace() string {
 	if s.Namespace != "" {
@@ -5,6 +5,7 @@ package catalog
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/consul-k8s/control-plane/helper/controller"
@@ -12,9 +13,11 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/require"
 	apiv1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
 )
 
 func init() {
@@ -396,6 +399,247 @@ func TestK8SSink_deleteReconcileLocal(t *testing.T) {
 	})
 }
 
+// Test that services are created as headless services with endpoint slices
+// when syncing endpoints.
+func TestK8SSink_createSyncEndpoints(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+
+	// Start the controller
+	sink := &K8SSink{
+		Client:        client,
+		Log:           hclog.Default(),
+		Ctx:           context.Background(),
+		SyncEndpoints: true,
+	}
+	closer := controller.TestControllerRun(sink)
+	defer closer()
+
+	// Set a service with its instances
+	sink.SetServices(map[string]string{"web": "web.service.local."})
+	sink.SetServiceInstances(map[string][]ServiceInstance{
+		"web": {
+			{Address: "1.1.1.1", Port: 8080},
+			{Address: "2.2.2.2", Port: 8080},
+		},
+	})
+
+	// Verify the headless service gets registered
+	retry.Run(t, func(r *retry.R) {
+		svc, err := client.CoreV1().Services(metav1.NamespaceDefault).Get(context.Background(), "web", metav1.GetOptions{})
+		require.NoError(r, err)
+		require.Equal(r, apiv1.ServiceTypeClusterIP, svc.Spec.Type)
+		require.Equal(r, apiv1.ClusterIPNone, svc.Spec.ClusterIP)
+		require.Empty(r, svc.Spec.Selector)
+		require.Len(r, svc.Spec.Ports, 1)
+		require.Equal(r, "port-8080", svc.Spec.Ports[0].Name)
+		require.Equal(r, int32(8080), svc.Spec.Ports[0].Port)
+
+		slice, err := client.DiscoveryV1().EndpointSlices(metav1.NamespaceDefault).Get(context.Background(), "web-ipv4-8080-0", metav1.GetOptions{})
+		require.NoError(r, err)
+		require.Equal(r, "web", slice.Labels[discoveryv1.LabelServiceName])
+		require.Len(r, slice.Endpoints, 2)
+		require.Equal(r, []string{"1.1.1.1"}, slice.Endpoints[0].Addresses)
+		require.Equal(r, []string{"2.2.2.2"}, slice.Endpoints[1].Addresses)
+	})
+
+	// Update the instances
+	sink.SetServiceInstances(map[string][]ServiceInstance{
+		"web": {
+			{Address: "2.2.2.2", Port: 8080},
+		},
+	})
+
+	// Verify the endpoint slice gets updated
+	retry.Run(t, func(r *retry.R) {
+		slice, err := client.DiscoveryV1().EndpointSlices(metav1.NamespaceDefault).Get(context.Background(), "web-ipv4-8080-0", metav1.GetOptions{})
+		require.NoError(r, err)
+		require.Len(r, slice.Endpoints, 1)
+		require.Equal(r, []string{"2.2.2.2"}, slice.Endpoints[0].Addresses)
+	})
+
+	// Clear
+	sink.SetServices(map[string]string{})
+	sink.SetServiceInstances(map[string][]ServiceInstance{})
+
+	// Verify the service and endpoint slices get cleared
+	retry.Run(t, func(r *retry.R) {
+		svcs, err := client.CoreV1().Services(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+		require.NoError(r, err)
+		require.Empty(r, svcs.Items)
+
+		slices, err := client.DiscoveryV1().EndpointSlices(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+		require.NoError(r, err)
+		require.Empty(r, slices.Items)
+	})
+}
+
+// Test that no endpoint slices are created for services that already
+// exist in Kubernetes.
+func TestK8SSink_createSyncEndpointsExists(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+
+	// Create the existing service
+	_, err := client.CoreV1().Services(metav1.NamespaceDefault).Create(
+		context.Background(),
+		&apiv1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "web",
+			},
+
+			Spec: apiv1.ServiceSpec{
+				Selector: map[string]string{"app": "web"},
+			},
+		},
+		metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// Start the controller
+	sink := &K8SSink{
+		Client:        client,
+		Log:           hclog.Default(),
+		Ctx:           context.Background(),
+		SyncEndpoints: true,
+	}
+	closer := controller.TestControllerRun(sink)
+	defer closer()
+
+	// Set services with their instances
+	sink.SetServices(map[string]string{
+		"web": "web.service.local.",
+		"api": "api.service.local.",
+	})
+	sink.SetServiceInstances(map[string][]ServiceInstance{
+		"web": {{Address: "1.1.1.1", Port: 8080}},
+		"api": {{Address: "2.2.2.2", Port: 9090}},
+	})
+
+	// Verify only the endpoint slice of the new service gets created
+	retry.Run(t, func(r *retry.R) {
+		slices, err := client.DiscoveryV1().EndpointSlices(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+		require.NoError(r, err)
+		require.Len(r, slices.Items, 1)
+		require.Equal(r, "api-ipv4-9090-0", slices.Items[0].Name)
+	})
+}
+
+// Test that in dry-run mode the sink plans the changes without making them.
+func TestK8SSink_dryRun(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+
+	// Start the controller
+	sink := &K8SSink{
+		Client:        client,
+		Log:           hclog.Default(),
+		Ctx:           context.Background(),
+		SyncEndpoints: true,
+		DryRun:        true,
+	}
+	closer := controller.TestControllerRun(sink)
+	defer closer()
+
+	// Set a service with its instances
+	sink.SetServices(map[string]string{"web": "web.service.local."})
+	sink.SetServiceInstances(map[string][]ServiceInstance{
+		"web": {{Address: "1.1.1.1", Port: 8080}},
+	})
+
+	// Verify the changes are planned
+	retry.Run(t, func(r *retry.R) {
+		plan := sink.Plan()
+		require.Equal(r, metav1.NamespaceDefault, plan.Namespace)
+		require.Equal(r, []string{"web"}, plan.CreateServices)
+		require.Equal(r, []string{"web-ipv4-8080-0"}, plan.CreateEndpointSlices)
+	})
+
+	// Verify nothing was created
+	svcs, err := client.CoreV1().Services(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, svcs.Items)
+	slices, err := client.DiscoveryV1().EndpointSlices(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, slices.Items)
+}
+
+func TestEndpointSlices(t *testing.T) {
+	t.Parallel()
+	tcp := apiv1.ProtocolTCP
+
+	cases := map[string]struct {
+		instances []ServiceInstance
+		expected  map[string][]string
+		ports     map[string][]discoveryv1.EndpointPort
+	}{
+		"no instances": {
+			instances: nil,
+			expected:  map[string][]string{},
+		},
+		"instances are grouped by address type and port": {
+			instances: []ServiceInstance{
+				{Address: "2.2.2.2", Port: 8080},
+				{Address: "1.1.1.1", Port: 8080},
+				{Address: "1.1.1.1", Port: 8080},
+				{Address: "3.3.3.3", Port: 9090},
+				{Address: "fe80::1", Port: 8080},
+				{Address: "web.example.com", Port: 8080},
+			},
+			expected: map[string][]string{
+				"web-ipv4-8080-0": {"1.1.1.1", "2.2.2.2"},
+				"web-ipv4-9090-0": {"3.3.3.3"},
+				"web-ipv6-8080-0": {"fe80::1"},
+			},
+			ports: map[string][]discoveryv1.EndpointPort{
+				"web-ipv4-8080-0": {{Name: pointer.String("port-8080"), Protocol: &tcp, Port: pointer.Int32(8080)}},
+				"web-ipv4-9090-0": {{Name: pointer.String("port-9090"), Protocol: &tcp, Port: pointer.Int32(9090)}},
+				"web-ipv6-8080-0": {{Name: pointer.String("port-8080"), Protocol: &tcp, Port: pointer.Int32(8080)}},
+			},
+		},
+		"instances without a port": {
+			instances: []ServiceInstance{
+				{Address: "1.1.1.1"},
+			},
+			expected: map[string][]string{
+				"web-ipv4-0-0": {"1.1.1.1"},
+			},
+			ports: map[string][]discoveryv1.EndpointPort{
+				"web-ipv4-0-0": nil,
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := make(map[string][]string)
+			for _, slice := range endpointSlices("web", c.instances) {
+				var addresses []string
+				for _, endpoint := range slice.Endpoints {
+					addresses = append(addresses, endpoint.Addresses...)
+				}
+				actual[slice.Name] = addresses
+				require.Equal(t, c.ports[slice.Name], slice.Ports)
+			}
+			require.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestEndpointSlices_maxEndpoints(t *testing.T) {
+	t.Parallel()
+	var instances []ServiceInstance
+	for i := 0; i < maxEndpointsPerSlice+1; i++ {
+		instances = append(instances, ServiceInstance{Address: fmt.Sprintf("10.0.%d.%d", i/256, i%256), Port: 8080})
+	}
+
+	slices := endpointSlices("web", instances)
+	require.Len(t, slices, 2)
+	require.Equal(t, "web-ipv4-8080-0", slices[0].Name)
+	require.Len(t, slices[0].Endpoints, maxEndpointsPerSlice)
+	require.Equal(t, "web-ipv4-8080-1", slices[1].Name)
+	require.Len(t, slices[1].Endpoints, 1)
+}
+
 func testSink(t *testing.T, client kubernetes.Interface) (*K8SSink, func()) {
 	sink := &K8SSink{
 		Client: client,
@@ -6,9 +6,13 @@ package catalog
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff"
+	mapset "github.com/deckarep/golang-set"
+	"github.com/hashicorp/consul-k8s/control-plane/catalog/metrics"
 	"github.com/hashicorp/consul-k8s/control-plane/consul"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/go-hclog"
@@ -26,15 +30,174 @@ type Source struct {
 	Prefix              string       // Prefix is a prefix to prepend to services
 	Log                 hclog.Logger // Logger
 	ConsulK8STag        string       // The tag value for services registered
+
+	// Filter is a Consul filter expression that selects the services to
+	// sync, e.g. `ServiceTags contains "public"` or `ServiceMeta.team == "a"`.
+	// All services are synced if it is empty.
+	Filter string
+
+	// WatchHealth enables watching the health of every Consul service. The
+	// addresses of the passing instances of each service are sent to the
+	// Sink with SetServiceInstances whenever they change.
+	WatchHealth bool
+
+	// EnableNamespaces enables syncing services from every Consul namespace
+	// that is allowed by AllowConsulNamespacesSet and DenyConsulNamespacesSet
+	// [Enterprise Only]. If false, only the services in the namespace of the
+	// Consul API client are synced.
+	EnableNamespaces bool
+
+	// AllowConsulNamespacesSet is a set of Consul namespaces to sync services
+	// from. If it contains "*", all Consul namespaces are allowed.
+	AllowConsulNamespacesSet mapset.Set
+
+	// DenyConsulNamespacesSet is a set of Consul namespaces to explicitly
+	// deny syncing services from. It takes precedence over
+	// AllowConsulNamespacesSet.
+	DenyConsulNamespacesSet mapset.Set
+
+	// NamespaceSinks maps Consul namespaces to the Sink their services are
+	// written to, so that services from different Consul namespaces can be
+	// written to different Kubernetes namespaces. Services in Consul
+	// namespaces that aren't in the map are written to Sink.
+	NamespaceSinks map[string]Sink
+
+	// Metrics records the metrics of the sync. If it is nil, metrics.Default
+	// is used.
+	Metrics *metrics.Metrics
+
+	// lock gates concurrent access to the maps below.
+	lock sync.Mutex
+
+	// services maps Consul namespaces to the names of the Consul services
+	// in that namespace that should be synced. If namespaces aren't enabled
+	// the only key is "".
+	services map[string][]string
+
+	// serviceInstances maps Consul namespaces and service names to the
+	// passing instances of the service. It's only populated if WatchHealth
+	// is true.
+	serviceInstances map[string]map[string][]ServiceInstance
+
+	// namespaceWatchers maps Consul namespaces to the cancel function of the
+	// goroutine watching the services in that namespace.
+	namespaceWatchers map[string]context.CancelFunc
+
+	// healthWatchers maps Consul namespaces and service names to the cancel
+	// function of the goroutine watching the health of that service.
+	healthWatchers map[string]map[string]context.CancelFunc
 }
 
 // Run is the long-running runloop for watching Consul services and
 // updating the Sink.
 func (s *Source) Run(ctx context.Context) {
+	if s.EnableNamespaces {
+		s.watchNamespaces(ctx)
+		return
+	}
+	s.watchServices(ctx, "")
+}
+
+// watchNamespaces is the long-running runloop for watching Consul namespaces
+// and starting a service watcher for every namespace that should be synced.
+func (s *Source) watchNamespaces(ctx context.Context) {
+	opts := (&api.QueryOptions{
+		AllowStale: true,
+		WaitIndex:  1,
+		WaitTime:   1 * time.Minute,
+	}).WithContext(ctx)
+	for {
+		consulClient, err := consul.NewClientFromConnMgr(s.ConsulClientConfig, s.ConsulServerConnMgr)
+		if err != nil {
+			s.Log.Error("failed to create Consul API client", "err", err)
+			return
+		}
+
+		// Get all namespaces.
+		var namespaces []*api.Namespace
+		var meta *api.QueryMeta
+		err = backoff.Retry(func() error {
+			namespaces, meta, err = consulClient.Namespaces().List(opts)
+			return err
+		}, backoff.WithContext(backoff.NewExponentialBackOff(), ctx))
+
+		// If the context is ended, then we end
+		if ctx.Err() != nil {
+			return
+		}
+
+		// If there was an error, handle that
+		if err != nil {
+			s.Metrics.OrDefault().WatchErrors.WithLabelValues(metrics.DirectionToK8S, "namespaces").Inc()
+			s.Log.Warn("error querying namespaces, will retry", "err", err)
+			continue
+		}
+
+		// Update our blocking index
+		opts.WaitIndex = meta.LastIndex
+
+		allowed := make(map[string]struct{})
+		for _, ns := range namespaces {
+			if s.shouldSyncNamespace(ns.Name) {
+				allowed[ns.Name] = struct{}{}
+			}
+		}
+		s.Log.Info("received namespaces from Consul", "count", len(namespaces), "synced", len(allowed))
+
+		s.updateNamespaceWatchers(ctx, allowed)
+	}
+}
+
+// updateNamespaceWatchers starts a service watcher for every Consul namespace
+// in namespaces that isn't watched yet and stops the watchers of namespaces
+// that should no longer be synced.
+func (s *Source) updateNamespaceWatchers(ctx context.Context, namespaces map[string]struct{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.namespaceWatchers == nil {
+		s.namespaceWatchers = make(map[string]context.CancelFunc)
+	}
+
+	// Stop watching namespaces that have been deleted or are no longer
+	// allowed. This also stops the health watchers of their services.
+	changed := false
+	for ns, cancelF := range s.namespaceWatchers {
+		if _, ok := namespaces[ns]; !ok {
+			cancelF()
+			delete(s.namespaceWatchers, ns)
+			delete(s.services, ns)
+			delete(s.serviceInstances, ns)
+			delete(s.healthWatchers, ns)
+			changed = true
+			s.Log.Debug("stopping service watcher", "namespace", ns)
+		}
+	}
+	if changed {
+		s.updateSinksLocked()
+	}
+
+	// Start watchers for all namespaces if they're not already running.
+	for ns := range namespaces {
+		if _, ok := s.namespaceWatchers[ns]; !ok {
+			nsCtx, cancelF := context.WithCancel(ctx)
+			go s.watchServices(nsCtx, ns)
+			s.namespaceWatchers[ns] = cancelF
+			s.Log.Debug("starting service watcher", "namespace", ns)
+		}
+	}
+}
+
+// watchServices is the long-running runloop for watching the services in a
+// Consul namespace and updating the Sink. If namespaces aren't enabled the
+// namespace is empty and the namespace of the Consul API client is used.
+func (s *Source) watchServices(ctx context.Context, namespace string) {
 	opts := (&api.QueryOptions{
 		AllowStale: true,
 		WaitIndex:  1,
 		WaitTime:   1 * time.Minute,
+		Namespace:  namespace,
+		Filter:     s.Filter,
 	}).WithContext(ctx)
 	for {
 		consulClient, err := consul.NewClientFromConnMgr(s.ConsulClientConfig, s.ConsulServerConnMgr)
@@ -58,7 +221,8 @@ func (s *Source) Run(ctx context.Context) {
 
 		// If there was an error, handle that
 		if err != nil {
-			s.Log.Warn("error querying services, will retry", "err", err)
+			s.Metrics.OrDefault().WatchErrors.WithLabelValues(metrics.DirectionToK8S, "services").Inc()
+			s.Log.Warn("error querying services, will retry", "namespace", namespace, "err", err)
 			continue
 		}
 
@@ -66,7 +230,7 @@ func (s *Source) Run(ctx context.Context) {
 		opts.WaitIndex = meta.LastIndex
 
 		// Setup the services
-		services := make(map[string]string, len(serviceMap))
+		services := make([]string, 0, len(serviceMap))
 		for name, tags := range serviceMap {
 			// We ignore services that are synced from k8s so we can avoid
 			// circular syncing. Realistically this shouldn't happen since
@@ -81,11 +245,238 @@ func (s *Source) Run(ctx context.Context) {
 			}
 
 			if !k8s {
-				services[s.Prefix+name] = fmt.Sprintf("%s.service.%s", name, s.Domain)
+				services = append(services, name)
 			}
 		}
-		s.Log.Info("received services from Consul", "count", len(services))
+		sort.Strings(services)
+		s.Log.Info("received services from Consul", "namespace", namespace, "count", len(services))
+
+		s.setServices(ctx, namespace, services)
+	}
+}
+
+// setServices stores the services that should be synced from a Consul
+// namespace and updates the Sinks.
+func (s *Source) setServices(ctx context.Context, namespace string, services []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// The watcher is stopped while the lock is held, so this makes sure we
+	// don't add back a namespace that is no longer synced.
+	if ctx.Err() != nil {
+		return
+	}
+
+	if s.services == nil {
+		s.services = make(map[string][]string)
+	}
+	s.services[namespace] = services
+	if s.WatchHealth {
+		s.updateHealthWatchersLocked(ctx, namespace)
+	}
+	s.updateSinksLocked()
+}
+
+// updateHealthWatchersLocked starts a health watcher for every service in the
+// Consul namespace that isn't watched yet and stops the watchers of services
+// that no longer exist.
+//
+// Precondition: lock must be held.
+func (s *Source) updateHealthWatchersLocked(ctx context.Context, namespace string) {
+	if s.healthWatchers == nil {
+		s.healthWatchers = make(map[string]map[string]context.CancelFunc)
+	}
+	if s.healthWatchers[namespace] == nil {
+		s.healthWatchers[namespace] = make(map[string]context.CancelFunc)
+	}
+	if s.serviceInstances == nil {
+		s.serviceInstances = make(map[string]map[string][]ServiceInstance)
+	}
+	if s.serviceInstances[namespace] == nil {
+		s.serviceInstances[namespace] = make(map[string][]ServiceInstance)
+	}
+
+	services := make(map[string]struct{}, len(s.services[namespace]))
+	for _, name := range s.services[namespace] {
+		services[name] = struct{}{}
+	}
+
+	// Stop watching services that have been deregistered.
+	for name, cancelF := range s.healthWatchers[namespace] {
+		if _, ok := services[name]; !ok {
+			cancelF()
+			delete(s.healthWatchers[namespace], name)
+			delete(s.serviceInstances[namespace], name)
+			s.Log.Debug("stopping health watcher", "service-name", name, "namespace", namespace)
+		}
+	}
+
+	// Start watchers for all services if they're not already running.
+	for name := range services {
+		if _, ok := s.healthWatchers[namespace][name]; !ok {
+			svcCtx, cancelF := context.WithCancel(ctx)
+			go s.watchHealth(svcCtx, namespace, name)
+			s.healthWatchers[namespace][name] = cancelF
+			s.Log.Debug("starting health watcher", "service-name", name, "namespace", namespace)
+		}
+	}
+}
+
+// watchHealth is the long-running runloop for watching the health of a
+// single Consul service and updating the Sink with its passing instances.
+func (s *Source) watchHealth(ctx context.Context, namespace, name string) {
+	opts := (&api.QueryOptions{
+		AllowStale: true,
+		WaitIndex:  1,
+		WaitTime:   1 * time.Minute,
+		Namespace:  namespace,
+	}).WithContext(ctx)
+	for {
+		consulClient, err := consul.NewClientFromConnMgr(s.ConsulClientConfig, s.ConsulServerConnMgr)
+		if err != nil {
+			s.Log.Error("failed to create Consul API client", "err", err)
+			return
+		}
+
+		// Get all passing instances of the service.
+		var entries []*api.ServiceEntry
+		var meta *api.QueryMeta
+		err = backoff.Retry(func() error {
+			entries, meta, err = consulClient.Health().Service(name, "", true, opts)
+			return err
+		}, backoff.WithContext(backoff.NewExponentialBackOff(), ctx))
+
+		// If the context is ended, then we end
+		if ctx.Err() != nil {
+			return
+		}
+
+		// If there was an error, handle that
+		if err != nil {
+			s.Metrics.OrDefault().WatchErrors.WithLabelValues(metrics.DirectionToK8S, "health").Inc()
+			s.Log.Warn("error querying service health, will retry", "service-name", name, "namespace", namespace, "err", err)
+			continue
+		}
+
+		// Update our blocking index
+		opts.WaitIndex = meta.LastIndex
+
+		instances := make([]ServiceInstance, 0, len(entries))
+		for _, entry := range entries {
+			// Consul DNS falls back to the node address if the service
+			// doesn't have its own address, so we do the same.
+			address := entry.Service.Address
+			if address == "" {
+				address = entry.Node.Address
+			}
+			instances = append(instances, ServiceInstance{
+				Address: address,
+				Port:    entry.Service.Port,
+			})
+		}
+		sort.Slice(instances, func(i, j int) bool {
+			if instances[i].Address != instances[j].Address {
+				return instances[i].Address < instances[j].Address
+			}
+			return instances[i].Port < instances[j].Port
+		})
+		s.Log.Debug("received service instances from Consul", "service-name", name, "namespace", namespace, "count", len(instances))
+
+		s.lock.Lock()
+		// The watcher is stopped while the lock is held, so this makes sure
+		// we don't add back a service that has been deregistered meanwhile.
+		if ctx.Err() == nil {
+			s.serviceInstances[namespace][name] = instances
+			s.updateSinksLocked()
+		}
+		s.lock.Unlock()
+	}
+}
+
+// updateSinksLocked sends the services, and their instances if WatchHealth is
+// true, to every Sink. The Sinks are updated while the lock is held so that
+// updates from different watchers can't be applied out of order.
+//
+// Precondition: lock must be held.
+func (s *Source) updateSinksLocked() {
+	services := map[Sink]map[string]string{s.Sink: make(map[string]string)}
+	instances := map[Sink]map[string][]ServiceInstance{s.Sink: make(map[string][]ServiceInstance)}
+	for _, sink := range s.NamespaceSinks {
+		services[sink] = make(map[string]string)
+		instances[sink] = make(map[string][]ServiceInstance)
+	}
+
+	// Go through the namespaces in order so that the same service wins each
+	// time if services from different namespaces have the same name in a Sink.
+	namespaces := make([]string, 0, len(s.services))
+	for ns := range s.services {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	for _, ns := range namespaces {
+		sink := s.sinkForNamespace(ns)
+		for _, name := range s.services[ns] {
+			sinkName := s.Prefix + name
+			if _, ok := services[sink][sinkName]; ok {
+				s.Log.Warn("service with the same name is synced from another Consul namespace, not syncing",
+					"name", name, "namespace", ns)
+				continue
+			}
+
+			services[sink][sinkName] = s.dnsName(name, ns)
+			if svcInstances, ok := s.serviceInstances[ns][name]; ok {
+				instances[sink][sinkName] = svcInstances
+			}
+		}
+	}
+
+	var synced int
+	for sink, svcs := range services {
+		synced += len(svcs)
+		sink.SetServices(svcs)
+		if s.WatchHealth {
+			sink.SetServiceInstances(instances[sink])
+		}
+	}
+	s.Metrics.OrDefault().SyncedServices.WithLabelValues(metrics.DirectionToK8S).Set(float64(synced))
+}
+
+// sinkForNamespace returns the Sink that the services in a Consul namespace
+// are written to.
+func (s *Source) sinkForNamespace(namespace string) Sink {
+	if sink, ok := s.NamespaceSinks[namespace]; ok {
+		return sink
+	}
+	return s.Sink
+}
+
+// shouldSyncNamespace returns true if services in the Consul namespace
+// should be synced.
+func (s *Source) shouldSyncNamespace(namespace string) bool {
+	// If in deny list, don't sync
+	if s.DenyConsulNamespacesSet != nil && s.DenyConsulNamespacesSet.Contains(namespace) {
+		return false
+	}
+
+	// If not in allow list or allow list is not *, don't sync
+	return s.AllowConsulNamespacesSet != nil &&
+		(s.AllowConsulNamespacesSet.Contains("*") || s.AllowConsulNamespacesSet.Contains(namespace))
+}
+
+// dnsName returns the Consul DNS entry of a service in a Consul namespace.
+// Services are qualified with their namespace and partition when namespaces
+// are enabled, since the Consul namespace of the service is not necessarily
+// the namespace the DNS lookup is made from.
+func (s *Source) dnsName(name, namespace string) string {
+	if !s.EnableNamespaces {
+		return fmt.Sprintf("%s.service.%s", name, s.Domain)
+	}
 
-		s.Sink.SetServices(services)
+	dnsName := fmt.Sprintf("%s.service.%s.ns", name, namespace)
+	if s.ConsulClientConfig != nil && s.ConsulClientConfig.APIClientConfig != nil &&
+		s.ConsulClientConfig.APIClientConfig.Partition != "" {
+		dnsName = fmt.Sprintf("%s.%s.ap", dnsName, s.ConsulClientConfig.APIClientConfig.Partition)
 	}
+	return fmt.Sprintf("%s.%s", dnsName, s.Domain)
 }
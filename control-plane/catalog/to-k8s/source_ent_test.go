@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build enterprise
+
+package catalog
+
+import (
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/hashicorp/consul-k8s/control-plane/helper/test"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil/retry"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that the source syncs services from the allowed Consul namespaces
+// and writes them to the sink mapped to their namespace.
+func TestSource_ConsulNamespaces(t *testing.T) {
+	t.Parallel()
+
+	// Set up server, client
+	testClient := test.TestServerWithMockConnMgrWatcher(t, nil)
+	client := testClient.APIClient
+
+	// Create the namespaces and services before the source is running
+	for _, ns := range []string{"foo", "bar", "baz"} {
+		_, _, err := client.Namespaces().Create(&api.Namespace{Name: ns}, nil)
+		require.NoError(t, err)
+
+		reg := testRegistration("hostA", "svc-"+ns, nil)
+		reg.Service.Namespace = ns
+		_, err = client.Catalog().Register(reg, nil)
+		require.NoError(t, err)
+	}
+
+	barSink := &TestSink{}
+	_, sink, closer := testSourceWithConfig(testClient.Cfg, testClient.Watcher, func(s *Source) {
+		s.EnableNamespaces = true
+		s.AllowConsulNamespacesSet = mapset.NewSet("*")
+		s.DenyConsulNamespacesSet = mapset.NewSet("baz")
+		s.NamespaceSinks = map[string]Sink{"bar": barSink}
+	})
+	defer closer()
+
+	retry.Run(t, func(r *retry.R) {
+		sink.Lock()
+		defer sink.Unlock()
+		require.Equal(r, map[string]string{
+			"consul":  "consul.service.default.ns.test",
+			"svc-foo": "svc-foo.service.foo.ns.test",
+		}, sink.Services)
+	})
+
+	retry.Run(t, func(r *retry.R) {
+		barSink.Lock()
+		defer barSink.Unlock()
+		require.Equal(r, map[string]string{
+			"svc-bar": "svc-bar.service.bar.ns.test",
+		}, barSink.Services)
+	})
+}
@@ -8,12 +8,16 @@ import (
 	"reflect"
 	"testing"
 
+	mapset "github.com/deckarep/golang-set"
+	"github.com/hashicorp/consul-k8s/control-plane/catalog/metrics"
 	toconsul "github.com/hashicorp/consul-k8s/control-plane/catalog/to-consul"
 	"github.com/hashicorp/consul-k8s/control-plane/consul"
 	"github.com/hashicorp/consul-k8s/control-plane/helper/test"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/go-hclog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,7 +37,10 @@ func TestSource_initServices(t *testing.T) {
 	_, err = client.Catalog().Register(testRegistration("hostB", "svcB", nil), nil)
 	require.NoError(t, err)
 
-	_, sink, closer := testSource(testClient.Cfg, testClient.Watcher)
+	m := metrics.New(prometheus.NewRegistry())
+	_, sink, closer := testSourceWithConfig(testClient.Cfg, testClient.Watcher, func(s *Source) {
+		s.Metrics = m
+	})
 	defer closer()
 
 	var actual map[string]string
@@ -52,6 +59,12 @@ func TestSource_initServices(t *testing.T) {
 		"svcB":   "svcB.service.test",
 	}
 	require.Equal(t, expected, actual)
+
+	// Verify the synced services are counted
+	retry.Run(t, func(r *retry.R) {
+		require.Equal(r, float64(3), testutil.ToFloat64(m.SyncedServices.WithLabelValues(metrics.DirectionToK8S)))
+	})
+	require.Equal(t, float64(0), testutil.ToFloat64(m.WatchErrors.WithLabelValues(metrics.DirectionToK8S, "services")))
 }
 
 // Test that we can specify a prefix to prepend to all destination services.
@@ -228,6 +241,197 @@ func TestSource_deleteServiceInstance(t *testing.T) {
 	})
 }
 
+// Test that the source sends the passing instances of services to the sink
+// when watching health.
+func TestSource_watchHealth(t *testing.T) {
+	t.Parallel()
+
+	// Set up server, client
+	testClient := test.TestServerWithMockConnMgrWatcher(t, nil)
+	client := testClient.APIClient
+
+	// Create services before the source is running
+	regA := testRegistration("hostA", "svcA", nil)
+	regA.Service.Address = "1.1.1.1"
+	regA.Service.Port = 8080
+	_, err := client.Catalog().Register(regA, nil)
+	require.NoError(t, err)
+	regB := testRegistration("hostB", "svcA", nil)
+	regB.Service.Port = 8080
+	_, err = client.Catalog().Register(regB, nil)
+	require.NoError(t, err)
+
+	_, sink, closer := testSourceWithConfig(testClient.Cfg, testClient.Watcher, func(s *Source) {
+		s.WatchHealth = true
+	})
+	defer closer()
+
+	retry.Run(t, func(r *retry.R) {
+		sink.Lock()
+		defer sink.Unlock()
+		expected := []ServiceInstance{
+			{Address: "1.1.1.1", Port: 8080},
+			{Address: "127.0.0.1", Port: 8080},
+		}
+		require.Equal(r, expected, sink.Instances["svcA"])
+	})
+
+	// Mark one of the instances as critical
+	regA.Check = &api.AgentCheck{
+		Node:      "hostA",
+		CheckID:   "svcA-check",
+		Name:      "svcA-check",
+		Status:    api.HealthCritical,
+		ServiceID: "svcA",
+	}
+	_, err = client.Catalog().Register(regA, nil)
+	require.NoError(t, err)
+
+	retry.Run(t, func(r *retry.R) {
+		sink.Lock()
+		defer sink.Unlock()
+		expected := []ServiceInstance{
+			{Address: "127.0.0.1", Port: 8080},
+		}
+		require.Equal(r, expected, sink.Instances["svcA"])
+	})
+
+	// Deregister the service
+	_, err = client.Catalog().Deregister(&api.CatalogDeregistration{
+		Node: "hostA", ServiceID: "svcA"}, nil)
+	require.NoError(t, err)
+	_, err = client.Catalog().Deregister(&api.CatalogDeregistration{
+		Node: "hostB", ServiceID: "svcA"}, nil)
+	require.NoError(t, err)
+
+	retry.Run(t, func(r *retry.R) {
+		sink.Lock()
+		defer sink.Unlock()
+		_, ok := sink.Instances["svcA"]
+		require.False(r, ok)
+	})
+}
+
+// Test that the source only syncs services matching the filter.
+func TestSource_filter(t *testing.T) {
+	t.Parallel()
+
+	// Set up server, client
+	testClient := test.TestServerWithMockConnMgrWatcher(t, nil)
+	client := testClient.APIClient
+
+	// Create services before the source is running
+	_, err := client.Catalog().Register(testRegistration("hostA", "svcA", []string{"public"}), nil)
+	require.NoError(t, err)
+	regB := testRegistration("hostB", "svcB", nil)
+	regB.Service.Meta = map[string]string{"team": "a"}
+	_, err = client.Catalog().Register(regB, nil)
+	require.NoError(t, err)
+	_, err = client.Catalog().Register(testRegistration("hostB", "svcC", nil), nil)
+	require.NoError(t, err)
+
+	_, sink, closer := testSourceWithConfig(testClient.Cfg, testClient.Watcher, func(s *Source) {
+		s.Filter = `ServiceTags contains "public" or ServiceMeta.team == "a"`
+	})
+	defer closer()
+
+	var actual map[string]string
+	retry.Run(t, func(r *retry.R) {
+		sink.Lock()
+		defer sink.Unlock()
+		actual = sink.Services
+		if len(actual) != 2 {
+			r.Fatal("services not found")
+		}
+	})
+
+	expected := map[string]string{
+		"svcA": "svcA.service.test",
+		"svcB": "svcB.service.test",
+	}
+	require.Equal(t, expected, actual)
+}
+
+func TestSource_shouldSyncNamespace(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		allow    mapset.Set
+		deny     mapset.Set
+		expected bool
+	}{
+		"allowed": {
+			allow:    mapset.NewSet("foo"),
+			deny:     mapset.NewSet(),
+			expected: true,
+		},
+		"allowed by wildcard": {
+			allow:    mapset.NewSet("*"),
+			deny:     mapset.NewSet(),
+			expected: true,
+		},
+		"not allowed": {
+			allow:    mapset.NewSet("bar"),
+			deny:     mapset.NewSet(),
+			expected: false,
+		},
+		"denied": {
+			allow:    mapset.NewSet("*"),
+			deny:     mapset.NewSet("foo"),
+			expected: false,
+		},
+		"allowed and denied": {
+			allow:    mapset.NewSet("foo"),
+			deny:     mapset.NewSet("foo"),
+			expected: false,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &Source{
+				AllowConsulNamespacesSet: c.allow,
+				DenyConsulNamespacesSet:  c.deny,
+			}
+			require.Equal(t, c.expected, s.shouldSyncNamespace("foo"))
+		})
+	}
+}
+
+func TestSource_dnsName(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		enableNamespaces bool
+		partition        string
+		expected         string
+	}{
+		"namespaces disabled": {
+			expected: "web.service.consul",
+		},
+		"namespaces enabled": {
+			enableNamespaces: true,
+			expected:         "web.service.foo.ns.consul",
+		},
+		"namespaces enabled with partition": {
+			enableNamespaces: true,
+			partition:        "bar",
+			expected:         "web.service.foo.ns.bar.ap.consul",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &Source{
+				ConsulClientConfig: &consul.Config{APIClientConfig: &api.Config{Partition: c.partition}},
+				Domain:             "consul",
+				EnableNamespaces:   c.enableNamespaces,
+			}
+			require.Equal(t, c.expected, s.dnsName("web", "foo"))
+		})
+	}
+}
+
 // testRegistration creates a Consul test registration.
 func testRegistration(node, service string, tags []string) *api.CatalogRegistration {
 	return &api.CatalogRegistration{
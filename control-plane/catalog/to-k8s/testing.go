@@ -11,7 +11,8 @@ import (
 // Reading/writing the services should be done only while the lock is held.
 type TestSink struct {
 	sync.Mutex
-	Services map[string]string
+	Services  map[string]string
+	Instances map[string][]ServiceInstance
 }
 
 func (s *TestSink) SetServices(raw map[string]string) {
@@ -19,3 +20,9 @@ func (s *TestSink) SetServices(raw map[string]string) {
 	defer s.Unlock()
 	s.Services = raw
 }
+
+func (s *TestSink) SetServiceInstances(raw map[string][]ServiceInstance) {
+	s.Lock()
+	defer s.Unlock()
+	s.Instances = raw
+}
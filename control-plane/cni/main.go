@@ -21,6 +21,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 
 	bv "github.com/containernetworking/plugins/pkg/utils/buildversion"
 )
@@ -275,13 +276,19 @@ func parseAnnotation(pod corev1.Pod, annotation string) (iptables.Config, error)
 
 // updateTransparentProxyStatusAnnotation updates the transparent-proxy-status annotation. We use it as a simple inicator of
 // CNI status on the pod.  Failing is not fatal.
+// The annotation is patched rather than updating the whole pod because updating would drop fields of the pod
+// which are unknown to the Kubernetes API types of this plugin, such as the restartPolicy of native sidecars.
 func (c *Command) updateTransparentProxyStatusAnnotation(podName, namespace, status string) bool {
-	// Refresh the pod so that we can update it without problems
-	pod, err := c.client.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				keyTransparentProxyStatus: status,
+			},
+		},
+	})
 	if err != nil {
 		return false
 	}
-	pod.Annotations[keyTransparentProxyStatus] = status
-	_, err = c.client.CoreV1().Pods(namespace).Update(context.Background(), pod, metav1.UpdateOptions{})
+	_, err = c.client.CoreV1().Pods(namespace).Patch(context.Background(), podName, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
 	return err == nil
 }
@@ -0,0 +1,393 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/constants"
+	"github.com/hashicorp/consul/api"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Upstream is an upstream of the service in the connect-service-upstreams-config annotation. It is the structured
+// alternative to the connect-service-upstreams annotation.
+type Upstream struct {
+	// DestinationName is the name of the upstream service or prepared query.
+	DestinationName string `json:"destinationName"`
+	// DestinationType is either "service", which is the default, or "prepared_query".
+	DestinationType string `json:"destinationType,omitempty"`
+	// DestinationNamespace is the Consul namespace of the upstream service.
+	DestinationNamespace string `json:"destinationNamespace,omitempty"`
+	// DestinationPartition is the Consul admin partition of the upstream service.
+	DestinationPartition string `json:"destinationPartition,omitempty"`
+	// DestinationPeer is the cluster peer of the upstream service.
+	DestinationPeer string `json:"destinationPeer,omitempty"`
+	// Datacenter is the datacenter of the upstream service.
+	Datacenter string `json:"datacenter,omitempty"`
+	// LocalBindAddress is the address the proxy listens on for the upstream. It defaults to 127.0.0.1.
+	LocalBindAddress string `json:"localBindAddress,omitempty"`
+	// LocalBindPort is the port the proxy listens on for the upstream.
+	LocalBindPort int `json:"localBindPort"`
+	// ConnectTimeout is the timeout for connections to the upstream, e.g. "5s".
+	ConnectTimeout string `json:"connectTimeout,omitempty"`
+}
+
+// ProcessUpstreams reads the list of upstreams from the connect-service-upstreams and
+// connect-service-upstreams-config annotations of the pod and converts them into a list of api.Upstream objects.
+func ProcessUpstreams(pod corev1.Pod, enableNamespaces, enablePartitions bool) ([]api.Upstream, error) {
+	var upstreams []api.Upstream
+	if raw, ok := pod.Annotations[constants.AnnotationUpstreams]; ok && raw != "" {
+		for _, raw := range strings.Split(raw, ",") {
+			var upstream api.Upstream
+
+			// parts separates out the port, and determines whether it's a prepared query or not, since parts[0] would
+			// be "prepared_query" if it is.
+			parts := strings.SplitN(raw, ":", 3)
+
+			// serviceParts helps determine which format of upstream we're processing,
+			// [service-name].[service-namespace].[service-partition]:[port]:[optional datacenter]
+			// or
+			// [service-name].svc.[service-namespace].ns.[service-peer].peer:[port]
+			// [service-name].svc.[service-namespace].ns.[service-partition].ap:[port]
+			// [service-name].svc.[service-namespace].ns.[service-datacenter].dc:[port]
+			labeledFormat := false
+			serviceParts := strings.Split(parts[0], ".")
+			if len(serviceParts) >= 2 {
+				if serviceParts[1] == "svc" {
+					labeledFormat = true
+				}
+			}
+
+			if strings.TrimSpace(parts[0]) == "prepared_query" {
+				upstream = processPreparedQueryUpstream(pod, raw)
+			} else if labeledFormat {
+				var err error
+				upstream, err = processLabeledUpstream(pod, raw, enableNamespaces, enablePartitions)
+				if err != nil {
+					return []api.Upstream{}, err
+				}
+			} else {
+				var err error
+				upstream, err = processUnlabeledUpstream(pod, raw, enableNamespaces, enablePartitions)
+				if err != nil {
+					return []api.Upstream{}, err
+				}
+			}
+
+			upstreams = append(upstreams, upstream)
+		}
+	}
+
+	configUpstreams, err := UpstreamsConfig(pod)
+	if err != nil {
+		return []api.Upstream{}, err
+	}
+	for _, upstream := range configUpstreams {
+		consulUpstream, err := upstream.toConsul()
+		if err != nil {
+			return []api.Upstream{}, err
+		}
+		upstreams = append(upstreams, consulUpstream)
+	}
+
+	return upstreams, nil
+}
+
+// ValidateUpstreams validates the connect-service-upstreams and connect-service-upstreams-config annotations of
+// the pod. Unlike ProcessUpstreams, it also returns an error for upstreams with a missing or invalid port, which
+// ProcessUpstreams ignores.
+func ValidateUpstreams(pod corev1.Pod, enableNamespaces, enablePartitions bool) error {
+	if raw, ok := pod.Annotations[constants.AnnotationUpstreams]; ok && raw != "" {
+		for _, raw := range strings.Split(raw, ",") {
+			if err := validateUpstream(pod, raw); err != nil {
+				return fmt.Errorf("%s annotation was invalid: %s", constants.AnnotationUpstreams, err)
+			}
+		}
+	}
+
+	configUpstreams, err := UpstreamsConfig(pod)
+	if err != nil {
+		return err
+	}
+	for i, upstream := range configUpstreams {
+		if err := upstream.validate(enableNamespaces, enablePartitions); err != nil {
+			return fmt.Errorf("%s annotation was invalid: upstream %d: %s", constants.AnnotationUpstreamsConfig, i, err)
+		}
+	}
+
+	// The upstreams in the connect-service-upstreams-config annotation are valid at this point, so any error is
+	// due to the format of an upstream in the connect-service-upstreams annotation.
+	if _, err := ProcessUpstreams(pod, enableNamespaces, enablePartitions); err != nil {
+		return fmt.Errorf("%s annotation was invalid: %s", constants.AnnotationUpstreams, err)
+	}
+
+	return nil
+}
+
+// validateUpstream validates an upstream of the connect-service-upstreams annotation. The format of the upstream
+// is validated by the functions that process it.
+func validateUpstream(pod corev1.Pod, rawUpstream string) error {
+	if strings.TrimSpace(rawUpstream) == "" {
+		return fmt.Errorf("upstreams must not be empty, check for extra commas")
+	}
+
+	parts := strings.SplitN(rawUpstream, ":", 3)
+	name, port := strings.TrimSpace(parts[0]), ""
+	if name == "prepared_query" {
+		if len(parts) < 3 || strings.TrimSpace(parts[1]) == "" {
+			return fmt.Errorf("upstream %q must be in the format prepared_query:[query name]:[port]", strings.TrimSpace(rawUpstream))
+		}
+		port = strings.TrimSpace(parts[2])
+	} else {
+		if name == "" || len(parts) < 2 {
+			return fmt.Errorf("upstream %q must be in the format [service-name]:[port]", strings.TrimSpace(rawUpstream))
+		}
+		port = strings.TrimSpace(parts[1])
+	}
+
+	value, err := PortValue(pod, port)
+	if err != nil || value < 1 || value > 65535 {
+		return fmt.Errorf("upstream %q has an invalid port %q: must be a port number or the name of a container port", strings.TrimSpace(rawUpstream), port)
+	}
+	return nil
+}
+
+// UpstreamsConfig parses the JSON or YAML list of upstreams in the connect-service-upstreams-config annotation.
+// Unknown fields are rejected so that typos are not silently ignored.
+func UpstreamsConfig(pod corev1.Pod) ([]Upstream, error) {
+	raw, ok := pod.Annotations[constants.AnnotationUpstreamsConfig]
+	if !ok || raw == "" {
+		return nil, nil
+	}
+
+	rawJSON, err := yaml.ToJSON([]byte(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%s annotation was invalid: %s", constants.AnnotationUpstreamsConfig, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(rawJSON))
+	decoder.DisallowUnknownFields()
+
+	var upstreams []Upstream
+	if err := decoder.Decode(&upstreams); err != nil {
+		return nil, fmt.Errorf("%s annotation was invalid: %s", constants.AnnotationUpstreamsConfig, err)
+	}
+	return upstreams, nil
+}
+
+func (u Upstream) validate(enableNamespaces, enablePartitions bool) error {
+	if u.DestinationName == "" {
+		return fmt.Errorf("destinationName must be set")
+	}
+	switch api.UpstreamDestType(u.DestinationType) {
+	case "", api.UpstreamDestTypeService:
+	case api.UpstreamDestTypePreparedQuery:
+		if u.DestinationNamespace != "" || u.DestinationPartition != "" || u.DestinationPeer != "" || u.Datacenter != "" {
+			return fmt.Errorf("destinationNamespace, destinationPartition, destinationPeer and datacenter cannot be set for prepared queries")
+		}
+	default:
+		return fmt.Errorf("destinationType must be %q or %q, got %q", api.UpstreamDestTypeService, api.UpstreamDestTypePreparedQuery, u.DestinationType)
+	}
+	if u.DestinationNamespace != "" && !enableNamespaces {
+		return fmt.Errorf("destinationNamespace cannot be set when Consul namespaces are not enabled")
+	}
+	if u.DestinationPartition != "" && !enablePartitions {
+		return fmt.Errorf("destinationPartition cannot be set when Consul admin partitions are not enabled")
+	}
+	if u.DestinationPeer != "" && (u.DestinationPartition != "" || u.Datacenter != "") {
+		return fmt.Errorf("destinationPeer cannot be set together with destinationPartition or datacenter")
+	}
+	if u.LocalBindAddress != "" && net.ParseIP(u.LocalBindAddress) == nil {
+		return fmt.Errorf("localBindAddress %q is not a valid IP address", u.LocalBindAddress)
+	}
+	if u.LocalBindPort < 1 || u.LocalBindPort > 65535 {
+		return fmt.Errorf("localBindPort %d must be between 1 and 65535", u.LocalBindPort)
+	}
+	if u.ConnectTimeout != "" {
+		timeout, err := time.ParseDuration(u.ConnectTimeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("connectTimeout %q must be a positive duration, e.g. \"5s\"", u.ConnectTimeout)
+		}
+	}
+	return nil
+}
+
+func (u Upstream) toConsul() (api.Upstream, error) {
+	upstream := api.Upstream{
+		DestinationType:      api.UpstreamDestTypeService,
+		DestinationName:      u.DestinationName,
+		DestinationNamespace: u.DestinationNamespace,
+		DestinationPartition: u.DestinationPartition,
+		DestinationPeer:      u.DestinationPeer,
+		Datacenter:           u.Datacenter,
+		LocalBindAddress:     u.LocalBindAddress,
+		LocalBindPort:        u.LocalBindPort,
+	}
+	if u.DestinationType != "" {
+		upstream.DestinationType = api.UpstreamDestType(u.DestinationType)
+	}
+	if u.ConnectTimeout != "" {
+		timeout, err := time.ParseDuration(u.ConnectTimeout)
+		if err != nil {
+			return api.Upstream{}, fmt.Errorf("%s annotation was invalid: connectTimeout %q: %s", constants.AnnotationUpstreamsConfig, u.ConnectTimeout, err)
+		}
+		upstream.Config = map[string]interface{}{
+			"connect_timeout_ms": timeout.Milliseconds(),
+		}
+	}
+	return upstream, nil
+}
+
+// processPreparedQueryUpstream processes an upstream in the format:
+// prepared_query:[query name]:[port].
+func processPreparedQueryUpstream(pod corev1.Pod, rawUpstream string) api.Upstream {
+	var preparedQuery string
+	var port int32
+	parts := strings.SplitN(rawUpstream, ":", 3)
+
+	port, _ = PortValue(pod, strings.TrimSpace(parts[2]))
+	preparedQuery = strings.TrimSpace(parts[1])
+	var upstream api.Upstream
+	if port > 0 {
+		upstream = api.Upstream{
+			DestinationType: api.UpstreamDestTypePreparedQuery,
+			DestinationName: preparedQuery,
+			LocalBindPort:   int(port),
+		}
+	}
+	return upstream
+}
+
+// processUnlabeledUpstream processes an upstream in the format:
+// [service-name].[service-namespace].[service-partition]:[port]:[optional datacenter].
+func processUnlabeledUpstream(pod corev1.Pod, rawUpstream string, enableNamespaces, enablePartitions bool) (api.Upstream, error) {
+	var datacenter, svcName, namespace, partition, peer string
+	var port int32
+	var upstream api.Upstream
+
+	parts := strings.SplitN(rawUpstream, ":", 3)
+
+	port, _ = PortValue(pod, strings.TrimSpace(parts[1]))
+
+	// If Consul Namespaces or Admin Partitions are enabled, attempt to parse the
+	// upstream for a namespace.
+	if enableNamespaces || enablePartitions {
+		pieces := strings.SplitN(parts[0], ".", 3)
+		switch len(pieces) {
+		case 3:
+			partition = strings.TrimSpace(pieces[2])
+			fallthrough
+		case 2:
+			namespace = strings.TrimSpace(pieces[1])
+			fallthrough
+		default:
+			svcName = strings.TrimSpace(pieces[0])
+		}
+	} else {
+		svcName = strings.TrimSpace(parts[0])
+	}
+
+	// parse the optional datacenter
+	if len(parts) > 2 {
+		datacenter = strings.TrimSpace(parts[2])
+	}
+	if port > 0 {
+		upstream = api.Upstream{
+			DestinationType:      api.UpstreamDestTypeService,
+			DestinationPartition: partition,
+			DestinationPeer:      peer,
+			DestinationNamespace: namespace,
+			DestinationName:      svcName,
+			Datacenter:           datacenter,
+			LocalBindPort:        int(port),
+		}
+	}
+	return upstream, nil
+}
+
+// processLabeledUpstream processes an upstream in the format:
+// [service-name].svc.[service-namespace].ns.[service-peer].peer:[port]
+// [service-name].svc.[service-namespace].ns.[service-partition].ap:[port]
+// [service-name].svc.[service-namespace].ns.[service-datacenter].dc:[port].
+func processLabeledUpstream(pod corev1.Pod, rawUpstream string, enableNamespaces, enablePartitions bool) (api.Upstream, error) {
+	var datacenter, svcName, namespace, partition, peer string
+	var port int32
+	var upstream api.Upstream
+
+	parts := strings.SplitN(rawUpstream, ":", 3)
+
+	port, _ = PortValue(pod, strings.TrimSpace(parts[1]))
+
+	service := parts[0]
+
+	pieces := strings.Split(service, ".")
+
+	if enableNamespaces || enablePartitions {
+		switch len(pieces) {
+		case 6:
+			end := strings.TrimSpace(pieces[5])
+			switch end {
+			case "peer":
+				peer = strings.TrimSpace(pieces[4])
+			case "ap":
+				partition = strings.TrimSpace(pieces[4])
+			case "dc":
+				datacenter = strings.TrimSpace(pieces[4])
+			default:
+				return api.Upstream{}, fmt.Errorf("upstream structured incorrectly: %s", rawUpstream)
+			}
+			fallthrough
+		case 4:
+			if strings.TrimSpace(pieces[3]) == "ns" {
+				namespace = strings.TrimSpace(pieces[2])
+			} else {
+				return api.Upstream{}, fmt.Errorf("upstream structured incorrectly: %s", rawUpstream)
+			}
+			fallthrough
+		case 2:
+			if strings.TrimSpace(pieces[1]) == "svc" {
+				svcName = strings.TrimSpace(pieces[0])
+			}
+		default:
+			return api.Upstream{}, fmt.Errorf("upstream structured incorrectly: %s", rawUpstream)
+		}
+	} else {
+		switch len(pieces) {
+		case 4:
+			end := strings.TrimSpace(pieces[3])
+			switch end {
+			case "peer":
+				peer = strings.TrimSpace(pieces[2])
+			case "dc":
+				datacenter = strings.TrimSpace(pieces[2])
+			default:
+				return api.Upstream{}, fmt.Errorf("upstream structured incorrectly: %s", rawUpstream)
+			}
+			fallthrough
+		case 2:
+			svcName = strings.TrimSpace(pieces[0])
+		default:
+			return api.Upstream{}, fmt.Errorf("upstream structured incorrectly: %s", rawUpstream)
+		}
+	}
+
+	if port > 0 {
+		upstream = api.Upstream{
+			DestinationType:      api.UpstreamDestTypeService,
+			DestinationPartition: partition,
+			DestinationPeer:      peer,
+			DestinationNamespace: namespace,
+			DestinationName:      svcName,
+			Datacenter:           datacenter,
+			LocalBindPort:        int(port),
+		}
+	}
+	return upstream, nil
+}
@@ -0,0 +1,236 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package common
+
+import (
+	"testing"
+
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/constants"
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestProcessUpstreams_UpstreamsConfig(t *testing.T) {
+	cases := map[string]struct {
+		annotations  map[string]string
+		expUpstreams []api.Upstream
+		expErr       string
+	}{
+		"json": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreamsConfig: `[{"destinationName": "db", "destinationNamespace": "data", "destinationPartition": "backend", "localBindAddress": "127.0.0.2", "localBindPort": 5432, "connectTimeout": "5s"}]`,
+			},
+			expUpstreams: []api.Upstream{
+				{
+					DestinationType:      api.UpstreamDestTypeService,
+					DestinationName:      "db",
+					DestinationNamespace: "data",
+					DestinationPartition: "backend",
+					LocalBindAddress:     "127.0.0.2",
+					LocalBindPort:        5432,
+					Config: map[string]interface{}{
+						"connect_timeout_ms": int64(5000),
+					},
+				},
+			},
+		},
+		"yaml": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreamsConfig: `
+- destinationName: db
+  destinationPeer: cluster-02
+  localBindPort: 5432
+- destinationName: queryname
+  destinationType: prepared_query
+  localBindPort: 8202
+`,
+			},
+			expUpstreams: []api.Upstream{
+				{
+					DestinationType: api.UpstreamDestTypeService,
+					DestinationName: "db",
+					DestinationPeer: "cluster-02",
+					LocalBindPort:   5432,
+				},
+				{
+					DestinationType: api.UpstreamDestTypePreparedQuery,
+					DestinationName: "queryname",
+					LocalBindPort:   8202,
+				},
+			},
+		},
+		"combined with the upstreams annotation": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreams:       "upstream1:1234:dc2",
+				constants.AnnotationUpstreamsConfig: `[{"destinationName": "db", "datacenter": "dc3", "localBindPort": 5432}]`,
+			},
+			expUpstreams: []api.Upstream{
+				{
+					DestinationType: api.UpstreamDestTypeService,
+					DestinationName: "upstream1",
+					Datacenter:      "dc2",
+					LocalBindPort:   1234,
+				},
+				{
+					DestinationType: api.UpstreamDestTypeService,
+					DestinationName: "db",
+					Datacenter:      "dc3",
+					LocalBindPort:   5432,
+				},
+			},
+		},
+		"unknown field": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreamsConfig: `[{"destinationName": "db", "localBindPrt": 5432}]`,
+			},
+			expErr: `consul.hashicorp.com/connect-service-upstreams-config annotation was invalid: json: unknown field "localBindPrt"`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: c.annotations}}
+
+			upstreams, err := ProcessUpstreams(pod, true, true)
+			if c.expErr != "" {
+				require.EqualError(t, err, c.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expUpstreams, upstreams)
+		})
+	}
+}
+
+func TestValidateUpstreams(t *testing.T) {
+	cases := map[string]struct {
+		annotations      map[string]string
+		enableNamespaces bool
+		expErr           string
+	}{
+		"no upstreams": {},
+		"valid upstreams": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreams:       "upstream1:1234, upstream2.svc.ns1.ns.peer1.peer:2234, prepared_query:queryname:3234, upstream3:http",
+				constants.AnnotationUpstreamsConfig: `[{"destinationName": "db", "destinationNamespace": "data", "localBindPort": 5432, "connectTimeout": "500ms"}]`,
+			},
+			enableNamespaces: true,
+		},
+		"empty upstream": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreams: "upstream1:1234,",
+			},
+			expErr: "consul.hashicorp.com/connect-service-upstreams annotation was invalid: upstreams must not be empty, check for extra commas",
+		},
+		"missing port": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreams: "upstream1",
+			},
+			expErr: `consul.hashicorp.com/connect-service-upstreams annotation was invalid: upstream "upstream1" must be in the format [service-name]:[port]`,
+		},
+		"invalid port": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreams: "upstream1:abc",
+			},
+			expErr: `consul.hashicorp.com/connect-service-upstreams annotation was invalid: upstream "upstream1:abc" has an invalid port "abc": must be a port number or the name of a container port`,
+		},
+		"port out of range": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreams: "upstream1:70000",
+			},
+			expErr: `consul.hashicorp.com/connect-service-upstreams annotation was invalid: upstream "upstream1:70000" has an invalid port "70000": must be a port number or the name of a container port`,
+		},
+		"prepared query without name": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreams: "prepared_query:1234",
+			},
+			expErr: `consul.hashicorp.com/connect-service-upstreams annotation was invalid: upstream "prepared_query:1234" must be in the format prepared_query:[query name]:[port]`,
+		},
+		"invalid labeled upstream": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreams: "upstream1.svc.ns1.ns.part1.err:1234",
+			},
+			enableNamespaces: true,
+			expErr:           "consul.hashicorp.com/connect-service-upstreams annotation was invalid: upstream structured incorrectly: upstream1.svc.ns1.ns.part1.err:1234",
+		},
+		"invalid upstreams config": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreamsConfig: `{"destinationName": "db"}`,
+			},
+			expErr: "consul.hashicorp.com/connect-service-upstreams-config annotation was invalid: json: cannot unmarshal object into Go value of type []common.Upstream",
+		},
+		"upstreams config without destination name": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreamsConfig: `[{"localBindPort": 5432}]`,
+			},
+			expErr: "consul.hashicorp.com/connect-service-upstreams-config annotation was invalid: upstream 0: destinationName must be set",
+		},
+		"upstreams config with invalid destination type": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreamsConfig: `[{"destinationName": "db", "destinationType": "svc", "localBindPort": 5432}]`,
+			},
+			expErr: `consul.hashicorp.com/connect-service-upstreams-config annotation was invalid: upstream 0: destinationType must be "service" or "prepared_query", got "svc"`,
+		},
+		"upstreams config with namespace when namespaces are disabled": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreamsConfig: `[{"destinationName": "db", "destinationNamespace": "data", "localBindPort": 5432}]`,
+			},
+			expErr: "consul.hashicorp.com/connect-service-upstreams-config annotation was invalid: upstream 0: destinationNamespace cannot be set when Consul namespaces are not enabled",
+		},
+		"upstreams config with peer and datacenter": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreamsConfig: `[{"destinationName": "db", "destinationPeer": "cluster-02", "datacenter": "dc2", "localBindPort": 5432}]`,
+			},
+			expErr: "consul.hashicorp.com/connect-service-upstreams-config annotation was invalid: upstream 0: destinationPeer cannot be set together with destinationPartition or datacenter",
+		},
+		"upstreams config with invalid local bind address": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreamsConfig: `[{"destinationName": "db", "localBindAddress": "localhost", "localBindPort": 5432}]`,
+			},
+			expErr: `consul.hashicorp.com/connect-service-upstreams-config annotation was invalid: upstream 0: localBindAddress "localhost" is not a valid IP address`,
+		},
+		"upstreams config without local bind port": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreamsConfig: `[{"destinationName": "db"}]`,
+			},
+			expErr: "consul.hashicorp.com/connect-service-upstreams-config annotation was invalid: upstream 0: localBindPort 0 must be between 1 and 65535",
+		},
+		"upstreams config with invalid connect timeout": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreamsConfig: `[{"destinationName": "db", "localBindPort": 5432, "connectTimeout": "5"}]`,
+			},
+			expErr: `consul.hashicorp.com/connect-service-upstreams-config annotation was invalid: upstream 0: connectTimeout "5" must be a positive duration, e.g. "5s"`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: c.annotations},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "web",
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "http",
+									ContainerPort: 8080,
+								},
+							},
+						},
+					},
+				},
+			}
+
+			err := ValidateUpstreams(pod, c.enableNamespaces, false)
+			if c.expErr != "" {
+				require.EqualError(t, err, c.expErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -80,6 +80,13 @@ const (
 	// be a named port.
 	AnnotationUpstreams = "consul.hashicorp.com/connect-service-upstreams"
 
+	// AnnotationUpstreamsConfig is a JSON or YAML list of upstreams to register with the
+	// proxy. It is a structured alternative to AnnotationUpstreams which also supports the
+	// local bind address and connect timeout of each upstream, e.g.
+	// `[{"destinationName": "db", "destinationNamespace": "data", "localBindPort": 5432, "connectTimeout": "5s"}]`.
+	// It can be combined with AnnotationUpstreams.
+	AnnotationUpstreamsConfig = "consul.hashicorp.com/connect-service-upstreams-config"
+
 	// AnnotationTags is a list of tags to register with the service
 	// this is specified as a comma separated list e.g. abc,123.
 	AnnotationTags = "consul.hashicorp.com/service-tags"
@@ -100,6 +107,13 @@ const (
 	AnnotationSidecarProxyMemoryLimit   = "consul.hashicorp.com/sidecar-proxy-memory-limit"
 	AnnotationSidecarProxyMemoryRequest = "consul.hashicorp.com/sidecar-proxy-memory-request"
 
+	// AnnotationEnableNativeSidecar controls whether consul-dataplane is injected as a native
+	// sidecar container, i.e. an init container with restartPolicy Always, rather than as a
+	// regular container. Native sidecars require Kubernetes 1.29 or later, pods enabling them
+	// on older versions are rejected.
+	// This annotation takes a boolean value (true/false).
+	AnnotationEnableNativeSidecar = "consul.hashicorp.com/enable-native-sidecar"
+
 	// annotations for sidecar volumes.
 	AnnotationConsulSidecarUserVolume      = "consul.hashicorp.com/consul-sidecar-user-volume"
 	AnnotationConsulSidecarUserVolumeMount = "consul.hashicorp.com/consul-sidecar-user-volume-mount"
@@ -107,6 +121,22 @@ const (
 	// annotations for sidecar concurrency.
 	AnnotationEnvoyProxyConcurrency = "consul.hashicorp.com/consul-envoy-proxy-concurrency"
 
+	// annotations for sidecar proxy lifecycle management. When enabled, consul-dataplane
+	// serves graceful startup and shutdown endpoints on the graceful port which are called
+	// from the lifecycle hooks of the consul-dataplane container.
+	// If the startup grace period is greater than zero, the application containers are held
+	// until the proxy is ready, or the grace period has passed.
+	// On shutdown, the proxy keeps running for the shutdown grace period so that the application
+	// can finish in-flight requests, and optionally drains its inbound listeners. The pod's
+	// terminationGracePeriodSeconds must be greater than the shutdown grace period.
+	AnnotationEnableSidecarProxyLifecycle                       = "consul.hashicorp.com/enable-sidecar-proxy-lifecycle"
+	AnnotationEnableSidecarProxyLifecycleShutdownDrainListeners = "consul.hashicorp.com/enable-sidecar-proxy-lifecycle-shutdown-drain-listeners"
+	AnnotationSidecarProxyLifecycleShutdownGracePeriodSeconds   = "consul.hashicorp.com/sidecar-proxy-lifecycle-shutdown-grace-period-seconds"
+	AnnotationSidecarProxyLifecycleStartupGracePeriodSeconds    = "consul.hashicorp.com/sidecar-proxy-lifecycle-startup-grace-period-seconds"
+	AnnotationSidecarProxyLifecycleGracefulPort                 = "consul.hashicorp.com/sidecar-proxy-lifecycle-graceful-port"
+	AnnotationSidecarProxyLifecycleGracefulShutdownPath         = "consul.hashicorp.com/sidecar-proxy-lifecycle-graceful-shutdown-path"
+	AnnotationSidecarProxyLifecycleGracefulStartupPath          = "consul.hashicorp.com/sidecar-proxy-lifecycle-graceful-startup-path"
+
 	// annotations for metrics to configure where Prometheus scrapes
 	// metrics from, whether to run a merged metrics endpoint on the consul
 	// sidecar, and configure the connect service metrics.
@@ -141,8 +171,8 @@ const (
 	KeyConsulDNS = "consul.hashicorp.com/consul-dns"
 
 	// KeyTransparentProxy enables or disables transparent proxy for a given pod. It can also be set as a label
-	// on a namespace to define the default behaviour for connect-injected pods which do not otherwise override this setting
-	// with their own annotation.
+	// or annotation on a namespace to define the default behaviour for connect-injected pods which do not otherwise
+	// override this setting with their own annotation. The namespace annotation takes precedence over the label.
 	// This annotation/label takes a boolean value (true/false).
 	KeyTransparentProxy = "consul.hashicorp.com/transparent-proxy"
 
@@ -162,6 +192,11 @@ const (
 	// to point to the Envoy proxy when running in Transparent Proxy mode.
 	AnnotationTransparentProxyOverwriteProbes = "consul.hashicorp.com/transparent-proxy-overwrite-probes"
 
+	// AnnotationInjectionConfig records the configuration of the injected containers, i.e. sidecar proxy
+	// resources and concurrency, metrics and transparent proxy, that the webhook resolved for the pod from the
+	// pod's annotations, the annotations of its namespace and the global defaults. It is set for debugging only.
+	AnnotationInjectionConfig = "consul.hashicorp.com/injection-config"
+
 	// AnnotationRedirectTraffic stores iptables.Config information so that the CNI plugin can use it to apply
 	// iptables rules.
 	AnnotationRedirectTraffic = "consul.hashicorp.com/redirect-traffic-config"
@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package endpoints
+
+import (
+	"context"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/hashicorp/consul-server-connection-manager/discovery"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/go-multierror"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// endpointSliceAddress is a single address of a Kubernetes service merged from all of its EndpointSlices.
+type endpointSliceAddress struct {
+	address      corev1.EndpointAddress
+	healthStatus string
+	zone         string
+}
+
+// reconcileEndpointSlices reads all EndpointSlices for a Kubernetes Service and reconciles the Consul services which
+// correspond to the Kubernetes Service. It is the EndpointSlice equivalent of the Endpoints reconcile in Reconcile.
+func (r *Controller) reconcileEndpointSlices(ctx context.Context, apiClient *api.Client, serverState discovery.State, req ctrl.Request) (ctrl.Result, error) {
+	var errs error
+	var sliceList discoveryv1.EndpointSliceList
+
+	err := r.Client.List(ctx, &sliceList, client.InNamespace(req.Namespace), client.MatchingLabels{discoveryv1.LabelServiceName: req.Name})
+	if err != nil {
+		r.Log.Error(err, "failed to list EndpointSlices", "name", req.Name, "ns", req.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	// Kubernetes keeps at least one (possibly empty) EndpointSlice for every Service, so if there are none left
+	// the Service has been deleted and we need to deregister all instances in Consul for that service.
+	if len(sliceList.Items) == 0 {
+		err = r.deregisterService(apiClient, req.Name, req.Namespace, nil)
+		return ctrl.Result{}, err
+	}
+
+	r.Log.Info("retrieved", "name", req.Name, "ns", req.Namespace, "endpointslices", len(sliceList.Items))
+
+	// The registration code only needs the name, namespace and labels of the Endpoints object. EndpointSlices
+	// carry the labels of their Service in the same way Endpoints do.
+	serviceEndpoints := corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      req.Name,
+			Namespace: req.Namespace,
+			Labels:    sliceList.Items[0].Labels,
+		},
+	}
+
+	if isLabeledIgnore(serviceEndpoints.Labels) {
+		// We always deregister the service to handle the case where a user has registered the service, then added the label later.
+		r.Log.Info("Ignoring endpoint labeled with `consul.hashicorp.com/service-ignore: \"true\"`", "name", req.Name, "namespace", req.Namespace)
+		err = r.deregisterService(apiClient, req.Name, req.Namespace, nil)
+		return ctrl.Result{}, err
+	}
+
+	endpointPods := mapset.NewSet()
+	endpointAddressMap := map[string]bool{}
+
+	// Register all addresses from every EndpointSlice of this service as service instances in Consul.
+	for _, sliceAddress := range mapEndpointSliceAddresses(sliceList.Items) {
+		err = r.registerEndpointAddress(ctx, apiClient, serverState, serviceEndpoints, sliceAddress.address, sliceAddress.healthStatus, sliceAddress.zone, endpointAddressMap, endpointPods)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	// Deregister any service instances in Consul whose address is no longer in any of the EndpointSlices.
+	if err = r.deregisterService(apiClient, req.Name, req.Namespace, endpointAddressMap); err != nil {
+		r.Log.Error(err, "failed to deregister endpoints", "name", req.Name, "ns", req.Namespace)
+		errs = multierror.Append(errs, err)
+	}
+
+	return ctrl.Result{}, errs
+}
+
+// requestsForEndpointSlice maps an EndpointSlice to a request for the Service it belongs to.
+func requestsForEndpointSlice(object client.Object) []reconcile.Request {
+	svcName, ok := object.GetLabels()[discoveryv1.LabelServiceName]
+	if !ok || svcName == "" {
+		return []ctrl.Request{}
+	}
+	return []ctrl.Request{{NamespacedName: types.NamespacedName{Namespace: object.GetNamespace(), Name: svcName}}}
+}
+
+// mapEndpointSliceAddresses merges the endpoints of all EndpointSlices of a service into a mapping of IP address to
+// the address and its health status. An address can briefly appear in more than one slice while the EndpointSlice
+// controller rebalances them, in which case the healthiest status wins.
+func mapEndpointSliceAddresses(slices []discoveryv1.EndpointSlice) map[string]endpointSliceAddress {
+	m := make(map[string]endpointSliceAddress)
+	for _, slice := range slices {
+		// FQDN slices don't point to pods and can't be registered.
+		if slice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+		for _, endpoint := range slice.Endpoints {
+			if len(endpoint.Addresses) == 0 {
+				continue
+			}
+			// Addresses within an endpoint are fungible, and Kubernetes only ever sets one of them for pods.
+			ip := endpoint.Addresses[0]
+			healthStatus := endpointSliceHealthStatus(endpoint.Conditions)
+			if existing, ok := m[ip]; ok && existing.healthStatus == api.HealthPassing {
+				continue
+			}
+
+			address := corev1.EndpointAddress{
+				IP:        ip,
+				NodeName:  endpoint.NodeName,
+				TargetRef: endpoint.TargetRef,
+			}
+			if endpoint.Hostname != nil {
+				address.Hostname = *endpoint.Hostname
+			}
+			m[ip] = endpointSliceAddress{
+				address:      address,
+				healthStatus: healthStatus,
+				zone:         endpointSliceZone(endpoint),
+			}
+		}
+	}
+	return m
+}
+
+// endpointSliceHealthStatus converts the conditions of an EndpointSlice endpoint to a Consul health status.
+// Terminating endpoints are always critical so that Consul stops routing new traffic to them while the pod drains,
+// even if they are still serving. An unknown ready condition is treated as ready, as recommended by the
+// EndpointSlice API, unless the endpoint reports that it is not serving.
+func endpointSliceHealthStatus(conditions discoveryv1.EndpointConditions) string {
+	if conditions.Terminating != nil && *conditions.Terminating {
+		return api.HealthCritical
+	}
+	if conditions.Ready != nil {
+		if *conditions.Ready {
+			return api.HealthPassing
+		}
+		return api.HealthCritical
+	}
+	if conditions.Serving != nil && !*conditions.Serving {
+		return api.HealthCritical
+	}
+	return api.HealthPassing
+}
+
+// endpointSliceZone returns the topology zone of an EndpointSlice endpoint. The zone of the endpoint itself is used
+// when it is set, otherwise the zone from the topology hints is used if the endpoint is hinted to exactly one zone.
+func endpointSliceZone(endpoint discoveryv1.Endpoint) string {
+	if endpoint.Zone != nil && *endpoint.Zone != "" {
+		return *endpoint.Zone
+	}
+	if endpoint.Hints != nil && len(endpoint.Hints.ForZones) == 1 {
+		return endpoint.Hints.ForZones[0].Name
+	}
+	return ""
+}
@@ -0,0 +1,306 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package endpoints
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestRequestsForEndpointSlice(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		labels   map[string]string
+		expected []ctrl.Request
+	}{
+		"slice with service name label": {
+			labels: map[string]string{discoveryv1.LabelServiceName: "service-created"},
+			expected: []ctrl.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "default", Name: "service-created"}},
+			},
+		},
+		"slice without service name label": {
+			labels:   map[string]string{"foo": "bar"},
+			expected: []ctrl.Request{},
+		},
+		"slice with empty service name label": {
+			labels:   map[string]string{discoveryv1.LabelServiceName: ""},
+			expected: []ctrl.Request{},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			slice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "service-created-abcde",
+					Namespace: "default",
+					Labels:    c.labels,
+				},
+			}
+			require.Equal(t, c.expected, requestsForEndpointSlice(slice))
+		})
+	}
+}
+
+func TestMapEndpointSliceAddresses(t *testing.T) {
+	t.Parallel()
+	pod1 := &corev1.ObjectReference{Kind: "Pod", Name: "pod1", Namespace: "default"}
+	pod2 := &corev1.ObjectReference{Kind: "Pod", Name: "pod2", Namespace: "default"}
+
+	cases := map[string]struct {
+		slices   []discoveryv1.EndpointSlice
+		expected map[string]endpointSliceAddress
+	}{
+		"addresses are merged across slices": {
+			slices: []discoveryv1.EndpointSlice{
+				{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints: []discoveryv1.Endpoint{
+						{
+							Addresses:  []string{"1.2.3.4"},
+							Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+							TargetRef:  pod1,
+							Zone:       pointer.String("us-west-1a"),
+						},
+					},
+				},
+				{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints: []discoveryv1.Endpoint{
+						{
+							Addresses:  []string{"2.2.3.4"},
+							Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(false)},
+							TargetRef:  pod2,
+						},
+					},
+				},
+			},
+			expected: map[string]endpointSliceAddress{
+				"1.2.3.4": {
+					address:      corev1.EndpointAddress{IP: "1.2.3.4", TargetRef: pod1},
+					healthStatus: api.HealthPassing,
+					zone:         "us-west-1a",
+				},
+				"2.2.3.4": {
+					address:      corev1.EndpointAddress{IP: "2.2.3.4", TargetRef: pod2},
+					healthStatus: api.HealthCritical,
+				},
+			},
+		},
+		"address in more than one slice prefers passing": {
+			slices: []discoveryv1.EndpointSlice{
+				{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints: []discoveryv1.Endpoint{
+						{
+							Addresses:  []string{"1.2.3.4"},
+							Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+							TargetRef:  pod1,
+						},
+					},
+				},
+				{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints: []discoveryv1.Endpoint{
+						{
+							Addresses:  []string{"1.2.3.4"},
+							Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(false)},
+							TargetRef:  pod1,
+						},
+					},
+				},
+			},
+			expected: map[string]endpointSliceAddress{
+				"1.2.3.4": {
+					address:      corev1.EndpointAddress{IP: "1.2.3.4", TargetRef: pod1},
+					healthStatus: api.HealthPassing,
+				},
+			},
+		},
+		"FQDN slices and endpoints without addresses are ignored": {
+			slices: []discoveryv1.EndpointSlice{
+				{
+					AddressType: discoveryv1.AddressTypeFQDN,
+					Endpoints: []discoveryv1.Endpoint{
+						{
+							Addresses:  []string{"example.com"},
+							Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+						},
+					},
+				},
+				{
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints: []discoveryv1.Endpoint{
+						{
+							Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+							TargetRef:  pod1,
+						},
+					},
+				},
+			},
+			expected: map[string]endpointSliceAddress{},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expected, mapEndpointSliceAddresses(c.slices))
+		})
+	}
+}
+
+func TestEndpointSliceHealthStatus(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		conditions discoveryv1.EndpointConditions
+		expected   string
+	}{
+		"ready": {
+			conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true), Serving: pointer.Bool(true), Terminating: pointer.Bool(false)},
+			expected:   api.HealthPassing,
+		},
+		"not ready": {
+			conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(false), Serving: pointer.Bool(false), Terminating: pointer.Bool(false)},
+			expected:   api.HealthCritical,
+		},
+		"terminating and serving": {
+			conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(false), Serving: pointer.Bool(true), Terminating: pointer.Bool(true)},
+			expected:   api.HealthCritical,
+		},
+		"terminating and not serving": {
+			conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(false), Serving: pointer.Bool(false), Terminating: pointer.Bool(true)},
+			expected:   api.HealthCritical,
+		},
+		"unknown conditions": {
+			conditions: discoveryv1.EndpointConditions{},
+			expected:   api.HealthPassing,
+		},
+		"unknown ready and not serving": {
+			conditions: discoveryv1.EndpointConditions{Serving: pointer.Bool(false)},
+			expected:   api.HealthCritical,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expected, endpointSliceHealthStatus(c.conditions))
+		})
+	}
+}
+
+func TestEndpointSliceZone(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		endpoint discoveryv1.Endpoint
+		expected string
+	}{
+		"no topology": {
+			endpoint: discoveryv1.Endpoint{},
+			expected: "",
+		},
+		"zone": {
+			endpoint: discoveryv1.Endpoint{Zone: pointer.String("us-west-1a")},
+			expected: "us-west-1a",
+		},
+		"zone takes precedence over hints": {
+			endpoint: discoveryv1.Endpoint{
+				Zone:  pointer.String("us-west-1a"),
+				Hints: &discoveryv1.EndpointHints{ForZones: []discoveryv1.ForZone{{Name: "us-west-1b"}}},
+			},
+			expected: "us-west-1a",
+		},
+		"single zone hint": {
+			endpoint: discoveryv1.Endpoint{
+				Hints: &discoveryv1.EndpointHints{ForZones: []discoveryv1.ForZone{{Name: "us-west-1b"}}},
+			},
+			expected: "us-west-1b",
+		},
+		"multiple zone hints": {
+			endpoint: discoveryv1.Endpoint{
+				Hints: &discoveryv1.EndpointHints{ForZones: []discoveryv1.ForZone{{Name: "us-west-1b"}, {Name: "us-west-1c"}}},
+			},
+			expected: "",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.expected, endpointSliceZone(c.endpoint))
+		})
+	}
+}
+
+// runWithEndpointSlices runs a reconcile test once against Endpoints and once against EndpointSlices.
+func runWithEndpointSlices(t *testing.T, test func(t *testing.T, useEndpointSlices bool)) {
+	for _, useEndpointSlices := range []bool{false, true} {
+		name := "Endpoints"
+		if useEndpointSlices {
+			name = "EndpointSlices"
+		}
+		t.Run(name, func(t *testing.T) {
+			test(t, useEndpointSlices)
+		})
+	}
+}
+
+// endpointSlicesFromEndpoints replaces every Endpoints object in objs with the EndpointSlices that
+// represent the same addresses, so that the Endpoints reconcile tests can be run against the
+// EndpointSlice reconcile path. Each address is placed in its own slice to exercise merging.
+func endpointSlicesFromEndpoints(objs []runtime.Object) []runtime.Object {
+	var result []runtime.Object
+	for _, obj := range objs {
+		endpoints, ok := obj.(*corev1.Endpoints)
+		if !ok {
+			result = append(result, obj)
+			continue
+		}
+
+		labels := map[string]string{discoveryv1.LabelServiceName: endpoints.Name}
+		for k, v := range endpoints.Labels {
+			labels[k] = v
+		}
+		newSlice := func(endpoint ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+			return &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("%s-%d", endpoints.Name, len(result)),
+					Namespace: endpoints.Namespace,
+					Labels:    labels,
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints:   endpoint,
+			}
+		}
+		sliceEndpoint := func(address corev1.EndpointAddress, ready bool) discoveryv1.Endpoint {
+			return discoveryv1.Endpoint{
+				Addresses:  []string{address.IP},
+				Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(ready)},
+				NodeName:   address.NodeName,
+				TargetRef:  address.TargetRef,
+			}
+		}
+
+		// Kubernetes always keeps at least one slice for a service, even if it has no addresses.
+		result = append(result, newSlice())
+		for _, subset := range endpoints.Subsets {
+			for _, address := range subset.Addresses {
+				result = append(result, newSlice(sliceEndpoint(address, true)))
+			}
+			for _, address := range subset.NotReadyAddresses {
+				result = append(result, newSlice(sliceEndpoint(address, false)))
+			}
+		}
+	}
+	return result
+}
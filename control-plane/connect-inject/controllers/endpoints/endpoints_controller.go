@@ -19,9 +19,11 @@ import (
 	"github.com/hashicorp/consul-k8s/control-plane/consul"
 	"github.com/hashicorp/consul-k8s/control-plane/helper/parsetags"
 	"github.com/hashicorp/consul-k8s/control-plane/namespaces"
+	"github.com/hashicorp/consul-server-connection-manager/discovery"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/go-multierror"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -29,6 +31,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
@@ -124,6 +128,11 @@ type Controller struct {
 	// with config to enable telemetry forwarding.
 	EnableTelemetryCollector bool
 
+	// EnableEndpointSlices controls whether service instances are reconciled from the
+	// discovery.k8s.io/v1 EndpointSlices of a service rather than from its Endpoints object.
+	// Endpoints are truncated at 1000 addresses, so this is required for larger services.
+	EnableEndpointSlices bool
+
 	MetricsConfig metrics.Config
 	Log           logr.Logger
 
@@ -137,6 +146,7 @@ type Controller struct {
 
 // Reconcile reads the state of an Endpoints object for a Kubernetes Service and reconciles Consul services which
 // correspond to the Kubernetes Service. These events are driven by changes to the Pods backing the Kube service.
+// If EnableEndpointSlices is set, the EndpointSlices of the Kubernetes Service are read instead.
 func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	var errs error
 	var serviceEndpoints corev1.Endpoints
@@ -158,6 +168,10 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
+	if r.EnableEndpointSlices {
+		return r.reconcileEndpointSlices(ctx, apiClient, serverState, req)
+	}
+
 	err = r.Client.Get(ctx, req.NamespacedName, &serviceEndpoints)
 
 	// endpointPods holds a set of all pods this endpoints object is currently pointing to.
@@ -195,57 +209,8 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// Register all addresses of this Endpoints object as service instances in Consul.
 	for _, subset := range serviceEndpoints.Subsets {
 		for address, healthStatus := range mapAddresses(subset) {
-			if address.TargetRef != nil && address.TargetRef.Kind == "Pod" {
-				var pod corev1.Pod
-				objectKey := types.NamespacedName{Name: address.TargetRef.Name, Namespace: address.TargetRef.Namespace}
-				if err = r.Client.Get(ctx, objectKey, &pod); err != nil {
-					r.Log.Error(err, "failed to get pod", "name", address.TargetRef.Name)
-					errs = multierror.Append(errs, err)
-					continue
-				}
-
-				svcName, ok := pod.Annotations[constants.AnnotationKubernetesService]
-				if ok && serviceEndpoints.Name != svcName {
-					r.Log.Info("ignoring endpoint because it doesn't match explicit service annotation", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
-					// deregistration for service instances that don't match the annotation happens
-					// later because we don't add this pod to the endpointAddressMap.
-					continue
-				}
-
-				if hasBeenInjected(pod) {
-					endpointPods.Add(address.TargetRef.Name)
-					if isConsulDataplaneSupported(pod) {
-						if err = r.registerServicesAndHealthCheck(apiClient, pod, serviceEndpoints, healthStatus, endpointAddressMap); err != nil {
-							r.Log.Error(err, "failed to register services or health check", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
-							errs = multierror.Append(errs, err)
-						}
-					} else {
-						r.Log.Info("detected an update to pre-consul-dataplane service", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
-						nodeAgentClientCfg, err := r.consulClientCfgForNodeAgent(apiClient, pod, serverState)
-						if err != nil {
-							r.Log.Error(err, "failed to create node-local Consul API client", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
-							errs = multierror.Append(errs, err)
-							continue
-						}
-						r.Log.Info("updating health check on the Consul client", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
-						if err = r.updateHealthCheckOnConsulClient(nodeAgentClientCfg, pod, serviceEndpoints, healthStatus); err != nil {
-							r.Log.Error(err, "failed to update health check on Consul client", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace, "consul-client-ip", pod.Status.HostIP)
-							errs = multierror.Append(errs, err)
-						}
-						// We want to skip the rest of the reconciliation because we only care about updating health checks for existing services
-						// in the case when Consul clients are running in the cluster. If endpoints are deleted, consul clients
-						// will detect that they are unhealthy, and we don't need to worry about keeping them up-to-date.
-						// This is so that health checks are still updated during an upgrade to consul-dataplane.
-						continue
-					}
-				}
-				if isGateway(pod) {
-					endpointPods.Add(address.TargetRef.Name)
-					if err = r.registerGateway(apiClient, pod, serviceEndpoints, healthStatus, endpointAddressMap); err != nil {
-						r.Log.Error(err, "failed to register gateway or health check", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
-						errs = multierror.Append(errs, err)
-					}
-				}
+			if err = r.registerEndpointAddress(ctx, apiClient, serverState, serviceEndpoints, address, healthStatus, "", endpointAddressMap, endpointPods); err != nil {
+				errs = multierror.Append(errs, err)
 			}
 		}
 	}
@@ -261,11 +226,82 @@ func (r *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	return ctrl.Result{}, errs
 }
 
+// registerEndpointAddress registers the service instance (or gateway) backed by a single address of a
+// Kubernetes service with Consul. The zone is the topology zone reported for the address, if any, and
+// overrides the zone label of the pod's node when computing the service locality.
+func (r *Controller) registerEndpointAddress(ctx context.Context, apiClient *api.Client, serverState discovery.State, serviceEndpoints corev1.Endpoints,
+	address corev1.EndpointAddress, healthStatus, zone string, endpointAddressMap map[string]bool, endpointPods mapset.Set) error {
+	if address.TargetRef == nil || address.TargetRef.Kind != "Pod" {
+		return nil
+	}
+
+	var errs error
+	var pod corev1.Pod
+	objectKey := types.NamespacedName{Name: address.TargetRef.Name, Namespace: address.TargetRef.Namespace}
+	if err := r.Client.Get(ctx, objectKey, &pod); err != nil {
+		r.Log.Error(err, "failed to get pod", "name", address.TargetRef.Name)
+		return err
+	}
+
+	svcName, ok := pod.Annotations[constants.AnnotationKubernetesService]
+	if ok && serviceEndpoints.Name != svcName {
+		r.Log.Info("ignoring endpoint because it doesn't match explicit service annotation", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
+		// deregistration for service instances that don't match the annotation happens
+		// later because we don't add this pod to the endpointAddressMap.
+		return nil
+	}
+
+	if hasBeenInjected(pod) {
+		endpointPods.Add(address.TargetRef.Name)
+		if isConsulDataplaneSupported(pod) {
+			if err := r.registerServicesAndHealthCheck(apiClient, pod, serviceEndpoints, healthStatus, zone, endpointAddressMap); err != nil {
+				r.Log.Error(err, "failed to register services or health check", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
+				errs = multierror.Append(errs, err)
+			}
+		} else {
+			r.Log.Info("detected an update to pre-consul-dataplane service", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
+			nodeAgentClientCfg, err := r.consulClientCfgForNodeAgent(apiClient, pod, serverState)
+			if err != nil {
+				r.Log.Error(err, "failed to create node-local Consul API client", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
+				return err
+			}
+			r.Log.Info("updating health check on the Consul client", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
+			if err = r.updateHealthCheckOnConsulClient(nodeAgentClientCfg, pod, serviceEndpoints, healthStatus); err != nil {
+				r.Log.Error(err, "failed to update health check on Consul client", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace, "consul-client-ip", pod.Status.HostIP)
+				return err
+			}
+			// We want to skip the rest of the reconciliation because we only care about updating health checks for existing services
+			// in the case when Consul clients are running in the cluster. If endpoints are deleted, consul clients
+			// will detect that they are unhealthy, and we don't need to worry about keeping them up-to-date.
+			// This is so that health checks are still updated during an upgrade to consul-dataplane.
+			return nil
+		}
+	}
+	if isGateway(pod) {
+		endpointPods.Add(address.TargetRef.Name)
+		if err := r.registerGateway(apiClient, pod, serviceEndpoints, healthStatus, endpointAddressMap); err != nil {
+			r.Log.Error(err, "failed to register gateway or health check", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
 func (r *Controller) Logger(name types.NamespacedName) logr.Logger {
 	return r.Log.WithValues("request", name)
 }
 
 func (r *Controller) SetupWithManager(mgr ctrl.Manager) error {
+	if r.EnableEndpointSlices {
+		// EndpointSlices are named independently of their service, so requests are keyed by the Service
+		// and every slice event is mapped back to the service that owns it.
+		return ctrl.NewControllerManagedBy(mgr).
+			For(&corev1.Service{}).
+			Watches(
+				&source.Kind{Type: &discoveryv1.EndpointSlice{}},
+				handler.EnqueueRequestsFromMapFunc(requestsForEndpointSlice),
+			).Complete(r)
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Endpoints{}).
 		Complete(r)
@@ -273,7 +309,7 @@ func (r *Controller) SetupWithManager(mgr ctrl.Manager) error {
 
 // registerServicesAndHealthCheck creates Consul registrations for the service and proxy and registers them with Consul.
 // It also upserts a Kubernetes health check for the service based on whether the endpoint address is ready.
-func (r *Controller) registerServicesAndHealthCheck(apiClient *api.Client, pod corev1.Pod, serviceEndpoints corev1.Endpoints, healthStatus string, endpointAddressMap map[string]bool) error {
+func (r *Controller) registerServicesAndHealthCheck(apiClient *api.Client, pod corev1.Pod, serviceEndpoints corev1.Endpoints, healthStatus, zone string, endpointAddressMap map[string]bool) error {
 	// Build the endpointAddressMap up for deregistering service instances later.
 	endpointAddressMap[pod.Status.PodIP] = true
 
@@ -284,7 +320,7 @@ func (r *Controller) registerServicesAndHealthCheck(apiClient *api.Client, pod c
 	// For pods managed by this controller, create and register the service instance.
 	if managedByEndpointsController {
 		// Get information from the pod to create service instance registrations.
-		serviceRegistration, proxyServiceRegistration, err := r.createServiceRegistrations(pod, serviceEndpoints, healthStatus)
+		serviceRegistration, proxyServiceRegistration, err := r.createServiceRegistrations(pod, serviceEndpoints, healthStatus, zone)
 		if err != nil {
 			r.Log.Error(err, "failed to create service registrations for endpoints", "name", serviceEndpoints.Name, "ns", serviceEndpoints.Namespace)
 			return err
@@ -399,8 +435,8 @@ func proxyServiceID(pod corev1.Pod, serviceEndpoints corev1.Endpoints) string {
 }
 
 // createServiceRegistrations creates the service and proxy service instance registrations with the information from the
-// Pod.
-func (r *Controller) createServiceRegistrations(pod corev1.Pod, serviceEndpoints corev1.Endpoints, healthStatus string) (*api.CatalogRegistration, *api.CatalogRegistration, error) {
+// Pod. If zone is set, it takes precedence over the zone label of the Pod's node in the service locality.
+func (r *Controller) createServiceRegistrations(pod corev1.Pod, serviceEndpoints corev1.Endpoints, healthStatus, zone string) (*api.CatalogRegistration, *api.CatalogRegistration, error) {
 	// If a port is specified, then we determine the value of that port
 	// and register that port for the host service.
 	// The meshWebhook will always set the port annotation if one is not provided on the pod.
@@ -423,6 +459,9 @@ func (r *Controller) createServiceRegistrations(pod corev1.Pod, serviceEndpoints
 	// Ignore errors because we don't want failures to block running services.
 	_ = r.Client.Get(context.Background(), types.NamespacedName{Name: pod.Spec.NodeName, Namespace: pod.Namespace}, &node)
 	locality := parseLocality(node)
+	if locality != nil && zone != "" {
+		locality.Zone = zone
+	}
 
 	// We only want that annotation to be present when explicitly overriding the consul svc name
 	// Otherwise, the Consul service name should equal the Kubernetes Service name.
@@ -1013,7 +1052,7 @@ func (r *Controller) deleteACLTokensForServiceInstance(apiClient *api.Client, sv
 	return nil
 }
 
-// processUpstreams reads the list of upstreams from the Pod annotation and converts them into a list of api.Upstream
+// processUpstreams reads the list of upstreams from the Pod annotations and converts them into a list of api.Upstream
 // objects.
 func (r *Controller) processUpstreams(pod corev1.Pod, endpoints corev1.Endpoints) ([]api.Upstream, error) {
 	// In a multiport pod, only the first service's proxy should have upstreams configured. This skips configuring
@@ -1023,50 +1062,7 @@ func (r *Controller) processUpstreams(pod corev1.Pod, endpoints corev1.Endpoints
 		return []api.Upstream{}, nil
 	}
 
-	var upstreams []api.Upstream
-	if raw, ok := pod.Annotations[constants.AnnotationUpstreams]; ok && raw != "" {
-		for _, raw := range strings.Split(raw, ",") {
-			var upstream api.Upstream
-
-			// parts separates out the port, and determines whether it's a prepared query or not, since parts[0] would
-			// be "prepared_query" if it is.
-			parts := strings.SplitN(raw, ":", 3)
-
-			// serviceParts helps determine which format of upstream we're processing,
-			// [service-name].[service-namespace].[service-partition]:[port]:[optional datacenter]
-			// or
-			// [service-name].svc.[service-namespace].ns.[service-peer].peer:[port]
-			// [service-name].svc.[service-namespace].ns.[service-partition].ap:[port]
-			// [service-name].svc.[service-namespace].ns.[service-datacenter].dc:[port]
-			labeledFormat := false
-			serviceParts := strings.Split(parts[0], ".")
-			if len(serviceParts) >= 2 {
-				if serviceParts[1] == "svc" {
-					labeledFormat = true
-				}
-			}
-
-			if strings.TrimSpace(parts[0]) == "prepared_query" {
-				upstream = processPreparedQueryUpstream(pod, raw)
-			} else if labeledFormat {
-				var err error
-				upstream, err = r.processLabeledUpstream(pod, raw)
-				if err != nil {
-					return []api.Upstream{}, err
-				}
-			} else {
-				var err error
-				upstream, err = r.processUnlabeledUpstream(pod, raw)
-				if err != nil {
-					return []api.Upstream{}, err
-				}
-			}
-
-			upstreams = append(upstreams, upstream)
-		}
-	}
-
-	return upstreams, nil
+	return common.ProcessUpstreams(pod, r.EnableConsulNamespaces, r.EnableConsulPartitions)
 }
 
 // getTokenMetaFromDescription parses JSON metadata from token's description.
@@ -1122,153 +1118,6 @@ func (r *Controller) serviceInstancesForK8SServiceNameAndNamespace(apiClient *ap
 	return serviceList, err
 }
 
-// processPreparedQueryUpstream processes an upstream in the format:
-// prepared_query:[query name]:[port].
-func processPreparedQueryUpstream(pod corev1.Pod, rawUpstream string) api.Upstream {
-	var preparedQuery string
-	var port int32
-	parts := strings.SplitN(rawUpstream, ":", 3)
-
-	port, _ = common.PortValue(pod, strings.TrimSpace(parts[2]))
-	preparedQuery = strings.TrimSpace(parts[1])
-	var upstream api.Upstream
-	if port > 0 {
-		upstream = api.Upstream{
-			DestinationType: api.UpstreamDestTypePreparedQuery,
-			DestinationName: preparedQuery,
-			LocalBindPort:   int(port),
-		}
-	}
-	return upstream
-}
-
-// processUnlabeledUpstream processes an upstream in the format:
-// [service-name].[service-namespace].[service-partition]:[port]:[optional datacenter].
-func (r *Controller) processUnlabeledUpstream(pod corev1.Pod, rawUpstream string) (api.Upstream, error) {
-	var datacenter, svcName, namespace, partition, peer string
-	var port int32
-	var upstream api.Upstream
-
-	parts := strings.SplitN(rawUpstream, ":", 3)
-
-	port, _ = common.PortValue(pod, strings.TrimSpace(parts[1]))
-
-	// If Consul Namespaces or Admin Partitions are enabled, attempt to parse the
-	// upstream for a namespace.
-	if r.EnableConsulNamespaces || r.EnableConsulPartitions {
-		pieces := strings.SplitN(parts[0], ".", 3)
-		switch len(pieces) {
-		case 3:
-			partition = strings.TrimSpace(pieces[2])
-			fallthrough
-		case 2:
-			namespace = strings.TrimSpace(pieces[1])
-			fallthrough
-		default:
-			svcName = strings.TrimSpace(pieces[0])
-		}
-	} else {
-		svcName = strings.TrimSpace(parts[0])
-	}
-
-	// parse the optional datacenter
-	if len(parts) > 2 {
-		datacenter = strings.TrimSpace(parts[2])
-	}
-	if port > 0 {
-		upstream = api.Upstream{
-			DestinationType:      api.UpstreamDestTypeService,
-			DestinationPartition: partition,
-			DestinationPeer:      peer,
-			DestinationNamespace: namespace,
-			DestinationName:      svcName,
-			Datacenter:           datacenter,
-			LocalBindPort:        int(port),
-		}
-	}
-	return upstream, nil
-}
-
-// processLabeledUpstream processes an upstream in the format:
-// [service-name].svc.[service-namespace].ns.[service-peer].peer:[port]
-// [service-name].svc.[service-namespace].ns.[service-partition].ap:[port]
-// [service-name].svc.[service-namespace].ns.[service-datacenter].dc:[port].
-func (r *Controller) processLabeledUpstream(pod corev1.Pod, rawUpstream string) (api.Upstream, error) {
-	var datacenter, svcName, namespace, partition, peer string
-	var port int32
-	var upstream api.Upstream
-
-	parts := strings.SplitN(rawUpstream, ":", 3)
-
-	port, _ = common.PortValue(pod, strings.TrimSpace(parts[1]))
-
-	service := parts[0]
-
-	pieces := strings.Split(service, ".")
-
-	if r.EnableConsulNamespaces || r.EnableConsulPartitions {
-		switch len(pieces) {
-		case 6:
-			end := strings.TrimSpace(pieces[5])
-			switch end {
-			case "peer":
-				peer = strings.TrimSpace(pieces[4])
-			case "ap":
-				partition = strings.TrimSpace(pieces[4])
-			case "dc":
-				datacenter = strings.TrimSpace(pieces[4])
-			default:
-				return api.Upstream{}, fmt.Errorf("upstream structured incorrectly: %s", rawUpstream)
-			}
-			fallthrough
-		case 4:
-			if strings.TrimSpace(pieces[3]) == "ns" {
-				namespace = strings.TrimSpace(pieces[2])
-			} else {
-				return api.Upstream{}, fmt.Errorf("upstream structured incorrectly: %s", rawUpstream)
-			}
-			fallthrough
-		case 2:
-			if strings.TrimSpace(pieces[1]) == "svc" {
-				svcName = strings.TrimSpace(pieces[0])
-			}
-		default:
-			return api.Upstream{}, fmt.Errorf("upstream structured incorrectly: %s", rawUpstream)
-		}
-	} else {
-		switch len(pieces) {
-		case 4:
-			end := strings.TrimSpace(pieces[3])
-			switch end {
-			case "peer":
-				peer = strings.TrimSpace(pieces[2])
-			case "dc":
-				datacenter = strings.TrimSpace(pieces[2])
-			default:
-				return api.Upstream{}, fmt.Errorf("upstream structured incorrectly: %s", rawUpstream)
-			}
-			fallthrough
-		case 2:
-			svcName = strings.TrimSpace(pieces[0])
-		default:
-			return api.Upstream{}, fmt.Errorf("upstream structured incorrectly: %s", rawUpstream)
-		}
-	}
-
-	if port > 0 {
-		upstream = api.Upstream{
-			DestinationType:      api.UpstreamDestTypeService,
-			DestinationPartition: partition,
-			DestinationPeer:      peer,
-			DestinationNamespace: namespace,
-			DestinationName:      svcName,
-			Datacenter:           datacenter,
-			LocalBindPort:        int(port),
-		}
-	}
-	return upstream, nil
-}
-
 // shouldIgnore ignores namespaces where we don't connect-inject.
 func shouldIgnore(namespace string, denySet, allowSet mapset.Set) bool {
 	// Ignores system namespaces.
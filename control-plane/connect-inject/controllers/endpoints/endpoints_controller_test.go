@@ -692,6 +692,11 @@ func TestGetServiceName(t *testing.T) {
 }
 
 func TestReconcileCreateEndpoint_MultiportService(t *testing.T) {
+	t.Parallel()
+	runWithEndpointSlices(t, testReconcileCreateEndpointMultiportService)
+}
+
+func testReconcileCreateEndpointMultiportService(t *testing.T, useEndpointSlices bool) {
 	t.Parallel()
 	cases := []struct {
 		name                       string
@@ -881,6 +886,9 @@ func TestReconcileCreateEndpoint_MultiportService(t *testing.T) {
 			node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
 			// Create fake k8s client
 			k8sObjects := append(tt.k8sObjects(), &ns, &node)
+			if useEndpointSlices {
+				k8sObjects = endpointSlicesFromEndpoints(k8sObjects)
+			}
 
 			fakeClient := fake.NewClientBuilder().WithRuntimeObjects(k8sObjects...).Build()
 
@@ -907,6 +915,7 @@ func TestReconcileCreateEndpoint_MultiportService(t *testing.T) {
 				ConsulServerConnMgr:   testClient.Watcher,
 				AllowK8sNamespacesSet: mapset.NewSetWith("*"),
 				DenyK8sNamespacesSet:  mapset.NewSetWith(),
+				EnableEndpointSlices:  useEndpointSlices,
 				ReleaseName:           "consul",
 				ReleaseNamespace:      "default",
 			}
@@ -986,6 +995,11 @@ func TestReconcileCreateEndpoint_MultiportService(t *testing.T) {
 // object. This test covers Controller.createServiceRegistrations and Controller.createGatewayRegistrations.
 // This test depends on a Consul binary being present on the host machine.
 func TestReconcileCreateEndpoint(t *testing.T) {
+	t.Parallel()
+	runWithEndpointSlices(t, testReconcileCreateEndpoint)
+}
+
+func testReconcileCreateEndpoint(t *testing.T, useEndpointSlices bool) {
 	t.Parallel()
 	cases := []struct {
 		name                       string
@@ -2151,6 +2165,9 @@ func TestReconcileCreateEndpoint(t *testing.T) {
 			node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
 			// Create fake k8s client
 			k8sObjects := append(tt.k8sObjects(), &ns, &node)
+			if useEndpointSlices {
+				k8sObjects = endpointSlicesFromEndpoints(k8sObjects)
+			}
 
 			fakeClient := fake.NewClientBuilder().WithRuntimeObjects(k8sObjects...).Build()
 
@@ -2166,6 +2183,7 @@ func TestReconcileCreateEndpoint(t *testing.T) {
 				ConsulServerConnMgr:   testClient.Watcher,
 				AllowK8sNamespacesSet: mapset.NewSetWith("*"),
 				DenyK8sNamespacesSet:  mapset.NewSetWith(),
+				EnableEndpointSlices:  useEndpointSlices,
 				ReleaseName:           "consulServer",
 				ReleaseNamespace:      "default",
 				NodeMeta:              tt.nodeMeta,
@@ -2297,6 +2315,11 @@ func TestParseLocality(t *testing.T) {
 // This test covers Controller.deregisterService when services should be selectively deregistered
 // since the map will not be nil.
 func TestReconcileUpdateEndpoint(t *testing.T) {
+	t.Parallel()
+	runWithEndpointSlices(t, testReconcileUpdateEndpoint)
+}
+
+func testReconcileUpdateEndpoint(t *testing.T, useEndpointSlices bool) {
 	t.Parallel()
 	cases := []struct {
 		name                       string
@@ -3457,6 +3480,9 @@ func TestReconcileUpdateEndpoint(t *testing.T) {
 			node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
 			// Create fake k8s client.
 			k8sObjects := append(tt.k8sObjects(), &ns, &node)
+			if useEndpointSlices {
+				k8sObjects = endpointSlicesFromEndpoints(k8sObjects)
+			}
 			fakeClient := fake.NewClientBuilder().WithRuntimeObjects(k8sObjects...).Build()
 
 			// Create test consulServer server
@@ -3520,6 +3546,7 @@ func TestReconcileUpdateEndpoint(t *testing.T) {
 				ConsulServerConnMgr:   testClient.Watcher,
 				AllowK8sNamespacesSet: mapset.NewSetWith("*"),
 				DenyK8sNamespacesSet:  mapset.NewSetWith(),
+				EnableEndpointSlices:  useEndpointSlices,
 				ReleaseName:           "consul",
 				ReleaseNamespace:      "default",
 			}
@@ -3797,6 +3824,11 @@ func TestReconcileUpdateEndpoint_LegacyService(t *testing.T) {
 // Tests deleting an Endpoints object, with and without matching Consul and K8s service names.
 // This test covers Controller.deregisterService when the map is nil (not selectively deregistered).
 func TestReconcileDeleteEndpoint(t *testing.T) {
+	t.Parallel()
+	runWithEndpointSlices(t, testReconcileDeleteEndpoint)
+}
+
+func testReconcileDeleteEndpoint(t *testing.T, useEndpointSlices bool) {
 	t.Parallel()
 	cases := []struct {
 		name                      string
@@ -4144,6 +4176,7 @@ func TestReconcileDeleteEndpoint(t *testing.T) {
 				ConsulServerConnMgr:   testClient.Watcher,
 				AllowK8sNamespacesSet: mapset.NewSetWith("*"),
 				DenyK8sNamespacesSet:  mapset.NewSetWith(),
+				EnableEndpointSlices:  useEndpointSlices,
 				ReleaseName:           "consul",
 				ReleaseNamespace:      "default",
 			}
@@ -4188,6 +4221,11 @@ func TestReconcileDeleteEndpoint(t *testing.T) {
 // with the service-ignore label and deregisters services previously registered if the service-ignore
 // label is added.
 func TestReconcileIgnoresServiceIgnoreLabel(t *testing.T) {
+	t.Parallel()
+	runWithEndpointSlices(t, testReconcileIgnoresServiceIgnoreLabel)
+}
+
+func testReconcileIgnoresServiceIgnoreLabel(t *testing.T, useEndpointSlices bool) {
 	t.Parallel()
 	svcName := "service-ignored"
 	namespace := "default"
@@ -4251,6 +4289,9 @@ func TestReconcileIgnoresServiceIgnoreLabel(t *testing.T) {
 			ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
 			node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
 			k8sObjects := []runtime.Object{endpoint, pod1, &ns, &node}
+			if useEndpointSlices {
+				k8sObjects = endpointSlicesFromEndpoints(k8sObjects)
+			}
 			fakeClient := fake.NewClientBuilder().WithRuntimeObjects(k8sObjects...).Build()
 
 			// Create test consulServer server
@@ -4289,6 +4330,7 @@ func TestReconcileIgnoresServiceIgnoreLabel(t *testing.T) {
 				ConsulServerConnMgr:   testClient.Watcher,
 				AllowK8sNamespacesSet: mapset.NewSetWith("*"),
 				DenyK8sNamespacesSet:  mapset.NewSetWith(),
+				EnableEndpointSlices:  useEndpointSlices,
 				ReleaseName:           "consul",
 				ReleaseNamespace:      namespace,
 			}
@@ -5852,7 +5894,7 @@ func TestCreateServiceRegistrations_withTransparentProxy(t *testing.T) {
 				Log:                    logrtest.New(t),
 			}
 
-			serviceRegistration, proxyServiceRegistration, err := epCtrl.createServiceRegistrations(*pod, *endpoints, api.HealthPassing)
+			serviceRegistration, proxyServiceRegistration, err := epCtrl.createServiceRegistrations(*pod, *endpoints, api.HealthPassing, "")
 			if c.expErr != "" {
 				require.EqualError(t, err, c.expErr)
 			} else {
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lifecycle
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/common"
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/constants"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	DefaultGracefulPort         = 20600
+	DefaultGracefulShutdownPath = "/graceful_shutdown"
+	DefaultGracefulStartupPath  = "/graceful_startup"
+)
+
+// Config represents configuration common to connect-inject components related to the lifecycle of the
+// sidecar proxy.
+type Config struct {
+	DefaultEnableProxyLifecycle         bool
+	DefaultEnableShutdownDrainListeners bool
+	DefaultShutdownGracePeriodSeconds   int
+	DefaultStartupGracePeriodSeconds    int
+	DefaultGracefulPort                 string
+	DefaultGracefulShutdownPath         string
+	DefaultGracefulStartupPath          string
+}
+
+// EnableProxyLifecycle returns whether proxy lifecycle management is enabled either via the default value in the
+// meshWebhook, or if it's been overridden via the annotation.
+func (lc Config) EnableProxyLifecycle(pod corev1.Pod) (bool, error) {
+	return parseBool(pod, constants.AnnotationEnableSidecarProxyLifecycle, lc.DefaultEnableProxyLifecycle)
+}
+
+// EnableShutdownDrainListeners returns whether the proxy should drain its inbound listeners on shutdown, either via
+// the default value in the meshWebhook, or if it's been overridden via the annotation.
+func (lc Config) EnableShutdownDrainListeners(pod corev1.Pod) (bool, error) {
+	return parseBool(pod, constants.AnnotationEnableSidecarProxyLifecycleShutdownDrainListeners, lc.DefaultEnableShutdownDrainListeners)
+}
+
+// ShutdownGracePeriodSeconds returns how long the proxy keeps running after it is asked to shut down, either via the
+// default value in the meshWebhook, or if it's been overridden via the annotation.
+func (lc Config) ShutdownGracePeriodSeconds(pod corev1.Pod) (int, error) {
+	return parseSeconds(pod, constants.AnnotationSidecarProxyLifecycleShutdownGracePeriodSeconds, lc.DefaultShutdownGracePeriodSeconds)
+}
+
+// StartupGracePeriodSeconds returns how long the application containers are held until the proxy is ready, either via
+// the default value in the meshWebhook, or if it's been overridden via the annotation. A value of 0 means the
+// application containers are not held.
+func (lc Config) StartupGracePeriodSeconds(pod corev1.Pod) (int, error) {
+	return parseSeconds(pod, constants.AnnotationSidecarProxyLifecycleStartupGracePeriodSeconds, lc.DefaultStartupGracePeriodSeconds)
+}
+
+// GracefulPort returns the port consul-dataplane serves the graceful startup and shutdown endpoints on, either via
+// the default value in the meshWebhook, or if it's been overridden via the annotation. It also validates the port is
+// in the unprivileged port range.
+func (lc Config) GracefulPort(pod corev1.Pod) (int, error) {
+	raw, ok := pod.Annotations[constants.AnnotationSidecarProxyLifecycleGracefulPort]
+	if !ok || raw == "" {
+		if lc.DefaultGracefulPort == "" {
+			return DefaultGracefulPort, nil
+		}
+		raw = lc.DefaultGracefulPort
+	}
+
+	port, err := common.PortValue(pod, raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s annotation value of %s is not a valid integer", constants.AnnotationSidecarProxyLifecycleGracefulPort, raw)
+	}
+	if port < 1024 || port > 65535 {
+		return 0, fmt.Errorf("%s annotation value of %d is not in the unprivileged port range 1024-65535", constants.AnnotationSidecarProxyLifecycleGracefulPort, port)
+	}
+
+	return int(port), nil
+}
+
+// GracefulShutdownPath returns the path of the graceful shutdown endpoint, either via the default value in the
+// meshWebhook, or if it's been overridden via the annotation.
+func (lc Config) GracefulShutdownPath(pod corev1.Pod) string {
+	if raw, ok := pod.Annotations[constants.AnnotationSidecarProxyLifecycleGracefulShutdownPath]; ok && raw != "" {
+		return raw
+	}
+
+	if lc.DefaultGracefulShutdownPath == "" {
+		return DefaultGracefulShutdownPath
+	}
+
+	return lc.DefaultGracefulShutdownPath
+}
+
+// GracefulStartupPath returns the path of the graceful startup endpoint, either via the default value in the
+// meshWebhook, or if it's been overridden via the annotation.
+func (lc Config) GracefulStartupPath(pod corev1.Pod) string {
+	if raw, ok := pod.Annotations[constants.AnnotationSidecarProxyLifecycleGracefulStartupPath]; ok && raw != "" {
+		return raw
+	}
+
+	if lc.DefaultGracefulStartupPath == "" {
+		return DefaultGracefulStartupPath
+	}
+
+	return lc.DefaultGracefulStartupPath
+}
+
+// HoldApplicationUntilProxyReady returns whether the application containers of the pod must not be started until
+// the proxy is ready. This is the case when proxy lifecycle management is enabled and the startup grace period is
+// greater than zero.
+func (lc Config) HoldApplicationUntilProxyReady(pod corev1.Pod) (bool, error) {
+	enabled, err := lc.EnableProxyLifecycle(pod)
+	if err != nil || !enabled {
+		return false, err
+	}
+
+	startupGracePeriodSeconds, err := lc.StartupGracePeriodSeconds(pod)
+	if err != nil {
+		return false, err
+	}
+
+	return startupGracePeriodSeconds > 0, nil
+}
+
+func parseBool(pod corev1.Pod, annotation string, defaultValue bool) (bool, error) {
+	if raw, ok := pod.Annotations[annotation]; ok && raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return false, fmt.Errorf("%s annotation value of %s was invalid: %s", annotation, raw, err)
+		}
+		return value, nil
+	}
+	return defaultValue, nil
+}
+
+func parseSeconds(pod corev1.Pod, annotation string, defaultValue int) (int, error) {
+	if raw, ok := pod.Annotations[annotation]; ok && raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return 0, fmt.Errorf("%s annotation value of %s was invalid: %s", annotation, raw, err)
+		}
+		if value < 0 {
+			return 0, fmt.Errorf("%s annotation value of %d must not be negative", annotation, value)
+		}
+		return value, nil
+	}
+	return defaultValue, nil
+}
@@ -0,0 +1,414 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/constants"
+	"github.com/hashicorp/consul-k8s/control-plane/namespaces"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLifecycleConfigEnableProxyLifecycle(t *testing.T) {
+	cases := []struct {
+		Name            string
+		Pod             func(*corev1.Pod) *corev1.Pod
+		LifecycleConfig Config
+		Expected        bool
+		Err             string
+	}{
+		{
+			Name: "Proxy lifecycle enabled via meshWebhook",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultEnableProxyLifecycle: true,
+			},
+			Expected: true,
+		},
+		{
+			Name: "Proxy lifecycle enabled via annotation",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationEnableSidecarProxyLifecycle] = "true"
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultEnableProxyLifecycle: false,
+			},
+			Expected: true,
+		},
+		{
+			Name: "Proxy lifecycle disabled via annotation",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationEnableSidecarProxyLifecycle] = "false"
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultEnableProxyLifecycle: true,
+			},
+			Expected: false,
+		},
+		{
+			Name: "Proxy lifecycle configured via invalid annotation",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationEnableSidecarProxyLifecycle] = "not-a-bool"
+				return pod
+			},
+			Err: "consul.hashicorp.com/enable-sidecar-proxy-lifecycle annotation value of not-a-bool was invalid: strconv.ParseBool: parsing \"not-a-bool\": invalid syntax",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			actual, err := tt.LifecycleConfig.EnableProxyLifecycle(*tt.Pod(minimal()))
+
+			if tt.Err == "" {
+				require.NoError(t, err)
+				require.Equal(t, tt.Expected, actual)
+			} else {
+				require.EqualError(t, err, tt.Err)
+			}
+		})
+	}
+}
+
+func TestLifecycleConfigEnableShutdownDrainListeners(t *testing.T) {
+	cases := []struct {
+		Name            string
+		Pod             func(*corev1.Pod) *corev1.Pod
+		LifecycleConfig Config
+		Expected        bool
+		Err             string
+	}{
+		{
+			Name: "Drain listeners enabled via meshWebhook",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultEnableShutdownDrainListeners: true,
+			},
+			Expected: true,
+		},
+		{
+			Name: "Drain listeners disabled via annotation",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationEnableSidecarProxyLifecycleShutdownDrainListeners] = "false"
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultEnableShutdownDrainListeners: true,
+			},
+			Expected: false,
+		},
+		{
+			Name: "Drain listeners configured via invalid annotation",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationEnableSidecarProxyLifecycleShutdownDrainListeners] = "not-a-bool"
+				return pod
+			},
+			Err: "consul.hashicorp.com/enable-sidecar-proxy-lifecycle-shutdown-drain-listeners annotation value of not-a-bool was invalid: strconv.ParseBool: parsing \"not-a-bool\": invalid syntax",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			actual, err := tt.LifecycleConfig.EnableShutdownDrainListeners(*tt.Pod(minimal()))
+
+			if tt.Err == "" {
+				require.NoError(t, err)
+				require.Equal(t, tt.Expected, actual)
+			} else {
+				require.EqualError(t, err, tt.Err)
+			}
+		})
+	}
+}
+
+func TestLifecycleConfigGracePeriodSeconds(t *testing.T) {
+	cases := []struct {
+		Name             string
+		Pod              func(*corev1.Pod) *corev1.Pod
+		LifecycleConfig  Config
+		ExpectedShutdown int
+		ExpectedStartup  int
+		Err              string
+	}{
+		{
+			Name: "Grace periods set via meshWebhook",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultShutdownGracePeriodSeconds: 30,
+				DefaultStartupGracePeriodSeconds:  10,
+			},
+			ExpectedShutdown: 30,
+			ExpectedStartup:  10,
+		},
+		{
+			Name: "Grace periods set via annotation",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationSidecarProxyLifecycleShutdownGracePeriodSeconds] = "15"
+				pod.Annotations[constants.AnnotationSidecarProxyLifecycleStartupGracePeriodSeconds] = "0"
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultShutdownGracePeriodSeconds: 30,
+				DefaultStartupGracePeriodSeconds:  10,
+			},
+			ExpectedShutdown: 15,
+			ExpectedStartup:  0,
+		},
+		{
+			Name: "Grace period set via invalid annotation",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationSidecarProxyLifecycleShutdownGracePeriodSeconds] = "not-an-int"
+				return pod
+			},
+			Err: "consul.hashicorp.com/sidecar-proxy-lifecycle-shutdown-grace-period-seconds annotation value of not-an-int was invalid: strconv.Atoi: parsing \"not-an-int\": invalid syntax",
+		},
+		{
+			Name: "Grace period set via negative annotation",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationSidecarProxyLifecycleStartupGracePeriodSeconds] = "-1"
+				return pod
+			},
+			Err: "consul.hashicorp.com/sidecar-proxy-lifecycle-startup-grace-period-seconds annotation value of -1 must not be negative",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			pod := *tt.Pod(minimal())
+
+			shutdown, shutdownErr := tt.LifecycleConfig.ShutdownGracePeriodSeconds(pod)
+			startup, startupErr := tt.LifecycleConfig.StartupGracePeriodSeconds(pod)
+
+			if tt.Err == "" {
+				require.NoError(t, shutdownErr)
+				require.NoError(t, startupErr)
+				require.Equal(t, tt.ExpectedShutdown, shutdown)
+				require.Equal(t, tt.ExpectedStartup, startup)
+			} else if shutdownErr != nil {
+				require.EqualError(t, shutdownErr, tt.Err)
+			} else {
+				require.EqualError(t, startupErr, tt.Err)
+			}
+		})
+	}
+}
+
+func TestLifecycleConfigGracefulPort(t *testing.T) {
+	cases := []struct {
+		Name            string
+		Pod             func(*corev1.Pod) *corev1.Pod
+		LifecycleConfig Config
+		Expected        int
+		Err             string
+	}{
+		{
+			Name: "Default graceful port",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				return pod
+			},
+			Expected: DefaultGracefulPort,
+		},
+		{
+			Name: "Graceful port set via meshWebhook",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultGracefulPort: "21600",
+			},
+			Expected: 21600,
+		},
+		{
+			Name: "Graceful port set via annotation",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationSidecarProxyLifecycleGracefulPort] = "22600"
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultGracefulPort: "21600",
+			},
+			Expected: 22600,
+		},
+		{
+			Name: "Graceful port set via invalid annotation",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationSidecarProxyLifecycleGracefulPort] = "not-a-port"
+				return pod
+			},
+			Err: "consul.hashicorp.com/sidecar-proxy-lifecycle-graceful-port annotation value of not-a-port is not a valid integer",
+		},
+		{
+			Name: "Graceful port set to a privileged port",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationSidecarProxyLifecycleGracefulPort] = "80"
+				return pod
+			},
+			Err: "consul.hashicorp.com/sidecar-proxy-lifecycle-graceful-port annotation value of 80 is not in the unprivileged port range 1024-65535",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			actual, err := tt.LifecycleConfig.GracefulPort(*tt.Pod(minimal()))
+
+			if tt.Err == "" {
+				require.NoError(t, err)
+				require.Equal(t, tt.Expected, actual)
+			} else {
+				require.EqualError(t, err, tt.Err)
+			}
+		})
+	}
+}
+
+func TestLifecycleConfigGracefulPaths(t *testing.T) {
+	cases := []struct {
+		Name             string
+		Pod              func(*corev1.Pod) *corev1.Pod
+		LifecycleConfig  Config
+		ExpectedShutdown string
+		ExpectedStartup  string
+	}{
+		{
+			Name: "Default graceful paths",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				return pod
+			},
+			ExpectedShutdown: DefaultGracefulShutdownPath,
+			ExpectedStartup:  DefaultGracefulStartupPath,
+		},
+		{
+			Name: "Graceful paths set via meshWebhook",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultGracefulShutdownPath: "/shutdown",
+				DefaultGracefulStartupPath:  "/startup",
+			},
+			ExpectedShutdown: "/shutdown",
+			ExpectedStartup:  "/startup",
+		},
+		{
+			Name: "Graceful paths set via annotation",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationSidecarProxyLifecycleGracefulShutdownPath] = "/custom-shutdown"
+				pod.Annotations[constants.AnnotationSidecarProxyLifecycleGracefulStartupPath] = "/custom-startup"
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultGracefulShutdownPath: "/shutdown",
+				DefaultGracefulStartupPath:  "/startup",
+			},
+			ExpectedShutdown: "/custom-shutdown",
+			ExpectedStartup:  "/custom-startup",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			pod := *tt.Pod(minimal())
+
+			require.Equal(t, tt.ExpectedShutdown, tt.LifecycleConfig.GracefulShutdownPath(pod))
+			require.Equal(t, tt.ExpectedStartup, tt.LifecycleConfig.GracefulStartupPath(pod))
+		})
+	}
+}
+
+func TestLifecycleConfigHoldApplicationUntilProxyReady(t *testing.T) {
+	cases := []struct {
+		Name            string
+		Pod             func(*corev1.Pod) *corev1.Pod
+		LifecycleConfig Config
+		Expected        bool
+		Err             string
+	}{
+		{
+			Name: "Proxy lifecycle disabled",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultStartupGracePeriodSeconds: 10,
+			},
+			Expected: false,
+		},
+		{
+			Name: "Startup grace period of zero",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultEnableProxyLifecycle: true,
+			},
+			Expected: false,
+		},
+		{
+			Name: "Startup grace period set via annotation",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationEnableSidecarProxyLifecycle] = "true"
+				pod.Annotations[constants.AnnotationSidecarProxyLifecycleStartupGracePeriodSeconds] = "10"
+				return pod
+			},
+			Expected: true,
+		},
+		{
+			Name: "Invalid startup grace period",
+			Pod: func(pod *corev1.Pod) *corev1.Pod {
+				pod.Annotations[constants.AnnotationSidecarProxyLifecycleStartupGracePeriodSeconds] = "ten"
+				return pod
+			},
+			LifecycleConfig: Config{
+				DefaultEnableProxyLifecycle: true,
+			},
+			Err: "consul.hashicorp.com/sidecar-proxy-lifecycle-startup-grace-period-seconds annotation value of ten was invalid: strconv.Atoi: parsing \"ten\": invalid syntax",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			actual, err := tt.LifecycleConfig.HoldApplicationUntilProxyReady(*tt.Pod(minimal()))
+
+			if tt.Err == "" {
+				require.NoError(t, err)
+				require.Equal(t, tt.Expected, actual)
+			} else {
+				require.EqualError(t, err, tt.Err)
+			}
+		})
+	}
+}
+
+func minimal() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespaces.DefaultNamespace,
+			Name:      "minimal",
+			Annotations: map[string]string{
+				constants.AnnotationService: "foo",
+			},
+		},
+
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "web",
+				},
+				{
+					Name: "web-side",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/shlex"
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/common"
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// annotationPrefix is the prefix of the annotations read by consul-k8s.
+	annotationPrefix = "consul.hashicorp.com/"
+
+	// The limits of service metadata enforced by Consul.
+	serviceMetaKeyMaxLength      = 128
+	serviceMetaValueMaxLength    = 512
+	serviceMetaKeyReservedPrefix = "consul-"
+)
+
+var serviceMetaKeyFormat = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// unvalidatedAnnotations are the known annotations whose values are free-form, are validated elsewhere, or are
+// set by consul-k8s itself. They are not reported as unknown annotations.
+var unvalidatedAnnotations = map[string]struct{}{
+	constants.KeyInjectStatus:                    {},
+	constants.KeyTransparentProxyStatus:          {},
+	constants.KeyManagedBy:                       {},
+	constants.AnnotationGatewayConsulServiceName: {},
+	constants.AnnotationGatewayWANAddress:        {},
+	constants.AnnotationGatewayNamespace:         {},
+	constants.AnnotationInjectMountVolumes:       {},
+	constants.AnnotationService:                  {},
+	constants.AnnotationKubernetesService:        {},
+	constants.AnnotationUpstreams:                {},
+	constants.AnnotationUpstreamsConfig:          {},
+	constants.AnnotationTags:                     {},
+	constants.AnnotationPrometheusCAFile:         {},
+	constants.AnnotationPrometheusCAPath:         {},
+	constants.AnnotationPrometheusCertFile:       {},
+	constants.AnnotationPrometheusKeyFile:        {},
+	constants.AnnotationConsulNamespace:          {},
+	constants.AnnotationInjectionConfig:          {},
+	constants.AnnotationRedirectTraffic:          {},
+	constants.AnnotationOriginalPod:              {},
+	constants.AnnotationPeeringVersion:           {},
+	constants.AnnotationConsulK8sVersion:         {},
+	constants.LabelServiceIgnore:                 {},
+	constants.LabelPeeringToken:                  {},
+}
+
+// validateAnnotations validates the values of the consul.hashicorp.com annotations of the pod in a single pass, so
+// that pods with invalid values are rejected at admission rather than being partially injected or silently falling
+// back to defaults. It returns all invalid values in a single error, and a warning for each consul.hashicorp.com
+// annotation that is not known. Each invalid value is reported together with where it was set, which is the
+// given namespace for the inherited annotations that applyNamespaceDefaults copied to the pod.
+func (w *MeshWebhook) validateAnnotations(pod corev1.Pod, namespace string, inherited []string) ([]string, error) {
+	validators := w.annotationValidators(pod)
+
+	source := func(key string) string {
+		for _, k := range inherited {
+			if k == key {
+				return fmt.Sprintf("inherited from the annotations of namespace %s", namespace)
+			}
+		}
+		return "set on the pod"
+	}
+
+	// Sort the annotations so that errors and warnings are reported in a stable order.
+	keys := make([]string, 0, len(pod.Annotations))
+	for key := range pod.Annotations {
+		if strings.HasPrefix(key, annotationPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var warnings, errs []string
+	for _, key := range keys {
+		value := pod.Annotations[key]
+		if validate, ok := validators[key]; ok {
+			if err := validate(value); err != nil {
+				errs = append(errs, fmt.Sprintf("%s (%s)", err, source(key)))
+			}
+			continue
+		}
+		if strings.HasPrefix(key, constants.AnnotationMeta) {
+			if err := validateServiceMeta(key, value); err != nil {
+				errs = append(errs, fmt.Sprintf("%s (%s)", err, source(key)))
+			}
+			continue
+		}
+		if _, ok := unvalidatedAnnotations[key]; !ok {
+			warnings = append(warnings, fmt.Sprintf("unknown annotation %s", key))
+		}
+	}
+
+	// The upstreams annotations are validated together since they can be combined.
+	if err := common.ValidateUpstreams(pod, w.EnableNamespaces, w.ConsulPartition != ""); err != nil {
+		errs = append(errs, fmt.Sprintf("%s (%s)", err, source(constants.AnnotationUpstreams)))
+	}
+
+	if len(errs) > 0 {
+		return warnings, errors.New(strings.Join(errs, "; "))
+	}
+	return warnings, nil
+}
+
+// annotationValidators returns the functions that validate the value of each annotation. Where the meshWebhook
+// already reads an annotation through a function that validates it, that function is used so that the validation
+// at admission matches how the annotation is used.
+func (w *MeshWebhook) annotationValidators(pod corev1.Pod) map[string]func(string) error {
+	return map[string]func(string) error{
+		constants.AnnotationInject:                                            validateBool(constants.AnnotationInject),
+		constants.AnnotationGatewayKind:                                       validateOneOf(constants.AnnotationGatewayKind, "mesh-gateway", "terminating-gateway", "ingress-gateway"),
+		constants.AnnotationMeshGatewayContainerPort:                          validatePortNumber(constants.AnnotationMeshGatewayContainerPort),
+		constants.AnnotationGatewayWANSource:                                  validateOneOf(constants.AnnotationGatewayWANSource, "NodeName", "NodeIP", "Static", "Service"),
+		constants.AnnotationGatewayWANPort:                                    validatePortNumber(constants.AnnotationGatewayWANPort),
+		constants.AnnotationPort:                                              validatePorts(pod, constants.AnnotationPort),
+		constants.AnnotationUseProxyHealthCheck:                               validateBool(constants.AnnotationUseProxyHealthCheck),
+		constants.AnnotationSidecarProxyCPULimit:                              validateQuantity(constants.AnnotationSidecarProxyCPULimit),
+		constants.AnnotationSidecarProxyCPURequest:                            validateQuantity(constants.AnnotationSidecarProxyCPURequest),
+		constants.AnnotationSidecarProxyMemoryLimit:                           validateQuantity(constants.AnnotationSidecarProxyMemoryLimit),
+		constants.AnnotationSidecarProxyMemoryRequest:                         validateQuantity(constants.AnnotationSidecarProxyMemoryRequest),
+		constants.AnnotationEnableNativeSidecar:                               validateBool(constants.AnnotationEnableNativeSidecar),
+		constants.AnnotationConsulSidecarUserVolume:                           validateJSON(constants.AnnotationConsulSidecarUserVolume, "sidecar user volumes", &[]corev1.Volume{}),
+		constants.AnnotationConsulSidecarUserVolumeMount:                      validateJSON(constants.AnnotationConsulSidecarUserVolumeMount, "sidecar user volume mounts", &[]corev1.VolumeMount{}),
+		constants.AnnotationEnvoyProxyConcurrency:                             withPod(pod, w.envoyProxyConcurrency),
+		constants.AnnotationEnableSidecarProxyLifecycle:                       withPod(pod, w.LifecycleConfig.EnableProxyLifecycle),
+		constants.AnnotationEnableSidecarProxyLifecycleShutdownDrainListeners: withPod(pod, w.LifecycleConfig.EnableShutdownDrainListeners),
+		constants.AnnotationSidecarProxyLifecycleShutdownGracePeriodSeconds:   withPod(pod, w.LifecycleConfig.ShutdownGracePeriodSeconds),
+		constants.AnnotationSidecarProxyLifecycleStartupGracePeriodSeconds:    withPod(pod, w.LifecycleConfig.StartupGracePeriodSeconds),
+		constants.AnnotationSidecarProxyLifecycleGracefulPort:                 withPod(pod, w.LifecycleConfig.GracefulPort),
+		constants.AnnotationSidecarProxyLifecycleGracefulShutdownPath:         validatePath(constants.AnnotationSidecarProxyLifecycleGracefulShutdownPath),
+		constants.AnnotationSidecarProxyLifecycleGracefulStartupPath:          validatePath(constants.AnnotationSidecarProxyLifecycleGracefulStartupPath),
+		constants.AnnotationEnableMetrics:                                     withPod(pod, w.MetricsConfig.EnableMetrics),
+		constants.AnnotationEnableMetricsMerging:                              withPod(pod, w.MetricsConfig.EnableMetricsMerging),
+		constants.AnnotationMergedMetricsPort:                                 withPod(pod, w.MetricsConfig.MergedMetricsPort),
+		constants.AnnotationPrometheusScrapePort:                              withPod(pod, w.MetricsConfig.PrometheusScrapePort),
+		constants.AnnotationPrometheusScrapePath:                              validatePath(constants.AnnotationPrometheusScrapePath),
+		constants.AnnotationServiceMetricsPort:                                withPod(pod, w.MetricsConfig.ServiceMetricsPort),
+		constants.AnnotationServiceMetricsPath:                                validatePath(constants.AnnotationServiceMetricsPath),
+		constants.AnnotationEnvoyExtraArgs:                                    validateEnvoyExtraArgs,
+		constants.KeyConsulDNS:                                                validateBool(constants.KeyConsulDNS),
+		constants.KeyTransparentProxy:                                         validateBool(constants.KeyTransparentProxy),
+		constants.AnnotationTProxyExcludeInboundPorts:                         validateList(constants.AnnotationTProxyExcludeInboundPorts, validatePortOrRange),
+		constants.AnnotationTProxyExcludeOutboundPorts:                        validateList(constants.AnnotationTProxyExcludeOutboundPorts, validatePortOrRange),
+		constants.AnnotationTProxyExcludeOutboundCIDRs:                        validateList(constants.AnnotationTProxyExcludeOutboundCIDRs, validateCIDR),
+		constants.AnnotationTProxyExcludeUIDs:                                 validateList(constants.AnnotationTProxyExcludeUIDs, validateUID),
+		constants.AnnotationTransparentProxyOverwriteProbes:                   validateBool(constants.AnnotationTransparentProxyOverwriteProbes),
+	}
+}
+
+// withPod returns a validator that calls a function of the meshWebhook which reads and validates the annotation of
+// the pod. The value is ignored since the function reads it from the pod.
+func withPod[T any](pod corev1.Pod, f func(corev1.Pod) (T, error)) func(string) error {
+	return func(string) error {
+		_, err := f(pod)
+		return err
+	}
+}
+
+func validateBool(annotation string) func(string) error {
+	return func(value string) error {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s annotation value of %s was invalid: %s", annotation, value, err)
+		}
+		return nil
+	}
+}
+
+func validateOneOf(annotation string, values ...string) func(string) error {
+	return func(value string) error {
+		for _, v := range values {
+			if value == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s annotation value of %s was invalid: must be one of %s", annotation, value, strings.Join(values, ", "))
+	}
+}
+
+func validateQuantity(annotation string) func(string) error {
+	return func(value string) error {
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return fmt.Errorf("%s annotation value of %s was invalid: %s", annotation, value, err)
+		}
+		return nil
+	}
+}
+
+func validatePortNumber(annotation string) func(string) error {
+	return func(value string) error {
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("%s annotation value of %s was invalid: must be a port number", annotation, value)
+		}
+		return nil
+	}
+}
+
+// validatePorts validates a comma-separated list of port numbers or names of container ports of the pod.
+func validatePorts(pod corev1.Pod, annotation string) func(string) error {
+	return func(value string) error {
+		for _, raw := range strings.Split(value, ",") {
+			port, err := common.PortValue(pod, strings.TrimSpace(raw))
+			if err != nil || port < 1 || port > 65535 {
+				return fmt.Errorf("%s annotation value of %s was invalid: %q must be a port number or the name of a container port", annotation, value, strings.TrimSpace(raw))
+			}
+		}
+		return nil
+	}
+}
+
+func validatePath(annotation string) func(string) error {
+	return func(value string) error {
+		if !strings.HasPrefix(value, "/") {
+			return fmt.Errorf("%s annotation value of %s was invalid: must start with /", annotation, value)
+		}
+		return nil
+	}
+}
+
+func validateJSON(annotation, description string, v interface{}) func(string) error {
+	return func(value string) error {
+		if err := json.Unmarshal([]byte(value), v); err != nil {
+			return fmt.Errorf("%s annotation was invalid: error unmarshalling %s: %s", annotation, description, err)
+		}
+		return nil
+	}
+}
+
+func validateEnvoyExtraArgs(value string) error {
+	if _, err := shlex.Split(value); err != nil {
+		return fmt.Errorf("%s annotation value of %s was invalid: %s", constants.AnnotationEnvoyExtraArgs, value, err)
+	}
+	return nil
+}
+
+// validateList validates each item of a comma-separated list.
+func validateList(annotation string, validate func(string) error) func(string) error {
+	return func(value string) error {
+		for _, item := range strings.Split(value, ",") {
+			if err := validate(strings.TrimSpace(item)); err != nil {
+				return fmt.Errorf("%s annotation value of %s was invalid: %s", annotation, value, err)
+			}
+		}
+		return nil
+	}
+}
+
+// validatePortOrRange validates a port number or a range of ports in the format [from]:[to].
+func validatePortOrRange(value string) error {
+	for _, raw := range strings.SplitN(value, ":", 2) {
+		port, err := strconv.Atoi(raw)
+		if err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("%q must be a port number or a range of ports", value)
+		}
+	}
+	return nil
+}
+
+// validateCIDR validates a CIDR or an IP address.
+func validateCIDR(value string) error {
+	if _, _, err := net.ParseCIDR(value); err == nil {
+		return nil
+	}
+	if net.ParseIP(value) != nil {
+		return nil
+	}
+	return fmt.Errorf("%q must be a CIDR or an IP address", value)
+}
+
+func validateUID(value string) error {
+	if _, err := strconv.ParseUint(value, 10, 32); err != nil {
+		return fmt.Errorf("%q must be a user ID", value)
+	}
+	return nil
+}
+
+// validateServiceMeta validates a service-meta- annotation against the limits of service metadata in Consul.
+func validateServiceMeta(annotation, value string) error {
+	key := strings.TrimPrefix(annotation, constants.AnnotationMeta)
+	switch {
+	case !serviceMetaKeyFormat.MatchString(key):
+		return fmt.Errorf("%s annotation was invalid: the metadata key %q may only contain alphanumeric characters, - and _", annotation, key)
+	case len(key) > serviceMetaKeyMaxLength:
+		return fmt.Errorf("%s annotation was invalid: the metadata key must not be longer than %d characters", annotation, serviceMetaKeyMaxLength)
+	case strings.HasPrefix(key, serviceMetaKeyReservedPrefix):
+		return fmt.Errorf("%s annotation was invalid: the metadata key must not start with the reserved prefix %q", annotation, serviceMetaKeyReservedPrefix)
+	case len(value) > serviceMetaValueMaxLength:
+		return fmt.Errorf("%s annotation was invalid: the value must not be longer than %d characters", annotation, serviceMetaValueMaxLength)
+	}
+	return nil
+}
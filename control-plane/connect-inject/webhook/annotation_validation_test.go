@@ -0,0 +1,241 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	logrtest "github.com/go-logr/logr/testr"
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/constants"
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/metrics"
+	"github.com/hashicorp/consul-k8s/control-plane/consul"
+	"github.com/hashicorp/consul-k8s/control-plane/namespaces"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestValidateAnnotations(t *testing.T) {
+	cases := map[string]struct {
+		annotations map[string]string
+		inherited   []string
+		expWarnings []string
+		expErr      string
+	}{
+		"no annotations": {},
+		"valid annotations": {
+			annotations: map[string]string{
+				constants.AnnotationInject:                     "true",
+				constants.AnnotationService:                    "web",
+				constants.AnnotationPort:                       "http",
+				constants.AnnotationUpstreams:                  "db:1234",
+				constants.AnnotationTags:                       "v1,v2",
+				constants.AnnotationMeta + "team":              "payments",
+				constants.AnnotationSidecarProxyCPURequest:     "100m",
+				constants.AnnotationSidecarProxyMemoryLimit:    "128Mi",
+				constants.AnnotationEnvoyProxyConcurrency:      "2",
+				constants.AnnotationEnableMetrics:              "true",
+				constants.AnnotationMergedMetricsPort:          "20100",
+				constants.AnnotationPrometheusScrapePath:       "/metrics",
+				constants.AnnotationEnvoyExtraArgs:             "--log-level debug",
+				constants.KeyTransparentProxy:                  "true",
+				constants.AnnotationTProxyExcludeInboundPorts:  "8080, 9090:9099",
+				constants.AnnotationTProxyExcludeOutboundCIDRs: "10.0.0.1,10.0.0.0/16",
+				constants.AnnotationTProxyExcludeUIDs:          "5995",
+				constants.AnnotationConsulSidecarUserVolume:    `[{"name": "tls", "secret": {"secretName": "tls"}}]`,
+				"example.com/owner":                            "team-a",
+			},
+		},
+		"unknown annotations": {
+			annotations: map[string]string{
+				"consul.hashicorp.com/connect-service-upstream": "db:1234",
+				"consul.hashicorp.com/enable-metric":            "true",
+			},
+			expWarnings: []string{
+				"unknown annotation consul.hashicorp.com/connect-service-upstream",
+				"unknown annotation consul.hashicorp.com/enable-metric",
+			},
+		},
+		"invalid bool": {
+			annotations: map[string]string{
+				constants.AnnotationUseProxyHealthCheck: "yes",
+			},
+			expErr: `consul.hashicorp.com/use-proxy-health-check annotation value of yes was invalid: strconv.ParseBool: parsing "yes": invalid syntax (set on the pod)`,
+		},
+		"invalid quantity": {
+			annotations: map[string]string{
+				constants.AnnotationSidecarProxyCPULimit: "one",
+			},
+			expErr: "consul.hashicorp.com/sidecar-proxy-cpu-limit annotation value of one was invalid: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$' (set on the pod)",
+		},
+		"invalid merged metrics port": {
+			annotations: map[string]string{
+				constants.AnnotationMergedMetricsPort: "80",
+			},
+			expErr: "consul.hashicorp.com/merged-metrics-port annotation value of 80 is not in the unprivileged port range 1024-65535 (set on the pod)",
+		},
+		"invalid connect service port": {
+			annotations: map[string]string{
+				constants.AnnotationPort: "grpc",
+			},
+			expErr: `consul.hashicorp.com/connect-service-port annotation value of grpc was invalid: "grpc" must be a port number or the name of a container port (set on the pod)`,
+		},
+		"invalid path": {
+			annotations: map[string]string{
+				constants.AnnotationServiceMetricsPath: "metrics",
+			},
+			expErr: "consul.hashicorp.com/service-metrics-path annotation value of metrics was invalid: must start with / (set on the pod)",
+		},
+		"invalid envoy extra args": {
+			annotations: map[string]string{
+				constants.AnnotationEnvoyExtraArgs: `--log-level "debug`,
+			},
+			expErr: `consul.hashicorp.com/envoy-extra-args annotation value of --log-level "debug was invalid: EOF found when expecting closing quote (set on the pod)`,
+		},
+		"invalid exclude outbound cidrs": {
+			annotations: map[string]string{
+				constants.AnnotationTProxyExcludeOutboundCIDRs: "10.0.0.0/16,10.0.0.300",
+			},
+			expErr: `consul.hashicorp.com/transparent-proxy-exclude-outbound-cidrs annotation value of 10.0.0.0/16,10.0.0.300 was invalid: "10.0.0.300" must be a CIDR or an IP address (set on the pod)`,
+		},
+		"invalid exclude inbound ports": {
+			annotations: map[string]string{
+				constants.AnnotationTProxyExcludeInboundPorts: "8080,http",
+			},
+			expErr: `consul.hashicorp.com/transparent-proxy-exclude-inbound-ports annotation value of 8080,http was invalid: "http" must be a port number or a range of ports (set on the pod)`,
+		},
+		"invalid exclude uids": {
+			annotations: map[string]string{
+				constants.AnnotationTProxyExcludeUIDs: "-1",
+			},
+			expErr: `consul.hashicorp.com/transparent-proxy-exclude-uids annotation value of -1 was invalid: "-1" must be a user ID (set on the pod)`,
+		},
+		"invalid sidecar user volume mount": {
+			annotations: map[string]string{
+				constants.AnnotationConsulSidecarUserVolumeMount: "[a]",
+			},
+			expErr: "consul.hashicorp.com/consul-sidecar-user-volume-mount annotation was invalid: error unmarshalling sidecar user volume mounts: invalid character 'a' looking for beginning of value (set on the pod)",
+		},
+		"invalid service meta key": {
+			annotations: map[string]string{
+				constants.AnnotationMeta + "team.name": "payments",
+			},
+			expErr: `consul.hashicorp.com/service-meta-team.name annotation was invalid: the metadata key "team.name" may only contain alphanumeric characters, - and _ (set on the pod)`,
+		},
+		"reserved service meta key": {
+			annotations: map[string]string{
+				constants.AnnotationMeta + "consul-version": "1.0",
+			},
+			expErr: `consul.hashicorp.com/service-meta-consul-version annotation was invalid: the metadata key must not start with the reserved prefix "consul-" (set on the pod)`,
+		},
+		"invalid upstreams": {
+			annotations: map[string]string{
+				constants.AnnotationUpstreams: "db",
+			},
+			expErr: `consul.hashicorp.com/connect-service-upstreams annotation was invalid: upstream "db" must be in the format [service-name]:[port] (set on the pod)`,
+		},
+		"invalid value inherited from the namespace": {
+			annotations: map[string]string{
+				constants.AnnotationEnableMetrics:        "yes",
+				constants.AnnotationEnableMetricsMerging: "true",
+			},
+			inherited: []string{constants.AnnotationEnableMetrics, constants.AnnotationEnableMetricsMerging},
+			expErr:    `consul.hashicorp.com/enable-metrics annotation value of yes was invalid: strconv.ParseBool: parsing "yes": invalid syntax (inherited from the annotations of namespace default)`,
+		},
+		"all invalid values are reported": {
+			annotations: map[string]string{
+				constants.AnnotationEnableMetrics:         "yes",
+				constants.AnnotationEnvoyProxyConcurrency: "-1",
+				"consul.hashicorp.com/unknown":            "true",
+			},
+			expWarnings: []string{"unknown annotation consul.hashicorp.com/unknown"},
+			expErr:      `unable to parse annotation "consul.hashicorp.com/consul-envoy-proxy-concurrency": strconv.ParseUint: parsing "-1": invalid syntax (set on the pod); consul.hashicorp.com/enable-metrics annotation value of yes was invalid: strconv.ParseBool: parsing "yes": invalid syntax (set on the pod)`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := MeshWebhook{
+				MetricsConfig: metrics.Config{
+					DefaultPrometheusScrapePort: "20200",
+					DefaultPrometheusScrapePath: "/metrics",
+				},
+			}
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: c.annotations,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "web",
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "http",
+									ContainerPort: 8080,
+								},
+							},
+						},
+					},
+				},
+			}
+
+			warnings, err := w.validateAnnotations(pod, "default", c.inherited)
+			require.Equal(t, c.expWarnings, warnings)
+			if c.expErr != "" {
+				require.EqualError(t, err, c.expErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestHandlerHandle_AnnotationWarnings(t *testing.T) {
+	s := runtime.NewScheme()
+	s.AddKnownTypes(schema.GroupVersion{
+		Group:   "",
+		Version: "v1",
+	}, &corev1.Pod{})
+	decoder, err := admission.NewDecoder(s)
+	require.NoError(t, err)
+
+	w := MeshWebhook{
+		Log:                   logrtest.New(t),
+		AllowK8sNamespacesSet: mapset.NewSetWith("*"),
+		DenyK8sNamespacesSet:  mapset.NewSet(),
+		decoder:               decoder,
+		Clientset:             defaultTestClientWithNamespace(),
+		ConsulConfig:          &consul.Config{HTTPPort: 8500},
+	}
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Namespace: namespaces.DefaultNamespace,
+			Object: encodeRaw(t, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"consul.hashicorp.com/connect-service-upstream": "db:1234",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "web",
+						},
+					},
+				},
+			}),
+		},
+	}
+
+	resp := w.Handle(context.Background(), req)
+	require.True(t, resp.Allowed, resp.Result)
+	require.Equal(t, []string{"unknown annotation consul.hashicorp.com/connect-service-upstream"}, resp.Warnings)
+}
@@ -21,6 +21,9 @@ import (
 const (
 	consulDataplaneDNSBindHost = "127.0.0.1"
 	consulDataplaneDNSBindPort = 8600
+
+	// containerRestartPolicyAlways is the restartPolicy of init containers which run as native sidecars.
+	containerRestartPolicyAlways = "Always"
 )
 
 func (w *MeshWebhook) consulDataplaneSidecar(namespace corev1.Namespace, pod corev1.Pod, mpi multiPortInfo) (corev1.Container, error) {
@@ -128,6 +131,13 @@ func (w *MeshWebhook) consulDataplaneSidecar(namespace corev1.Namespace, pod cor
 		})
 	}
 
+	// Configure the lifecycle hooks which call the graceful startup and shutdown endpoints of consul-dataplane.
+	containerLifecycle, err := w.sidecarLifecycle(pod, mpi)
+	if err != nil {
+		return corev1.Container{}, err
+	}
+	container.Lifecycle = containerLifecycle
+
 	// Add any extra VolumeMounts.
 	if userVolMount, ok := pod.Annotations[constants.AnnotationConsulSidecarUserVolumeMount]; ok {
 		var volumeMounts []corev1.VolumeMount
@@ -179,15 +189,9 @@ func (w *MeshWebhook) getContainerSidecarArgs(namespace corev1.Namespace, mpi mu
 		proxyIDFileName = fmt.Sprintf("/consul/connect-inject/proxyid-%s", mpi.serviceName)
 	}
 
-	envoyConcurrency := w.DefaultEnvoyProxyConcurrency
-
-	// Check to see if the user has overriden concurrency via an annotation.
-	if envoyConcurrencyAnnotation, ok := pod.Annotations[constants.AnnotationEnvoyProxyConcurrency]; ok {
-		val, err := strconv.ParseUint(envoyConcurrencyAnnotation, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse annotation %q: %w", constants.AnnotationEnvoyProxyConcurrency, err)
-		}
-		envoyConcurrency = int(val)
+	envoyConcurrency, err := w.envoyProxyConcurrency(pod)
+	if err != nil {
+		return nil, err
 	}
 
 	args := []string{
@@ -247,6 +251,13 @@ func (w *MeshWebhook) getContainerSidecarArgs(namespace corev1.Namespace, mpi mu
 		args = append(args, fmt.Sprintf("-envoy-admin-bind-port=%d", 19000+mpi.serviceIndex))
 	}
 
+	// Configure the graceful startup and shutdown of the proxy if lifecycle management is enabled.
+	lifecycleArgs, err := w.sidecarLifecycleArgs(pod, mpi)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, lifecycleArgs...)
+
 	// Set a default scrape path that can be overwritten by the annotation.
 	prometheusScrapePath := w.MetricsConfig.PrometheusScrapePath(pod)
 	args = append(args, "-telemetry-prom-scrape-path="+prometheusScrapePath)
@@ -359,6 +370,96 @@ func (w *MeshWebhook) getContainerSidecarArgs(namespace corev1.Namespace, mpi mu
 	return args, nil
 }
 
+// sidecarLifecycleArgs returns the consul-dataplane arguments which configure the graceful startup and
+// shutdown of the proxy. No arguments are returned if proxy lifecycle management is disabled.
+func (w *MeshWebhook) sidecarLifecycleArgs(pod corev1.Pod, mpi multiPortInfo) ([]string, error) {
+	enabled, err := w.LifecycleConfig.EnableProxyLifecycle(pod)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine if proxy lifecycle management is enabled: %w", err)
+	}
+	if !enabled {
+		return nil, nil
+	}
+
+	gracefulPort, err := w.LifecycleConfig.GracefulPort(pod)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine proxy lifecycle graceful port: %w", err)
+	}
+	shutdownGracePeriodSeconds, err := w.LifecycleConfig.ShutdownGracePeriodSeconds(pod)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine proxy lifecycle shutdown grace period: %w", err)
+	}
+	startupGracePeriodSeconds, err := w.LifecycleConfig.StartupGracePeriodSeconds(pod)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine proxy lifecycle startup grace period: %w", err)
+	}
+	shutdownDrainListeners, err := w.LifecycleConfig.EnableShutdownDrainListeners(pod)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine if proxy lifecycle shutdown should drain listeners: %w", err)
+	}
+
+	args := []string{
+		fmt.Sprintf("-graceful-port=%d", gracefulPort+mpi.serviceIndex),
+		fmt.Sprintf("-shutdown-grace-period-seconds=%d", shutdownGracePeriodSeconds),
+		"-graceful-shutdown-path=" + w.LifecycleConfig.GracefulShutdownPath(pod),
+	}
+	// The graceful startup flags are only passed when a startup grace period is set so that proxies
+	// which don't hold the application keep working with consul-dataplane versions without them.
+	if startupGracePeriodSeconds > 0 {
+		args = append(args,
+			fmt.Sprintf("-startup-grace-period-seconds=%d", startupGracePeriodSeconds),
+			"-graceful-startup-path="+w.LifecycleConfig.GracefulStartupPath(pod),
+		)
+	}
+	if shutdownDrainListeners {
+		args = append(args, "-shutdown-drain-listeners")
+	}
+
+	return args, nil
+}
+
+// sidecarLifecycle returns the lifecycle hooks of the consul-dataplane container when proxy lifecycle management
+// is enabled. The PreStop hook starts the graceful shutdown of the proxy so that it keeps serving the application
+// while it finishes in-flight requests. If the application should be held until the proxy is ready, the PostStart
+// hook blocks until the proxy is ready, which delays the start of the containers following the sidecar.
+func (w *MeshWebhook) sidecarLifecycle(pod corev1.Pod, mpi multiPortInfo) (*corev1.Lifecycle, error) {
+	enabled, err := w.LifecycleConfig.EnableProxyLifecycle(pod)
+	if err != nil || !enabled {
+		return nil, err
+	}
+
+	gracefulPort, err := w.LifecycleConfig.GracefulPort(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	containerLifecycle := &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Port: intstr.FromInt(gracefulPort + mpi.serviceIndex),
+				Path: w.LifecycleConfig.GracefulShutdownPath(pod),
+			},
+		},
+	}
+
+	// The kubelet doesn't start the containers following the sidecar until its PostStart hook has
+	// completed. With native sidecars, this holds the pod's own init containers as well.
+	holdApplication, err := w.LifecycleConfig.HoldApplicationUntilProxyReady(pod)
+	if err != nil {
+		return nil, err
+	}
+	if holdApplication {
+		containerLifecycle.PostStart = &corev1.LifecycleHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Port: intstr.FromInt(gracefulPort + mpi.serviceIndex),
+				Path: w.LifecycleConfig.GracefulStartupPath(pod),
+			},
+		}
+	}
+
+	return containerLifecycle, nil
+}
+
 func (w *MeshWebhook) sidecarResources(pod corev1.Pod) (corev1.ResourceRequirements, error) {
 	resources := corev1.ResourceRequirements{
 		Limits:   corev1.ResourceList{},
@@ -425,6 +526,69 @@ func (w *MeshWebhook) sidecarResources(pod corev1.Pod) (corev1.ResourceRequireme
 	return resources, nil
 }
 
+// nativeSidecarEnabled returns whether consul-dataplane is injected as a native sidecar container either via
+// the default value in the meshWebhook, or if it's been overridden via the annotation. Enabling native sidecars
+// via the annotation is an error if the Kubernetes cluster does not support them. The default is only enabled
+// on clusters which do.
+func (w *MeshWebhook) nativeSidecarEnabled(pod corev1.Pod) (bool, error) {
+	if raw, ok := pod.Annotations[constants.AnnotationEnableNativeSidecar]; ok && raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return false, fmt.Errorf("%s annotation value of %s was invalid: %s", constants.AnnotationEnableNativeSidecar, raw, err)
+		}
+		if enabled && !w.NativeSidecarSupported {
+			return false, fmt.Errorf("%s annotation requires Kubernetes 1.29 or later", constants.AnnotationEnableNativeSidecar)
+		}
+		return enabled, nil
+	}
+	return w.EnableNativeSidecar, nil
+}
+
+// isSidecarContainer returns whether the container is a consul-dataplane container injected by the meshWebhook.
+// In multi port pods, the name of the container has the name of the service appended.
+func isSidecarContainer(container corev1.Container) bool {
+	return container.Name == sidecarContainer || strings.HasPrefix(container.Name, sidecarContainer+"-")
+}
+
+// withNativeSidecars sets the restartPolicy of the consul-dataplane init containers of the marshalled pod to
+// Always, which makes them native sidecar containers. The kubelet keeps them running next to the application
+// containers and stops them once the application containers have exited, so that Jobs can complete.
+// The field is set on the JSON because the restartPolicy of containers is not part of the Kubernetes API
+// types this module is built with.
+func withNativeSidecars(podJson []byte) ([]byte, error) {
+	var pod map[string]interface{}
+	if err := json.Unmarshal(podJson, &pod); err != nil {
+		return nil, err
+	}
+
+	spec, _ := pod["spec"].(map[string]interface{})
+	initContainers, _ := spec["initContainers"].([]interface{})
+	for _, raw := range initContainers {
+		container, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _ := container["name"].(string); isSidecarContainer(corev1.Container{Name: name}) {
+			container["restartPolicy"] = containerRestartPolicyAlways
+		}
+	}
+
+	return json.Marshal(pod)
+}
+
+// envoyProxyConcurrency returns the number of worker threads of Envoy, either via the default value in the
+// meshWebhook, or if it's been overridden via the annotation.
+func (w *MeshWebhook) envoyProxyConcurrency(pod corev1.Pod) (int, error) {
+	if raw, ok := pod.Annotations[constants.AnnotationEnvoyProxyConcurrency]; ok {
+		val, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse annotation %q: %w", constants.AnnotationEnvoyProxyConcurrency, err)
+		}
+		return int(val), nil
+	}
+	return w.DefaultEnvoyProxyConcurrency, nil
+}
+
 // useProxyHealthCheck returns true if the pod has the annotation 'consul.hashicorp.com/use-proxy-health-check'
 // set to truthy values.
 func useProxyHealthCheck(pod corev1.Pod) bool {
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/constants"
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/lifecycle"
 	"github.com/hashicorp/consul-k8s/control-plane/consul"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -1299,6 +1300,171 @@ func TestHandlerConsulDataplaneSidecar_Metrics(t *testing.T) {
 	}
 }
 
+func TestHandlerConsulDataplaneSidecar_Lifecycle(t *testing.T) {
+	cases := []struct {
+		name            string
+		lifecycleConfig lifecycle.Config
+		annotations     map[string]string
+		mpi             multiPortInfo
+		expCmdArgs      string
+		unexpCmdArgs    string
+		expLifecycle    *corev1.Lifecycle
+		expErr          string
+	}{
+		{
+			name:         "disabled by default",
+			expCmdArgs:   "",
+			expLifecycle: nil,
+		},
+		{
+			name: "enabled via meshWebhook",
+			lifecycleConfig: lifecycle.Config{
+				DefaultEnableProxyLifecycle:         true,
+				DefaultEnableShutdownDrainListeners: true,
+				DefaultShutdownGracePeriodSeconds:   30,
+			},
+			expCmdArgs:   "-graceful-port=20600 -shutdown-grace-period-seconds=30 -graceful-shutdown-path=/graceful_shutdown -shutdown-drain-listeners",
+			unexpCmdArgs: "-startup-grace-period-seconds",
+			expLifecycle: &corev1.Lifecycle{
+				PreStop: &corev1.LifecycleHandler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Port: intstr.FromInt(20600),
+						Path: "/graceful_shutdown",
+					},
+				},
+			},
+		},
+		{
+			name: "disabled via annotation",
+			lifecycleConfig: lifecycle.Config{
+				DefaultEnableProxyLifecycle: true,
+			},
+			annotations: map[string]string{
+				constants.AnnotationEnableSidecarProxyLifecycle: "false",
+			},
+			expCmdArgs:   "",
+			expLifecycle: nil,
+		},
+		{
+			name: "enabled via annotations with the application held until the proxy is ready",
+			annotations: map[string]string{
+				constants.AnnotationEnableSidecarProxyLifecycle:                     "true",
+				constants.AnnotationSidecarProxyLifecycleShutdownGracePeriodSeconds: "15",
+				constants.AnnotationSidecarProxyLifecycleStartupGracePeriodSeconds:  "10",
+				constants.AnnotationSidecarProxyLifecycleGracefulPort:               "21600",
+				constants.AnnotationSidecarProxyLifecycleGracefulShutdownPath:       "/shutdown",
+				constants.AnnotationSidecarProxyLifecycleGracefulStartupPath:        "/startup",
+			},
+			expCmdArgs: "-graceful-port=21600 -shutdown-grace-period-seconds=15 -graceful-shutdown-path=/shutdown -startup-grace-period-seconds=10 -graceful-startup-path=/startup",
+			expLifecycle: &corev1.Lifecycle{
+				PostStart: &corev1.LifecycleHandler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Port: intstr.FromInt(21600),
+						Path: "/startup",
+					},
+				},
+				PreStop: &corev1.LifecycleHandler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Port: intstr.FromInt(21600),
+						Path: "/shutdown",
+					},
+				},
+			},
+		},
+		{
+			name: "native sidecars hold the application",
+			lifecycleConfig: lifecycle.Config{
+				DefaultEnableProxyLifecycle:      true,
+				DefaultStartupGracePeriodSeconds: 10,
+			},
+			annotations: map[string]string{
+				constants.AnnotationEnableNativeSidecar: "true",
+			},
+			expCmdArgs: "-startup-grace-period-seconds=10",
+			expLifecycle: &corev1.Lifecycle{
+				PostStart: &corev1.LifecycleHandler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Port: intstr.FromInt(20600),
+						Path: "/graceful_startup",
+					},
+				},
+				PreStop: &corev1.LifecycleHandler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Port: intstr.FromInt(20600),
+						Path: "/graceful_shutdown",
+					},
+				},
+			},
+		},
+		{
+			name: "multiport uses a graceful port per service",
+			lifecycleConfig: lifecycle.Config{
+				DefaultEnableProxyLifecycle: true,
+			},
+			mpi: multiPortInfo{
+				serviceIndex: 1,
+				serviceName:  "web-admin",
+			},
+			expCmdArgs: "-graceful-port=20601",
+			expLifecycle: &corev1.Lifecycle{
+				PreStop: &corev1.LifecycleHandler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Port: intstr.FromInt(20601),
+						Path: "/graceful_shutdown",
+					},
+				},
+			},
+		},
+		{
+			name: "invalid graceful port gives an error",
+			lifecycleConfig: lifecycle.Config{
+				DefaultEnableProxyLifecycle: true,
+			},
+			annotations: map[string]string{
+				constants.AnnotationSidecarProxyLifecycleGracefulPort: "80",
+			},
+			expErr: "consul.hashicorp.com/sidecar-proxy-lifecycle-graceful-port annotation value of 80 is not in the unprivileged port range 1024-65535",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := MeshWebhook{
+				ConsulConfig:           &consul.Config{HTTPPort: 8500, GRPCPort: 8502},
+				LifecycleConfig:        c.lifecycleConfig,
+				NativeSidecarSupported: true,
+			}
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: c.annotations,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "web",
+						},
+					},
+				},
+			}
+			container, err := h.consulDataplaneSidecar(testNS, pod, c.mpi)
+			if c.expErr != "" {
+				require.NotNil(t, err)
+				require.Contains(t, err.Error(), c.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, strings.Join(container.Args, " "), c.expCmdArgs)
+			if c.unexpCmdArgs != "" {
+				require.NotContains(t, strings.Join(container.Args, " "), c.unexpCmdArgs)
+			}
+			if c.expCmdArgs == "" {
+				require.NotContains(t, strings.Join(container.Args, " "), "-graceful-port")
+			}
+			require.Equal(t, c.expLifecycle, container.Lifecycle)
+		})
+	}
+}
+
 // boolPtr returns pointer to b.
 func boolPtr(b bool) *bool {
 	return &b
@@ -14,29 +14,44 @@ import (
 )
 
 func (w *MeshWebhook) containerEnvVars(pod corev1.Pod) []corev1.EnvVar {
-	raw, ok := pod.Annotations[constants.AnnotationUpstreams]
-	if !ok || raw == "" {
-		return []corev1.EnvVar{}
-	}
-
 	var result []corev1.EnvVar
-	for _, raw := range strings.Split(raw, ",") {
-		parts := strings.SplitN(raw, ":", 3)
-		port, _ := common.PortValue(pod, strings.TrimSpace(parts[1]))
-		if port > 0 {
-			name := strings.TrimSpace(parts[0])
-			name = strings.ToUpper(strings.Replace(name, "-", "_", -1))
-			portStr := strconv.Itoa(int(port))
+	if raw, ok := pod.Annotations[constants.AnnotationUpstreams]; ok && raw != "" {
+		for _, raw := range strings.Split(raw, ",") {
+			parts := strings.SplitN(raw, ":", 3)
+			port, _ := common.PortValue(pod, strings.TrimSpace(parts[1]))
+			if port > 0 {
+				result = append(result, upstreamEnvVars(strings.TrimSpace(parts[0]), "127.0.0.1", int(port))...)
+			}
+		}
+	}
 
-			result = append(result, corev1.EnvVar{
-				Name:  fmt.Sprintf("%s_CONNECT_SERVICE_HOST", name),
-				Value: "127.0.0.1",
-			}, corev1.EnvVar{
-				Name:  fmt.Sprintf("%s_CONNECT_SERVICE_PORT", name),
-				Value: portStr,
-			})
+	// The upstreams have been validated by the time the env vars are added, so errors can be ignored.
+	upstreams, _ := common.UpstreamsConfig(pod)
+	for _, upstream := range upstreams {
+		host := upstream.LocalBindAddress
+		if host == "" {
+			host = "127.0.0.1"
 		}
+		result = append(result, upstreamEnvVars(upstream.DestinationName, host, upstream.LocalBindPort)...)
 	}
 
+	if result == nil {
+		return []corev1.EnvVar{}
+	}
 	return result
 }
+
+// upstreamEnvVars returns the env vars with the host and port of the upstream that are added for service discovery.
+func upstreamEnvVars(name, host string, port int) []corev1.EnvVar {
+	name = strings.ToUpper(strings.Replace(name, "-", "_", -1))
+	return []corev1.EnvVar{
+		{
+			Name:  fmt.Sprintf("%s_CONNECT_SERVICE_HOST", name),
+			Value: host,
+		},
+		{
+			Name:  fmt.Sprintf("%s_CONNECT_SERVICE_PORT", name),
+			Value: strconv.Itoa(port),
+		},
+	}
+}
@@ -54,3 +54,38 @@ func TestContainerEnvVars(t *testing.T) {
 		})
 	}
 }
+
+func TestContainerEnvVars_UpstreamsConfig(t *testing.T) {
+	var w MeshWebhook
+	envVars := w.containerEnvVars(corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				constants.AnnotationService:         "foo",
+				constants.AnnotationUpstreams:       "static-server:7890",
+				constants.AnnotationUpstreamsConfig: `[{"destinationName": "db", "localBindPort": 5432}, {"destinationName": "cache-server", "localBindAddress": "127.0.0.2", "localBindPort": 6379}]`,
+			},
+		},
+	})
+
+	require.ElementsMatch(t, envVars, []corev1.EnvVar{
+		{
+			Name:  "STATIC_SERVER_CONNECT_SERVICE_HOST",
+			Value: "127.0.0.1",
+		}, {
+			Name:  "STATIC_SERVER_CONNECT_SERVICE_PORT",
+			Value: "7890",
+		}, {
+			Name:  "DB_CONNECT_SERVICE_HOST",
+			Value: "127.0.0.1",
+		}, {
+			Name:  "DB_CONNECT_SERVICE_PORT",
+			Value: "5432",
+		}, {
+			Name:  "CACHE_SERVER_CONNECT_SERVICE_HOST",
+			Value: "127.0.0.2",
+		}, {
+			Name:  "CACHE_SERVER_CONNECT_SERVICE_PORT",
+			Value: "6379",
+		},
+	})
+}
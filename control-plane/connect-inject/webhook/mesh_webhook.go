@@ -17,6 +17,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/common"
 	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/constants"
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/lifecycle"
 	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/metrics"
 	"github.com/hashicorp/consul-k8s/control-plane/consul"
 	"github.com/hashicorp/consul-k8s/control-plane/namespaces"
@@ -159,6 +160,11 @@ type MeshWebhook struct {
 	// annotations and the merged metrics server.
 	MetricsConfig metrics.Config
 
+	// LifecycleConfig contains proxy lifecycle management configuration from the inject-connect command and has methods
+	// to determine whether configuration should come from the default flags or annotations. The meshWebhook uses this
+	// to configure the graceful startup and shutdown of the consul-dataplane sidecar.
+	LifecycleConfig lifecycle.Config
+
 	// Resource settings for init container. All of these fields
 	// will be populated by the defaults provided in the initial flags.
 	InitContainerResources corev1.ResourceRequirements
@@ -172,6 +178,16 @@ type MeshWebhook struct {
 	// so that all traffic will go through the Envoy proxy.
 	EnableTransparentProxy bool
 
+	// EnableNativeSidecar injects consul-dataplane as a native sidecar container, i.e. an init
+	// container with restartPolicy Always, right after the connect-inject init container rather than
+	// as a regular container. This can be overridden per pod via annotation.
+	EnableNativeSidecar bool
+
+	// NativeSidecarSupported is whether the Kubernetes cluster supports native sidecar containers,
+	// which is the case since Kubernetes 1.29. Pods which enable native sidecars via annotation are
+	// rejected if it does not.
+	NativeSidecarSupported bool
+
 	// EnableCNI enables the CNI plugin and prevents the connect-inject init container
 	// from running the consul redirect-traffic command as the CNI plugin handles traffic
 	// redirection
@@ -250,6 +266,27 @@ func (w *MeshWebhook) Handle(ctx context.Context, req admission.Request) admissi
 
 	w.Log.Info("received pod", "name", req.Name, "ns", req.Namespace)
 
+	// A user can enable/disable tproxy for an entire namespace via a label, and set defaults for the pods in a
+	// namespace via annotations.
+	ns, err := w.Clientset.CoreV1().Namespaces().Get(ctx, req.Namespace, metav1.GetOptions{})
+	if err != nil {
+		w.Log.Error(err, "error fetching namespace metadata for container", "request name", req.Name)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("error getting namespace metadata for container: %s", err))
+	}
+
+	// Settings that aren't set on the pod are defaulted from the annotations of its namespace before the
+	// global defaults are used.
+	inherited := applyNamespaceDefaults(*ns, &pod)
+
+	// Validate the annotations before the pod is mutated, since many of them are otherwise only read once the
+	// containers are built or the endpoints controller registers the service. Unknown annotations are returned
+	// to the user as warnings.
+	warnings, err := w.validateAnnotations(pod, ns.Name, inherited)
+	if err != nil {
+		w.Log.Error(err, "invalid annotations", "request name", req.Name)
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("invalid annotations: %s", err))
+	}
+
 	// Add our volume that will be shared by the init container and
 	// the sidecar for passing data in the pod.
 	pod.Spec.Volumes = append(pod.Spec.Volumes, w.containerVolume())
@@ -278,18 +315,21 @@ func (w *MeshWebhook) Handle(ctx context.Context, req admission.Request) admissi
 		pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, containerEnvVars...)
 	}
 
-	// A user can enable/disable tproxy for an entire namespace via a label.
-	ns, err := w.Clientset.CoreV1().Namespaces().Get(ctx, req.Namespace, metav1.GetOptions{})
-	if err != nil {
-		w.Log.Error(err, "error fetching namespace metadata for container", "request name", req.Name)
-		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("error getting namespace metadata for container: %s", err))
-	}
-
 	// Get service names from the annotation. If theres 0-1 service names, it's a single port pod, otherwise it's multi
 	// port.
 	annotatedSvcNames := w.annotatedServiceNames(pod)
 	multiPort := len(annotatedSvcNames) > 1
 
+	// With native sidecars, the init containers and Envoy sidecars are injected ahead of the pod's own
+	// init containers so that those have access to the mesh too. Each init container comes before its
+	// sidecar because consul-dataplane reads the proxy ID the init container writes when it starts.
+	nativeSidecar, err := w.nativeSidecarEnabled(pod)
+	if err != nil {
+		w.Log.Error(err, "error determining if native sidecars are enabled", "request name", req.Name)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("error determining if native sidecars are enabled: %s", err))
+	}
+	var injectedInitContainers []corev1.Container
+
 	// For single port pods, add the single init container and envoy sidecar.
 	if !multiPort {
 		// Add the init container that registers the service and sets up the Envoy configuration.
@@ -298,7 +338,6 @@ func (w *MeshWebhook) Handle(ctx context.Context, req admission.Request) admissi
 			w.Log.Error(err, "error configuring injection init container", "request name", req.Name)
 			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("error configuring injection init container: %s", err))
 		}
-		pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
 
 		// Add the Envoy sidecar.
 		envoySidecar, err := w.consulDataplaneSidecar(*ns, pod, multiPortInfo{})
@@ -306,7 +345,13 @@ func (w *MeshWebhook) Handle(ctx context.Context, req admission.Request) admissi
 			w.Log.Error(err, "error configuring injection sidecar container", "request name", req.Name)
 			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("error configuring injection sidecar container: %s", err))
 		}
-		pod.Spec.Containers = append(pod.Spec.Containers, envoySidecar)
+
+		if nativeSidecar {
+			injectedInitContainers = append(injectedInitContainers, initContainer, envoySidecar)
+		} else {
+			pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
+			pod.Spec.Containers = append(pod.Spec.Containers, envoySidecar)
+		}
 	} else {
 		// For multi port pods, check for unsupported cases, mount all relevant service account tokens, and mount an init
 		// container and envoy sidecar per port. Tproxy, metrics, and metrics merging are not supported for multi port pods.
@@ -368,7 +413,6 @@ func (w *MeshWebhook) Handle(ctx context.Context, req admission.Request) admissi
 				w.Log.Error(err, "error configuring injection init container", "request name", req.Name)
 				return admission.Errored(http.StatusInternalServerError, fmt.Errorf("error configuring injection init container: %s", err))
 			}
-			pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
 
 			// Add the Envoy sidecar.
 			envoySidecar, err := w.consulDataplaneSidecar(*ns, pod, mpi)
@@ -376,9 +420,18 @@ func (w *MeshWebhook) Handle(ctx context.Context, req admission.Request) admissi
 				w.Log.Error(err, "error configuring injection sidecar container", "request name", req.Name)
 				return admission.Errored(http.StatusInternalServerError, fmt.Errorf("error configuring injection sidecar container: %s", err))
 			}
-			pod.Spec.Containers = append(pod.Spec.Containers, envoySidecar)
+
+			if nativeSidecar {
+				injectedInitContainers = append(injectedInitContainers, initContainer, envoySidecar)
+			} else {
+				pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
+				pod.Spec.Containers = append(pod.Spec.Containers, envoySidecar)
+			}
 		}
 	}
+	if nativeSidecar {
+		pod.Spec.InitContainers = append(injectedInitContainers, pod.Spec.InitContainers...)
+	}
 
 	// pod.Annotations has already been initialized by h.defaultAnnotations()
 	// and does not need to be checked for being a nil value.
@@ -415,6 +468,12 @@ func (w *MeshWebhook) Handle(ctx context.Context, req admission.Request) admissi
 		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("error configuring prometheus annotations: %s", err))
 	}
 
+	// Record the resolved configuration on the pod for debugging.
+	if err = w.addInjectionConfigAnnotation(*ns, &pod); err != nil {
+		w.Log.Error(err, "error adding injection config annotation", "request name", req.Name)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("error adding injection config annotation: %s", err))
+	}
+
 	if pod.Labels == nil {
 		pod.Labels = make(map[string]string)
 	}
@@ -445,12 +504,30 @@ func (w *MeshWebhook) Handle(ctx context.Context, req admission.Request) admissi
 		}
 	}
 
+	// Move the consul-dataplane sidecars ahead of the application containers if the application
+	// should be held until the proxy is ready. This is done last so that the indexes of the
+	// application containers, from which the exposed paths ports are derived, are unchanged.
+	holdApplication, err := w.LifecycleConfig.HoldApplicationUntilProxyReady(pod)
+	if err != nil {
+		w.Log.Error(err, "error determining if the application should be held until the proxy is ready", "request name", req.Name)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("error determining if the application should be held until the proxy is ready: %s", err))
+	}
+	if holdApplication {
+		moveSidecarsFirst(&pod)
+	}
+
 	// Marshall the pod into JSON after it has the desired envs, annotations, labels,
 	// sidecars and initContainers appended to it.
 	updatedPodJson, err := json.Marshal(pod)
 	if err != nil {
 		return admission.Errored(http.StatusBadRequest, err)
 	}
+	if nativeSidecar {
+		updatedPodJson, err = withNativeSidecars(updatedPodJson)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
 
 	// Create a patches based on the Pod that was received by the meshWebhook
 	// and the desired Pod spec.
@@ -484,7 +561,9 @@ func (w *MeshWebhook) Handle(ctx context.Context, req admission.Request) admissi
 
 	// Return a Patched response along with the patches we intend on applying to the
 	// Pod received by the meshWebhook.
-	return admission.Patched(fmt.Sprintf("valid %s request", pod.Kind), patches...)
+	resp := admission.Patched(fmt.Sprintf("valid %s request", pod.Kind), patches...)
+	resp.Warnings = warnings
+	return resp
 }
 
 // overwriteProbes overwrites readiness/liveness probes of this pod when
@@ -501,9 +580,11 @@ func (w *MeshWebhook) overwriteProbes(ns corev1.Namespace, pod *corev1.Pod) erro
 	}
 
 	if tproxyEnabled && overwriteProbes {
+		// Native sidecars are injected as init containers, so the indexes of the application containers
+		// are the same with and without native sidecars.
 		for i, container := range pod.Spec.Containers {
 			// skip the "envoy-sidecar" container from having it's probes overridden
-			if container.Name == sidecarContainer {
+			if isSidecarContainer(container) {
 				continue
 			}
 			if container.LivenessProbe != nil && container.LivenessProbe.HTTPGet != nil {
@@ -520,6 +601,22 @@ func (w *MeshWebhook) overwriteProbes(ns corev1.Namespace, pod *corev1.Pod) erro
 	return nil
 }
 
+// moveSidecarsFirst moves the consul-dataplane containers ahead of the other containers of the pod.
+// The kubelet starts containers in order and waits for the PostStart hook of a container to complete
+// before starting the next one, so the application containers are only started once the
+// graceful startup endpoint of consul-dataplane reports that the proxy is ready.
+func moveSidecarsFirst(pod *corev1.Pod) {
+	var sidecars, containers []corev1.Container
+	for _, container := range pod.Spec.Containers {
+		if isSidecarContainer(container) {
+			sidecars = append(sidecars, container)
+		} else {
+			containers = append(containers, container)
+		}
+	}
+	pod.Spec.Containers = append(sidecars, containers...)
+}
+
 func (w *MeshWebhook) injectVolumeMount(pod corev1.Pod) {
 	containersToInject := splitCommaSeparatedItemsFromAnnotation(constants.AnnotationInjectMountVolumes, pod)
 
@@ -175,6 +175,10 @@ func TestHandlerHandle(t *testing.T) {
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationOriginalPod),
 				},
+				{
+					Operation: "add",
+					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationInjectionConfig),
+				},
 				{
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationConsulK8sVersion),
@@ -268,6 +272,10 @@ func TestHandlerHandle(t *testing.T) {
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationOriginalPod),
 				},
+				{
+					Operation: "add",
+					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationInjectionConfig),
+				},
 				{
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationConsulK8sVersion),
@@ -323,6 +331,10 @@ func TestHandlerHandle(t *testing.T) {
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationOriginalPod),
 				},
+				{
+					Operation: "add",
+					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationInjectionConfig),
+				},
 				{
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationConsulK8sVersion),
@@ -397,6 +409,10 @@ func TestHandlerHandle(t *testing.T) {
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationOriginalPod),
 				},
+				{
+					Operation: "add",
+					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationInjectionConfig),
+				},
 				{
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationConsulK8sVersion),
@@ -457,6 +473,10 @@ func TestHandlerHandle(t *testing.T) {
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationOriginalPod),
 				},
+				{
+					Operation: "add",
+					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationInjectionConfig),
+				},
 				{
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationConsulK8sVersion),
@@ -542,6 +562,10 @@ func TestHandlerHandle(t *testing.T) {
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationOriginalPod),
 				},
+				{
+					Operation: "add",
+					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationInjectionConfig),
+				},
 				{
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationConsulK8sVersion),
@@ -679,6 +703,10 @@ func TestHandlerHandle(t *testing.T) {
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationOriginalPod),
 				},
+				{
+					Operation: "add",
+					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationInjectionConfig),
+				},
 				{
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationConsulK8sVersion),
@@ -742,6 +770,10 @@ func TestHandlerHandle(t *testing.T) {
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationOriginalPod),
 				},
+				{
+					Operation: "add",
+					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationInjectionConfig),
+				},
 				{
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationConsulK8sVersion),
@@ -801,6 +833,10 @@ func TestHandlerHandle(t *testing.T) {
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationOriginalPod),
 				},
+				{
+					Operation: "add",
+					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationInjectionConfig),
+				},
 				{
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationConsulK8sVersion),
@@ -871,6 +907,10 @@ func TestHandlerHandle(t *testing.T) {
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationOriginalPod),
 				},
+				{
+					Operation: "add",
+					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationInjectionConfig),
+				},
 				{
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationConsulK8sVersion),
@@ -943,6 +983,10 @@ func TestHandlerHandle(t *testing.T) {
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationOriginalPod),
 				},
+				{
+					Operation: "add",
+					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationInjectionConfig),
+				},
 				{
 					Operation: "add",
 					Path:      "/metadata/annotations/" + escapeJSONPointer(constants.AnnotationConsulK8sVersion),
@@ -950,6 +994,56 @@ func TestHandlerHandle(t *testing.T) {
 				// Note: no DNS policy/config additions.
 			},
 		},
+		{
+			"pod with invalid upstreams annotation",
+			MeshWebhook{
+				Log:                   logrtest.New(t),
+				AllowK8sNamespacesSet: mapset.NewSetWith("*"),
+				DenyK8sNamespacesSet:  mapset.NewSet(),
+				decoder:               decoder,
+				Clientset:             defaultTestClientWithNamespace(),
+			},
+			admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Namespace: namespaces.DefaultNamespace,
+					Object: encodeRaw(t, &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								constants.AnnotationUpstreams: "echo:1234,db",
+							},
+						},
+						Spec: basicSpec,
+					}),
+				},
+			},
+			`invalid annotations: consul.hashicorp.com/connect-service-upstreams annotation was invalid: upstream "db" must be in the format [service-name]:[port]`,
+			nil,
+		},
+		{
+			"pod with invalid upstreams config annotation",
+			MeshWebhook{
+				Log:                   logrtest.New(t),
+				AllowK8sNamespacesSet: mapset.NewSetWith("*"),
+				DenyK8sNamespacesSet:  mapset.NewSet(),
+				decoder:               decoder,
+				Clientset:             defaultTestClientWithNamespace(),
+			},
+			admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Namespace: namespaces.DefaultNamespace,
+					Object: encodeRaw(t, &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								constants.AnnotationUpstreamsConfig: `[{"destinationName": "db", "localBindPort": 0}]`,
+							},
+						},
+						Spec: basicSpec,
+					}),
+				},
+			},
+			"invalid annotations: consul.hashicorp.com/connect-service-upstreams-config annotation was invalid: upstream 0: localBindPort 0 must be between 1 and 65535",
+			nil,
+		},
 	}
 
 	for _, tt := range cases {
@@ -976,6 +1070,175 @@ func TestHandlerHandle(t *testing.T) {
 	}
 }
 
+func TestHandlerHandle_NativeSidecar(t *testing.T) {
+	t.Parallel()
+	s := runtime.NewScheme()
+	s.AddKnownTypes(schema.GroupVersion{
+		Group:   "",
+		Version: "v1",
+	}, &corev1.Pod{})
+	decoder, err := admission.NewDecoder(s)
+	require.NoError(t, err)
+
+	cases := map[string]struct {
+		enableNativeSidecar   bool
+		unsupported           bool
+		annotations           map[string]string
+		expInitContainers     []string
+		expNativeSidecars     []string
+		expContainerPatchPath string
+		expErr                string
+	}{
+		"disabled": {
+			expInitContainers:     []string{"consul-connect-inject-init"},
+			expContainerPatchPath: "/spec/containers/1",
+		},
+		"enabled via meshWebhook": {
+			enableNativeSidecar: true,
+			expInitContainers:   []string{"consul-connect-inject-init", "consul-dataplane"},
+			expNativeSidecars:   []string{"consul-dataplane"},
+		},
+		"enabled via annotation": {
+			annotations: map[string]string{
+				constants.AnnotationEnableNativeSidecar: "true",
+			},
+			expInitContainers: []string{"consul-connect-inject-init", "consul-dataplane"},
+			expNativeSidecars: []string{"consul-dataplane"},
+		},
+		"enabled via annotation on an unsupported Kubernetes version": {
+			unsupported: true,
+			annotations: map[string]string{
+				constants.AnnotationEnableNativeSidecar: "true",
+			},
+			expErr: "consul.hashicorp.com/enable-native-sidecar annotation requires Kubernetes 1.29 or later",
+		},
+		"disabled via annotation": {
+			enableNativeSidecar: true,
+			annotations: map[string]string{
+				constants.AnnotationEnableNativeSidecar: "false",
+			},
+			expInitContainers:     []string{"consul-connect-inject-init"},
+			expContainerPatchPath: "/spec/containers/1",
+		},
+		"multiport": {
+			enableNativeSidecar: true,
+			annotations: map[string]string{
+				constants.AnnotationService: "web,web-admin",
+			},
+			expInitContainers: []string{
+				"consul-connect-inject-init-web",
+				"consul-dataplane-web",
+				"consul-connect-inject-init-web-admin",
+				"consul-dataplane-web-admin",
+			},
+			expNativeSidecars: []string{"consul-dataplane-web", "consul-dataplane-web-admin"},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			w := MeshWebhook{
+				Log:                    logrtest.New(t),
+				AllowK8sNamespacesSet:  mapset.NewSetWith("*"),
+				DenyK8sNamespacesSet:   mapset.NewSet(),
+				decoder:                decoder,
+				Clientset:              defaultTestClientWithNamespace(),
+				ConsulConfig:           &consul.Config{HTTPPort: 8500},
+				EnableNativeSidecar:    c.enableNativeSidecar,
+				NativeSidecarSupported: !c.unsupported,
+			}
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Namespace: namespaces.DefaultNamespace,
+					Object: encodeRaw(t, &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: c.annotations,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name: "web",
+								},
+							},
+						},
+					}),
+				},
+			}
+
+			resp := w.Handle(context.Background(), req)
+			if c.expErr != "" {
+				require.False(t, resp.Allowed)
+				require.Contains(t, resp.Result.Message, c.expErr)
+				return
+			}
+			require.True(t, resp.Allowed, resp.Result)
+
+			var initContainers []interface{}
+			var containerPatchPaths []string
+			for _, patch := range resp.Patches {
+				if patch.Path == "/spec/initContainers" {
+					initContainers = patch.Value.([]interface{})
+				}
+				if strings.HasPrefix(patch.Path, "/spec/containers/") && !strings.HasPrefix(patch.Path, "/spec/containers/0") {
+					containerPatchPaths = append(containerPatchPaths, patch.Path)
+				}
+			}
+
+			var names, nativeSidecars []string
+			for _, raw := range initContainers {
+				container := raw.(map[string]interface{})
+				names = append(names, container["name"].(string))
+				if container["restartPolicy"] == containerRestartPolicyAlways {
+					nativeSidecars = append(nativeSidecars, container["name"].(string))
+				}
+			}
+			require.Equal(t, c.expInitContainers, names)
+			require.Equal(t, c.expNativeSidecars, nativeSidecars)
+
+			// The sidecar is only added to the containers if it is not a native sidecar.
+			if c.expContainerPatchPath == "" {
+				require.Empty(t, containerPatchPaths)
+			} else {
+				require.Equal(t, []string{c.expContainerPatchPath}, containerPatchPaths)
+			}
+		})
+	}
+}
+
+func TestWithNativeSidecars(t *testing.T) {
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{Name: "consul-connect-inject-init"},
+				{Name: "consul-dataplane"},
+				{Name: "user-init"},
+			},
+			Containers: []corev1.Container{
+				{Name: "web"},
+			},
+		},
+	}
+	podJson, err := json.Marshal(pod)
+	require.NoError(t, err)
+
+	actualJson, err := withNativeSidecars(podJson)
+	require.NoError(t, err)
+
+	var actual struct {
+		Spec struct {
+			InitContainers []map[string]interface{} `json:"initContainers"`
+			Containers     []map[string]interface{} `json:"containers"`
+		} `json:"spec"`
+	}
+	require.NoError(t, json.Unmarshal(actualJson, &actual))
+	require.Len(t, actual.Spec.InitContainers, 3)
+	require.NotContains(t, actual.Spec.InitContainers[0], "restartPolicy")
+	require.Equal(t, "Always", actual.Spec.InitContainers[1]["restartPolicy"])
+	require.NotContains(t, actual.Spec.InitContainers[2], "restartPolicy")
+	require.NotContains(t, actual.Spec.Containers[0], "restartPolicy")
+}
+
 func TestHandlerDefaultAnnotations(t *testing.T) {
 	cases := []struct {
 		Name     string
@@ -1886,6 +2149,46 @@ func TestOverwriteProbes(t *testing.T) {
 	}
 }
 
+func TestMoveSidecarsFirst(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		containers []string
+		expected   []string
+	}{
+		"single port": {
+			containers: []string{"web", "logger", sidecarContainer},
+			expected:   []string{sidecarContainer, "web", "logger"},
+		},
+		"multi port": {
+			containers: []string{"web", "web-admin", sidecarContainer + "-web", sidecarContainer + "-web-admin"},
+			expected:   []string{sidecarContainer + "-web", sidecarContainer + "-web-admin", "web", "web-admin"},
+		},
+		"sidecar already first": {
+			containers: []string{sidecarContainer, "web"},
+			expected:   []string{sidecarContainer, "web"},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			pod := &corev1.Pod{}
+			for _, container := range c.containers {
+				pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+			}
+
+			moveSidecarsFirst(pod)
+
+			var actual []string
+			for _, container := range pod.Spec.Containers {
+				actual = append(actual, container.Name)
+			}
+			require.Equal(t, c.expected, actual)
+		})
+	}
+}
+
 func TestHandler_checkUnsupportedMultiPortCases(t *testing.T) {
 	cases := []struct {
 		name        string
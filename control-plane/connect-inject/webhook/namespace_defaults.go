@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/common"
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/constants"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// namespaceDefaultAnnotations are the pod annotations that can also be set as annotations on a namespace to
+// define the default for the connect-injected pods in that namespace which do not set the annotation themselves.
+var namespaceDefaultAnnotations = []string{
+	constants.AnnotationSidecarProxyCPULimit,
+	constants.AnnotationSidecarProxyCPURequest,
+	constants.AnnotationSidecarProxyMemoryLimit,
+	constants.AnnotationSidecarProxyMemoryRequest,
+	constants.AnnotationEnvoyProxyConcurrency,
+	constants.AnnotationEnableMetrics,
+	constants.AnnotationEnableMetricsMerging,
+	constants.AnnotationMergedMetricsPort,
+	constants.AnnotationPrometheusScrapePort,
+	constants.AnnotationPrometheusScrapePath,
+	constants.KeyTransparentProxy,
+	constants.AnnotationTransparentProxyOverwriteProbes,
+}
+
+// injectionConfig is the configuration of the injected containers resolved for a pod. It is recorded on the pod
+// in the injection-config annotation so that users can see which settings a pod ended up with.
+type injectionConfig struct {
+	SidecarProxyCPULimit            string `json:"sidecarProxyCPULimit,omitempty"`
+	SidecarProxyCPURequest          string `json:"sidecarProxyCPURequest,omitempty"`
+	SidecarProxyMemoryLimit         string `json:"sidecarProxyMemoryLimit,omitempty"`
+	SidecarProxyMemoryRequest       string `json:"sidecarProxyMemoryRequest,omitempty"`
+	EnvoyProxyConcurrency           int    `json:"envoyProxyConcurrency"`
+	EnableMetrics                   bool   `json:"enableMetrics"`
+	EnableMetricsMerging            bool   `json:"enableMetricsMerging"`
+	MergedMetricsPort               string `json:"mergedMetricsPort,omitempty"`
+	PrometheusScrapePort            string `json:"prometheusScrapePort,omitempty"`
+	PrometheusScrapePath            string `json:"prometheusScrapePath,omitempty"`
+	TransparentProxy                bool   `json:"transparentProxy"`
+	TransparentProxyOverwriteProbes bool   `json:"transparentProxyOverwriteProbes"`
+}
+
+// applyNamespaceDefaults copies the namespaceDefaultAnnotations which are set on the namespace to the pod,
+// unless the pod sets them itself. Settings are thereby resolved from the pod, then its namespace and then the
+// global defaults of the meshWebhook. The annotations are kept on the pod so that the endpoints controller,
+// which reads the same annotations when registering the service, resolves the same settings. It returns the
+// annotations that were copied so that invalid values can be reported against the namespace.
+func applyNamespaceDefaults(ns corev1.Namespace, pod *corev1.Pod) []string {
+	var inherited []string
+	for _, key := range namespaceDefaultAnnotations {
+		raw, ok := ns.Annotations[key]
+		if !ok {
+			continue
+		}
+		if _, ok := pod.Annotations[key]; ok {
+			continue
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[key] = raw
+		inherited = append(inherited, key)
+	}
+	return inherited
+}
+
+// addInjectionConfigAnnotation records the configuration of the injected containers resolved for the pod as a
+// JSON annotation on the pod. It must be called after applyNamespaceDefaults.
+func (w *MeshWebhook) addInjectionConfigAnnotation(ns corev1.Namespace, pod *corev1.Pod) error {
+	var cfg injectionConfig
+
+	resources, err := w.sidecarResources(*pod)
+	if err != nil {
+		return err
+	}
+	if cpu, ok := resources.Limits[corev1.ResourceCPU]; ok {
+		cfg.SidecarProxyCPULimit = cpu.String()
+	}
+	if cpu, ok := resources.Requests[corev1.ResourceCPU]; ok {
+		cfg.SidecarProxyCPURequest = cpu.String()
+	}
+	if memory, ok := resources.Limits[corev1.ResourceMemory]; ok {
+		cfg.SidecarProxyMemoryLimit = memory.String()
+	}
+	if memory, ok := resources.Requests[corev1.ResourceMemory]; ok {
+		cfg.SidecarProxyMemoryRequest = memory.String()
+	}
+
+	cfg.EnvoyProxyConcurrency, err = w.envoyProxyConcurrency(*pod)
+	if err != nil {
+		return err
+	}
+
+	cfg.EnableMetrics, err = w.MetricsConfig.EnableMetrics(*pod)
+	if err != nil {
+		return err
+	}
+	cfg.EnableMetricsMerging, err = w.MetricsConfig.EnableMetricsMerging(*pod)
+	if err != nil {
+		return err
+	}
+	if cfg.EnableMetricsMerging {
+		cfg.MergedMetricsPort, err = w.MetricsConfig.MergedMetricsPort(*pod)
+		if err != nil {
+			return err
+		}
+	}
+	if cfg.EnableMetrics {
+		cfg.PrometheusScrapePort, err = w.MetricsConfig.PrometheusScrapePort(*pod)
+		if err != nil {
+			return err
+		}
+		cfg.PrometheusScrapePath = w.MetricsConfig.PrometheusScrapePath(*pod)
+	}
+
+	cfg.TransparentProxy, err = common.TransparentProxyEnabled(ns, *pod, w.EnableTransparentProxy)
+	if err != nil {
+		return err
+	}
+	if cfg.TransparentProxy {
+		cfg.TransparentProxyOverwriteProbes, err = common.ShouldOverwriteProbes(*pod, w.TProxyOverwriteProbes)
+		if err != nil {
+			return err
+		}
+	}
+
+	cfgJSON, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("could not marshal injection config: %w", err)
+	}
+	pod.Annotations[constants.AnnotationInjectionConfig] = string(cfgJSON)
+	return nil
+}
@@ -0,0 +1,226 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	logrtest "github.com/go-logr/logr/testr"
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/constants"
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/metrics"
+	"github.com/hashicorp/consul-k8s/control-plane/consul"
+	"github.com/hashicorp/consul-k8s/control-plane/namespaces"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestApplyNamespaceDefaults(t *testing.T) {
+	cases := map[string]struct {
+		nsAnnotations  map[string]string
+		podAnnotations map[string]string
+		expAnnotations map[string]string
+		expInherited   []string
+	}{
+		"no namespace annotations": {
+			podAnnotations: map[string]string{
+				constants.AnnotationSidecarProxyCPURequest: "100m",
+			},
+			expAnnotations: map[string]string{
+				constants.AnnotationSidecarProxyCPURequest: "100m",
+			},
+		},
+		"namespace annotations are copied": {
+			nsAnnotations: map[string]string{
+				constants.AnnotationSidecarProxyCPURequest: "200m",
+				constants.AnnotationEnvoyProxyConcurrency:  "4",
+				constants.AnnotationEnableMetrics:          "true",
+				constants.KeyTransparentProxy:              "false",
+			},
+			expAnnotations: map[string]string{
+				constants.AnnotationSidecarProxyCPURequest: "200m",
+				constants.AnnotationEnvoyProxyConcurrency:  "4",
+				constants.AnnotationEnableMetrics:          "true",
+				constants.KeyTransparentProxy:              "false",
+			},
+			expInherited: []string{
+				constants.AnnotationSidecarProxyCPURequest,
+				constants.AnnotationEnvoyProxyConcurrency,
+				constants.AnnotationEnableMetrics,
+				constants.KeyTransparentProxy,
+			},
+		},
+		"pod annotations take precedence": {
+			nsAnnotations: map[string]string{
+				constants.AnnotationSidecarProxyCPURequest: "200m",
+				constants.AnnotationEnvoyProxyConcurrency:  "4",
+			},
+			podAnnotations: map[string]string{
+				constants.AnnotationEnvoyProxyConcurrency: "1",
+			},
+			expAnnotations: map[string]string{
+				constants.AnnotationSidecarProxyCPURequest: "200m",
+				constants.AnnotationEnvoyProxyConcurrency:  "1",
+			},
+			expInherited: []string{constants.AnnotationSidecarProxyCPURequest},
+		},
+		"other namespace annotations are not copied": {
+			nsAnnotations: map[string]string{
+				constants.AnnotationService:   "web",
+				constants.AnnotationUpstreams: "db:1234",
+				"example.com/team":            "payments",
+			},
+			expAnnotations: nil,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Annotations: c.nsAnnotations}}
+			pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: c.podAnnotations}}
+
+			inherited := applyNamespaceDefaults(ns, &pod)
+			require.Equal(t, c.expAnnotations, pod.Annotations)
+			require.ElementsMatch(t, c.expInherited, inherited)
+		})
+	}
+}
+
+func TestHandlerHandle_NamespaceDefaults(t *testing.T) {
+	s := runtime.NewScheme()
+	s.AddKnownTypes(schema.GroupVersion{
+		Group:   "",
+		Version: "v1",
+	}, &corev1.Pod{})
+	decoder, err := admission.NewDecoder(s)
+	require.NoError(t, err)
+
+	ns := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespaces.DefaultNamespace,
+			Annotations: map[string]string{
+				constants.AnnotationSidecarProxyCPURequest: "200m",
+				constants.AnnotationEnvoyProxyConcurrency:  "4",
+				constants.AnnotationEnableMetrics:          "true",
+			},
+		},
+	}
+	w := MeshWebhook{
+		Log:                          logrtest.New(t),
+		AllowK8sNamespacesSet:        mapset.NewSetWith("*"),
+		DenyK8sNamespacesSet:         mapset.NewSet(),
+		decoder:                      decoder,
+		Clientset:                    fake.NewSimpleClientset(&ns),
+		ConsulConfig:                 &consul.Config{HTTPPort: 8500},
+		DefaultProxyMemoryRequest:    resource.MustParse("64Mi"),
+		DefaultEnvoyProxyConcurrency: 2,
+		MetricsConfig: metrics.Config{
+			DefaultPrometheusScrapePort: "20200",
+			DefaultPrometheusScrapePath: "/metrics",
+		},
+	}
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Namespace: namespaces.DefaultNamespace,
+			Object: encodeRaw(t, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						constants.AnnotationEnvoyProxyConcurrency: "1",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "web",
+						},
+					},
+				},
+			}),
+		},
+	}
+
+	resp := w.Handle(context.Background(), req)
+	require.True(t, resp.Allowed, resp.Result)
+
+	annotations := make(map[string]interface{})
+	for _, patch := range resp.Patches {
+		if key := strings.TrimPrefix(patch.Path, "/metadata/annotations/"); key != patch.Path {
+			annotations[key] = patch.Value
+		}
+	}
+
+	// The namespace defaults that the pod doesn't override are kept on the pod.
+	require.Equal(t, "200m", annotations[escapeJSONPointer(constants.AnnotationSidecarProxyCPURequest)])
+	require.Equal(t, "true", annotations[escapeJSONPointer(constants.AnnotationEnableMetrics)])
+	require.NotContains(t, annotations, escapeJSONPointer(constants.AnnotationEnvoyProxyConcurrency))
+
+	raw, ok := annotations[escapeJSONPointer(constants.AnnotationInjectionConfig)].(string)
+	require.True(t, ok)
+	var cfg injectionConfig
+	require.NoError(t, json.Unmarshal([]byte(raw), &cfg))
+	require.Equal(t, injectionConfig{
+		SidecarProxyCPURequest:    "200m",
+		SidecarProxyMemoryRequest: "64Mi",
+		EnvoyProxyConcurrency:     1,
+		EnableMetrics:             true,
+		PrometheusScrapePort:      "20200",
+		PrometheusScrapePath:      "/metrics",
+	}, cfg)
+}
+
+func TestHandlerHandle_InvalidNamespaceDefaults(t *testing.T) {
+	s := runtime.NewScheme()
+	s.AddKnownTypes(schema.GroupVersion{
+		Group:   "",
+		Version: "v1",
+	}, &corev1.Pod{})
+	decoder, err := admission.NewDecoder(s)
+	require.NoError(t, err)
+
+	ns := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespaces.DefaultNamespace,
+			Annotations: map[string]string{
+				constants.AnnotationEnableMetrics: "yes",
+			},
+		},
+	}
+	w := MeshWebhook{
+		Log:                   logrtest.New(t),
+		AllowK8sNamespacesSet: mapset.NewSetWith("*"),
+		DenyK8sNamespacesSet:  mapset.NewSet(),
+		decoder:               decoder,
+		Clientset:             fake.NewSimpleClientset(&ns),
+		ConsulConfig:          &consul.Config{HTTPPort: 8500},
+	}
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Namespace: namespaces.DefaultNamespace,
+			Object: encodeRaw(t, &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "web",
+						},
+					},
+				},
+			}),
+		},
+	}
+
+	resp := w.Handle(context.Background(), req)
+	require.False(t, resp.Allowed)
+	require.Contains(t, resp.Result.Message, "consul.hashicorp.com/enable-metrics annotation value of yes was invalid")
+	require.Contains(t, resp.Result.Message, "(inherited from the annotations of namespace default)")
+}
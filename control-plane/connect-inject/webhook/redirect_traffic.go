@@ -21,7 +21,7 @@ import (
 //	ProxyUserID: a constant set in Annotations
 //	ProxyInboundPort: the service port or bind port
 //	ProxyOutboundPort: default transparent proxy outbound port or transparent proxy outbound listener port
-//	ExcludeInboundPorts: prometheus, envoy stats, expose paths, checks and excluded pod annotations
+//	ExcludeInboundPorts: prometheus, envoy stats, proxy lifecycle, expose paths, checks and excluded pod annotations
 //	ExcludeOutboundPorts: pod annotations
 //	ExcludeOutboundCIDRs: pod annotations
 //	ExcludeUIDs: pod annotations
@@ -61,10 +61,24 @@ func (w *MeshWebhook) iptablesConfigJSON(pod corev1.Pod, ns corev1.Namespace) (s
 		cfg.ExcludeInboundPorts = append(cfg.ExcludeInboundPorts, strconv.Itoa(constants.ProxyDefaultHealthPort))
 	}
 
+	// Exclude the port on which the graceful startup and shutdown endpoints of the proxy are served
+	// if proxy lifecycle management is enabled, so that the lifecycle hooks reach consul-dataplane.
+	enableProxyLifecycle, err := w.LifecycleConfig.EnableProxyLifecycle(pod)
+	if err != nil {
+		return "", err
+	}
+	if enableProxyLifecycle {
+		gracefulPort, err := w.LifecycleConfig.GracefulPort(pod)
+		if err != nil {
+			return "", err
+		}
+		cfg.ExcludeInboundPorts = append(cfg.ExcludeInboundPorts, strconv.Itoa(gracefulPort))
+	}
+
 	if overwriteProbes {
 		for i, container := range pod.Spec.Containers {
 			// skip the "envoy-sidecar" container from having its probes overridden
-			if container.Name == sidecarContainer {
+			if isSidecarContainer(container) {
 				continue
 			}
 			if container.LivenessProbe != nil && container.LivenessProbe.HTTPGet != nil {
@@ -25,6 +25,11 @@ type Controller struct {
 	Log      hclog.Logger
 	Resource Resource
 
+	// Name is the name of the workqueue of the controller. If it is set, the
+	// workqueue reports its metrics, such as its depth, to the workqueue
+	// metrics provider under this name.
+	Name string
+
 	informer cache.SharedIndexInformer
 }
 
@@ -55,7 +60,7 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 
 	// Create a queue for storing items to process from the informer.
 	var queueOnce sync.Once
-	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), c.Name)
 	shutdown := func() { queue.ShutDown() }
 	defer queueOnce.Do(shutdown)
 
@@ -21,6 +21,7 @@ import (
 	"github.com/hashicorp/consul-k8s/control-plane/api/v1alpha1"
 	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/controllers/endpoints"
 	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/controllers/peering"
+	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/lifecycle"
 	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/metrics"
 	"github.com/hashicorp/consul-k8s/control-plane/connect-inject/webhook"
 	"github.com/hashicorp/consul-k8s/control-plane/controllers"
@@ -34,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	k8sversion "k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -86,6 +88,15 @@ type Command struct {
 	flagDefaultSidecarProxyMemoryRequest string
 	flagDefaultEnvoyProxyConcurrency     int
 
+	// Proxy lifecycle settings.
+	flagDefaultEnableSidecarProxyLifecycle                       bool
+	flagDefaultEnableSidecarProxyLifecycleShutdownDrainListeners bool
+	flagDefaultSidecarProxyLifecycleShutdownGracePeriodSeconds   int
+	flagDefaultSidecarProxyLifecycleStartupGracePeriodSeconds    int
+	flagDefaultSidecarProxyLifecycleGracefulPort                 string
+	flagDefaultSidecarProxyLifecycleGracefulShutdownPath         string
+	flagDefaultSidecarProxyLifecycleGracefulStartupPath          string
+
 	// Metrics settings.
 	flagDefaultEnableMetrics        bool
 	flagEnableGatewayMetrics        bool
@@ -104,6 +115,9 @@ type Command struct {
 	flagDefaultEnableTransparentProxy          bool
 	flagTransparentProxyDefaultOverwriteProbes bool
 
+	// Native sidecar flag.
+	flagDefaultEnableNativeSidecar bool
+
 	// CNI flag.
 	flagEnableCNI bool
 
@@ -121,6 +135,9 @@ type Command struct {
 	// Consul telemetry collector
 	flagEnableTelemetryCollector bool
 
+	// Endpoints controller flags.
+	flagEnableEndpointSlices bool
+
 	// Consul DNS flags.
 	flagEnableConsulDNS bool
 	flagResourcePrefix  string
@@ -208,6 +225,8 @@ func (c *Command) init() {
 		"Indicates whether TLS with auto-encrypt should be used when talking to Consul clients.")
 	c.flagSet.BoolVar(&c.flagEnableTelemetryCollector, "enable-telemetry-collector", false,
 		"Indicates whether proxies should be registered with configuration to enable forwarding metrics to consul-telemetry-collector")
+	c.flagSet.BoolVar(&c.flagEnableEndpointSlices, "enable-endpoint-slices", false,
+		"Reconcile service instances from discovery.k8s.io/v1 EndpointSlices instead of Endpoints. Required for services with more than 1000 pods.")
 	c.flagSet.StringVar(&c.flagLogLevel, "log-level", zapcore.InfoLevel.String(),
 		fmt.Sprintf("Log verbosity level. Supported values (in order of detail) are "+
 			"%q, %q, %q, and %q.", zapcore.DebugLevel.String(), zapcore.InfoLevel.String(), zapcore.WarnLevel.String(), zapcore.ErrorLevel.String()))
@@ -220,6 +239,18 @@ func (c *Command) init() {
 	c.flagSet.StringVar(&c.flagDefaultSidecarProxyMemoryRequest, "default-sidecar-proxy-memory-request", "", "Default sidecar proxy memory request.")
 	c.flagSet.StringVar(&c.flagDefaultSidecarProxyMemoryLimit, "default-sidecar-proxy-memory-limit", "", "Default sidecar proxy memory limit.")
 
+	c.flagSet.BoolVar(&c.flagDefaultEnableNativeSidecar, "default-enable-native-sidecar", false,
+		"Default for injecting the sidecar proxy as a native sidecar container, i.e. an init container with restartPolicy Always. Requires Kubernetes 1.29 or later.")
+
+	// Proxy lifecycle setting flags.
+	c.flagSet.BoolVar(&c.flagDefaultEnableSidecarProxyLifecycle, "default-enable-sidecar-proxy-lifecycle", false, "Default for enabling graceful startup and shutdown of the sidecar proxy.")
+	c.flagSet.BoolVar(&c.flagDefaultEnableSidecarProxyLifecycleShutdownDrainListeners, "default-enable-sidecar-proxy-lifecycle-shutdown-drain-listeners", true, "Default for enabling draining of inbound listeners of the sidecar proxy on shutdown.")
+	c.flagSet.IntVar(&c.flagDefaultSidecarProxyLifecycleShutdownGracePeriodSeconds, "default-sidecar-proxy-lifecycle-shutdown-grace-period-seconds", 30, "Default number of seconds the sidecar proxy keeps running after it is asked to shut down.")
+	c.flagSet.IntVar(&c.flagDefaultSidecarProxyLifecycleStartupGracePeriodSeconds, "default-sidecar-proxy-lifecycle-startup-grace-period-seconds", 0, "Default number of seconds the application containers are held until the sidecar proxy is ready. 0 disables holding the application.")
+	c.flagSet.StringVar(&c.flagDefaultSidecarProxyLifecycleGracefulPort, "default-sidecar-proxy-lifecycle-graceful-port", strconv.Itoa(lifecycle.DefaultGracefulPort), "Default port the sidecar proxy serves its graceful startup and shutdown endpoints on.")
+	c.flagSet.StringVar(&c.flagDefaultSidecarProxyLifecycleGracefulShutdownPath, "default-sidecar-proxy-lifecycle-graceful-shutdown-path", lifecycle.DefaultGracefulShutdownPath, "Default path of the graceful shutdown endpoint of the sidecar proxy.")
+	c.flagSet.StringVar(&c.flagDefaultSidecarProxyLifecycleGracefulStartupPath, "default-sidecar-proxy-lifecycle-graceful-startup-path", lifecycle.DefaultGracefulStartupPath, "Default path of the graceful startup endpoint of the sidecar proxy.")
+
 	// Metrics setting flags.
 	c.flagSet.BoolVar(&c.flagDefaultEnableMetrics, "default-enable-metrics", false, "Default for enabling connect service metrics.")
 	c.flagSet.BoolVar(&c.flagEnableGatewayMetrics, "enable-gateway-metrics", false, "Allows enabling Consul gateway metrics.")
@@ -316,6 +347,13 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
+	// Validate the port in proxy lifecycle flags.
+	err = common.ValidateUnprivilegedPort("-default-sidecar-proxy-lifecycle-graceful-port", c.flagDefaultSidecarProxyLifecycleGracefulPort)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	// Validate resource request/limit flags and parse into corev1.ResourceRequirements
 	initResources, err := c.parseAndValidateResourceFlags()
 	if err != nil {
@@ -337,6 +375,20 @@ func (c *Command) Run(args []string) int {
 		}
 	}
 
+	// Whether native sidecar containers are supported is also passed to the webhook so that it can reject
+	// pods which enable them via annotation on clusters which don't support them.
+	nativeSidecarSupported, k8sVersion, nativeSidecarErr := c.nativeSidecarSupported()
+	if c.flagDefaultEnableNativeSidecar {
+		if nativeSidecarErr != nil {
+			c.UI.Error(fmt.Sprintf("unable to determine the Kubernetes version for -default-enable-native-sidecar: %s", nativeSidecarErr))
+			return 1
+		}
+		if !nativeSidecarSupported {
+			c.UI.Error(fmt.Sprintf("-default-enable-native-sidecar requires Kubernetes 1.29 or later, but the Kubernetes version is %s", k8sVersion))
+			return 1
+		}
+	}
+
 	// Convert allow/deny lists to sets.
 	allowK8sNamespaces := flags.ToSet(c.flagAllowK8sNamespacesList)
 	denyK8sNamespaces := flags.ToSet(c.flagDenyK8sNamespacesList)
@@ -349,6 +401,10 @@ func (c *Command) Run(args []string) int {
 	ctrl.SetLogger(zapLogger)
 	klog.SetLogger(zapLogger)
 
+	if nativeSidecarErr != nil {
+		setupLog.Error(nativeSidecarErr, "unable to determine if native sidecar containers are supported, pods enabling them will be rejected")
+	}
+
 	// TODO (agentless): find a way to integrate zap logger (via having a generic logger interface in connection manager).
 	hcLog, err := common.NamedLogger(c.flagLogLevel, c.flagLogJSON, "consul-server-connection-manager")
 	if err != nil {
@@ -431,6 +487,16 @@ func (c *Command) Run(args []string) int {
 		DefaultPrometheusScrapePath: c.flagDefaultPrometheusScrapePath,
 	}
 
+	lifecycleConfig := lifecycle.Config{
+		DefaultEnableProxyLifecycle:         c.flagDefaultEnableSidecarProxyLifecycle,
+		DefaultEnableShutdownDrainListeners: c.flagDefaultEnableSidecarProxyLifecycleShutdownDrainListeners,
+		DefaultShutdownGracePeriodSeconds:   c.flagDefaultSidecarProxyLifecycleShutdownGracePeriodSeconds,
+		DefaultStartupGracePeriodSeconds:    c.flagDefaultSidecarProxyLifecycleStartupGracePeriodSeconds,
+		DefaultGracefulPort:                 c.flagDefaultSidecarProxyLifecycleGracefulPort,
+		DefaultGracefulShutdownPath:         c.flagDefaultSidecarProxyLifecycleGracefulShutdownPath,
+		DefaultGracefulStartupPath:          c.flagDefaultSidecarProxyLifecycleGracefulStartupPath,
+	}
+
 	if err = (&endpoints.Controller{
 		Client:                     mgr.GetClient(),
 		ConsulClientConfig:         consulConfig,
@@ -455,6 +521,7 @@ func (c *Command) Run(args []string) int {
 		ReleaseNamespace:           c.flagReleaseNamespace,
 		EnableAutoEncrypt:          c.flagEnableAutoEncrypt,
 		EnableTelemetryCollector:   c.flagEnableTelemetryCollector,
+		EnableEndpointSlices:       c.flagEnableEndpointSlices,
 		Context:                    ctx,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", endpoints.Controller{})
@@ -726,6 +793,7 @@ func (c *Command) Run(args []string) int {
 			DefaultProxyMemoryLimit:      sidecarProxyMemoryLimit,
 			DefaultEnvoyProxyConcurrency: c.flagDefaultEnvoyProxyConcurrency,
 			MetricsConfig:                metricsConfig,
+			LifecycleConfig:              lifecycleConfig,
 			InitContainerResources:       initResources,
 			ConsulPartition:              c.consul.Partition,
 			AllowK8sNamespacesSet:        allowK8sNamespaces,
@@ -736,6 +804,8 @@ func (c *Command) Run(args []string) int {
 			K8SNSMirroringPrefix:         c.flagK8SNSMirroringPrefix,
 			CrossNamespaceACLPolicy:      c.flagCrossNamespaceACLPolicy,
 			EnableTransparentProxy:       c.flagDefaultEnableTransparentProxy,
+			EnableNativeSidecar:          c.flagDefaultEnableNativeSidecar,
+			NativeSidecarSupported:       nativeSidecarSupported,
 			EnableCNI:                    c.flagEnableCNI,
 			TProxyOverwriteProbes:        c.flagTransparentProxyDefaultOverwriteProbes,
 			EnableConsulDNS:              c.flagEnableConsulDNS,
@@ -888,9 +958,31 @@ func (c *Command) validateFlags() error {
 		return errors.New("-default-envoy-proxy-concurrency must be >= 0 if set")
 	}
 
+	if c.flagDefaultSidecarProxyLifecycleShutdownGracePeriodSeconds < 0 {
+		return errors.New("-default-sidecar-proxy-lifecycle-shutdown-grace-period-seconds must be >= 0 if set")
+	}
+
+	if c.flagDefaultSidecarProxyLifecycleStartupGracePeriodSeconds < 0 {
+		return errors.New("-default-sidecar-proxy-lifecycle-startup-grace-period-seconds must be >= 0 if set")
+	}
+
 	return nil
 }
 
+// nativeSidecarSupported returns whether the Kubernetes cluster supports native sidecar containers, which
+// are enabled by default since Kubernetes 1.29, together with the version of the cluster.
+func (c *Command) nativeSidecarSupported() (bool, string, error) {
+	serverVersion, err := c.clientset.Discovery().ServerVersion()
+	if err != nil {
+		return false, "", err
+	}
+	v, err := k8sversion.ParseGeneric(serverVersion.GitVersion)
+	if err != nil {
+		return false, serverVersion.GitVersion, fmt.Errorf("unable to parse the Kubernetes version %q: %s", serverVersion.GitVersion, err)
+	}
+	return v.AtLeast(k8sversion.MajorMinor(1, 29)), serverVersion.GitVersion, nil
+}
+
 func (c *Command) parseAndValidateResourceFlags() (corev1.ResourceRequirements, error) {
 	// Init container
 	var initContainerCPULimit, initContainerCPURequest, initContainerMemoryLimit, initContainerMemoryRequest resource.Quantity
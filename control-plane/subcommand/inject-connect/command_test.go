@@ -8,6 +8,8 @@ import (
 
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -132,6 +134,24 @@ func TestRun_FlagValidation(t *testing.T) {
 			},
 			expErr: "-default-envoy-proxy-concurrency must be >= 0 if set",
 		},
+		{
+			flags: []string{"-consul-k8s-image", "foo", "-consul-image", "foo", "-consul-dataplane-image", "consul-dataplane:1.14.0",
+				"-default-sidecar-proxy-lifecycle-shutdown-grace-period-seconds=-1",
+			},
+			expErr: "-default-sidecar-proxy-lifecycle-shutdown-grace-period-seconds must be >= 0 if set",
+		},
+		{
+			flags: []string{"-consul-k8s-image", "foo", "-consul-image", "foo", "-consul-dataplane-image", "consul-dataplane:1.14.0",
+				"-default-sidecar-proxy-lifecycle-startup-grace-period-seconds=-1",
+			},
+			expErr: "-default-sidecar-proxy-lifecycle-startup-grace-period-seconds must be >= 0 if set",
+		},
+		{
+			flags: []string{"-consul-k8s-image", "foo", "-consul-image", "foo", "-consul-dataplane-image", "consul-dataplane:1.14.0",
+				"-default-sidecar-proxy-lifecycle-graceful-port=80",
+			},
+			expErr: "-default-sidecar-proxy-lifecycle-graceful-port value of 80 is not in the unprivileged port range 1024-65535",
+		},
 	}
 
 	for _, c := range cases {
@@ -159,3 +179,17 @@ func TestRun_ResourceLimitDefaults(t *testing.T) {
 	require.Equal(t, cmd.flagInitContainerMemoryRequest, "25Mi")
 	require.Equal(t, cmd.flagInitContainerMemoryLimit, "150Mi")
 }
+
+func TestRun_NativeSidecarKubernetesVersion(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset()
+	k8sClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.28.3-eks-4f4795d"}
+	ui := cli.NewMockUi()
+	cmd := Command{
+		UI:        ui,
+		clientset: k8sClient,
+	}
+	code := cmd.Run([]string{"-consul-k8s-image", "foo", "-consul-image", "foo", "-consul-dataplane-image", "consul-dataplane:1.14.0",
+		"-default-enable-native-sidecar"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "-default-enable-native-sidecar requires Kubernetes 1.29 or later, but the Kubernetes version is v1.28.3-eks-4f4795d")
+}
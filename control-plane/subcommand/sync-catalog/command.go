@@ -5,17 +5,22 @@ package synccatalog
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"regexp"
+	"sort"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	mapset "github.com/deckarep/golang-set"
+	catalogmetrics "github.com/hashicorp/consul-k8s/control-plane/catalog/metrics"
 	catalogtoconsul "github.com/hashicorp/consul-k8s/control-plane/catalog/to-consul"
 	catalogtok8s "github.com/hashicorp/consul-k8s/control-plane/catalog/to-k8s"
 	"github.com/hashicorp/consul-k8s/control-plane/consul"
@@ -26,9 +31,11 @@ import (
 	"github.com/hashicorp/consul-server-connection-manager/discovery"
 	"github.com/hashicorp/go-hclog"
 	"github.com/mitchellh/cli"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 // Command is the command for syncing the K8S and Consul service
@@ -50,6 +57,8 @@ type Command struct {
 	flagConsulServicePrefix   string
 	flagK8SSourceNamespace    string
 	flagK8SWriteNamespace     string
+	flagK8SSyncEndpoints      bool
+	flagConsulServiceFilter   string
 	flagConsulWritePeriod     time.Duration
 	flagSyncClusterIPServices bool
 	flagSyncLBEndpoints       bool
@@ -57,6 +66,7 @@ type Command struct {
 	flagAddK8SNamespaceSuffix bool
 	flagLogLevel              string
 	flagLogJSON               bool
+	flagDryRun                bool
 
 	// Flags to support namespaces
 	flagEnableNamespaces           bool     // Use namespacing on all components
@@ -66,6 +76,9 @@ type Command struct {
 	flagEnableK8SNSMirroring       bool     // Enables mirroring of k8s namespaces into Consul
 	flagK8SNSMirroringPrefix       string   // Prefix added to Consul namespaces created when mirroring
 	flagCrossNamespaceACLPolicy    string   // The name of the ACL policy to add to every created namespace if ACLs are enabled
+	flagAllowConsulNamespacesList  []string // Consul namespaces to sync services to K8s from
+	flagDenyConsulNamespacesList   []string // Consul namespaces to deny syncing services to K8s from (has precedence)
+	flagK8SWriteNamespaceMapping   []string // Mappings of Consul namespaces to the K8s namespaces their services are written to
 
 	// Flags to support Kubernetes Ingress resources
 	flagEnableIngress   bool // Register services using the hostname from an ingress resource
@@ -73,6 +86,11 @@ type Command struct {
 
 	clientset kubernetes.Interface
 
+	// consulSyncer and k8sSinks are the writers of each sync direction.
+	// They're kept to serve their plans in dry-run mode.
+	consulSyncer *catalogtoconsul.ConsulSyncer
+	k8sSinks     []*catalogtok8s.K8SSink
+
 	// ready indicates whether this controller is ready to sync services. This will be changed to true once the
 	// consul-server-connection-manager has finished initial initialization.
 	ready bool
@@ -107,6 +125,14 @@ func (c *Command) init() {
 	c.flags.StringVar(&c.flagK8SWriteNamespace, "k8s-write-namespace", metav1.NamespaceDefault,
 		"The Kubernetes namespace to write to for services from Consul. "+
 			"If this is not set then it will default to the default namespace.")
+	c.flags.BoolVar(&c.flagK8SSyncEndpoints, "k8s-sync-endpoints", false,
+		"If true, Consul services are written to Kubernetes as headless Services with EndpointSlices "+
+			"that hold the addresses of their passing instances. If false, Consul services are written "+
+			"as ExternalName Services that point to Consul DNS.")
+	c.flags.StringVar(&c.flagConsulServiceFilter, "consul-service-filter", "",
+		"A Consul filter expression that selects the Consul services to sync to Kubernetes, "+
+			"e.g. 'ServiceTags contains \"public\"' or 'ServiceMeta.team == \"payments\"'. "+
+			"If this is not set then all services are synced.")
 	c.flags.StringVar(&c.flagConsulDomain, "consul-domain", "consul",
 		"The domain for Consul services to use when writing services to "+
 			"Kubernetes. Defaults to consul.")
@@ -132,6 +158,9 @@ func (c *Command) init() {
 		"If true, Kubernetes namespace will be appended to service names synced to Consul separated by a dash. "+
 			"If false, no suffix will be appended to the service names in Consul. "+
 			"If the service name annotation is provided, the suffix is not appended.")
+	c.flags.BoolVar(&c.flagDryRun, "dry-run", false,
+		"If true, services are watched in both directions but nothing is written to Consul or Kubernetes. "+
+			"Instead, the changes that would be made are logged and served as JSON on /plan at the -listen address.")
 	c.flags.StringVar(&c.flagLogLevel, "log-level", "info",
 		"Log verbosity level. Supported values (in order of detail) are \"trace\", "+
 			"\"debug\", \"info\", \"warn\", and \"error\".")
@@ -147,6 +176,17 @@ func (c *Command) init() {
 	c.flags.StringVar(&c.flagConsulDestinationNamespace, "consul-destination-namespace", "default",
 		"[Enterprise Only] Defines which Consul namespace to register all synced services into. If '-enable-k8s-namespace-mirroring' "+
 			"is true, this is not used.")
+	c.flags.Var((*flags.AppendSliceValue)(&c.flagAllowConsulNamespacesList), "allow-consul-namespace",
+		"[Enterprise Only] Consul namespaces to explicitly allow syncing services to Kubernetes from. "+
+			"Use '*' to allow all Consul namespaces. May be specified multiple times. "+
+			"Defaults to the default namespace.")
+	c.flags.Var((*flags.AppendSliceValue)(&c.flagDenyConsulNamespacesList), "deny-consul-namespace",
+		"[Enterprise Only] Consul namespaces to explicitly deny syncing services to Kubernetes from. "+
+			"Takes precedence over allow. May be specified multiple times.")
+	c.flags.Var((*flags.AppendSliceValue)(&c.flagK8SWriteNamespaceMapping), "k8s-write-namespace-mapping",
+		"[Enterprise Only] A mapping in the form <consul namespace>=<k8s namespace> of a Consul namespace "+
+			"to the Kubernetes namespace its services are written to. Services in Consul namespaces "+
+			"that aren't mapped are written to -k8s-write-namespace. May be specified multiple times.")
 	c.flags.BoolVar(&c.flagEnableK8SNSMirroring, "enable-k8s-namespace-mirroring", false, "[Enterprise Only] Enables "+
 		"namespace mirroring.")
 	c.flags.StringVar(&c.flagK8SNSMirroringPrefix, "k8s-namespace-mirroring-prefix", "",
@@ -191,6 +231,11 @@ func (c *Command) Run(args []string) int {
 		c.UI.Error(err.Error())
 		return 1
 	}
+	k8sWriteNamespaceMapping, err := parseNamespaceMapping(c.flagK8SWriteNamespaceMapping)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
 
 	// Create the k8s clientset
 	if c.clientset == nil {
@@ -243,13 +288,17 @@ func (c *Command) Run(args []string) int {
 
 	// This is a blocking command that is run in order to ensure we only start the
 	// sync-catalog controllers only after we have access to the Consul server.
-	_, err := c.connMgr.State()
+	_, err = c.connMgr.State()
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("unable to start Consul server watcher: %s", err))
 		return 1
 	}
 	c.ready = true
 
+	if c.flagDryRun {
+		c.logger.Info("dry-run mode enabled: no changes will be written to Consul or Kubernetes")
+	}
+
 	// Convert allow/deny lists to sets
 	allowSet := flags.ToSet(c.flagAllowK8sNamespacesList)
 	denySet := flags.ToSet(c.flagDenyK8sNamespacesList)
@@ -278,12 +327,15 @@ func (c *Command) Run(args []string) int {
 			ServicePollPeriod:       c.flagConsulWritePeriod * 2,
 			ConsulK8STag:            c.flagConsulK8STag,
 			ConsulNodeName:          c.flagConsulNodeName,
+			DryRun:                  c.flagDryRun,
 		}
+		c.consulSyncer = syncer
 		go syncer.Run(ctx)
 
 		// Build the controller and start it
 		ctl := &controller.Controller{
-			Log: c.logger.Named("to-consul/controller"),
+			Name: catalogmetrics.QueueToConsul,
+			Log:  c.logger.Named("to-consul/controller"),
 			Resource: &catalogtoconsul.ServiceResource{
 				Log:                        c.logger.Named("to-consul/source"),
 				Client:                     c.clientset,
@@ -318,41 +370,87 @@ func (c *Command) Run(args []string) int {
 	// Start Consul-to-K8S sync
 	var toK8SCh chan struct{}
 	if c.flagToK8S {
-		sink := &catalogtok8s.K8SSink{
-			Client:    c.clientset,
-			Namespace: c.flagK8SWriteNamespace,
-			Log:       c.logger.Named("to-k8s/sink"),
-			Ctx:       ctx,
+		// There is a sink for every K8s namespace services are written to.
+		sinks := make(map[string]*catalogtok8s.K8SSink)
+		newSink := func(k8sNS string) *catalogtok8s.K8SSink {
+			if sink, ok := sinks[k8sNS]; ok {
+				return sink
+			}
+			sinks[k8sNS] = &catalogtok8s.K8SSink{
+				Client:        c.clientset,
+				Namespace:     k8sNS,
+				SyncEndpoints: c.flagK8SSyncEndpoints,
+				DryRun:        c.flagDryRun,
+				Log:           c.logger.Named("to-k8s/sink").With("namespace", k8sNS),
+				Ctx:           ctx,
+			}
+			c.k8sSinks = append(c.k8sSinks, sinks[k8sNS])
+			return sinks[k8sNS]
+		}
+
+		namespaceSinks := make(map[string]catalogtok8s.Sink)
+		for consulNS, k8sNS := range k8sWriteNamespaceMapping {
+			namespaceSinks[consulNS] = newSink(k8sNS)
+		}
+
+		allowConsulNSSet := flags.ToSet(c.flagAllowConsulNamespacesList)
+		if len(c.flagAllowConsulNamespacesList) == 0 {
+			allowConsulNSSet = mapset.NewSet("default")
+		}
+		denyConsulNSSet := flags.ToSet(c.flagDenyConsulNamespacesList)
+		if c.flagEnableNamespaces {
+			c.logger.Info("Consul namespace syncing configuration", "consul namespaces allowed to be synced", allowConsulNSSet,
+				"consul namespaces denied from syncing", denyConsulNSSet)
 		}
 
 		source := &catalogtok8s.Source{
-			ConsulClientConfig:  consulConfig,
-			ConsulServerConnMgr: c.connMgr,
-			Domain:              c.flagConsulDomain,
-			Sink:                sink,
-			Prefix:              c.flagK8SServicePrefix,
-			Log:                 c.logger.Named("to-k8s/source"),
-			ConsulK8STag:        c.flagConsulK8STag,
+			ConsulClientConfig:       consulConfig,
+			ConsulServerConnMgr:      c.connMgr,
+			Domain:                   c.flagConsulDomain,
+			Sink:                     newSink(c.flagK8SWriteNamespace),
+			Prefix:                   c.flagK8SServicePrefix,
+			Log:                      c.logger.Named("to-k8s/source"),
+			ConsulK8STag:             c.flagConsulK8STag,
+			Filter:                   c.flagConsulServiceFilter,
+			WatchHealth:              c.flagK8SSyncEndpoints,
+			EnableNamespaces:         c.flagEnableNamespaces,
+			AllowConsulNamespacesSet: allowConsulNSSet,
+			DenyConsulNamespacesSet:  denyConsulNSSet,
+			NamespaceSinks:           namespaceSinks,
 		}
 		go source.Run(ctx)
 
-		// Build the controller and start it
-		ctl := &controller.Controller{
-			Log:      c.logger.Named("to-k8s/controller"),
-			Resource: sink,
+		// Build a controller for every sink and start them
+		var wg sync.WaitGroup
+		for _, sink := range sinks {
+			ctl := &controller.Controller{
+				Name:     catalogmetrics.QueueToK8S,
+				Log:      c.logger.Named("to-k8s/controller").With("namespace", sink.Namespace),
+				Resource: sink,
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctl.Run(ctx.Done())
+			}()
 		}
 
 		toK8SCh = make(chan struct{})
 		go func() {
 			defer close(toK8SCh)
-			ctl.Run(ctx.Done())
+			wg.Wait()
 		}()
 	}
 
-	// Start healthcheck handler
+	// Start healthcheck and metrics handler
 	go func() {
 		mux := http.NewServeMux()
 		mux.HandleFunc("/health/ready", c.handleReady)
+		mux.Handle("/metrics", promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{}))
+		if c.flagDryRun {
+			mux.HandleFunc("/plan", c.handlePlan)
+		}
 		var handler http.Handler = mux
 
 		c.UI.Info(fmt.Sprintf("Listening on %q...", c.flagListen))
@@ -401,6 +499,31 @@ func (c *Command) handleReady(rw http.ResponseWriter, _ *http.Request) {
 	rw.WriteHeader(204)
 }
 
+// dryRunPlan is the plan served on /plan in dry-run mode.
+type dryRunPlan struct {
+	ToConsul *catalogtoconsul.SyncPlan `json:"toConsul,omitempty"`
+	ToK8S    []catalogtok8s.SinkPlan   `json:"toK8S,omitempty"`
+}
+
+func (c *Command) handlePlan(rw http.ResponseWriter, _ *http.Request) {
+	var plan dryRunPlan
+	if c.consulSyncer != nil {
+		syncPlan := c.consulSyncer.Plan()
+		plan.ToConsul = &syncPlan
+	}
+	for _, sink := range c.k8sSinks {
+		plan.ToK8S = append(plan.ToK8S, sink.Plan())
+	}
+	sort.Slice(plan.ToK8S, func(i, j int) bool {
+		return plan.ToK8S[i].Namespace < plan.ToK8S[j].Namespace
+	})
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(plan); err != nil {
+		c.UI.Error(fmt.Sprintf("[GET /plan] error encoding plan: %s", err))
+	}
+}
+
 func (c *Command) Synopsis() string { return synopsis }
 func (c *Command) Help() string {
 	c.once.Do(c.init)
@@ -437,9 +560,32 @@ func (c *Command) validateFlags() error {
 		)
 	}
 
+	if !c.flagEnableNamespaces && (len(c.flagAllowConsulNamespacesList) > 0 ||
+		len(c.flagDenyConsulNamespacesList) > 0 || len(c.flagK8SWriteNamespaceMapping) > 0) {
+		return errors.New("-allow-consul-namespace, -deny-consul-namespace and -k8s-write-namespace-mapping " +
+			"can only be set if -enable-namespaces is true")
+	}
+
 	return nil
 }
 
+// parseNamespaceMapping parses mappings in the form <consul namespace>=<k8s namespace>
+// into a map of Consul namespaces to K8s namespaces.
+func parseNamespaceMapping(mappings []string) (map[string]string, error) {
+	result := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		consulNS, k8sNS, ok := strings.Cut(mapping, "=")
+		if !ok || consulNS == "" || k8sNS == "" {
+			return nil, fmt.Errorf("-k8s-write-namespace-mapping=%s is invalid: must be in the form <consul namespace>=<k8s namespace>", mapping)
+		}
+		if existing, ok := result[consulNS]; ok && existing != k8sNS {
+			return nil, fmt.Errorf("-k8s-write-namespace-mapping=%s is invalid: Consul namespace %q is already mapped to %q", mapping, consulNS, existing)
+		}
+		result[consulNS] = k8sNS
+	}
+	return result, nil
+}
+
 const synopsis = "Sync Kubernetes services and Consul services."
 const help = `
 Usage: consul-k8s-control-plane sync-catalog [options]
@@ -5,6 +5,10 @@ package synccatalog
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"strconv"
 	"syscall"
@@ -12,6 +16,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/consul-k8s/control-plane/helper/test"
+	"github.com/hashicorp/consul/sdk/freeport"
 	"github.com/hashicorp/consul/sdk/testutil/retry"
 	"github.com/hashicorp/go-hclog"
 	"github.com/mitchellh/cli"
@@ -39,6 +44,19 @@ func TestRun_FlagValidation(t *testing.T) {
 			ExpErr: "-consul-node-name=5r9OPGfSRXUdGzNjBdAwmhCBrzHDNYs4XjZVR4wp7lSLIzqwS0ta51nBLIN0TMPV-too-long is invalid: node name will not be discoverable " +
 				"via DNS due to it being too long. Valid lengths are between 1 and 63 bytes",
 		},
+		{
+			Flags: []string{"-allow-consul-namespace=foo"},
+			ExpErr: "-allow-consul-namespace, -deny-consul-namespace and -k8s-write-namespace-mapping " +
+				"can only be set if -enable-namespaces is true",
+		},
+		{
+			Flags:  []string{"-enable-namespaces", "-k8s-write-namespace-mapping=foo"},
+			ExpErr: "-k8s-write-namespace-mapping=foo is invalid: must be in the form <consul namespace>=<k8s namespace>",
+		},
+		{
+			Flags:  []string{"-enable-namespaces", "-k8s-write-namespace-mapping=foo=bar", "-k8s-write-namespace-mapping=foo=baz"},
+			ExpErr: "-k8s-write-namespace-mapping=foo=baz is invalid: Consul namespace \"foo\" is already mapped to \"bar\"",
+		},
 	}
 
 	for _, c := range cases {
@@ -87,6 +105,93 @@ func TestRun_Defaults_SyncsConsulServiceToK8s(t *testing.T) {
 	})
 }
 
+// Test that the sync is reported on /metrics.
+func TestRun_Metrics(t *testing.T) {
+	t.Parallel()
+
+	k8s, testClient := completeSetup(t)
+	listenPort := freeport.GetN(t, 1)[0]
+
+	// Run the command.
+	ui := cli.NewMockUi()
+	cmd := Command{
+		UI:        ui,
+		clientset: k8s,
+		logger: hclog.New(&hclog.LoggerOptions{
+			Name:  t.Name(),
+			Level: hclog.Debug,
+		}),
+		connMgr: testClient.Watcher,
+	}
+
+	exitChan := runCommandAsynchronously(&cmd, []string{
+		"-addresses", "127.0.0.1",
+		"-http-port", strconv.Itoa(testClient.Cfg.HTTPPort),
+		"-listen", fmt.Sprintf("127.0.0.1:%d", listenPort),
+	})
+	defer stopCommand(t, &cmd, exitChan)
+
+	retry.Run(t, func(r *retry.R) {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/metrics", listenPort))
+		require.NoError(r, err)
+		defer resp.Body.Close()
+		require.Equal(r, http.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(r, err)
+		require.Contains(r, string(body), `consul_sync_catalog_k8s_writes_total{operation="create",resource="service",result="success"}`)
+		require.Contains(r, string(body), `workqueue_depth{name="sync-catalog-to-consul"}`)
+		require.Contains(r, string(body), `workqueue_depth{name="sync-catalog-to-k8s"}`)
+	})
+}
+
+// Test that in dry-run mode the default consul service is planned but not
+// synced to k8s, and the plan is served on /plan.
+func TestRun_DryRun(t *testing.T) {
+	t.Parallel()
+
+	k8s, testClient := completeSetup(t)
+	listenPort := freeport.GetN(t, 1)[0]
+
+	// Run the command.
+	ui := cli.NewMockUi()
+	cmd := Command{
+		UI:        ui,
+		clientset: k8s,
+		logger: hclog.New(&hclog.LoggerOptions{
+			Name:  t.Name(),
+			Level: hclog.Debug,
+		}),
+		connMgr: testClient.Watcher,
+	}
+
+	exitChan := runCommandAsynchronously(&cmd, []string{
+		"-addresses", "127.0.0.1",
+		"-http-port", strconv.Itoa(testClient.Cfg.HTTPPort),
+		"-listen", fmt.Sprintf("127.0.0.1:%d", listenPort),
+		"-dry-run",
+	})
+	defer stopCommand(t, &cmd, exitChan)
+
+	retry.Run(t, func(r *retry.R) {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/plan", listenPort))
+		require.NoError(r, err)
+		defer resp.Body.Close()
+		require.Equal(r, http.StatusOK, resp.StatusCode)
+
+		var plan dryRunPlan
+		require.NoError(r, json.NewDecoder(resp.Body).Decode(&plan))
+		require.NotNil(r, plan.ToConsul)
+		require.Len(r, plan.ToK8S, 1)
+		require.Equal(r, metav1.NamespaceDefault, plan.ToK8S[0].Namespace)
+		require.Equal(r, []string{"consul"}, plan.ToK8S[0].CreateServices)
+	})
+
+	serviceList, err := k8s.CoreV1().Services(metav1.NamespaceDefault).List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, serviceList.Items)
+}
+
 // Test that the command exits cleanly on signals.
 func TestRun_ExitCleanlyOnSignals(t *testing.T) {
 	t.Run("SIGINT", testSignalHandling(syscall.SIGINT))